@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	"golang.org/x/net/websocket"
 )
@@ -35,42 +37,70 @@ func HandleResponse(c *Client, resp *http.Response, pretty bool) {
 		}
 		fmt.Printf("error: %d%s", resp.StatusCode, sbody)
 	} else if !c.Dump && len(body) > 0 {
-		var out string
-		if pretty {
-			var jbody interface{}
-			err = json.Unmarshal(body, &jbody)
-			if err != nil {
-				out = string(body)
-			} else {
-				var b []byte
-				b, err = json.MarshalIndent(jbody, "", "    ")
-				if err == nil {
-					out = string(b)
-				} else {
-					out = string(body)
-				}
-			}
-		} else {
-			out = string(body)
+		fmt.Print(formatBody(body, pretty))
+	}
+	os.Exit(exitStatus(resp))
+}
+
+// HandleEventStream prints the data of the Server-Sent Events streamed in the response as they
+// arrive, one event per line. It exits the process once the server closes the stream. Responses
+// that are not event streams are handled by HandleResponse.
+func HandleEventStream(c *Client, resp *http.Response, pretty bool) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		HandleResponse(c, resp, pretty)
+		return
+	}
+	r := NewEventReader(resp.Body)
+	for {
+		ev, err := r.Next()
+		if err == io.EOF {
+			break
 		}
-		fmt.Print(out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read event: %s", err)
+			os.Exit(-1)
+		}
+		if ev.Name != "" {
+			fmt.Printf("%s: ", ev.Name)
+		}
+		fmt.Println(formatBody(ev.Data, pretty))
 	}
+	r.Close()
+	os.Exit(exitStatus(resp))
+}
 
-	// Figure out exit code
-	exitStatus := 0
+// formatBody returns the given response body indented if pretty is true and the body is JSON.
+func formatBody(body []byte, pretty bool) string {
+	if !pretty {
+		return string(body)
+	}
+	var jbody interface{}
+	if err := json.Unmarshal(body, &jbody); err != nil {
+		return string(body)
+	}
+	b, err := json.MarshalIndent(jbody, "", "    ")
+	if err != nil {
+		return string(body)
+	}
+	return string(b)
+}
+
+// exitStatus computes the process exit status from the response status code, see HandleResponse.
+func exitStatus(resp *http.Response) int {
+	status := 0
 	switch {
 	case resp.StatusCode == 401:
-		exitStatus = 1
+		status = 1
 	case resp.StatusCode == 403:
-		exitStatus = 3
+		status = 3
 	case resp.StatusCode == 404:
-		exitStatus = 4
+		status = 4
 	case resp.StatusCode > 399 && resp.StatusCode < 500:
-		exitStatus = 2
+		status = 2
 	case resp.StatusCode > 499:
-		exitStatus = 5
+		status = 5
 	}
-	os.Exit(exitStatus)
+	return status
 }
 
 // WSWrite sends STDIN lines to a websocket server.
@@ -94,3 +124,33 @@ func WSRead(ws *websocket.Conn) {
 		fmt.Printf("<< %s\n", msg[:n])
 	}
 }
+
+// WSWriteMessages reads STDIN lines and sends them to a websocket server using the given function.
+// Lines that fail to be sent are reported on STDERR.
+func WSWriteMessages(send func(line []byte) error) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		t := scanner.Text()
+		if err := send([]byte(t)); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to send message: %s\n", err)
+			continue
+		}
+		fmt.Printf(">> %s\n", t)
+	}
+}
+
+// WSReadMessages receives messages from a websocket server using the given function and prints them
+// to STDOUT encoded in JSON.
+func WSReadMessages(receive func() (interface{}, error)) {
+	for {
+		msg, err := receive()
+		if err != nil {
+			log.Fatal(err)
+		}
+		b, err := json.Marshal(msg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("<< %s\n", b)
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Backoff computes the delays between the requests that poll the status of a job.
+type Backoff struct {
+	// Initial is the delay before the first poll.
+	Initial time.Duration
+	// Max caps the delay between two polls.
+	Max time.Duration
+	// Factor multiplies the delay after each poll.
+	Factor float64
+}
+
+// DefaultBackoff is the backoff used by the generated helpers that wait for the completion of
+// jobs started by long-running actions.
+var DefaultBackoff = &Backoff{Initial: 500 * time.Millisecond, Max: 30 * time.Second, Factor: 2}
+
+// WaitForCompletion calls poll until it returns true or an error, waiting between calls as
+// dictated by the given backoff. It returns the context error if ctx is done first.
+func WaitForCompletion(ctx context.Context, b *Backoff, poll func(context.Context) (bool, error)) error {
+	if b == nil {
+		b = DefaultBackoff
+	}
+	delay := b.Initial
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		done, err := poll(ctx)
+		if err != nil || done {
+			return err
+		}
+		if b.Factor > 1 {
+			delay = time.Duration(float64(delay) * b.Factor)
+		}
+		if b.Max > 0 && delay > b.Max {
+			delay = b.Max
+		}
+	}
+}
+
+// JobStatusPath returns the path to the job status given in the Location header of the 202
+// Accepted response of a long-running action.
+func JobStatusPath(resp *http.Response) (string, error) {
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("unexpected response status %s, the job was not started", resp.Status)
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("missing Location header in response")
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid Location header: %s", err)
+	}
+	return u.Path, nil
+}
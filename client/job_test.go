@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WaitForCompletion", func() {
+	backoff := &client.Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Factor: 2}
+
+	It("polls until the job is done", func() {
+		calls := 0
+		err := client.WaitForCompletion(context.Background(), backoff, func(context.Context) (bool, error) {
+			calls++
+			return calls == 3, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("stops on errors", func() {
+		err := client.WaitForCompletion(context.Background(), backoff, func(context.Context) (bool, error) {
+			return false, errors.New("boom")
+		})
+		Expect(err).To(MatchError("boom"))
+	})
+
+	It("stops when the context is canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := client.WaitForCompletion(ctx, backoff, func(context.Context) (bool, error) {
+			return false, nil
+		})
+		Expect(err).To(Equal(context.Canceled))
+	})
+})
+
+var _ = Describe("JobStatusPath", func() {
+	It("returns the path of the Location header", func() {
+		resp := &http.Response{StatusCode: 202, Header: http.Header{"Location": {"http://localhost/imports/jobs/abc"}}}
+		path, err := client.JobStatusPath(resp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(path).To(Equal("/imports/jobs/abc"))
+	})
+
+	It("fails if the job was not started", func() {
+		_, err := client.JobStatusPath(&http.Response{StatusCode: 400, Status: "400 Bad Request"})
+		Expect(err).To(HaveOccurred())
+	})
+})
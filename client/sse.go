@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Event is a Server-Sent Event read from a response body.
+	Event struct {
+		// ID is the event identifier, empty if the server did not set one.
+		ID string
+		// Name is the event type, empty if the server did not set one.
+		Name string
+		// Data is the raw event data.
+		Data []byte
+		// Retry is the reconnection time in milliseconds requested by the server, 0 if not set.
+		Retry int
+	}
+
+	// EventReader reads Server-Sent Events from a response body.
+	EventReader struct {
+		body    io.ReadCloser
+		scanner *bufio.Scanner
+		lastID  string
+	}
+)
+
+// NewEventReader returns a reader that reads the events streamed in the given response body.
+func NewEventReader(body io.ReadCloser) *EventReader {
+	return &EventReader{body: body, scanner: bufio.NewScanner(body)}
+}
+
+// Next blocks until the next event is received and returns it. It returns io.EOF once the server
+// closes the stream.
+func (r *EventReader) Next() (*Event, error) {
+	var (
+		ev      Event
+		data    bytes.Buffer
+		hasData bool
+	)
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if !hasData {
+				ev = Event{}
+				continue
+			}
+			ev.ID = r.lastID
+			ev.Data = data.Bytes()
+			return &ev, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "id":
+			r.lastID = value
+		case "event":
+			ev.Name = value
+		case "retry":
+			if retry, err := strconv.Atoi(value); err == nil {
+				ev.Retry = retry
+			}
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// LastEventID returns the ID of the last event received, clients reconnecting to the stream should
+// send it in the Last-Event-ID header.
+func (r *EventReader) LastEventID() string {
+	return r.lastID
+}
+
+// Close closes the underlying response body.
+func (r *EventReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventReader", func() {
+	var body string
+	var reader *client.EventReader
+
+	JustBeforeEach(func() {
+		reader = client.NewEventReader(ioutil.NopCloser(strings.NewReader(body)))
+	})
+
+	Context("with a stream of events", func() {
+		BeforeEach(func() {
+			body = ": comment\n\nid: 1\nevent: update\ndata: line1\ndata: line2\n\nretry: 1000\ndata:{\"a\":1}\n\n"
+		})
+
+		It("reads the events", func() {
+			ev, err := reader.Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ev.ID).To(Equal("1"))
+			Expect(ev.Name).To(Equal("update"))
+			Expect(string(ev.Data)).To(Equal("line1\nline2"))
+
+			ev, err = reader.Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ev.ID).To(Equal("1"))
+			Expect(ev.Name).To(BeEmpty())
+			Expect(ev.Retry).To(Equal(1000))
+			Expect(string(ev.Data)).To(Equal(`{"a":1}`))
+			Expect(reader.LastEventID()).To(Equal("1"))
+
+			_, err = reader.Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+
+	Context("with an event not terminated by a blank line", func() {
+		BeforeEach(func() {
+			body = "data: partial\n"
+		})
+
+		It("discards the event", func() {
+			_, err := reader.Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+})
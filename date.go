@@ -0,0 +1,59 @@
+package goa
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DateLayout is the RFC3339 full-date layout used to format and parse Date values.
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date with no time component. Date values are encoded using the RFC3339
+// full-date format (e.g. "2017-03-21"). Date is the Go type used to represent attributes of type
+// Date in the generated code.
+type Date struct {
+	time.Time
+}
+
+// ParseDate parses a RFC3339 full-date formatted value.
+func ParseDate(val string) (Date, error) {
+	t, err := time.Parse(DateLayout, val)
+	if err != nil {
+		return Date{}, err
+	}
+	return Date{Time: t}, nil
+}
+
+// String returns the RFC3339 full-date representation of the date.
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(data []byte) error {
+	pd, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = pd
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(val))
+}
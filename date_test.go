@@ -0,0 +1,40 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Date", func() {
+	Context("ParseDate", func() {
+		It("parses full-date values", func() {
+			d, err := goa.ParseDate("2017-03-21")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(d.Year()).Should(Equal(2017))
+			Ω(d.Month()).Should(Equal(time.March))
+			Ω(d.Day()).Should(Equal(21))
+		})
+
+		It("rejects date-time values", func() {
+			_, err := goa.ParseDate("2017-03-21T10:00:00Z")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("JSON encoding", func() {
+		It("round trips", func() {
+			d, err := goa.ParseDate("2017-03-21")
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := json.Marshal(d)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(`"2017-03-21"`))
+			var decoded goa.Date
+			Ω(json.Unmarshal(b, &decoded)).ShouldNot(HaveOccurred())
+			Ω(decoded).Should(Equal(d))
+		})
+	})
+})
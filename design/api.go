@@ -185,6 +185,117 @@ var (
 		AttributeDefinition: &AttributeDefinition{Type: errorMediaType},
 		Name:                "default",
 	}
+
+	// ProblemMediaIdentifier is the media type identifier used for error responses when the API
+	// renders errors as RFC 7807 problem details.
+	ProblemMediaIdentifier = "application/problem+json"
+
+	// ProblemMedia is the built-in media type for error responses rendered as RFC 7807 problem
+	// details. It replaces ErrorMedia in APIs that use the ProblemDetails DSL.
+	ProblemMedia = &MediaTypeDefinition{
+		UserTypeDefinition: &UserTypeDefinition{
+			AttributeDefinition: &AttributeDefinition{
+				Type:        problemMediaType,
+				Description: "Error response media type, the error metadata is rendered as extension members",
+				Validation:  &dslengine.ValidationDefinition{Required: []string{"type", "title", "status"}},
+				Example: map[string]interface{}{
+					"type":     "invalid_value",
+					"title":    "Bad Request",
+					"status":   400,
+					"detail":   "Value of ID must be an integer",
+					"instance": "3F1FKVRR",
+				},
+			},
+			TypeName: "error",
+		},
+		Identifier: ProblemMediaIdentifier,
+		Views:      map[string]*ViewDefinition{"default": problemMediaView},
+	}
+
+	problemMediaType = Object{
+		"type": &AttributeDefinition{
+			Type:        String,
+			Description: "the problem type, the application-specific error code.",
+			Example:     "invalid_value",
+		},
+		"title": &AttributeDefinition{
+			Type:        String,
+			Description: "a short human-readable summary of the problem type.",
+			Example:     "Bad Request",
+		},
+		"status": &AttributeDefinition{
+			Type:        Integer,
+			Description: "the HTTP status code applicable to this problem.",
+			Example:     400,
+		},
+		"detail": &AttributeDefinition{
+			Type:        String,
+			Description: "a human-readable explanation specific to this occurrence of the problem.",
+			Example:     "Value of ID must be an integer",
+		},
+		"instance": &AttributeDefinition{
+			Type:        String,
+			Description: "a unique identifier for this particular occurrence of the problem.",
+			Example:     "3F1FKVRR",
+		},
+	}
+
+	problemMediaView = &ViewDefinition{
+		AttributeDefinition: &AttributeDefinition{Type: problemMediaType},
+		Name:                "default",
+	}
+
+	// JobMediaIdentifier is the media type identifier used for the status of the jobs started
+	// by long-running actions.
+	JobMediaIdentifier = "application/vnd.goa.job"
+
+	// JobMedia is the built-in media type for the status of the jobs started by long-running
+	// actions.
+	JobMedia = &MediaTypeDefinition{
+		UserTypeDefinition: &UserTypeDefinition{
+			AttributeDefinition: &AttributeDefinition{
+				Type:        jobMediaType,
+				Description: "Status of a long-running operation",
+				Validation:  &dslengine.ValidationDefinition{Required: []string{"id", "state"}},
+				Example: map[string]interface{}{
+					"id":    "Ow3qM9Ti",
+					"state": "succeeded",
+				},
+			},
+			TypeName: "GoaJob",
+		},
+		Identifier: JobMediaIdentifier,
+		Views:      map[string]*ViewDefinition{"default": jobMediaView},
+	}
+
+	jobMediaType = Object{
+		"id": &AttributeDefinition{
+			Type:        String,
+			Description: "the job identifier.",
+			Example:     "Ow3qM9Ti",
+		},
+		"state": &AttributeDefinition{
+			Type:        String,
+			Description: "the job state.",
+			Validation: &dslengine.ValidationDefinition{
+				Values: []interface{}{"pending", "running", "succeeded", "failed"},
+			},
+			Example: "succeeded",
+		},
+		"error": &AttributeDefinition{
+			Type:        String,
+			Description: "a description of the failure if the job failed.",
+		},
+		"result": &AttributeDefinition{
+			Type:        Any,
+			Description: "the job result if the job succeeded.",
+		},
+	}
+
+	jobMediaView = &ViewDefinition{
+		AttributeDefinition: &AttributeDefinition{Type: jobMediaType},
+		Name:                "default",
+	}
 )
 
 func init() {
@@ -200,6 +311,8 @@ func init() {
 		{MIMETypes: GobContentTypes, PackagePath: goa, Function: "NewGobDecoder"},
 	}
 	errorMediaView.Parent = ErrorMedia
+	problemMediaView.Parent = ProblemMedia
+	jobMediaView.Parent = JobMedia
 }
 
 // CanonicalIdentifier returns the media type identifier sans suffix
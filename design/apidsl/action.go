@@ -2,6 +2,7 @@ package apidsl
 
 import (
 	"fmt"
+	"strings"
 	"unicode"
 
 	"github.com/goadesign/goa/design"
@@ -113,6 +114,9 @@ func Action(name string, dsl func()) {
 			return
 		}
 		r.Actions[name] = action
+		if action.Async != nil && action.Async.StatusAction == nil {
+			asyncStatusAction(r, action)
+		}
 	}
 }
 
@@ -316,6 +320,75 @@ func Headers(params ...interface{}) {
 	}
 }
 
+// Cookies can be used in: Action, Resource, Response
+//
+// Cookies implements the DSL for describing HTTP request and response cookies. The DSL syntax is identical to
+// the one of Attribute, cookies must be of primitive types. Here is an example defining a session
+// cookie:
+//
+//	Cookies(func() {
+//		Cookie("session", String, func() {
+//			MinLength(16)
+//		})
+//		Required("session")
+//	})
+//
+// Cookies can be used inside Action to define the action request cookies or Resource to define
+// common request cookies to all the resource actions. The generated action contexts expose the
+// decoded and validated cookie values as fields.
+//
+// Cookies can also be used inside Response to define the cookies written by the response. The
+// CookieMaxAge, CookieSecure, CookieHTTPOnly, CookieSameSite, CookiePath and CookieDomain
+// functions set the attributes of the cookies:
+//
+//	Response(OK, func() {
+//		Cookies(func() {
+//			Cookie("session", String, func() {
+//				CookieSecure()
+//				CookieHTTPOnly()
+//				CookieSameSite("Strict")
+//			})
+//			Required("session")
+//		})
+//	})
+//
+// The generated response methods take the values of the response headers and cookies and return
+// an error if a required one is missing.
+func Cookies(dsl func()) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		cookies := newAttribute(def.Parent.MediaType)
+		if dslengine.Execute(dsl, cookies) {
+			def.Cookies = def.Cookies.Merge(cookies)
+		}
+
+	case *design.ResourceDefinition:
+		cookies := newAttribute(def.MediaType)
+		if dslengine.Execute(dsl, cookies) {
+			def.Cookies = def.Cookies.Merge(cookies)
+		}
+
+	case *design.ResponseDefinition:
+		var c *design.AttributeDefinition
+		switch actual := def.Parent.(type) {
+		case *design.ResourceDefinition:
+			c = newAttribute(actual.MediaType)
+		case *design.ActionDefinition:
+			c = newAttribute(actual.Parent.MediaType)
+		case nil: // API ResponseTemplate
+			c = &design.AttributeDefinition{}
+		default:
+			dslengine.ReportError("invalid use of Response or ResponseTemplate")
+		}
+		if dslengine.Execute(dsl, c) {
+			def.Cookies = def.Cookies.Merge(c)
+		}
+
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
 // Params can be used in: Action, Resource, API
 //
 // Params describe the action parameters, either path parameters identified via wildcards or query
@@ -385,7 +458,7 @@ func Params(dsl func()) {
 	}
 }
 
-// Payload can be used in: Action
+// Payload can be used in: Action, Webhook
 //
 // Payload implements the action payload DSL. An action payload describes the HTTP request body
 // data structure. The function accepts either a type or a DSL that describes the payload members
@@ -423,58 +496,237 @@ func payload(isOptional bool, p interface{}, dsls ...func()) {
 		dslengine.ReportError("too many arguments given to Payload")
 		return
 	}
-	if a, ok := actionDefinition(); ok {
-		var att *design.AttributeDefinition
-		var dsl func()
-		switch actual := p.(type) {
-		case func():
-			dsl = actual
-			att = newAttribute(a.Parent.MediaType)
-			att.Type = design.Object{}
-		case *design.AttributeDefinition:
-			att = design.DupAtt(actual)
-		case *design.UserTypeDefinition:
-			if len(dsls) == 0 {
-				a.Payload = actual
-				a.PayloadOptional = isOptional
-				return
-			}
-			att = design.DupAtt(actual.Definition())
-		case *design.MediaTypeDefinition:
-			att = design.DupAtt(actual.AttributeDefinition)
-		case string:
-			ut, ok := design.Design.Types[actual]
-			if !ok {
-				dslengine.ReportError("unknown payload type %s", actual)
-			}
-			att = design.DupAtt(ut.AttributeDefinition)
-		case *design.Array:
-			att = &design.AttributeDefinition{Type: actual}
-		case *design.Hash:
-			att = &design.AttributeDefinition{Type: actual}
-		case design.Primitive:
-			att = &design.AttributeDefinition{Type: actual}
-		default:
-			dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		typeName := fmt.Sprintf("%s%sPayload", camelize(def.Name), camelize(def.Parent.Name))
+		if ut := payloadType(def.Parent.MediaType, typeName, p, dsls...); ut != nil {
+			def.Payload = ut
+			def.PayloadOptional = isOptional
+		}
+	case *design.WebhookDefinition:
+		if isOptional {
+			dslengine.ReportError("webhook payloads cannot be optional")
 			return
 		}
-		if len(dsls) == 1 {
-			if dsl != nil {
-				dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
-			}
-			dsl = dsls[0]
+		def.Payload = payloadType("", camelize(def.Name)+"WebhookPayload", p, dsls...)
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// payloadType returns the user type described by the arguments of Payload. Inline definitions
+// produce a type with the given name.
+func payloadType(baseMT, typeName string, p interface{}, dsls ...func()) *design.UserTypeDefinition {
+	var att *design.AttributeDefinition
+	var dsl func()
+	switch actual := p.(type) {
+	case func():
+		dsl = actual
+		att = newAttribute(baseMT)
+		att.Type = design.Object{}
+	case *design.AttributeDefinition:
+		att = design.DupAtt(actual)
+	case *design.UserTypeDefinition:
+		if len(dsls) == 0 {
+			return actual
+		}
+		att = design.DupAtt(actual.Definition())
+	case *design.MediaTypeDefinition:
+		att = design.DupAtt(actual.AttributeDefinition)
+	case string:
+		ut, ok := design.Design.Types[actual]
+		if !ok {
+			dslengine.ReportError("unknown payload type %s", actual)
 		}
+		att = design.DupAtt(ut.AttributeDefinition)
+	case *design.Array:
+		att = &design.AttributeDefinition{Type: actual}
+	case *design.Hash:
+		att = &design.AttributeDefinition{Type: actual}
+	case design.Primitive:
+		att = &design.AttributeDefinition{Type: actual}
+	default:
+		dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+		return nil
+	}
+	if len(dsls) == 1 {
 		if dsl != nil {
-			dslengine.Execute(dsl, att)
+			dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
 		}
-		rn := camelize(a.Parent.Name)
-		an := camelize(a.Name)
-		a.Payload = &design.UserTypeDefinition{
-			AttributeDefinition: att,
-			TypeName:            fmt.Sprintf("%s%sPayload", an, rn),
+		dsl = dsls[0]
+	}
+	if dsl != nil {
+		dslengine.Execute(dsl, att)
+	}
+	if ut, ok := att.Type.(*design.UserTypeDefinition); ok && ut.IsUnion() {
+		// OneOf defined the payload type
+		return ut
+	}
+	return &design.UserTypeDefinition{
+		AttributeDefinition: att,
+		TypeName:            typeName,
+	}
+}
+
+// InboundMessage can be used in: Action
+//
+// InboundMessage sets the type of the messages sent by clients to a websocket action. The argument
+// is a user type, a media type or the name of a user type or identifier of a media type defined in
+// the design. goagen generates helpers that decode and validate the messages received on the
+// websocket connection:
+//
+//	Action("chat", func() {
+//		Routing(GET("/chat"))
+//		Scheme("ws")
+//		InboundMessage(ChatMessage)
+//		OutboundMessage(ChatEventMedia)
+//		Response(SwitchingProtocols)
+//	})
+func InboundMessage(message interface{}) {
+	if a, ok := actionDefinition(); ok {
+		if t := messageType(message); t != nil {
+			a.InboundMessage = t
+		}
+	}
+}
+
+// OutboundMessage can be used in: Action
+//
+// OutboundMessage sets the type of the messages sent by a websocket action to clients, see
+// InboundMessage. goagen generates helpers that validate and encode the messages written to the
+// websocket connection.
+func OutboundMessage(message interface{}) {
+	if a, ok := actionDefinition(); ok {
+		if t := messageType(message); t != nil {
+			a.OutboundMessage = t
+		}
+	}
+}
+
+// messageType returns the user type or media type used to define websocket messages.
+func messageType(message interface{}) design.DataType {
+	switch actual := message.(type) {
+	case *design.MediaTypeDefinition:
+		return actual
+	case *design.UserTypeDefinition:
+		return actual
+	case string:
+		if mt := design.Design.MediaTypeWithIdentifier(actual); mt != nil {
+			return mt
+		}
+		if ut, ok := design.Design.Types[actual]; ok {
+			return ut
+		}
+		dslengine.ReportError("unknown message type %s", actual)
+	default:
+		dslengine.ReportError("invalid message type %#v, must be a user type, a media type or the name of one", message)
+	}
+	return nil
+}
+
+// Async can be used in: Action
+//
+// Async is equivalent to LongRunning(JobMedia): it makes the action start a long-running operation
+// whose status is described using the built-in "application/vnd.goa.job" media type.
+func Async() {
+	LongRunning(design.JobMedia)
+}
+
+// LongRunning can be used in: Action
+//
+// LongRunning makes the action start a long-running operation (a job). The action responds with
+// 202 Accepted and a Location header pointing to the job status. The argument is the media type
+// or identifier of the media type describing the job status, it must define a required "state"
+// string attribute whose value is one of "pending", "running", "succeeded" or "failed".
+//
+// LongRunning also defines a companion action named after the action with a "_status" suffix.
+// The companion action routes append "/jobs/:jobID" to the action routes and return the job
+// status:
+//
+//	Action("import", func() {
+//		Routing(POST("/imports"))
+//		Payload(ImportPayload)
+//		LongRunning(ImportStatusMedia)
+//	})
+//
+// defines the "import_status" action with route "GET /imports/jobs/:jobID". goagen generates
+// context helpers that write the responses given a goa.Job and client helpers that poll the job
+// status until it completes.
+func LongRunning(statusMediaType interface{}) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	var identifier string
+	switch actual := statusMediaType.(type) {
+	case *design.MediaTypeDefinition:
+		identifier = actual.Identifier
+	case string:
+		identifier = actual
+	default:
+		dslengine.ReportError("invalid status media type %#v, must be a media type or the identifier of one", statusMediaType)
+		return
+	}
+	if design.CanonicalIdentifier(identifier) == design.CanonicalIdentifier(design.JobMediaIdentifier) {
+		if design.Design.MediaTypes == nil {
+			design.Design.MediaTypes = make(map[string]*design.MediaTypeDefinition)
 		}
-		a.PayloadOptional = isOptional
+		design.Design.MediaTypes[design.CanonicalIdentifier(design.JobMediaIdentifier)] = design.JobMedia
+	}
+	a.Async = &design.AsyncDefinition{StatusMediaType: identifier}
+	Response(design.Accepted, func() {
+		Description("The job was started, the Location header points to its status.")
+		Headers(func() {
+			Header("Location", design.String, "Path to the job status")
+			Required("Location")
+		})
+	})
+}
+
+// asyncStatusAction creates the companion action that returns the status of the jobs started by
+// the given long-running action.
+func asyncStatusAction(r *design.ResourceDefinition, a *design.ActionDefinition) {
+	name := a.Name + "_status"
+	if _, ok := r.Actions[name]; ok {
+		dslengine.ReportError("action %s conflicts with the status action of long running action %s", name, a.Name)
+		return
+	}
+	status := &design.ActionDefinition{
+		Parent:      r,
+		Name:        name,
+		Description: fmt.Sprintf("Returns the status of the jobs started by the %s action.", a.Name),
+		Schemes:     a.Schemes,
+		Security:    a.Security,
+		Deprecation: a.Deprecation,
+		Metadata:    make(dslengine.MetadataDefinition),
+		Params: &design.AttributeDefinition{
+			Type: design.Object{
+				"jobID": &design.AttributeDefinition{Type: design.String, Description: "ID of the job"},
+			},
+		},
+	}
+	for _, route := range a.Routes {
+		status.Routes = append(status.Routes, &design.RouteDefinition{
+			Verb:   "GET",
+			Path:   strings.TrimSuffix(route.Path, "/") + "/jobs/:jobID",
+			Parent: status,
+		})
+	}
+	status.Responses = map[string]*design.ResponseDefinition{
+		design.OK: {
+			Name:      design.OK,
+			Status:    200,
+			MediaType: a.Async.StatusMediaType,
+			Parent:    status,
+		},
+		design.NotFound: {
+			Name:   design.NotFound,
+			Status: 404,
+			Parent: status,
+		},
 	}
+	a.Async.StatusAction = status
+	r.Actions[name] = status
 }
 
 // MultipartForm can be used in: Action
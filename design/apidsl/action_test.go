@@ -94,6 +94,40 @@ var _ = Describe("Action", func() {
 		})
 	})
 
+	Context("with a deprecation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Deprecated("2017-01-01", "2017-06-30", "bar")
+			}
+		})
+
+		It("records the deprecation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Deprecation).Should(Equal(&DeprecationDefinition{
+				Since:       "2017-01-01",
+				Sunset:      "2017-06-30",
+				Replacement: "bar",
+			}))
+		})
+
+		Context("with an invalid sunset date", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Deprecated("v2", "next year", "")
+				}
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid sunset date"))
+			})
+		})
+	})
+
 	Context("with a string payload", func() {
 		BeforeEach(func() {
 			name = "foo"
@@ -187,6 +221,276 @@ var _ = Describe("Action", func() {
 		})
 	})
 
+	Context("with cookies", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Cookies(func() {
+					Cookie("session", String, func() {
+						MinLength(8)
+					})
+					Cookie("theme")
+					Required("session")
+				})
+			}
+		})
+
+		It("produces a valid action with the cookies", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Cookies).ShouldNot(BeNil())
+			Ω(action.Cookies.Type.(Object)).Should(HaveLen(2))
+			Ω(action.Cookies.Type.(Object)["session"].Type).Should(Equal(String))
+			Ω(action.Cookies.IsRequired("session")).Should(BeTrue())
+			Ω(action.AllCookies().Type.(Object)).Should(HaveLen(2))
+		})
+
+		Context("of a non primitive type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Cookies(func() {
+						Cookie("ids", ArrayOf(Integer))
+					})
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("cookies must be primitives"))
+			})
+		})
+	})
+
+	Context("with websocket messages", func() {
+		BeforeEach(func() {
+			name = "chat"
+			Type("Message", func() {
+				Attribute("text", String)
+			})
+			dsl = func() {
+				Routing(GET("/chat"))
+				Scheme("ws")
+				InboundMessage("Message")
+				OutboundMessage("Message")
+			}
+		})
+
+		It("produces a valid action with the message types", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.InboundMessage).ShouldNot(BeNil())
+			Ω(action.InboundMessage.(*UserTypeDefinition).TypeName).Should(Equal("Message"))
+			Ω(action.OutboundMessage).Should(Equal(action.InboundMessage))
+		})
+
+		Context("on an action that does not use websockets", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/chat"))
+					InboundMessage("Message")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("does not use the ws or wss schemes"))
+			})
+		})
+
+		Context("with an unknown type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/chat"))
+					Scheme("ws")
+					OutboundMessage("Unknown")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("unknown message type Unknown"))
+			})
+		})
+	})
+
+	Context("with a long running operation", func() {
+		BeforeEach(func() {
+			name = "import"
+			dsl = func() {
+				Routing(POST("/imports/"))
+				Async()
+			}
+		})
+
+		It("produces the accepted response and status action", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Async).ShouldNot(BeNil())
+			Ω(action.Async.StatusMediaType).Should(Equal(JobMediaIdentifier))
+			accepted := action.Responses[Accepted]
+			Ω(accepted).ShouldNot(BeNil())
+			Ω(accepted.Headers.IsRequired("Location")).Should(BeTrue())
+			status := Design.Resources["res"].Actions["import_status"]
+			Ω(status).ShouldNot(BeNil())
+			Ω(action.Async.StatusAction).Should(Equal(status))
+			Ω(status.Routes).Should(HaveLen(1))
+			Ω(status.Routes[0].Verb).Should(Equal("GET"))
+			Ω(status.Routes[0].Path).Should(Equal("/imports/jobs/:jobID"))
+			Ω(status.Responses[OK].MediaType).Should(Equal(JobMediaIdentifier))
+			Ω(Design.MediaTypeWithIdentifier(JobMediaIdentifier)).Should(Equal(JobMedia))
+		})
+
+		Context("with a status media type without state", func() {
+			BeforeEach(func() {
+				MediaType("application/vnd.status", func() {
+					Attributes(func() {
+						Attribute("progress", Integer)
+					})
+					View("default", func() {
+						Attribute("progress")
+					})
+				})
+				dsl = func() {
+					Routing(POST("/imports"))
+					LongRunning("application/vnd.status")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`must define a required "state" string attribute`))
+			})
+		})
+	})
+
+	Context("with params defining serialization styles", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("filter", HashOf(String, String), func() {
+						Style("deepObject")
+					})
+					Param("tags", ArrayOf(String), func() {
+						Style("pipeDelimited")
+					})
+					Param("ids", ArrayOf(Integer), func() {
+						Explode(false)
+					})
+				})
+			}
+		})
+
+		It("produces a valid action with the param styles", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			params := action.Params.Type.(Object)
+			Ω(params["filter"].ParamStyle()).Should(Equal(ParamStyleDeepObject))
+			Ω(params["filter"].IsExploded()).Should(BeTrue())
+			Ω(params["tags"].ParamStyle()).Should(Equal(ParamStylePipeDelimited))
+			Ω(params["tags"].ParamDelimiter()).Should(Equal("|"))
+			Ω(params["ids"].ParamStyle()).Should(Equal(ParamStyleForm))
+			Ω(params["ids"].ParamDelimiter()).Should(Equal(","))
+		})
+
+		Context("with an unknown style", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("tags", ArrayOf(String), func() {
+							Style("matrix")
+						})
+					})
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid style "matrix"`))
+			})
+		})
+
+		Context("with a deepObject style on a non hash param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("filter", String, func() {
+							Style("deepObject")
+						})
+					})
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("must be a hash of strings to strings"))
+			})
+		})
+
+		Context("with a deepObject style on an object param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("filter", func() {
+							Attribute("status", String)
+							Attribute("owner", String)
+							Style("deepObject")
+						})
+					})
+				}
+			})
+
+			It("reports that only hashes are supported", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+					"the deepObject style only supports parameters of type HashOf(String, String)"))
+			})
+		})
+
+		Context("with a delimited style on an exploded param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("tags", ArrayOf(String), func() {
+							Style("spaceDelimited")
+							Explode(true)
+						})
+					})
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot use the spaceDelimited style with Explode(true)"))
+			})
+		})
+
+		Context("with a style on a path param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("id", ArrayOf(String), func() {
+							Explode(false)
+						})
+					})
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("is a path parameter"))
+			})
+		})
+	})
+
 	Context("using a response with a media type modifier", func() {
 		const mtID = "application/vnd.app.foo+json"
 
@@ -5,7 +5,9 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
@@ -99,7 +101,7 @@ func Version(ver string) {
 	}
 }
 
-// Description can be used in: API, Resource, Action, MediaType, Attribute, Response or ResponseTemplate
+// Description can be used in: API, Resource, Action, MediaType, Attribute, Response, ResponseTemplate, Webhook or Error
 //
 // Description sets the definition description.
 func Description(d string) {
@@ -122,6 +124,10 @@ func Description(d string) {
 		def.Description = d
 	case *design.SecuritySchemeDefinition:
 		def.Description = d
+	case *design.WebhookDefinition:
+		def.Description = d
+	case *design.ErrorDefinition:
+		def.Description = d
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -338,6 +344,42 @@ func Docs(dsl func()) {
 	}
 }
 
+// Deprecated can be used in: Resource, Action, Attribute, Param
+//
+// Deprecated marks the resource, action or attribute as deprecated. since is the version or date
+// the deprecation took effect at, sunset is the date after which the definition may be removed
+// and replacement describes what clients should use instead. All three may be empty, dates must
+// be formatted as 2006-01-02.
+//
+// Deprecated operations and attributes are flagged in the generated swagger and JSON schema, the
+// generated client methods and CLI commands are documented as deprecated and the CLI prints a
+// warning when used. The generated controllers send the Deprecation (RFC 9745) and Sunset
+// (RFC 8594) response headers for all deprecated actions. Example:
+//
+//	Action("show", func() {
+//		Deprecated("2017-01-01", "2017-06-30", "the get action")
+//		Routing(GET("/:id"))
+//	})
+func Deprecated(since, sunset, replacement string) {
+	if sunset != "" {
+		if _, err := time.Parse(goa.DateLayout, sunset); err != nil {
+			dslengine.ReportError("invalid sunset date %#v, must be formatted as 2006-01-02", sunset)
+			return
+		}
+	}
+	dep := &design.DeprecationDefinition{Since: since, Sunset: sunset, Replacement: replacement}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ResourceDefinition:
+		def.Deprecation = dep
+	case *design.ActionDefinition:
+		def.Deprecation = dep
+	case *design.AttributeDefinition:
+		def.Deprecation = dep
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
 // Name can be used in: Contact, License.
 //
 // Name sets the contact or license name.
@@ -588,16 +630,58 @@ func Trait(name string, val ...func()) {
 			dslengine.ReportError("too many arguments given to Trait")
 			return
 		}
-		if _, ok := design.Design.Traits[name]; ok {
-			dslengine.ReportError("multiple definitions for trait %s%s", name, design.Design.Context())
+		addTrait(a, &dslengine.TraitDefinition{Name: name, DSLFunc: val[0]})
+	}
+}
+
+// ParameterizedTrait can be used in: API
+//
+// ParameterizedTrait defines an API trait whose DSL is a function that takes parameters. The trait
+// is called via the UseTraitWith function which provides the corresponding arguments:
+//
+//	ParameterizedTrait("RequiresScope", func(scope string) {
+//		Security("oauth2", func() {
+//			Scope(scope)
+//		})
+//	})
+//
+//	ParameterizedTrait("Paginated", func(of *design.MediaTypeDefinition, max int) {
+//		Params(func() {
+//			Param("page", Integer, "Page number", func() {
+//				Minimum(1)
+//			})
+//			Param("per_page", Integer, "Page size", func() {
+//				Maximum(max)
+//			})
+//		})
+//		Response(OK, CollectionOf(of))
+//	})
+//
+func ParameterizedTrait(name string, dsl interface{}) {
+	if a, ok := apiDefinition(); ok {
+		typ := reflect.TypeOf(dsl)
+		if typ == nil || typ.Kind() != reflect.Func {
+			dslengine.ReportError("trait DSL must be a function but got %#v", dsl)
 			return
 		}
-		trait := &dslengine.TraitDefinition{Name: name, DSLFunc: val[0]}
-		if a.Traits == nil {
-			a.Traits = make(map[string]*dslengine.TraitDefinition)
+		if typ.NumOut() > 0 {
+			dslengine.ReportError("trait DSL function must not return values")
+			return
 		}
-		a.Traits[name] = trait
+		addTrait(a, &dslengine.TraitDefinition{Name: name, ParamsDSLFunc: dsl})
+	}
+}
+
+// addTrait registers the given trait with the API definition.
+func addTrait(a *design.APIDefinition, trait *dslengine.TraitDefinition) {
+	if _, ok := design.Design.Traits[trait.Name]; ok {
+		dslengine.ReportError("multiple definitions for trait %s%s", trait.Name, design.Design.Context())
+		return
 	}
+	if a.Traits == nil {
+		a.Traits = make(map[string]*dslengine.TraitDefinition)
+	}
+	a.Traits[trait.Name] = trait
 }
 
 // UseTrait can be used in: Resource, Action, Type, MediaType, Attribute
@@ -605,28 +689,56 @@ func Trait(name string, val ...func()) {
 // UseTrait executes the API trait with the given name. An API level DSL trait must be
 // defined first. UseTrait takes a variable number of trait names.
 func UseTrait(names ...string) {
-	var def dslengine.Definition
+	if def := traitTarget(); def != nil {
+		for _, name := range names {
+			useTrait(def, name)
+		}
+	}
+}
 
+// UseTraitWith can be used in: Resource, Action, Type, MediaType, Attribute
+//
+// UseTraitWith executes the API trait with the given name defined with ParameterizedTrait. The
+// arguments given after the name are passed to the trait DSL function, they must match its
+// parameters:
+//
+//	UseTraitWith("RequiresScope", "api:read")
+//	UseTraitWith("Paginated", BottleMedia, 50)
+//
+func UseTraitWith(name string, args ...interface{}) {
+	if def := traitTarget(); def != nil {
+		useTrait(def, name, args...)
+	}
+}
+
+// traitTarget returns the current definition if traits may be used in it, nil otherwise.
+func traitTarget() dslengine.Definition {
 	switch typedDef := dslengine.CurrentDefinition().(type) {
 	case *design.ResourceDefinition:
-		def = typedDef
+		return typedDef
 	case *design.ActionDefinition:
-		def = typedDef
+		return typedDef
 	case *design.AttributeDefinition:
-		def = typedDef
+		return typedDef
 	case *design.MediaTypeDefinition:
-		def = typedDef
+		return typedDef
 	default:
 		dslengine.IncompatibleDSL()
 	}
+	return nil
+}
 
-	if def != nil {
-		for _, name := range names {
-			if trait, ok := design.Design.Traits[name]; ok {
-				dslengine.Execute(trait.DSLFunc, def)
-			} else {
-				dslengine.ReportError("unknown trait %s", name)
-			}
-		}
+// useTrait executes the DSL of the trait with the given name in def with the given arguments.
+func useTrait(def dslengine.Definition, name string, args ...interface{}) {
+	trait, ok := design.Design.Traits[name]
+	if !ok {
+		dslengine.ReportError("unknown trait %s", name)
+		return
+	}
+	dsl, err := trait.Bind(args...)
+	if err != nil {
+		dslengine.ReportError("%s", err)
+		return
 	}
+	dslengine.Execute(dsl, def)
 }
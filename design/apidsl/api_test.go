@@ -448,6 +448,95 @@ var _ = Describe("API", func() {
 				Ω(o).Should(HaveKey("baz"))
 			})
 		})
+
+		Context("using parameterized Traits", func() {
+			const traitName = "Named"
+			var args []interface{}
+
+			BeforeEach(func() {
+				args = []interface{}{"foo", 3}
+				dsl = func() {
+					ParameterizedTrait(traitName, func(attName string, max float64) {
+						Attributes(func() {
+							Attribute(attName, Number, func() {
+								Maximum(max)
+							})
+						})
+					})
+				}
+			})
+
+			JustBeforeEach(func() {
+				API(name, dsl)
+				MediaType("application/vnd.foo", func() {
+					UseTraitWith(traitName, args...)
+					Attributes(func() {
+						Attribute("bar")
+					})
+					View("default", func() {
+						Attribute("bar")
+					})
+				})
+				dslengine.Run()
+			})
+
+			It("executes the trait DSL with the arguments", func() {
+				Ω(Design.MediaTypes).Should(HaveKey("application/vnd.foo"))
+				o := Design.MediaTypes["application/vnd.foo"].Type.ToObject()
+				Ω(o).Should(HaveKey("foo"))
+				Ω(o["foo"].Validation).ShouldNot(BeNil())
+				Ω(*o["foo"].Validation.Maximum).Should(Equal(3.0))
+				Ω(o).Should(HaveKey("bar"))
+			})
+
+			Context("with missing arguments", func() {
+				BeforeEach(func() {
+					args = []interface{}{"foo"}
+				})
+
+				It("reports an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+						`missing arguments for trait "Named", expected 2 but got 1`))
+				})
+			})
+
+			Context("with too many arguments", func() {
+				BeforeEach(func() {
+					args = []interface{}{"foo", 3, true}
+				})
+
+				It("reports an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+						`too many arguments given to trait "Named", expected 2 but got 3`))
+				})
+			})
+
+			Context("used without arguments", func() {
+				BeforeEach(func() {
+					args = nil
+				})
+
+				It("reports an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+						`missing arguments for trait "Named", expected 2 but got 0`))
+				})
+			})
+
+			Context("with an argument of the wrong type", func() {
+				BeforeEach(func() {
+					args = []interface{}{42, 3}
+				})
+
+				It("reports an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+						`invalid argument 42 at position 1 for trait "Named", expected a value of type string`))
+				})
+			})
+		})
 	})
 
 })
@@ -2,6 +2,7 @@ package apidsl
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -113,6 +114,12 @@ func Attribute(name string, args ...interface{}) {
 		}
 
 		baseAttr := attributeFromRef(name, parent.Reference)
+		for i := 0; baseAttr == nil && i < len(parent.Bases); i++ {
+			// The DSL of extended types has already run, see Extend.
+			if att, ok := parent.Bases[i].ToObject()[name]; ok {
+				baseAttr = design.DupAtt(att)
+			}
+		}
 		dataType, description, dsl := parseAttributeArgs(baseAttr, args...)
 		if baseAttr != nil {
 			if description != "" {
@@ -142,9 +149,19 @@ func Attribute(name string, args ...interface{}) {
 // attributeFromRef returns a base attribute given a reference data type.
 // It takes care of running the DSL on the reference type if it hasn't run yet.
 func attributeFromRef(name string, ref design.DataType) *design.AttributeDefinition {
-	if ref == nil {
-		return nil
+	if att := refAttribute(ref); att != nil && att.Type != nil {
+		if o := att.Type.ToObject(); o != nil {
+			if att, ok := o[name]; ok {
+				return design.DupAtt(att)
+			}
+		}
 	}
+	return nil
+}
+
+// refAttribute returns the attribute of a reference data type. It takes care of running the DSL on
+// the reference type if it hasn't run yet.
+func refAttribute(ref design.DataType) *design.AttributeDefinition {
 	switch t := ref.(type) {
 	case *design.UserTypeDefinition:
 		if t.DSLFunc != nil {
@@ -152,22 +169,16 @@ func attributeFromRef(name string, ref design.DataType) *design.AttributeDefinit
 			t.DSLFunc = nil
 			dslengine.Execute(dsl, t.AttributeDefinition)
 		}
-		if att, ok := t.ToObject()[name]; ok {
-			return design.DupAtt(att)
-		}
+		return t.AttributeDefinition
 	case *design.MediaTypeDefinition:
 		if t.DSLFunc != nil {
 			dsl := t.DSLFunc
 			t.DSLFunc = nil
 			dslengine.Execute(dsl, t)
 		}
-		if att, ok := t.ToObject()[name]; ok {
-			return design.DupAtt(att)
-		}
+		return t.AttributeDefinition
 	case design.Object:
-		if att, ok := t[name]; ok {
-			return design.DupAtt(att)
-		}
+		return &design.AttributeDefinition{Type: t}
 	}
 	return nil
 }
@@ -261,6 +272,60 @@ func Header(name string, args ...interface{}) {
 	Attribute(name, args...)
 }
 
+// Cookie can be used in: Cookies
+//
+// Cookie is an alias of Attribute.
+func Cookie(name string, args ...interface{}) {
+	Attribute(name, args...)
+}
+
+// Style can be used in: Param
+//
+// Style sets the serialization style of a query string parameter. The supported styles are:
+//
+//	"form" (default): arrays are serialized as repeated parameters, e.g. "id=1&id=2", or as
+//	comma separated values if Explode(false) is used, e.g. "id=1,2".
+//	"spaceDelimited": arrays are serialized as space separated values, e.g. "id=1%202".
+//	"pipeDelimited": arrays are serialized as pipe separated values, e.g. "id=1|2".
+//	"deepObject": hashes of strings are serialized as one parameter per key, e.g.
+//	"filter[status]=x&filter[owner]=y".
+//
+// The deepObject style only applies to parameters of type HashOf(String, String): parameters
+// cannot be objects or user types, use a hash and validate its values to accept a known set of
+// keys. Example:
+//
+//	Params(func() {
+//		Param("filter", HashOf(String, String), func() {
+//			Style("deepObject")
+//		})
+//	})
+func Style(style string) {
+	switch style {
+	case design.ParamStyleForm, design.ParamStyleSpaceDelimited, design.ParamStylePipeDelimited, design.ParamStyleDeepObject:
+	default:
+		dslengine.ReportError(`invalid style %#v, must be one of "form", "spaceDelimited", "pipeDelimited" or "deepObject"`, style)
+		return
+	}
+	if a, ok := attributeDefinition(); ok {
+		a.Style = style
+	}
+}
+
+// Explode can be used in: Param
+//
+// Explode sets whether the values of an array or hash query string parameter are serialized as
+// separate parameters. Explode defaults to true for the "form" and "deepObject" styles and to false
+// for the "spaceDelimited" and "pipeDelimited" styles. Example:
+//
+//	Param("ids", ArrayOf(Integer), func() {
+//		Explode(false) // ids=1,2,3
+//	})
+func Explode(explode bool) {
+	if a, ok := attributeDefinition(); ok {
+		a.Explode = &explode
+	}
+}
+
 // Member can be used in: Payload
 //
 // Member is an alias of Attribute.
@@ -329,6 +394,23 @@ func ReadOnly() {
 	}
 }
 
+// Nullable can be used in: Attribute
+//
+// Nullable makes it possible for request and response bodies to explicitly set the attribute
+// value to null. The generated struct field records whether the attribute was present in the
+// body and whether its value was null, this makes it possible to implement JSON Merge Patch
+// (RFC 7396) semantics. Nullable only applies to attributes of primitive types and cannot be
+// used together with Default.
+//
+//	Attribute("nickname", String, func() {
+//		Nullable()
+//	})
+func Nullable() {
+	if a, ok := attributeDefinition(); ok {
+		a.SetNullable()
+	}
+}
+
 // NoExample can be used in: Attribute, Header, Param, HashOf, ArrayOf
 //
 // NoExample sets the example of an attribute to be blank for the documentation. It is used when
@@ -382,6 +464,35 @@ func Enum(val ...interface{}) {
 	}
 }
 
+// EnumValue can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// EnumValue adds a value to the "enum" validation of the attribute together with its description.
+// The descriptions are included in the generated documentation and CLI help. goagen also generates
+// a named Go type with one constant per value and a Valid method for the attributes of user types,
+// media types and payloads whose values are described with EnumValue:
+//
+//	Attribute("color", String, func() {
+//		EnumValue("red", "Red wine")
+//		EnumValue("white", "White wine")
+//	})
+//
+// The generated type name is the concatenation of the type and attribute names, e.g. BottleColor,
+// and can be overridden with the "struct:enum:name" metadata.
+func EnumValue(val interface{}, desc string) {
+	if a, ok := attributeDefinition(); ok {
+		if val == nil || !reflect.TypeOf(val).Comparable() {
+			dslengine.ReportError("invalid enum value %#v, must be a primitive value", val)
+			return
+		}
+		if a.Type != nil && !a.Type.IsCompatible(val) {
+			dslengine.ReportError("value %#v is incompatible with attribute of type %s",
+				val, a.Type.Name())
+			return
+		}
+		a.AddValue(val, desc)
+	}
+}
+
 // SupportedValidationFormats lists the supported formats for use with the
 // Format DSL.
 var SupportedValidationFormats = []string{
@@ -477,22 +588,15 @@ func Pattern(p string) {
 // See http://json-schema.org/latest/json-schema-validation.html#anchor21.
 func Minimum(val interface{}) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
+		if a.Type != nil && !design.IsNumber(a.Type) {
 			incompatibleAttributeType("minimum", a.Type.Name(), "an integer or a number")
 		} else {
-			var f float64
-			switch v := val.(type) {
-			case float32, float64, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-				f = reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0.0))).Float()
-			case string:
-				var err error
-				f, err = strconv.ParseFloat(v, 64)
-				if err != nil {
-					dslengine.ReportError("invalid number value %#v", v)
-					return
-				}
-			default:
-				dslengine.ReportError("invalid number value %#v", v)
+			f, ok := numberValue(val)
+			if !ok {
+				return
+			}
+			if !inBounds(a.Type, f) {
+				dslengine.ReportError("minimum value %v is out of range for type %s", val, qualifiedTypeName(a.Type))
 				return
 			}
 			if a.Validation == nil {
@@ -509,22 +613,15 @@ func Minimum(val interface{}) {
 // See http://json-schema.org/latest/json-schema-validation.html#anchor17.
 func Maximum(val interface{}) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
+		if a.Type != nil && !design.IsNumber(a.Type) {
 			incompatibleAttributeType("maximum", a.Type.Name(), "an integer or a number")
 		} else {
-			var f float64
-			switch v := val.(type) {
-			case float32, float64, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-				f = reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0.0))).Float()
-			case string:
-				var err error
-				f, err = strconv.ParseFloat(v, 64)
-				if err != nil {
-					dslengine.ReportError("invalid number value %#v", v)
-					return
-				}
-			default:
-				dslengine.ReportError("invalid number value %#v", v)
+			f, ok := numberValue(val)
+			if !ok {
+				return
+			}
+			if !inBounds(a.Type, f) {
+				dslengine.ReportError("maximum value %v is out of range for type %s", val, qualifiedTypeName(a.Type))
 				return
 			}
 			if a.Validation == nil {
@@ -535,13 +632,97 @@ func Maximum(val interface{}) {
 	}
 }
 
+// ExclusiveMinimum can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// ExclusiveMinimum adds an "exclusiveMinimum" validation to the attribute: the attribute value
+// must be strictly greater than the given value.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor21.
+func ExclusiveMinimum(val interface{}) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && !design.IsNumber(a.Type) {
+			incompatibleAttributeType("exclusive minimum", a.Type.Name(), "an integer or a number")
+		} else {
+			f, ok := numberValue(val)
+			if !ok {
+				return
+			}
+			if !inBounds(a.Type, f) {
+				dslengine.ReportError("exclusive minimum value %v is out of range for type %s", val, qualifiedTypeName(a.Type))
+				return
+			}
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.ExclusiveMinimum = &f
+		}
+	}
+}
+
+// ExclusiveMaximum can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// ExclusiveMaximum adds an "exclusiveMaximum" validation to the attribute: the attribute value
+// must be strictly less than the given value.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor17.
+func ExclusiveMaximum(val interface{}) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && !design.IsNumber(a.Type) {
+			incompatibleAttributeType("exclusive maximum", a.Type.Name(), "an integer or a number")
+		} else {
+			f, ok := numberValue(val)
+			if !ok {
+				return
+			}
+			if !inBounds(a.Type, f) {
+				dslengine.ReportError("exclusive maximum value %v is out of range for type %s", val, qualifiedTypeName(a.Type))
+				return
+			}
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.ExclusiveMaximum = &f
+		}
+	}
+}
+
+// MultipleOf can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// MultipleOf adds a "multipleOf" validation to the attribute: the attribute value must be a
+// multiple of the given value. The value must be strictly positive and must be a whole number
+// when the attribute is an integer.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor14.
+func MultipleOf(val interface{}) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && !design.IsNumber(a.Type) {
+			incompatibleAttributeType("multiple of", a.Type.Name(), "an integer or a number")
+		} else {
+			f, ok := numberValue(val)
+			if !ok {
+				return
+			}
+			if f <= 0 {
+				dslengine.ReportError("multiple of value %v must be strictly positive", val)
+				return
+			}
+			if a.Type != nil && design.IsInteger(a.Type) && f != math.Trunc(f) {
+				dslengine.ReportError("multiple of value %v must be a whole number for type %s", val, qualifiedTypeName(a.Type))
+				return
+			}
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MultipleOf = &f
+		}
+	}
+}
+
 // MinLength can be used in: Attribute, Header, Param, HashOf, ArrayOf
 //
 // MinLength adds a "minItems" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor45.
+// The length of Bytes attributes is the number of decoded bytes.
 func MinLength(val int) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.ArrayKind && a.Type.Kind() != design.HashKind {
+		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.BytesKind && a.Type.Kind() != design.ArrayKind && a.Type.Kind() != design.HashKind {
 			incompatibleAttributeType("minimum length", a.Type.Name(), "a string or an array")
 		} else {
 			if a.Validation == nil {
@@ -556,9 +737,10 @@ func MinLength(val int) {
 //
 // MaxLength adds a "maxItems" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor42.
+// The length of Bytes attributes is the number of decoded bytes.
 func MaxLength(val int) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.ArrayKind {
+		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.BytesKind && a.Type.Kind() != design.ArrayKind {
 			incompatibleAttributeType("maximum length", a.Type.Name(), "a string or an array")
 		} else {
 			if a.Validation == nil {
@@ -569,11 +751,143 @@ func MaxLength(val int) {
 	}
 }
 
+// UniqueItems can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// UniqueItems adds a "uniqueItems" validation to the attribute: the elements of the array must all
+// be distinct. Elements are compared by value.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor49.
+func UniqueItems() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.ArrayKind {
+			incompatibleAttributeType("unique items", a.Type.Name(), "an array")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.UniqueItems = true
+		}
+	}
+}
+
+// MinProperties can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// MinProperties adds a "minProperties" validation to the attribute: the hash must contain at least
+// the given number of entries.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor57.
+func MinProperties(val int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.HashKind {
+			incompatibleAttributeType("minimum properties", a.Type.Name(), "a hash")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MinProperties = &val
+		}
+	}
+}
+
+// MaxProperties can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// MaxProperties adds a "maxProperties" validation to the attribute: the hash must contain at most
+// the given number of entries.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor54.
+func MaxProperties(val int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.HashKind {
+			incompatibleAttributeType("maximum properties", a.Type.Name(), "a hash")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MaxProperties = &val
+		}
+	}
+}
+
 // Required can be used in: Attributes, Headers, Payload, Type, Params
 //
 // Required adds a "required" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor61.
 func Required(names ...string) {
+	if val := objectValidation("required"); val != nil {
+		val.AddRequired(names)
+	}
+}
+
+// RequiredIf can be used in: Attributes, Payload, Type
+//
+// RequiredIf adds a conditional "required" validation to the attribute: the attributes with the
+// given names are required when the attribute named attName is set to value. attName must be
+// the name of a boolean, integer, number or string attribute.
+//
+//	Payload(func() {
+//		Attribute("payment_type", String, func() {
+//			Enum("card", "cash")
+//		})
+//		Attribute("card_number", String)
+//		RequiredIf("payment_type", "card", "card_number")
+//	})
+func RequiredIf(attName string, value interface{}, names ...string) {
+	if len(names) == 0 {
+		dslengine.ReportError("RequiredIf must list at least one required attribute")
+		return
+	}
+	if val := objectValidation("required if"); val != nil {
+		val.AddRequiredIf(&dslengine.RequiredIfDefinition{
+			Attribute: attName,
+			Value:     value,
+			Required:  names,
+		})
+	}
+}
+
+// DependentRequired can be used in: Attributes, Payload, Type
+//
+// DependentRequired adds a "dependencies" validation to the attribute: the attributes with the
+// given names are required when the attribute named attName is set.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor70.
+//
+//	Payload(func() {
+//		Attribute("credit_card", String)
+//		Attribute("billing_address", String)
+//		DependentRequired("credit_card", "billing_address")
+//	})
+func DependentRequired(attName string, names ...string) {
+	if len(names) == 0 {
+		dslengine.ReportError("DependentRequired must list at least one required attribute")
+		return
+	}
+	if val := objectValidation("dependent required"); val != nil {
+		val.AddDependentRequired(attName, names)
+	}
+}
+
+// MutuallyExclusive can be used in: Attributes, Payload, Type
+//
+// MutuallyExclusive adds a validation to the attribute that makes sure that at most one of the
+// attributes with the given names is set. The attributes cannot be required or have a default
+// value.
+//
+//	Payload(func() {
+//		Attribute("email", String)
+//		Attribute("phone", String)
+//		MutuallyExclusive("email", "phone")
+//	})
+func MutuallyExclusive(names ...string) {
+	if len(names) < 2 {
+		dslengine.ReportError("MutuallyExclusive must list at least two attributes")
+		return
+	}
+	if val := objectValidation("mutually exclusive"); val != nil {
+		val.AddMutuallyExclusive(names)
+	}
+}
+
+// objectValidation returns the validation of the object attribute or media type being defined,
+// it creates the validation if needed. It reports an error and returns nil if the current
+// definition is not an object.
+func objectValidation(validation string) *dslengine.ValidationDefinition {
 	var at *design.AttributeDefinition
 
 	switch def := dslengine.CurrentDefinition().(type) {
@@ -583,17 +897,17 @@ func Required(names ...string) {
 		at = def.AttributeDefinition
 	default:
 		dslengine.IncompatibleDSL()
-		return
+		return nil
 	}
 
 	if at.Type != nil && at.Type.Kind() != design.ObjectKind {
-		incompatibleAttributeType("required", at.Type.Name(), "an object")
-	} else {
-		if at.Validation == nil {
-			at.Validation = &dslengine.ValidationDefinition{}
-		}
-		at.Validation.AddRequired(names)
+		incompatibleAttributeType(validation, at.Type.Name(), "an object")
+		return nil
+	}
+	if at.Validation == nil {
+		at.Validation = &dslengine.ValidationDefinition{}
 	}
+	return at.Validation
 }
 
 // incompatibleAttributeType reports an error for validations defined on
@@ -603,6 +917,36 @@ func incompatibleAttributeType(validation, actual, expected string) {
 		validation, expected, actual)
 }
 
+// numberValue converts the value given to a numeric validation DSL to a float64. It reports an
+// error and returns false if the value is not a number or a string representing a number.
+func numberValue(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float32, float64, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		return reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0.0))).Float(), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			dslengine.ReportError("invalid number value %#v", v)
+			return 0, false
+		}
+		return f, true
+	default:
+		dslengine.ReportError("invalid number value %#v", v)
+		return 0, false
+	}
+}
+
+// inBounds returns true if the value can be represented by the given type. Only sized number types
+// have bounds.
+func inBounds(t design.DataType, f float64) bool {
+	p, ok := t.(design.Primitive)
+	if !ok {
+		return true
+	}
+	min, max, ok := p.Bounds()
+	return !ok || (f >= min && f <= max)
+}
+
 // qualifiedTypeName returns the qualified type name for the given data type.
 // This is useful in reporting types in error messages.
 // (e.g) array<string>, hash<string, string>, hash<string, array<int>>
@@ -610,6 +954,22 @@ func qualifiedTypeName(t design.DataType) string {
 	switch t.Kind() {
 	case design.DateTimeKind:
 		return "datetime"
+	case design.DateKind:
+		return "date"
+	case design.Int32Kind:
+		return "int32"
+	case design.Int64Kind:
+		return "int64"
+	case design.UInt32Kind:
+		return "uint32"
+	case design.UInt64Kind:
+		return "uint64"
+	case design.Float32Kind:
+		return "float32"
+	case design.Float64Kind:
+		return "float64"
+	case design.BytesKind:
+		return "bytes"
 	case design.ArrayKind:
 		return fmt.Sprintf("%s<%s>", t.Name(), qualifiedTypeName(t.ToArray().ElemType.Type))
 	case design.HashKind:
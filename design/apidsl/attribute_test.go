@@ -326,6 +326,243 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 
+	Context("with a name, type int32 and a DSL defining a minimum", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = Int32
+			dsl = func() {
+				Minimum(-10)
+				Maximum(10)
+			}
+		})
+
+		It("produces an attribute of type int32 with a range validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Type).Should(Equal(Int32))
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(*o[name].Validation.Minimum).Should(Equal(float64(-10)))
+			Ω(*o[name].Validation.Maximum).Should(Equal(float64(10)))
+		})
+
+		Context("that is out of the type range", func() {
+			BeforeEach(func() {
+				dataType = UInt32
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("out of range"))
+			})
+		})
+	})
+
+	Context("with a name, type date and a DSL defining a default value", func() {
+		BeforeEach(func() {
+			name = "day"
+			dataType = Date
+			dsl = func() {
+				Default("2017-01-02")
+			}
+		})
+
+		It("produces an attribute of type date with a default value", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Type).Should(Equal(Date))
+			Ω(o[name].DefaultValue).Should(Equal("2017-01-02"))
+		})
+	})
+
+	Context("with a name, type bytes and a DSL defining length validations", func() {
+		BeforeEach(func() {
+			name = "data"
+			dataType = Bytes
+			dsl = func() {
+				MinLength(1)
+				MaxLength(64)
+			}
+		})
+
+		It("produces an attribute of type bytes with the validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Type).Should(Equal(Bytes))
+			Ω(*o[name].Validation.MinLength).Should(Equal(1))
+			Ω(*o[name].Validation.MaxLength).Should(Equal(64))
+		})
+	})
+
+	Context("with a name, type number and a DSL defining exclusive bounds and a multiple of", func() {
+		BeforeEach(func() {
+			name = "price"
+			dataType = Number
+			dsl = func() {
+				ExclusiveMinimum(0)
+				ExclusiveMaximum(1000)
+				MultipleOf(0.01)
+			}
+		})
+
+		It("produces an attribute with the validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(*o[name].Validation.ExclusiveMinimum).Should(Equal(float64(0)))
+			Ω(*o[name].Validation.ExclusiveMaximum).Should(Equal(float64(1000)))
+			Ω(*o[name].Validation.MultipleOf).Should(Equal(0.01))
+		})
+
+		Context("with an integer type and a fractional multiple of", func() {
+			BeforeEach(func() {
+				dataType = Integer
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("must be a whole number"))
+			})
+		})
+
+		Context("with a string type", func() {
+			BeforeEach(func() {
+				dataType = String
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid exclusive minimum validation definition"))
+			})
+		})
+	})
+
+	Context("with a name, type array and a DSL defining a unique items validation", func() {
+		BeforeEach(func() {
+			name = "tags"
+			dataType = ArrayOf(String)
+			dsl = func() {
+				UniqueItems()
+			}
+		})
+
+		It("produces an attribute with the validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Validation.UniqueItems).Should(BeTrue())
+		})
+	})
+
+	Context("with a name, type hash and a DSL defining properties validations", func() {
+		BeforeEach(func() {
+			name = "labels"
+			dataType = HashOf(String, String)
+			dsl = func() {
+				MinProperties(1)
+				MaxProperties(10)
+			}
+		})
+
+		It("produces an attribute with the validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(*o[name].Validation.MinProperties).Should(Equal(1))
+			Ω(*o[name].Validation.MaxProperties).Should(Equal(10))
+		})
+
+		Context("with an array type", func() {
+			BeforeEach(func() {
+				dataType = ArrayOf(String)
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("attribute must be a hash"))
+			})
+		})
+	})
+
+	Context("with a name and a DSL defining a nullable attribute", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() {
+				Nullable()
+			}
+		})
+
+		It("produces a nullable attribute", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].IsNullable()).Should(BeTrue())
+		})
+
+		Context("with a default value", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Nullable()
+					Default("bar")
+				}
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("nullable attributes cannot have a default value"))
+			})
+		})
+
+		Context("of a non primitive type", func() {
+			BeforeEach(func() {
+				dataType = ArrayOf(String)
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("can be nullable"))
+			})
+		})
+	})
+
+	Context("with a name and a DSL defining described enum values", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() {
+				EnumValue("red", "Red wine")
+				EnumValue("white", "White wine")
+			}
+		})
+
+		It("records the values and their descriptions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Validation.Values).Should(Equal([]interface{}{"red", "white"}))
+			Ω(o[name].Validation.ValueDescriptions).Should(Equal(map[interface{}]string{
+				"red":   "Red wine",
+				"white": "White wine",
+			}))
+			Ω(o[name].HasEnumDescriptions()).Should(BeTrue())
+		})
+
+		Context("with an incompatible value", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					EnumValue(1, "One")
+				}
+			})
+
+			It("records an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Context("with a name and a type defined by name", func() {
 		var Foo *UserTypeDefinition
 
@@ -0,0 +1,74 @@
+package apidsl
+
+import (
+	"strconv"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// CookieMaxAge can be used in: Cookie
+//
+// CookieMaxAge sets the number of seconds the cookie written by a response is valid for. It is
+// applied to the Max-Age attribute of the cookie set by the generated response methods:
+//
+//	Response(OK, func() {
+//		Cookies(func() {
+//			Cookie("session", String, func() {
+//				CookieMaxAge(3600)
+//			})
+//		})
+//	})
+func CookieMaxAge(seconds int) {
+	setCookieMetadata("cookie:max-age", strconv.Itoa(seconds))
+}
+
+// CookieSecure can be used in: Cookie
+//
+// CookieSecure sets the Secure attribute of the cookie written by a response.
+func CookieSecure() {
+	setCookieMetadata("cookie:secure", "true")
+}
+
+// CookieHTTPOnly can be used in: Cookie
+//
+// CookieHTTPOnly sets the HttpOnly attribute of the cookie written by a response.
+func CookieHTTPOnly() {
+	setCookieMetadata("cookie:http-only", "true")
+}
+
+// CookieSameSite can be used in: Cookie
+//
+// CookieSameSite sets the SameSite attribute of the cookie written by a response. The value must
+// be one of "Strict", "Lax" or "None".
+func CookieSameSite(mode string) {
+	switch mode {
+	case "Strict", "Lax", "None":
+		setCookieMetadata("cookie:same-site", mode)
+	default:
+		dslengine.ReportError(`invalid SameSite mode %#v, must be one of "Strict", "Lax" or "None"`, mode)
+	}
+}
+
+// CookiePath can be used in: Cookie
+//
+// CookiePath sets the Path attribute of the cookie written by a response.
+func CookiePath(path string) {
+	setCookieMetadata("cookie:path", path)
+}
+
+// CookieDomain can be used in: Cookie
+//
+// CookieDomain sets the Domain attribute of the cookie written by a response.
+func CookieDomain(domain string) {
+	setCookieMetadata("cookie:domain", domain)
+}
+
+// setCookieMetadata records the cookie attribute in the metadata of the current attribute.
+func setCookieMetadata(key, value string) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(map[string][]string)
+		}
+		a.Metadata[key] = []string{value}
+	}
+}
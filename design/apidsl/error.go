@@ -0,0 +1,105 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Error can be used in: API, Resource, Action
+//
+// Error describes an error that may be returned by the actions of the API, of the resource or by
+// the action. The first argument is the error name which is also the value of the "code" member
+// of the error responses. The optional second argument is the type of the error "meta" member: a
+// user type, a media type or the name of a user type defined in the design. The last optional
+// argument is a DSL that sets the error status (400 Bad Request by default) and description.
+// Error names must be unique across the API:
+//
+//	Error("order_not_found", OrderInfo, func() {
+//		Status(404)
+//		Description("The order does not exist")
+//	})
+//
+// goagen adds a response rendering the error media type for the error status to the actions
+// unless they already define one. It generates an error class and a constructor for each error
+// in the app package, error types that can be checked with errors.As in the client package and
+// documents the error codes of each response in the swagger specification.
+func Error(name string, args ...interface{}) {
+	var errs map[string]*design.ErrorDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if def.Errors == nil {
+			def.Errors = make(map[string]*design.ErrorDefinition)
+		}
+		errs = def.Errors
+	case *design.ResourceDefinition:
+		if def.Errors == nil {
+			def.Errors = make(map[string]*design.ErrorDefinition)
+		}
+		errs = def.Errors
+	case *design.ActionDefinition:
+		if def.Errors == nil {
+			def.Errors = make(map[string]*design.ErrorDefinition)
+		}
+		errs = def.Errors
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if _, ok := errs[name]; ok {
+		dslengine.ReportError("error %s is defined twice", name)
+		return
+	}
+	e := &design.ErrorDefinition{Name: name, Status: 400, Parent: dslengine.CurrentDefinition()}
+	var dsl func()
+	if len(args) > 0 {
+		if d, ok := args[len(args)-1].(func()); ok {
+			dsl = d
+			args = args[:len(args)-1]
+		}
+	}
+	if len(args) > 1 {
+		dslengine.ReportError("too many arguments given to Error")
+		return
+	}
+	if len(args) == 1 {
+		switch actual := args[0].(type) {
+		case *design.UserTypeDefinition:
+			e.Meta = actual
+		case *design.MediaTypeDefinition:
+			e.Meta = actual.UserTypeDefinition
+		case string:
+			ut, ok := design.Design.Types[actual]
+			if !ok {
+				dslengine.ReportError("unknown error meta type %s", actual)
+				return
+			}
+			e.Meta = ut
+		default:
+			dslengine.ReportError("invalid Error argument, must be a user type, a media type or the name of a user type")
+			return
+		}
+	}
+	if dsl != nil && !dslengine.Execute(dsl, e) {
+		return
+	}
+	errs[name] = e
+}
+
+// ProblemDetails can be used in: API
+//
+// ProblemDetails causes the API to render error responses as RFC 7807 problem details using the
+// "application/problem+json" media type instead of the default goa error media type. The error
+// code is rendered in the "type" member, the error ID in the "instance" member and the error
+// metadata as extension members:
+//
+//	var _ = API("cellar", func() {
+//		ProblemDetails()
+//	})
+//
+// The generated service renders errors as problem details, the generated client decodes them and
+// the swagger specification describes the problem details media type.
+func ProblemDetails() {
+	if a, ok := apiDefinition(); ok {
+		a.ProblemDetails = true
+	}
+}
@@ -0,0 +1,184 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	var apiDSL func()
+	var resourceDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+		resourceDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", apiDSL)
+		if resourceDSL != nil {
+			Resource("orders", resourceDSL)
+		}
+		dslengine.Run()
+	})
+
+	Context("defined at all levels", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Error("rate_limited", func() {
+					Status(429)
+					Description("Too many requests")
+				})
+			}
+			resourceDSL = func() {
+				Error("order_not_found", func() {
+					Status(404)
+				})
+				Action("cancel", func() {
+					Routing(POST("/:id/cancel"))
+					Error("already_cancelled", func() {
+						Status(409)
+					})
+					Response(NoContent)
+				})
+			}
+		})
+
+		It("produces valid error definitions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Validate()).ShouldNot(HaveOccurred())
+			Ω(Design.Errors).Should(HaveKey("rate_limited"))
+			Ω(Design.Errors["rate_limited"].Status).Should(Equal(429))
+			Ω(Design.Errors["rate_limited"].Description).Should(Equal("Too many requests"))
+			Ω(Design.Resources["orders"].Errors).Should(HaveKey("order_not_found"))
+		})
+
+		It("adds the error responses to the actions", func() {
+			a := Design.Resources["orders"].Actions["cancel"]
+			var names []string
+			for _, e := range a.AllErrors() {
+				names = append(names, e.Name)
+			}
+			Ω(names).Should(Equal([]string{"already_cancelled", "order_not_found", "rate_limited"}))
+			Ω(a.Responses).Should(HaveKey("Conflict"))
+			Ω(a.Responses["Conflict"].MediaType).Should(Equal(ErrorMediaIdentifier))
+			Ω(a.Responses).Should(HaveKey("NotFound"))
+			Ω(a.Responses).Should(HaveKey("TooManyRequests"))
+			Ω(a.Responses["TooManyRequests"].Status).Should(Equal(429))
+			Ω(Design.MediaTypes).Should(HaveKey(CanonicalIdentifier(ErrorMediaIdentifier)))
+		})
+	})
+
+	Context("with an action that already describes the error status", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				Error("order_not_found", func() {
+					Status(404)
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(NotFound)
+				})
+			}
+		})
+
+		It("keeps the action response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			resp := Design.Resources["orders"].Actions["show"].Responses["NotFound"]
+			Ω(resp).ShouldNot(BeNil())
+			Ω(resp.MediaType).Should(BeEmpty())
+		})
+	})
+
+	Context("with a meta type", func() {
+		BeforeEach(func() {
+			Type("OrderInfo", func() {
+				Attribute("id", Integer)
+			})
+			apiDSL = func() {
+				Error("order_not_found", "OrderInfo")
+			}
+		})
+
+		It("sets the meta type and defaults the status to 400", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Errors["order_not_found"].Meta).Should(Equal(Design.Types["OrderInfo"]))
+			Ω(Design.Errors["order_not_found"].Status).Should(Equal(400))
+		})
+	})
+
+	Context("with an unknown meta type", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Error("order_not_found", "Unknown")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a status that is not an error status", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Error("order_not_found", func() {
+					Status(200)
+				})
+			}
+		})
+
+		It("fails validation", func() {
+			Ω(Design.Validate()).Should(HaveOccurred())
+		})
+	})
+
+	Context("defined at different levels with the same name", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Error("order_not_found")
+			}
+			resourceDSL = func() {
+				Error("order_not_found")
+			}
+		})
+
+		It("fails validation", func() {
+			Ω(Design.Validate()).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ProblemDetails", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", func() {
+			ProblemDetails()
+		})
+		Resource("orders", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(NotFound, ErrorMedia)
+				Error("out_of_stock", func() {
+					Status(409)
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("makes the error responses use the problem details media type", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.ProblemDetails).Should(BeTrue())
+		action := Design.Resources["orders"].Actions["show"]
+		Ω(action.Responses["NotFound"].MediaType).Should(Equal(ProblemMediaIdentifier))
+		Ω(action.Responses["NotFound"].Type).Should(Equal(ProblemMedia))
+		Ω(action.Responses["Conflict"].MediaType).Should(Equal(ProblemMediaIdentifier))
+		Ω(Design.MediaTypeWithIdentifier(ProblemMediaIdentifier)).Should(Equal(ProblemMedia))
+		Ω(Design.MediaTypeWithIdentifier(ErrorMediaIdentifier)).Should(BeNil())
+	})
+})
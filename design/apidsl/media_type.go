@@ -274,6 +274,19 @@ func View(name string, apidsl ...func()) {
 					}
 				}
 			}
+		} else if len(mt.Bases) > 0 {
+			// inherit view from extended media type if present
+			v := baseView(mt, name)
+			if v == nil {
+				dslengine.ReportError("unknown view %#v", name)
+				return
+			}
+			o := make(design.Object)
+			for n, vat := range v.Type.ToObject() {
+				o[n] = &design.AttributeDefinition{View: vat.View}
+			}
+			at = &design.AttributeDefinition{Type: o}
+			ok = true
 		}
 		if ok {
 			view, err := buildView(name, mt, at)
@@ -292,6 +305,19 @@ func View(name string, apidsl ...func()) {
 	}
 }
 
+// baseView returns the view with the given name of the first media type extended by mt that
+// defines it, nil if there isn't one.
+func baseView(mt *design.MediaTypeDefinition, name string) *design.ViewDefinition {
+	for _, b := range mt.Bases {
+		if bmt, ok := b.(*design.MediaTypeDefinition); ok {
+			if v, ok := bmt.Views[name]; ok {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
 // buildView builds a view definition given an attribute and a corresponding media type.
 func buildView(name string, mt *design.MediaTypeDefinition, at *design.AttributeDefinition) (*design.ViewDefinition, error) {
 	if at.Type == nil || !at.Type.IsObject() {
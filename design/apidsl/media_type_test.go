@@ -217,6 +217,63 @@ var _ = Describe("MediaType", func() {
 			Ω(o[viewAtt].Type).Should(Equal(String))
 		})
 	})
+
+	Context("extending another media type", func() {
+		var base *MediaTypeDefinition
+
+		BeforeEach(func() {
+			base = MediaType("application/base", func() {
+				Attributes(func() {
+					Attribute("name", String, func() {
+						MinLength(3)
+					})
+					Attribute("vintage", Integer)
+					Required("name")
+				})
+				View("default", func() {
+					Attribute("name")
+					Attribute("vintage")
+				})
+				View("tiny", func() {
+					Attribute("name")
+				})
+			})
+			name = "application/foo"
+			dslFunc = func() {
+				Extend(base)
+				Attributes(func() {
+					Attribute("href")
+					Attribute("name", func() {
+						MaxLength(10)
+					})
+				})
+				View("default", func() {
+					Attribute("href")
+					Attribute("name")
+				})
+				View("tiny")
+			}
+		})
+
+		It("inherits the attributes, validations and views", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(mt.Validate()).ShouldNot(HaveOccurred())
+			o := mt.Type.ToObject()
+			Ω(o).Should(HaveLen(3))
+			Ω(o).Should(HaveKey("href"))
+			Ω(o).Should(HaveKey("vintage"))
+			Ω(o["name"].Type).Should(Equal(String))
+			Ω(*o["name"].Validation.MinLength).Should(Equal(3))
+			Ω(*o["name"].Validation.MaxLength).Should(Equal(10))
+			Ω(mt.Validation.Required).Should(Equal([]string{"name"}))
+			Ω(mt.Views).Should(HaveKey("tiny"))
+			tiny := mt.Views["tiny"]
+			Ω(tiny.Parent).Should(Equal(mt))
+			Ω(tiny.Type.ToObject()).Should(HaveLen(1))
+			Ω(tiny.Type.ToObject()).Should(HaveKey("name"))
+			Ω(*tiny.Type.ToObject()["name"].Validation.MaxLength).Should(Equal(10))
+		})
+	})
 })
 
 var _ = Describe("Duplicate media types", func() {
@@ -27,6 +27,12 @@ import (
 //        Metadata("struct:field:type", "json.RawMessage", "encoding/json")
 //        Metadata("struct:field:type", "mypackage.MyType", "github.com/me/mypackage")
 //
+// `struct:enum:name`: overrides the name of the Go type generated for attributes whose enum values
+// are described with EnumValue.
+// Applicable to attributes only.
+//
+//        Metadata("struct:enum:name", "WineColor")
+//
 // `struct:tag:xxx`: sets the struct field tag xxx on generated Go structs.  Overrides tags that
 // goagen would otherwise set.  If the metadata value is a slice then the strings are joined with
 // the space character as separator.
@@ -67,6 +73,8 @@ import (
 //
 //        Metadata("swagger:extension:x-api", `{"foo":"bar"}`)
 //
+// The `swagger` keys above also apply to the OpenAPI 3.0 specification produced by "goagen openapi".
+//
 // The special key names listed above may be used as follows:
 //
 //        var Account = Type("Account", func() {
@@ -5,7 +5,7 @@ import (
 	"github.com/goadesign/goa/dslengine"
 )
 
-// Response can be used in: Action, Resource
+// Response can be used in: Action, Resource, Webhook
 //
 // Response implements the response definition DSL. Response takes the name of the response as
 // first parameter. goa defines all the standard HTTP status name as global variables so they can be
@@ -91,6 +91,19 @@ func Response(name string, paramsAndDSL ...interface{}) {
 			def.Responses[name] = resp
 		}
 
+	case *design.WebhookDefinition:
+		if def.Responses == nil {
+			def.Responses = make(map[string]*design.ResponseDefinition)
+		}
+		if _, ok := def.Responses[name]; ok {
+			dslengine.ReportError("response %s is defined twice", name)
+			return
+		}
+		if resp := executeResponseDSL(name, paramsAndDSL...); resp != nil {
+			resp.Parent = def
+			def.Responses[name] = resp
+		}
+
 	case *design.ResourceDefinition:
 		if def.Responses == nil {
 			def.Responses = make(map[string]*design.ResponseDefinition)
@@ -113,15 +126,57 @@ func Response(name string, paramsAndDSL ...interface{}) {
 	}
 }
 
-// Status can be used in: Response, ResponseTemplate
+// Status can be used in: Response, ResponseTemplate, Error
 //
-// Status sets the Response status.
+// Status sets the Response or Error status.
 func Status(status int) {
+	if e, ok := dslengine.CurrentDefinition().(*design.ErrorDefinition); ok {
+		e.Status = status
+		return
+	}
 	if r, ok := responseDefinition(); ok {
 		r.Status = status
 	}
 }
 
+// Stream can be used in: Response
+//
+// Stream makes the response a stream of events sent using Server-Sent Events. The argument is the
+// type of the events data: a data type, a media type or the identifier of a media type or name of a
+// user type defined in the design. The response media type is set to "text/event-stream".
+//
+//	Response(OK, func() {
+//		Stream(EventMedia)
+//	})
+//
+// goagen generates a context method that writes the events received on a channel to the response
+// and a client iterator that decodes the events as they arrive.
+func Stream(event interface{}) {
+	r, ok := responseDefinition()
+	if !ok {
+		return
+	}
+	var dt design.DataType
+	switch actual := event.(type) {
+	case design.DataType:
+		dt = actual
+	case string:
+		if mt := design.Design.MediaTypeWithIdentifier(actual); mt != nil {
+			dt = mt
+		} else if ut, ok := design.Design.Types[actual]; ok {
+			dt = ut
+		} else {
+			dslengine.ReportError("unknown stream event type %s", actual)
+			return
+		}
+	default:
+		dslengine.ReportError("invalid Stream argument, must be a type, a media type or the name of a type")
+		return
+	}
+	r.Stream = dt
+	r.MediaType = design.EventStreamMediaType
+}
+
 func executeResponseDSL(name string, paramsAndDSL ...interface{}) *design.ResponseDefinition {
 	var params []string
 	var dsl func()
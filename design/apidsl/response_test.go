@@ -177,6 +177,121 @@ var _ = Describe("Response", func() {
 		})
 	})
 
+	Context("with a status and cookies", func() {
+		const status = 200
+		const cookieName = "session"
+
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Status(status)
+				Cookies(func() {
+					Cookie(cookieName, String, func() {
+						CookieMaxAge(3600)
+						CookieSecure()
+						CookieHTTPOnly()
+						CookieSameSite("Lax")
+					})
+					Required(cookieName)
+				})
+			}
+		})
+
+		It("sets the cookies and their attributes", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Cookies).ShouldNot(BeNil())
+			Ω(res.Cookies.IsRequired(cookieName)).Should(BeTrue())
+			o := res.Cookies.Type.ToObject()
+			Ω(o).Should(HaveKey(cookieName))
+			md := o[cookieName].Metadata
+			Ω(md["cookie:max-age"]).Should(Equal([]string{"3600"}))
+			Ω(md["cookie:secure"]).Should(Equal([]string{"true"}))
+			Ω(md["cookie:http-only"]).Should(Equal([]string{"true"}))
+			Ω(md["cookie:same-site"]).Should(Equal([]string{"Lax"}))
+		})
+
+		Context("with an invalid SameSite mode", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Status(status)
+					Cookies(func() {
+						Cookie(cookieName, String, func() {
+							CookieSameSite("strict")
+						})
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid SameSite mode"))
+			})
+		})
+
+		Context("with a cookie and a header of the same name", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Status(status)
+					Headers(func() {
+						Header("Session")
+					})
+					Cookies(func() {
+						Cookie(cookieName, String)
+					})
+				}
+			})
+
+			It("produces an invalid response definition", func() {
+				Ω(res.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a status and a non primitive header", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Status(201)
+				Headers(func() {
+					Header("Link", ArrayOf(String))
+				})
+			}
+		})
+
+		It("produces an invalid response definition", func() {
+			Ω(res.Validate()).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a status and an event stream", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Status(200)
+				Stream(String)
+			}
+		})
+
+		It("sets the stream type and media type", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.IsStream()).Should(BeTrue())
+			Ω(res.Stream).Should(Equal(String))
+			Ω(res.MediaType).Should(Equal(EventStreamMediaType))
+		})
+
+		Context("and a type override", func() {
+			BeforeEach(func() {
+				dt = Integer
+			})
+
+			It("produces an invalid response definition", func() {
+				Ω(res.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Context("not from the goa default definitions", func() {
 		BeforeEach(func() {
 			name = "foo"
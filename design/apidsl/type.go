@@ -1,6 +1,8 @@
 package apidsl
 
 import (
+	"strings"
+
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
@@ -61,6 +63,108 @@ func Type(name string, dsl func()) *design.UserTypeDefinition {
 	return t
 }
 
+// Extend can be used in: Type, MediaType, Attribute, Payload
+//
+// Extend copies the attributes, validations and required attributes of the given user type or media
+// type into the type being defined. Attributes defined locally override the base type attributes
+// with the same name. As with Reference the overriding attributes default to the base attribute
+// type, description and validations so that only the differences need to be specified:
+//
+//	var BottlePayload = Type("BottlePayload", func() {
+//		Attribute("name", String, func() {
+//			MinLength(3)
+//		})
+//		Attribute("vintage", Integer, func() {
+//			Minimum(1970)
+//		})
+//		Required("name", "vintage")
+//	})
+//
+//	var UpdateBottlePayload = Type("UpdateBottlePayload", func() {
+//		Extend(BottlePayload)
+//		Attribute("id", Integer)	// Additional attribute
+//		Attribute("name", func() {	// Inherits type and MinLength validation
+//			MaxLength(30)
+//		})
+//		Required("id")			// Required attributes are "id", "name" and "vintage"
+//	})
+//
+// Media types extending other media types may also inherit their views by declaring them without
+// a DSL:
+//
+//	var BottleMedia = MediaType("application/vnd.goa.bottle", func() {
+//		Extend(BottleBaseMedia)
+//		Attributes(func() {
+//			Attribute("href")
+//		})
+//		View("default", func() {
+//			Attribute("href")
+//			Attribute("name")
+//		})
+//		View("tiny")	// Uses the attributes of the BottleBaseMedia "tiny" view
+//	})
+//
+// The generated code and JSON schemas describe the combined shape, i.e. all the attributes of the
+// extended types are flattened into the extending type.
+func Extend(t design.DataType) {
+	var parent *design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		parent = def
+	case *design.MediaTypeDefinition:
+		parent = def.AttributeDefinition
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	base := extendedAttribute(t)
+	if base == nil || base.Type == nil || !base.Type.IsObject() {
+		dslengine.ReportError("invalid type to extend, must be a user type or media type with attributes")
+		return
+	}
+	if parent.Type == nil {
+		parent.Type = make(design.Object)
+	}
+	o, ok := parent.Type.(design.Object)
+	if !ok {
+		dslengine.ReportError("can't extend attribute of type %s", parent.Type.Name())
+		return
+	}
+	for n, att := range base.Type.ToObject() {
+		if _, ok := o[n]; !ok {
+			o[n] = design.DupAtt(att)
+		}
+	}
+	if base.Validation != nil {
+		if parent.Validation == nil {
+			parent.Validation = &dslengine.ValidationDefinition{}
+		}
+		parent.Validation.Merge(base.Validation)
+	}
+	for _, b := range parent.Bases {
+		if b == t {
+			return
+		}
+	}
+	parent.Bases = append(parent.Bases, t)
+}
+
+// extendedAttribute returns the attribute of the type being extended. It runs the type DSL if it
+// hasn't run yet, i.e. if the type has no attribute.
+func extendedAttribute(t design.DataType) *design.AttributeDefinition {
+	var att *design.AttributeDefinition
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		att = actual.AttributeDefinition
+	case *design.MediaTypeDefinition:
+		att = actual.AttributeDefinition
+	}
+	if att != nil && att.Type != nil && len(att.Type.ToObject()) > 0 {
+		return att
+	}
+	return refAttribute(t)
+}
+
 // ArrayOf creates an array type from its element type. The result can be used
 // anywhere a type can. Examples:
 //
@@ -169,6 +273,124 @@ func HashOf(k, v interface{}, dsls ...func()) *design.Hash {
 	return &design.Hash{KeyType: &kat, ElemType: &vat}
 }
 
+// OneOf defines a union type: the value may be any one of the given user types. The
+// discriminator is the name of the string attribute whose value identifies the actual type. Each
+// type must define the discriminator attribute. The discriminator value of a type is the single
+// value listed in the attribute Enum validation if any, the type name otherwise.
+//
+// OneOf may appear in Type, MediaType, Attribute or Payload. When used in Attribute or Payload
+// the union is defined as a user type whose name is built from the names of the union types.
+// Examples:
+//
+//	var Cat = Type("Cat", func() {
+//		Attribute("kind", String, func() {
+//			Enum("cat")
+//		})
+//		Attribute("lives", Integer)
+//		Required("kind")
+//	})
+//
+//	var Dog = Type("Dog", func() {
+//		Attribute("kind", String, func() {
+//			Enum("dog")
+//		})
+//		Attribute("breed", String)
+//		Required("kind")
+//	})
+//
+//	var Pet = Type("Pet", func() {
+//		OneOf("kind", Cat, Dog)
+//	})
+//
+//	Action("adopt", func() {
+//		Payload(func() {
+//			OneOf("kind", Cat, Dog) // Defines the "CatOrDog" type
+//		})
+//	})
+func OneOf(discriminator string, types ...interface{}) {
+	u := &design.Union{Discriminator: discriminator}
+	for _, v := range types {
+		t := resolveType(v)
+		ut, ok := t.(*design.UserTypeDefinition)
+		if !ok {
+			if mt, ok := t.(*design.MediaTypeDefinition); ok {
+				ut = mt.UserTypeDefinition
+			} else {
+				dslengine.InvalidArgError("user type or name of user type", v)
+				return
+			}
+		}
+		u.Types = append(u.Types, ut)
+	}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.MediaTypeDefinition:
+		if !canBeUnion(def.Type) {
+			dslengine.ReportError("OneOf cannot be used together with Attribute")
+			return
+		}
+		def.Type = u
+		if _, ok := def.Views["default"]; !ok {
+			if def.Views == nil {
+				def.Views = make(map[string]*design.ViewDefinition)
+			}
+			def.Views["default"] = &design.ViewDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: u},
+				Name:                "default",
+				Parent:              def,
+			}
+		}
+	case *design.AttributeDefinition:
+		if !canBeUnion(def.Type) {
+			dslengine.ReportError("OneOf cannot be used together with Attribute")
+			return
+		}
+		for _, ut := range design.Design.Types {
+			if ut.AttributeDefinition == def {
+				def.Type = u
+				return
+			}
+		}
+		def.Type = unionType(u)
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// canBeUnion returns true if the given attribute type may be overridden with a union.
+func canBeUnion(t design.DataType) bool {
+	if t == nil {
+		return true
+	}
+	o, ok := t.(design.Object)
+	return ok && len(o) == 0
+}
+
+// unionType returns the user type that defines the given union. The type name is built by
+// joining the names of the union types. unionType reuses existing types with the same name.
+func unionType(u *design.Union) *design.UserTypeDefinition {
+	names := make([]string, len(u.Types))
+	for i, t := range u.Types {
+		names[i] = t.TypeName
+	}
+	name := strings.Join(names, "Or")
+	if ut, ok := design.Design.Types[name]; ok {
+		if eu, ok := ut.Type.(*design.Union); ok && eu.Discriminator == u.Discriminator {
+			return ut
+		}
+		dslengine.ReportError("union type name %#v is already used by another type", name)
+		return ut
+	}
+	if design.Design.Types == nil {
+		design.Design.Types = make(map[string]*design.UserTypeDefinition)
+	}
+	ut := &design.UserTypeDefinition{
+		TypeName:            name,
+		AttributeDefinition: &design.AttributeDefinition{Type: u},
+	}
+	design.Design.Types[name] = ut
+	return ut
+}
+
 func resolveType(v interface{}) design.DataType {
 	if t, ok := v.(design.DataType); ok {
 		return t
@@ -106,6 +106,154 @@ var _ = Describe("Type", func() {
 			Ω(o[attName].Type).Should(Equal(DateTime))
 		})
 	})
+
+	Context("extending another type", func() {
+		BeforeEach(func() {
+			Type("base", func() {
+				Attribute("name", String, func() {
+					MinLength(3)
+				})
+				Attribute("vintage", Integer)
+				Required("name", "vintage")
+			})
+			name = "foo"
+			dsl = func() {
+				Extend(Design.Types["base"])
+				Attribute("id", Integer)
+				Attribute("vintage", func() {
+					Minimum(1970)
+				})
+				Required("id")
+			}
+		})
+
+		It("inherits the attributes and validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(ut).ShouldNot(BeNil())
+			Ω(ut.Validate("test", Design)).ShouldNot(HaveOccurred())
+			o := ut.Type.ToObject()
+			Ω(o).Should(HaveLen(3))
+			Ω(o["name"].Type).Should(Equal(String))
+			Ω(*o["name"].Validation.MinLength).Should(Equal(3))
+			Ω(o["vintage"].Type).Should(Equal(Integer))
+			Ω(*o["vintage"].Validation.Minimum).Should(Equal(1970.0))
+			Ω(ut.Validation.Required).Should(ConsistOf("name", "vintage", "id"))
+			Ω(ut.Bases).Should(Equal([]DataType{Design.Types["base"]}))
+		})
+
+		Context("with a non object type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Extend(String)
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid type to extend"))
+			})
+		})
+	})
+
+	Context("with conditional required validations", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Attribute("payment_type", String)
+				Attribute("card_number", String)
+				Attribute("email", String)
+				Attribute("phone", String)
+				RequiredIf("payment_type", "card", "card_number")
+				DependentRequired("email", "phone")
+				MutuallyExclusive("card_number", "email")
+			}
+		})
+
+		It("sets the validations", func() {
+			Ω(ut).ShouldNot(BeNil())
+			Ω(ut.Validate("test", Design)).ShouldNot(HaveOccurred())
+			val := ut.Validation
+			Ω(val).ShouldNot(BeNil())
+			Ω(val.RequiredIf).Should(HaveLen(1))
+			Ω(val.RequiredIf[0].Attribute).Should(Equal("payment_type"))
+			Ω(val.RequiredIf[0].Value).Should(Equal("card"))
+			Ω(val.RequiredIf[0].Required).Should(Equal([]string{"card_number"}))
+			Ω(val.DependentRequired).Should(Equal(map[string][]string{"email": {"phone"}}))
+			Ω(val.MutuallyExclusive).Should(Equal([][]string{{"card_number", "email"}}))
+		})
+
+		Context("referring to unknown attributes", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute("payment_type", String)
+					RequiredIf("payment_type", 1, "card_number")
+					MutuallyExclusive("payment_type", "email")
+				}
+			})
+
+			It("produces an invalid type definition", func() {
+				Ω(ut).ShouldNot(BeNil())
+				err := dslengine.Errors
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring(`condition value 1 is incompatible with the type of field "payment_type"`))
+				Ω(err.Error()).Should(ContainSubstring(`conditionally required field "card_number" does not exist`))
+				Ω(err.Error()).Should(ContainSubstring(`mutually exclusive field "email" does not exist`))
+			})
+		})
+
+		Context("with a required mutually exclusive attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute("email", String)
+					Attribute("phone", String)
+					Required("email")
+					MutuallyExclusive("email", "phone")
+				}
+			})
+
+			It("produces an invalid type definition", func() {
+				Ω(ut).ShouldNot(BeNil())
+				err := dslengine.Errors
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring(`mutually exclusive field "email" cannot be required`))
+			})
+		})
+	})
+
+	Context("with a reference to a type with described enum values", func() {
+		var color, paint *UserTypeDefinition
+
+		BeforeEach(func() {
+			color = Type("color", func() {
+				Attribute("color", String, func() {
+					EnumValue("red", "Red")
+				})
+			})
+			paint = Type("paint", func() {
+				Reference(color)
+				Attribute("color", func() {
+					EnumValue("red", "Red paint")
+				})
+			})
+			name = "wine"
+			dsl = func() {
+				Reference(color)
+				Attribute("color", func() {
+					EnumValue("red", "Red wine")
+				})
+			}
+		})
+
+		It("does not share the descriptions between the attributes", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			desc := func(t *UserTypeDefinition) string {
+				return t.Type.ToObject()["color"].Validation.ValueDescriptions["red"]
+			}
+			Ω(desc(color)).Should(Equal("Red"))
+			Ω(desc(paint)).Should(Equal("Red paint"))
+			Ω(desc(ut)).Should(Equal("Red wine"))
+		})
+	})
 })
 
 var _ = Describe("ArrayOf", func() {
@@ -289,3 +437,105 @@ var _ = Describe("HashOf", func() {
 		})
 	})
 })
+
+var _ = Describe("OneOf", func() {
+	var cat, dog *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		cat = Type("Cat", func() {
+			Attribute("kind", String, func() {
+				Enum("cat")
+			})
+			Attribute("lives", Integer)
+		})
+		dog = Type("Dog", func() {
+			Attribute("kind", String)
+			Attribute("breed", String)
+		})
+	})
+
+	Context("used in a type", func() {
+		var pet *UserTypeDefinition
+
+		BeforeEach(func() {
+			pet = Type("Pet", func() {
+				OneOf("kind", cat, "Dog")
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("produces a union type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(pet.IsUnion()).Should(BeTrue())
+			u := pet.ToUnion()
+			Ω(u.Kind()).Should(Equal(UnionKind))
+			Ω(u.Discriminator).Should(Equal("kind"))
+			Ω(u.Types).Should(Equal([]*UserTypeDefinition{cat, dog}))
+			Ω(u.DiscriminatorValues()).Should(Equal([]string{"cat", "Dog"}))
+		})
+	})
+
+	Context("used in an attribute", func() {
+		var owner *UserTypeDefinition
+
+		BeforeEach(func() {
+			owner = Type("Owner", func() {
+				Attribute("pet", func() {
+					OneOf("kind", cat, dog)
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("defines a union user type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			pet := owner.ToObject()["pet"]
+			Ω(pet).ShouldNot(BeNil())
+			ut, ok := pet.Type.(*UserTypeDefinition)
+			Ω(ok).Should(BeTrue())
+			Ω(ut.TypeName).Should(Equal("CatOrDog"))
+			Ω(ut.IsUnion()).Should(BeTrue())
+			Ω(Design.Types).Should(HaveKeyWithValue("CatOrDog", ut))
+		})
+	})
+
+	Context("with a type missing the discriminator", func() {
+		BeforeEach(func() {
+			Type("Pet", func() {
+				OneOf("type", cat, dog)
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`does not define discriminator attribute "type"`))
+		})
+	})
+
+	Context("with an invalid type", func() {
+		BeforeEach(func() {
+			Type("Pet", func() {
+				OneOf("kind", cat, String)
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Webhook can be used in: API, Resource
+//
+// Webhook describes a HTTP POST request sent by the API to URLs registered by third parties when
+// an event occurs. The DSL describes the request payload with Payload and the responses expected
+// from the receivers with Response. Webhook names must be unique across the API:
+//
+//	Webhook("order_created", func() {
+//		Description("Sent when an order is created")
+//		Payload(func() {
+//			Member("id", Integer)
+//			Member("total", Number)
+//			Required("id", "total")
+//		})
+//		Response(OK)
+//		Response(NoContent)
+//	})
+//
+// goagen generates a function that validates, signs and sends the webhook payload with retries
+// in the app package and a function that verifies the signature of the requests and decodes
+// their payload in the client package. Webhooks are documented in the "x-webhooks" extension of
+// the swagger and JSON schema specifications.
+func Webhook(name string, dsl func()) {
+	var webhooks map[string]*design.WebhookDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if def.Webhooks == nil {
+			def.Webhooks = make(map[string]*design.WebhookDefinition)
+		}
+		webhooks = def.Webhooks
+	case *design.ResourceDefinition:
+		if def.Webhooks == nil {
+			def.Webhooks = make(map[string]*design.WebhookDefinition)
+		}
+		webhooks = def.Webhooks
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if _, ok := webhooks[name]; ok {
+		dslengine.ReportError("webhook %s is defined twice", name)
+		return
+	}
+	webhook := &design.WebhookDefinition{Name: name, Parent: dslengine.CurrentDefinition()}
+	if !dslengine.Execute(dsl, webhook) {
+		return
+	}
+	webhooks[name] = webhook
+}
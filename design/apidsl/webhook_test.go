@@ -0,0 +1,161 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", dsl)
+		dslengine.Run()
+	})
+
+	Context("defined at the API level", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Webhook("order_created", func() {
+					Description("Sent when an order is created")
+					Payload(func() {
+						Member("id", Integer)
+						Required("id")
+					})
+					Response(OK)
+				})
+			}
+		})
+
+		It("produces a valid webhook definition", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Validate()).ShouldNot(HaveOccurred())
+			Ω(Design.Webhooks).Should(HaveKey("order_created"))
+			wh := Design.Webhooks["order_created"]
+			Ω(wh.Description).Should(Equal("Sent when an order is created"))
+			Ω(wh.Payload).ShouldNot(BeNil())
+			Ω(wh.Payload.TypeName).Should(Equal("OrderCreatedWebhookPayload"))
+			Ω(wh.Payload.Type.ToObject()).Should(HaveKey("id"))
+			Ω(wh.Responses).Should(HaveKey("OK"))
+			Ω(wh.Responses["OK"].Status).Should(Equal(200))
+		})
+	})
+
+	Context("defined in a resource", func() {
+		BeforeEach(func() {
+			dsl = func() {}
+			Resource("orders", func() {
+				Webhook("order_closed", func() {
+					Payload(func() {
+						Member("id", Integer)
+					})
+				})
+			})
+		})
+
+		It("is iterated over with the API webhooks", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Validate()).ShouldNot(HaveOccurred())
+			var names []string
+			Design.IterateWebhooks(func(wh *WebhookDefinition) error {
+				names = append(names, wh.Name)
+				return nil
+			})
+			Ω(names).Should(Equal([]string{"order_closed"}))
+		})
+	})
+
+	Context("with a user type payload", func() {
+		BeforeEach(func() {
+			order := Type("Order", func() {
+				Attribute("id", Integer)
+			})
+			dsl = func() {
+				Webhook("order_created", func() {
+					Payload(order)
+				})
+			}
+		})
+
+		It("uses the type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Webhooks["order_created"].Payload.TypeName).Should(Equal("Order"))
+		})
+	})
+
+	Context("defined twice", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Webhook("ping", func() { Payload(func() { Member("id", Integer) }) })
+				Webhook("ping", func() { Payload(func() { Member("id", Integer) }) })
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("defined in the API and in a resource with the same name", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Webhook("ping", func() { Payload(func() { Member("id", Integer) }) })
+			}
+			Resource("orders", func() {
+				Webhook("ping", func() { Payload(func() { Member("id", Integer) }) })
+			})
+		})
+
+		It("fails validation", func() {
+			Ω(Design.Validate()).Should(HaveOccurred())
+		})
+	})
+
+	Context("with no payload", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Webhook("ping", func() {})
+			}
+		})
+
+		It("fails validation", func() {
+			Ω(Design.Validate()).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an optional payload", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Webhook("ping", func() {
+					OptionalPayload(func() { Member("id", Integer) })
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a payload that is not an object", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Webhook("ping", func() {
+					Payload(ArrayOf(String))
+				})
+			}
+		})
+
+		It("fails validation", func() {
+			Ω(Design.Validate()).Should(HaveOccurred())
+		})
+	})
+})
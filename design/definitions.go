@@ -7,11 +7,33 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/dimfeld/httppath"
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/dslengine"
 )
 
+const (
+	// ParamStyleForm serializes array query string parameters as repeated parameters, e.g.
+	// "id=1&id=2", or as comma separated values when not exploded, e.g. "id=1,2".
+	ParamStyleForm = "form"
+	// ParamStyleSpaceDelimited serializes array query string parameters as space separated
+	// values, e.g. "id=1%202".
+	ParamStyleSpaceDelimited = "spaceDelimited"
+	// ParamStylePipeDelimited serializes array query string parameters as pipe separated
+	// values, e.g. "id=1|2".
+	ParamStylePipeDelimited = "pipeDelimited"
+	// ParamStyleDeepObject serializes hash query string parameters as one parameter per key,
+	// e.g. "filter[status]=x&filter[owner]=y". Only hashes of strings to strings may use it,
+	// object and user type parameters are not supported.
+	ParamStyleDeepObject = "deepObject"
+)
+
+// EventStreamMediaType is the media type of responses streaming Server-Sent Events.
+const EventStreamMediaType = "text/event-stream"
+
 type (
 	// APIDefinition defines the global properties of the API.
 	APIDefinition struct {
@@ -74,6 +96,13 @@ type (
 		Security *SecurityDefinition
 		// NoExamples indicates whether to bypass automatic example generation.
 		NoExamples bool
+		// Webhooks lists the API-level webhooks indexed by name.
+		Webhooks map[string]*WebhookDefinition
+		// Errors lists the errors that may be returned by all the API actions indexed by name.
+		Errors map[string]*ErrorDefinition
+		// ProblemDetails indicates whether error responses are rendered as RFC 7807 problem
+		// details using ProblemMedia instead of ErrorMedia.
+		ProblemDetails bool
 
 		// rand is the random generator used to generate examples.
 		rand *RandomGenerator
@@ -105,6 +134,18 @@ type (
 		URL string `json:"url,omitempty"`
 	}
 
+	// DeprecationDefinition describes the deprecation of a resource, action or attribute.
+	DeprecationDefinition struct {
+		// Since is the version or date (formatted as 2006-01-02) the definition was
+		// deprecated at if any.
+		Since string
+		// Sunset is the date (formatted as 2006-01-02) after which the definition may be
+		// removed if any.
+		Sunset string
+		// Replacement describes what should be used instead if any.
+		Replacement string
+	}
+
 	// ResourceDefinition describes a REST resource.
 	// It defines both a media type and a set of actions that can be executed through HTTP
 	// requests.
@@ -135,6 +176,8 @@ type (
 		Responses map[string]*ResponseDefinition
 		// Request headers that apply to all actions.
 		Headers *AttributeDefinition
+		// Request cookies that apply to all actions.
+		Cookies *AttributeDefinition
 		// Origins defines the CORS policies that apply to this resource.
 		Origins map[string]*CORSDefinition
 		// DSLFunc contains the DSL used to create this definition if any.
@@ -144,6 +187,43 @@ type (
 		// Security defines security requirements for the Resource,
 		// for actions that don't define one themselves.
 		Security *SecurityDefinition
+		// Deprecation describes the deprecation of the resource actions if any.
+		Deprecation *DeprecationDefinition
+		// Webhooks lists the webhooks sent by the resource indexed by name.
+		Webhooks map[string]*WebhookDefinition
+		// Errors lists the errors that may be returned by all the resource actions indexed
+		// by name.
+		Errors map[string]*ErrorDefinition
+	}
+
+	// ErrorDefinition describes an error that may be returned by actions. Errors are rendered
+	// using the error media type, the error name is the value of its "code" member.
+	ErrorDefinition struct {
+		// Error name, e.g. "order_not_found"
+		Name string
+		// Error description
+		Description string
+		// Status is the HTTP status code of the responses rendering the error
+		Status int
+		// Meta is the type of the error "meta" member if any
+		Meta *UserTypeDefinition
+		// Parent API, resource or action
+		Parent dslengine.Definition
+	}
+
+	// WebhookDefinition describes a HTTP request sent by the API to URLs registered by third
+	// parties when an event occurs.
+	WebhookDefinition struct {
+		// Webhook name, e.g. "order_created"
+		Name string
+		// Webhook description
+		Description string
+		// Parent API or resource
+		Parent dslengine.Definition
+		// Payload describes the request body
+		Payload *UserTypeDefinition
+		// Responses lists the responses expected from the receivers indexed by name
+		Responses map[string]*ResponseDefinition
 	}
 
 	// CORSDefinition contains the definition for a specific origin CORS policy.
@@ -198,6 +278,11 @@ type (
 		ViewName string
 		// Response header definitions
 		Headers *AttributeDefinition
+		// Response cookie definitions
+		Cookies *AttributeDefinition
+		// Stream is the type of the events streamed by the response using Server-Sent Events,
+		// nil if the response is not an event stream.
+		Stream DataType
 		// Parent action or resource
 		Parent dslengine.Definition
 		// Metadata is a list of key/value pairs
@@ -249,10 +334,34 @@ type (
 		PayloadMultipart bool
 		// Request headers that need to be made available to action
 		Headers *AttributeDefinition
+		// Request cookies that need to be made available to action
+		Cookies *AttributeDefinition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Deprecation describes the deprecation of the action if any.
+		Deprecation *DeprecationDefinition
+		// InboundMessage is the user type or media type of the messages sent by clients to a
+		// websocket action if any.
+		InboundMessage DataType
+		// OutboundMessage is the user type or media type of the messages sent by a websocket
+		// action to clients if any.
+		OutboundMessage DataType
+		// Async describes the long-running operation started by the action if any.
+		Async *AsyncDefinition
+		// Errors lists the errors that may be returned by the action indexed by name.
+		Errors map[string]*ErrorDefinition
+	}
+
+	// AsyncDefinition describes an action that starts a long-running operation (a job). Such
+	// actions respond with 202 Accepted and a Location header pointing to the status of the job
+	// exposed by a companion status action.
+	AsyncDefinition struct {
+		// StatusMediaType is the identifier of the media type that describes the job status.
+		StatusMediaType string
+		// StatusAction is the companion action that returns the status of the job.
+		StatusAction *ActionDefinition
 	}
 
 	// FileServerDefinition defines an endpoint that servers static assets.
@@ -318,6 +427,8 @@ type (
 		Type DataType
 		// Attribute reference type if any
 		Reference DataType
+		// Types extended by the attribute type if any, see Extend
+		Bases []DataType
 		// Optional description
 		Description string
 		// Optional validations
@@ -330,6 +441,15 @@ type (
 		Example interface{}
 		// Optional view used to render Attribute (only applies to media type attributes).
 		View string
+		// Deprecation describes the deprecation of the attribute if any.
+		Deprecation *DeprecationDefinition
+		// Style is the serialization style of the query string parameter described by the
+		// attribute, one of the ParamStyleXXX constants.
+		Style string
+		// Explode indicates whether the values of the array or hash query string parameter
+		// described by the attribute are serialized as separate parameters. The default
+		// depends on Style.
+		Explode *bool
 		// NonZeroAttributes lists the names of the child attributes that cannot have a
 		// zero value (and thus whose presence does not need to be validated).
 		NonZeroAttributes map[string]bool
@@ -364,6 +484,12 @@ type (
 
 	// ResponseIterator is the type of functions given to IterateResponses.
 	ResponseIterator func(r *ResponseDefinition) error
+
+	// WebhookIterator is the type of functions given to IterateWebhooks.
+	WebhookIterator func(w *WebhookDefinition) error
+
+	// ErrorIterator is the type of functions given to IterateErrors.
+	ErrorIterator func(e *ErrorDefinition) error
 )
 
 // NewAPIDefinition returns a new design with built-in response templates.
@@ -590,6 +716,55 @@ func (a *APIDefinition) IterateResponses(it ResponseIterator) error {
 	return nil
 }
 
+// IterateWebhooks calls the given iterator passing in each webhook defined at the API level or by
+// a resource sorted in alphabetical order. Iteration stops if an iterator returns an error and in
+// this case IterateWebhooks returns that error.
+func (a *APIDefinition) IterateWebhooks(it WebhookIterator) error {
+	var webhooks []*WebhookDefinition
+	for _, w := range a.Webhooks {
+		webhooks = append(webhooks, w)
+	}
+	for _, r := range a.Resources {
+		for _, w := range r.Webhooks {
+			webhooks = append(webhooks, w)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].Name < webhooks[j].Name })
+	for _, w := range webhooks {
+		if err := it(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IterateErrors calls the given iterator passing in each error defined at the API level, by a
+// resource or by an action sorted in alphabetical order. Iteration stops if an iterator returns an
+// error and in this case IterateErrors returns that error.
+func (a *APIDefinition) IterateErrors(it ErrorIterator) error {
+	var errs []*ErrorDefinition
+	for _, e := range a.Errors {
+		errs = append(errs, e)
+	}
+	for _, r := range a.Resources {
+		for _, e := range r.Errors {
+			errs = append(errs, e)
+		}
+		for _, act := range r.Actions {
+			for _, e := range act.Errors {
+				errs = append(errs, e)
+			}
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Name < errs[j].Name })
+	for _, e := range errs {
+		if err := it(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RandomGenerator is seeded after the API name. It's used to generate examples.
 func (a *APIDefinition) RandomGenerator() *RandomGenerator {
 	if a.rand == nil {
@@ -657,21 +832,34 @@ func (a *APIDefinition) DSL() func() {
 }
 
 // Finalize sets the Consumes and Produces fields to the defaults if empty.
-// Also it records built-in media types that are used by the user design.
+// Also it records built-in media types that are used by the user design and
+// names the Go types generated for the described enum values of user types.
 func (a *APIDefinition) Finalize() {
+	a.IterateWebhooks(func(w *WebhookDefinition) error {
+		w.Finalize()
+		return nil
+	})
 	if len(a.Consumes) == 0 {
 		a.Consumes = DefaultDecoders
 	}
 	if len(a.Produces) == 0 {
 		a.Produces = DefaultEncoders
 	}
+	a.IterateUserTypes(func(u *UserTypeDefinition) error {
+		u.setEnumTypeNames()
+		return nil
+	})
 	a.IterateResources(func(r *ResourceDefinition) error {
 		returnsError := func(resp *ResponseDefinition) bool {
 			if resp.MediaType == ErrorMediaIdentifier {
 				if a.MediaTypes == nil {
 					a.MediaTypes = make(map[string]*MediaTypeDefinition)
 				}
-				a.MediaTypes[CanonicalIdentifier(ErrorMediaIdentifier)] = ErrorMedia
+				if a.ProblemDetails {
+					a.MediaTypes[CanonicalIdentifier(ProblemMediaIdentifier)] = ProblemMedia
+				} else {
+					a.MediaTypes[CanonicalIdentifier(ErrorMediaIdentifier)] = ErrorMedia
+				}
 				return true
 			}
 			return false
@@ -687,6 +875,10 @@ func (a *APIDefinition) Finalize() {
 			}
 		}
 		return r.IterateActions(func(action *ActionDefinition) error {
+			if len(action.AllErrors()) > 0 {
+				returnsError(&ResponseDefinition{MediaType: ErrorMediaIdentifier})
+				return errors.New("done")
+			}
 			for _, resp := range action.Responses {
 				if returnsError(resp) {
 					return errors.New("done")
@@ -1008,6 +1200,24 @@ func (a *AttributeDefinition) SetDefault(def interface{}) {
 	}
 }
 
+// AddValue adds the given Enum value and its description to the attribute's validation
+// definition unless the value is already listed in which case only the description is set.
+func (a *AttributeDefinition) AddValue(value interface{}, desc string) {
+	if a.Validation == nil {
+		a.Validation = &dslengine.ValidationDefinition{}
+	}
+	if a.Validation.ValueDescriptions == nil {
+		a.Validation.ValueDescriptions = make(map[interface{}]string)
+	}
+	a.Validation.ValueDescriptions[value] = desc
+	for _, v := range a.Validation.Values {
+		if v == value {
+			return
+		}
+	}
+	a.Validation.Values = append(a.Validation.Values, value)
+}
+
 // AddValues adds the Enum values to the attribute's validation definition.
 // It also performs any conversion needed for HashVal and ArrayVal types.
 func (a *AttributeDefinition) AddValues(values []interface{}) {
@@ -1054,12 +1264,50 @@ func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if att == nil {
 		return false
 	}
-	if att.Type.IsPrimitive() {
-		return (!a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName) && !a.IsInterface(attName)) || a.IsFile(attName)
+	if att.Type.IsPrimitive() && !att.IsNullable() {
+		return (!a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName) && !a.IsInterface(attName) && !a.IsBytes(attName)) || a.IsFile(attName)
+	}
+	return false
+}
+
+// ParamStyle returns the serialization style of the query string parameter described by the
+// attribute, ParamStyleForm if none was set.
+func (a *AttributeDefinition) ParamStyle() string {
+	if a.Style == "" {
+		return ParamStyleForm
+	}
+	return a.Style
+}
+
+// IsExploded returns true if the values of the array or hash query string parameter described by
+// the attribute are serialized as separate parameters.
+func (a *AttributeDefinition) IsExploded() bool {
+	if a.Explode != nil {
+		return *a.Explode
+	}
+	switch a.ParamStyle() {
+	case ParamStyleForm, ParamStyleDeepObject:
+		return true
 	}
 	return false
 }
 
+// ParamDelimiter returns the string separating the values of the array query string parameter
+// described by the attribute, the empty string if the values are serialized as separate
+// parameters.
+func (a *AttributeDefinition) ParamDelimiter() string {
+	if !a.Type.IsArray() || a.IsExploded() {
+		return ""
+	}
+	switch a.ParamStyle() {
+	case ParamStyleSpaceDelimited:
+		return " "
+	case ParamStylePipeDelimited:
+		return "|"
+	}
+	return ","
+}
+
 // IsInterface returns true if the field generated for the given attribute has
 // an interface type that should not be referenced as a "*interface{}" pointer.
 // The target attribute must be an object.
@@ -1074,6 +1322,19 @@ func (a *AttributeDefinition) IsInterface(attName string) bool {
 	return att.Type.Kind() == AnyKind
 }
 
+// IsBytes returns true if the field generated for the given attribute is a byte slice which
+// should not be referenced as a "*[]byte" pointer. The target attribute must be an object.
+func (a *AttributeDefinition) IsBytes(attName string) bool {
+	if !a.Type.IsObject() {
+		panic("checking pointer field on non-object") // bug
+	}
+	att := a.Type.ToObject()[attName]
+	if att == nil {
+		return false
+	}
+	return att.Type.Kind() == BytesKind
+}
+
 // IsFile returns true if the attribute is of type File or if any its children attributes (if any) is.
 func (a *AttributeDefinition) IsFile(attName string) bool {
 	if !a.Type.IsObject() {
@@ -1156,6 +1417,73 @@ func (a *AttributeDefinition) SetReadOnly() {
 	a.Metadata["swagger:read-only"] = nil
 }
 
+// SetNullable marks the attribute as nullable: the attribute value may be explicitly set to null
+// in request and response bodies.
+func (a *AttributeDefinition) SetNullable() {
+	if a.Metadata == nil {
+		a.Metadata = map[string][]string{}
+	}
+	a.Metadata["nullable"] = nil
+}
+
+// IsNullable returns true if attribute is nullable (set using SetNullable() method)
+func (a *AttributeDefinition) IsNullable() bool {
+	_, ok := a.Metadata["nullable"]
+	return ok
+}
+
+// HasEnumDescriptions returns true if the attribute enum values are described, see AddValue.
+func (a *AttributeDefinition) HasEnumDescriptions() bool {
+	return a.Validation != nil && len(a.Validation.ValueDescriptions) > 0
+}
+
+// EnumTypeName returns the name of the Go type generated for the attribute enum values as set
+// with the "struct:enum:name" metadata, the empty string if there isn't one or if the attribute
+// cannot have such a type.
+func (a *AttributeDefinition) EnumTypeName() string {
+	if !a.canHaveEnumType() {
+		return ""
+	}
+	if n, ok := a.Metadata["struct:enum:name"]; ok && len(n) > 0 {
+		return n[0]
+	}
+	return ""
+}
+
+// canHaveEnumType returns true if a Go type may be generated for the attribute enum values, that
+// is if the values are described and are of a non-nullable basic type.
+func (a *AttributeDefinition) canHaveEnumType() bool {
+	if !a.HasEnumDescriptions() || a.IsNullable() {
+		return false
+	}
+	switch a.Type.Kind() {
+	case BooleanKind, IntegerKind, NumberKind, StringKind, Int32Kind, Int64Kind,
+		UInt32Kind, UInt64Kind, Float32Kind, Float64Kind:
+		return true
+	}
+	return false
+}
+
+// setEnumTypeNames records the name of the Go types generated for the enum values of the user
+// type child attributes unless the "struct:enum:name" metadata is already set.
+func (u *UserTypeDefinition) setEnumTypeNames() {
+	if u.Type == nil {
+		return
+	}
+	for name, att := range u.Type.ToObject() {
+		if !att.canHaveEnumType() || att.EnumTypeName() != "" {
+			continue
+		}
+		// Attributes may share their metadata, see DupAtt.
+		md := make(dslengine.MetadataDefinition, len(att.Metadata)+1)
+		for k, v := range att.Metadata {
+			md[k] = v
+		}
+		md["struct:enum:name"] = []string{u.TypeName + "_" + name}
+		att.Metadata = md
+	}
+}
+
 // IsReadOnly returns true if attribute is read-only (set using SetReadOnly() method)
 func (a *AttributeDefinition) IsReadOnly() bool {
 	if _, readOnlyMetadataIsPresent := a.Metadata["swagger:read-only"]; readOnlyMetadataIsPresent {
@@ -1166,7 +1494,15 @@ func (a *AttributeDefinition) IsReadOnly() bool {
 
 func (a *AttributeDefinition) arrayExample(rand *RandomGenerator, seen []string) interface{} {
 	ary := a.Type.ToArray()
-	ln := newExampleGenerator(a, rand).ExampleLength()
+	eg := newExampleGenerator(a, rand)
+	ln := eg.ExampleLength()
+	if a.Validation != nil && a.Validation.UniqueItems {
+		res := eg.generateUniqueExamples(ary.ElemType, ln, seen)
+		if len(res) == 0 {
+			return nil
+		}
+		return ary.MakeSlice(res)
+	}
 	var res []interface{}
 	for i := 0; i < ln; i++ {
 		ex := ary.ElemType.GenerateExample(rand, seen)
@@ -1182,8 +1518,21 @@ func (a *AttributeDefinition) arrayExample(rand *RandomGenerator, seen []string)
 
 func (a *AttributeDefinition) hashExample(rand *RandomGenerator, seen []string) interface{} {
 	h := a.Type.ToHash()
-	ln := newExampleGenerator(a, rand).ExampleLength()
+	eg := newExampleGenerator(a, rand)
+	ln := eg.ExampleLength()
 	res := make(map[interface{}]interface{})
+	if a.Validation != nil && a.Validation.MinProperties != nil {
+		// Make sure the example has enough distinct keys.
+		for _, k := range eg.generateUniqueExamples(h.KeyType, ln, seen) {
+			if v := h.ElemType.GenerateExample(rand, seen); v != nil {
+				res[k] = v
+			}
+		}
+		if len(res) == 0 {
+			return nil
+		}
+		return h.MakeMap(res)
+	}
 	for i := 0; i < ln; i++ {
 		k := h.KeyType.GenerateExample(rand, seen)
 		v := h.ElemType.GenerateExample(rand, seen)
@@ -1311,6 +1660,9 @@ func (a *AttributeDefinition) inheritRecursive(parent *AttributeDefinition, seen
 			if att.View == "" {
 				att.View = patt.View
 			}
+			if att.Deprecation == nil {
+				att.Deprecation = patt.Deprecation
+			}
 			if att.Type == nil {
 				att.Type = patt.Type
 			} else if att.shouldInherit(patt) {
@@ -1373,6 +1725,46 @@ func (d *DocsDefinition) Context() string {
 	return fmt.Sprintf("documentation for %s", Design.Name)
 }
 
+// String returns a human readable description of the deprecation, e.g. "deprecated since v2,
+// sunset on 2017-06-30, use list instead".
+func (d *DeprecationDefinition) String() string {
+	var details []string
+	if d.Since != "" {
+		details = append(details, "since "+d.Since)
+	}
+	if d.Sunset != "" {
+		details = append(details, "sunset on "+d.Sunset)
+	}
+	if d.Replacement != "" {
+		details = append(details, "use "+d.Replacement+" instead")
+	}
+	if len(details) == 0 {
+		return "deprecated"
+	}
+	details[0] = "deprecated " + details[0]
+	return strings.Join(details, ", ")
+}
+
+// DeprecationHeader returns the value of the Deprecation HTTP response header (RFC 9745). The
+// value is the deprecation date as a Unix timestamp prefixed with "@" if Since is a date, "true"
+// otherwise.
+func (d *DeprecationDefinition) DeprecationHeader() string {
+	if t, err := time.Parse(goa.DateLayout, d.Since); err == nil {
+		return fmt.Sprintf("@%d", t.Unix())
+	}
+	return "true"
+}
+
+// SunsetHeader returns the value of the Sunset HTTP response header (RFC 8594), the empty string
+// if there is no sunset date.
+func (d *DeprecationDefinition) SunsetHeader() string {
+	t, err := time.Parse(goa.DateLayout, d.Sunset)
+	if err != nil {
+		return ""
+	}
+	return t.Format(http.TimeFormat)
+}
+
 // Context returns the generic definition name used in error messages.
 func (t *UserTypeDefinition) Context() string {
 	if t.TypeName != "" {
@@ -1400,6 +1792,11 @@ func (r *ResponseDefinition) Context() string {
 	return prefix + suffix
 }
 
+// IsStream returns true if the response streams events using Server-Sent Events.
+func (r *ResponseDefinition) IsStream() bool {
+	return r.Stream != nil
+}
+
 // Finalize sets the response media type from its type if the type is a media type and no media
 // type is already specified.
 func (r *ResponseDefinition) Finalize() {
@@ -1428,6 +1825,10 @@ func (r *ResponseDefinition) Dup() *ResponseDefinition {
 	if r.Headers != nil {
 		res.Headers = DupAtt(r.Headers)
 	}
+	if r.Cookies != nil {
+		res.Cookies = DupAtt(r.Cookies)
+	}
+	res.Stream = r.Stream
 	return &res
 }
 
@@ -1449,6 +1850,9 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 		r.MediaType = other.MediaType
 		r.ViewName = other.ViewName
 	}
+	if r.Stream == nil {
+		r.Stream = other.Stream
+	}
 	if other.Headers != nil {
 		otherHeaders := other.Headers.Type.ToObject()
 		if len(otherHeaders) > 0 {
@@ -1463,6 +1867,20 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 			}
 		}
 	}
+	if other.Cookies != nil {
+		otherCookies := other.Cookies.Type.ToObject()
+		if len(otherCookies) > 0 {
+			if r.Cookies == nil {
+				r.Cookies = &AttributeDefinition{Type: Object{}}
+			}
+			cookies := r.Cookies.Type.ToObject()
+			for n, c := range otherCookies {
+				if _, ok := cookies[n]; !ok {
+					cookies[n] = c
+				}
+			}
+		}
+	}
 }
 
 // Context returns the generic definition name used in error messages.
@@ -1521,6 +1939,21 @@ func (a *ActionDefinition) AllParams() *AttributeDefinition {
 	return res.Merge(Design.Params)
 }
 
+// AllCookies returns the request cookies of the action merged with the cookies that apply to all
+// the parent resource actions, nil if there are none.
+func (a *ActionDefinition) AllCookies() *AttributeDefinition {
+	res := &AttributeDefinition{Type: Object{}}
+	for _, cookies := range []*AttributeDefinition{a.Parent.Cookies, a.Cookies} {
+		if cookies != nil {
+			res.Merge(cookies)
+		}
+	}
+	if len(res.Type.ToObject()) == 0 {
+		return nil
+	}
+	return res
+}
+
 // HasAbsoluteRoutes returns true if all the action routes are absolute.
 func (a *ActionDefinition) HasAbsoluteRoutes() bool {
 	for _, r := range a.Routes {
@@ -1586,6 +2019,11 @@ func (a *ActionDefinition) WebSocket() bool {
 
 // Finalize inherits security scheme and action responses from parent and top level design.
 func (a *ActionDefinition) Finalize() {
+	// Inherit deprecation
+	if a.Deprecation == nil {
+		a.Deprecation = a.Parent.Deprecation
+	}
+
 	// Inherit security scheme
 	if a.Security == nil {
 		a.Security = a.Parent.Security // ResourceDefinition
@@ -1602,7 +2040,9 @@ func (a *ActionDefinition) Finalize() {
 		a.Payload.Finalize()
 	}
 
+	a.initErrorResponses()
 	a.mergeResponses()
+	a.initProblemResponses()
 	a.initImplicitParams()
 	a.initQueryParams()
 }
@@ -1665,6 +2105,70 @@ func (a *ActionDefinition) IterateResponses(it ResponseIterator) error {
 	return nil
 }
 
+// AllErrors returns the errors that may be returned by the action: the errors defined by the
+// action, its resource and the API sorted by name.
+func (a *ActionDefinition) AllErrors() []*ErrorDefinition {
+	all := make(map[string]*ErrorDefinition)
+	for _, errs := range []map[string]*ErrorDefinition{Design.Errors, a.Parent.Errors, a.Errors} {
+		for n, e := range errs {
+			all[n] = e
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	res := make([]*ErrorDefinition, 0, len(all))
+	for _, e := range all {
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// ErrorsWithStatus returns the errors that may be returned by the action with the given status
+// sorted by name.
+func (a *ActionDefinition) ErrorsWithStatus(status int) []*ErrorDefinition {
+	var res []*ErrorDefinition
+	for _, e := range a.AllErrors() {
+		if e.Status == status {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// initErrorResponses adds a response rendering the error media type for each status of the
+// errors returned by the action that no response describes.
+func (a *ActionDefinition) initErrorResponses() {
+	for _, e := range a.AllErrors() {
+		var found bool
+		for _, resps := range []map[string]*ResponseDefinition{a.Responses, a.Parent.Responses} {
+			for _, resp := range resps {
+				if resp.Status == e.Status {
+					found = true
+				}
+			}
+		}
+		if found {
+			continue
+		}
+		name := e.ResponseName()
+		if name == "" {
+			continue
+		}
+		if a.Responses == nil {
+			a.Responses = make(map[string]*ResponseDefinition)
+		}
+		a.Responses[name] = &ResponseDefinition{
+			Name:        name,
+			Status:      e.Status,
+			Description: http.StatusText(e.Status),
+			MediaType:   ErrorMediaIdentifier,
+			Parent:      a,
+		}
+	}
+}
+
 // mergeResponses merges the parent resource and design responses.
 func (a *ActionDefinition) mergeResponses() {
 	for name, resp := range a.Parent.Responses {
@@ -1689,6 +2193,22 @@ func (a *ActionDefinition) mergeResponses() {
 	}
 }
 
+// initProblemResponses makes the error responses use ProblemMedia if the API renders errors as
+// RFC 7807 problem details.
+func (a *ActionDefinition) initProblemResponses() {
+	if !Design.ProblemDetails {
+		return
+	}
+	for _, resp := range a.Responses {
+		if resp.Type == ErrorMedia {
+			resp.Type = ProblemMedia
+		}
+		if resp.MediaType != "" && CanonicalIdentifier(resp.MediaType) == CanonicalIdentifier(ErrorMediaIdentifier) {
+			resp.MediaType = ProblemMediaIdentifier
+		}
+	}
+}
+
 // initImplicitParams creates params for path segments that don't have one.
 func (a *ActionDefinition) initImplicitParams() {
 	for _, ro := range a.Routes {
@@ -1882,6 +2402,82 @@ func (r *RouteDefinition) IsAbsolute() bool {
 	return strings.HasPrefix(r.Path, "//")
 }
 
+// Context returns the generic definition name used in error messages.
+func (w *WebhookDefinition) Context() string {
+	var prefix, suffix string
+	if w.Name != "" {
+		suffix = fmt.Sprintf("webhook %#v", w.Name)
+	} else {
+		suffix = "unnamed webhook"
+	}
+	if r, ok := w.Parent.(*ResourceDefinition); ok {
+		prefix = r.Context() + " "
+	}
+	return prefix + suffix
+}
+
+// Finalize finalizes the webhook payload and responses.
+func (w *WebhookDefinition) Finalize() {
+	if w.Payload != nil {
+		w.Payload.Finalize()
+	}
+	for _, resp := range w.Responses {
+		resp.Finalize()
+	}
+}
+
+// IterateResponses calls the given iterator passing in each response sorted in alphabetical order.
+// Iteration stops if an iterator returns an error and in this case IterateResponses returns that
+// error.
+func (w *WebhookDefinition) IterateResponses(it ResponseIterator) error {
+	names := make([]string, len(w.Responses))
+	i := 0
+	for n := range w.Responses {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := it(w.Responses[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Context returns the generic definition name used in error messages.
+func (e *ErrorDefinition) Context() string {
+	var prefix, suffix string
+	if e.Name != "" {
+		suffix = fmt.Sprintf("error %#v", e.Name)
+	} else {
+		suffix = "unnamed error"
+	}
+	if e.Parent != nil {
+		if _, ok := e.Parent.(*APIDefinition); !ok {
+			prefix = e.Parent.Context() + " "
+		}
+	}
+	return prefix + suffix
+}
+
+// ResponseName returns the name of the response rendering the error, e.g. "NotFound". The name is
+// the name of the built-in response with the error status if any, the status text without spaces
+// otherwise. ResponseName returns the empty string if the status is unknown.
+func (e *ErrorDefinition) ResponseName() string {
+	for n, r := range Design.DefaultResponses {
+		if r.Status == e.Status {
+			return n
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, http.StatusText(e.Status))
+}
+
 func iterateHeaders(headers *AttributeDefinition, isRequired func(name string) bool, it HeaderIterator) error {
 	if headers == nil || !headers.Type.IsObject() {
 		return nil
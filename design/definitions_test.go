@@ -167,6 +167,50 @@ var _ = Describe("Finalize ActionDefinition", func() {
 			Ω(action.Responses).Should(HaveKey("NotFound"))
 		})
 	})
+
+	Context("with a deprecated resource", func() {
+		var action *design.ActionDefinition
+		var dep *design.DeprecationDefinition
+
+		BeforeEach(func() {
+			dep = &design.DeprecationDefinition{Since: "v2"}
+			resource := &design.ResourceDefinition{Deprecation: dep}
+			action = &design.ActionDefinition{Parent: resource}
+		})
+
+		It("inherits the resource deprecation", func() {
+			action.Finalize()
+			Ω(action.Deprecation).Should(Equal(dep))
+		})
+	})
+})
+
+var _ = Describe("DeprecationDefinition", func() {
+	var dep *design.DeprecationDefinition
+
+	Context("with dates and a replacement", func() {
+		BeforeEach(func() {
+			dep = &design.DeprecationDefinition{Since: "2017-01-01", Sunset: "2017-06-30", Replacement: "get"}
+		})
+
+		It("computes the description and header values", func() {
+			Ω(dep.String()).Should(Equal("deprecated since 2017-01-01, sunset on 2017-06-30, use get instead"))
+			Ω(dep.DeprecationHeader()).Should(Equal("@1483228800"))
+			Ω(dep.SunsetHeader()).Should(Equal("Fri, 30 Jun 2017 00:00:00 GMT"))
+		})
+	})
+
+	Context("with a version and no sunset", func() {
+		BeforeEach(func() {
+			dep = &design.DeprecationDefinition{Since: "v2"}
+		})
+
+		It("computes the description and header values", func() {
+			Ω(dep.String()).Should(Equal("deprecated since v2"))
+			Ω(dep.DeprecationHeader()).Should(Equal("true"))
+			Ω(dep.SunsetHeader()).Should(BeEmpty())
+		})
+	})
 })
 
 var _ = Describe("FullPath", func() {
@@ -48,6 +48,9 @@ func (d *dupper) DupAttribute(att *AttributeDefinition) *AttributeDefinition {
 		DefaultValue:      att.DefaultValue,
 		NonZeroAttributes: att.NonZeroAttributes,
 		View:              att.View,
+		Deprecation:       att.Deprecation,
+		Style:             att.Style,
+		Explode:           att.Explode,
 		DSLFunc:           att.DSLFunc,
 		Example:           att.Example,
 	}
@@ -95,6 +98,12 @@ func (d *dupper) DupType(t DataType) DataType {
 		d.dmts[actual.Identifier] = m
 		m.UserTypeDefinition = d.DupUserType(actual.UserTypeDefinition)
 		return m
+	case *Union:
+		types := make([]*UserTypeDefinition, len(actual.Types))
+		for i, ut := range actual.Types {
+			types[i] = d.DupType(ut).(*UserTypeDefinition)
+		}
+		return &Union{Discriminator: actual.Discriminator, Types: types}
 	}
 	panic("unknown type " + t.Name())
 }
@@ -3,9 +3,11 @@ package design
 import (
 	"fmt"
 	"math"
+	"reflect"
 	"regexp"
 	"time"
 
+	"github.com/goadesign/goa"
 	regen "github.com/zach-klippenstein/goregen"
 )
 
@@ -35,6 +37,7 @@ func (eg *exampleGenerator) Generate(seen []string) interface{} {
 	}
 	// loop until a satisified example is generated
 	hasFormat, hasPattern, hasMinMax := eg.hasFormatValidation(), eg.hasPatternValidation(), eg.hasMinMaxValidation()
+	hasMultipleOf := eg.hasMultipleOfValidation()
 	attempts := 0
 	for attempts < maxAttempts {
 		attempts++
@@ -51,6 +54,13 @@ func (eg *exampleGenerator) Generate(seen []string) interface{} {
 				continue
 			}
 		}
+		if hasMultipleOf {
+			if example == nil {
+				example = eg.generateValidatedMultipleOfExample()
+			} else if !eg.checkMultipleOfValidation(example) {
+				continue
+			}
+		}
 		if hasMinMax {
 			if example == nil {
 				example = eg.generateValidatedMinMaxValueExample()
@@ -67,7 +77,7 @@ func (eg *exampleGenerator) Generate(seen []string) interface{} {
 }
 
 func (eg *exampleGenerator) ExampleLength() int {
-	if eg.hasLengthValidation() {
+	if eg.hasLengthValidation() || eg.hasPropertiesValidation() {
 		minlength, maxlength := math.Inf(1), math.Inf(-1)
 		if eg.a.Validation.MinLength != nil {
 			minlength = float64(*eg.a.Validation.MinLength)
@@ -75,6 +85,12 @@ func (eg *exampleGenerator) ExampleLength() int {
 		if eg.a.Validation.MaxLength != nil {
 			maxlength = float64(*eg.a.Validation.MaxLength)
 		}
+		if p := eg.a.Validation.MinProperties; p != nil && (math.IsInf(minlength, 1) || float64(*p) > minlength) {
+			minlength = float64(*p)
+		}
+		if p := eg.a.Validation.MaxProperties; p != nil && (math.IsInf(maxlength, -1) || float64(*p) < maxlength) {
+			maxlength = float64(*p)
+		}
 		count := 0
 		if math.IsInf(minlength, 1) {
 			count = int(maxlength) - (eg.r.Int() % 3)
@@ -109,6 +125,13 @@ func (eg *exampleGenerator) hasLengthValidation() bool {
 	return eg.a.Validation.MinLength != nil || eg.a.Validation.MaxLength != nil
 }
 
+func (eg *exampleGenerator) hasPropertiesValidation() bool {
+	if eg.a.Validation == nil {
+		return false
+	}
+	return eg.a.Validation.MinProperties != nil || eg.a.Validation.MaxProperties != nil
+}
+
 const maxExampleLength = 10
 
 // generateValidatedLengthExample generates a random size array of examples based on what's given.
@@ -124,6 +147,41 @@ func (eg *exampleGenerator) generateValidatedLengthExample(seen []string) interf
 	return res
 }
 
+// generateUniqueExamples generates n distinct examples of the given attribute. It returns fewer
+// examples if the attribute does not allow for enough distinct values.
+func (eg *exampleGenerator) generateUniqueExamples(att *AttributeDefinition, n int, seen []string) []interface{} {
+	var res []interface{}
+	// The first example honors any example defined in the design.
+	if ex := att.GenerateExample(eg.r, seen); ex != nil {
+		res = append(res, ex)
+	}
+	for attempts := 0; len(res) < n && attempts < maxAttempts; attempts++ {
+		var ex interface{}
+		if att.Type.IsPrimitive() {
+			ex = newExampleGenerator(att, eg.r).Generate(seen)
+		} else {
+			ex = att.Type.GenerateExample(eg.r, seen)
+		}
+		if ex == nil {
+			continue
+		}
+		dup := false
+		for _, e := range res {
+			if reflect.DeepEqual(e, ex) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			res = append(res, ex)
+		}
+	}
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
 func (eg *exampleGenerator) hasEnumValidation() bool {
 	return eg.a.Validation != nil && len(eg.a.Validation.Values) > 0
 }
@@ -152,8 +210,8 @@ func (eg *exampleGenerator) generateFormatExample() interface{} {
 	if res, ok := map[string]interface{}{
 		"email":     eg.r.faker.Email(),
 		"hostname":  eg.r.faker.DomainName() + "." + eg.r.faker.DomainSuffix(),
-		"date":      time.Unix(int64(eg.r.Int())%1454957045, 0).Format("2006-01-02"), // to obtain a "fixed" rand
-		"date-time": time.Unix(int64(eg.r.Int())%1454957045, 0).Format(time.RFC3339), // to obtain a "fixed" rand
+		"date":      time.Unix(int64(eg.r.Int())%1454957045, 0).Format(goa.DateLayout), // to obtain a "fixed" rand
+		"date-time": time.Unix(int64(eg.r.Int())%1454957045, 0).Format(time.RFC3339),   // to obtain a "fixed" rand
 		"ipv4":      eg.r.faker.IPv4Address().String(),
 		"ipv6":      eg.r.faker.IPv6Address().String(),
 		"ip":        eg.r.faker.IPv4Address().String(),
@@ -211,30 +269,57 @@ func (eg *exampleGenerator) hasMinMaxValidation() bool {
 	if eg.a.Validation == nil {
 		return false
 	}
-	return eg.a.Validation.Minimum != nil || eg.a.Validation.Maximum != nil
+	v := eg.a.Validation
+	return v.Minimum != nil || v.Maximum != nil || v.ExclusiveMinimum != nil || v.ExclusiveMaximum != nil
+}
+
+// minMax returns the inclusive bounds of the values that satisfy the minimum, maximum, exclusive
+// minimum and exclusive maximum validations. A missing minimum is returned as +Inf and a missing
+// maximum as -Inf.
+func (eg *exampleGenerator) minMax() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	v := eg.a.Validation
+	if v.Minimum != nil {
+		min = *v.Minimum
+	}
+	if v.Maximum != nil {
+		max = *v.Maximum
+	}
+	if v.ExclusiveMinimum != nil {
+		m := math.Nextafter(*v.ExclusiveMinimum, math.Inf(1))
+		if IsInteger(eg.a.Type) {
+			m = math.Floor(*v.ExclusiveMinimum) + 1
+		}
+		if math.IsInf(min, 1) || m > min {
+			min = m
+		}
+	}
+	if v.ExclusiveMaximum != nil {
+		m := math.Nextafter(*v.ExclusiveMaximum, math.Inf(-1))
+		if IsInteger(eg.a.Type) {
+			m = math.Ceil(*v.ExclusiveMaximum) - 1
+		}
+		if math.IsInf(max, -1) || m < max {
+			max = m
+		}
+	}
+	return
 }
 
 func (eg *exampleGenerator) checkMinMaxValueValidation(example interface{}) bool {
 	if !eg.hasMinMaxValidation() {
 		return true
 	}
-	valid := true
-	if min := eg.a.Validation.Minimum; min != nil {
-		if v, ok := example.(int); ok && float64(v) < *min {
-			valid = false
-		} else if v, ok := example.(float64); ok && v < *min {
-			valid = false
-		}
+	v, ok := exampleNumber(example)
+	if !ok {
+		return true
 	}
-	if !valid {
+	min, max := eg.minMax()
+	if !math.IsInf(min, 1) && v < min {
 		return false
 	}
-	if max := eg.a.Validation.Maximum; max != nil {
-		if v, ok := example.(int); ok && float64(v) > *max {
-			return false
-		} else if v, ok := example.(float64); ok && v > *max {
-			return false
-		}
+	if !math.IsInf(max, -1) && v > max {
+		return false
 	}
 	return true
 }
@@ -243,39 +328,103 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 	if !eg.hasMinMaxValidation() {
 		return nil
 	}
-	min, max := math.Inf(1), math.Inf(-1)
-	if eg.a.Validation.Minimum != nil {
-		min = *eg.a.Validation.Minimum
-	}
-	if eg.a.Validation.Maximum != nil {
-		max = *eg.a.Validation.Maximum
-	}
+	min, max := eg.minMax()
 	if math.IsInf(min, 1) {
-		if eg.a.Type.Kind() == IntegerKind {
-			if max == 0 {
+		if IsInteger(eg.a.Type) {
+			if max <= 0 {
 				return int(max) - eg.r.Int()%3
 			}
 			return eg.r.Int() % int(max)
 		}
+		if max <= 0 {
+			return max - eg.r.Float64()
+		}
 		return eg.r.Float64() * max
 	} else if math.IsInf(max, -1) {
-		if eg.a.Type.Kind() == IntegerKind {
+		if IsInteger(eg.a.Type) {
 			if min == 0 {
 				return int(min) + eg.r.Int()%3
 			}
 			return int(min) + eg.r.Int()%int(min)
 		}
+		if min <= 0 {
+			return min + eg.r.Float64()
+		}
 		return min + eg.r.Float64()*min
 	} else if min < max {
-		if eg.a.Type.Kind() == IntegerKind {
+		if IsInteger(eg.a.Type) {
 			return int(min) + eg.r.Int()%int(max-min)
 		}
 		return min + eg.r.Float64()*(max-min)
 	} else if min == max {
-		if eg.a.Type.Kind() == IntegerKind {
+		if IsInteger(eg.a.Type) {
 			return int(min)
 		}
 		return min
 	}
 	panic("Validation: Min > Max")
 }
+
+func (eg *exampleGenerator) hasMultipleOfValidation() bool {
+	return eg.a.Validation != nil && eg.a.Validation.MultipleOf != nil
+}
+
+func (eg *exampleGenerator) checkMultipleOfValidation(example interface{}) bool {
+	if !eg.hasMultipleOfValidation() {
+		return true
+	}
+	v, ok := exampleNumber(example)
+	if !ok {
+		return true
+	}
+	q := v / *eg.a.Validation.MultipleOf
+	return math.Abs(q-math.Floor(q+0.5)) <= 1e-9*math.Max(1, math.Abs(q))
+}
+
+// generateValidatedMultipleOfExample generates a random multiple of the multipleOf validation
+// value that lies within the min and max validations if any.
+func (eg *exampleGenerator) generateValidatedMultipleOfExample() interface{} {
+	if !eg.hasMultipleOfValidation() {
+		return nil
+	}
+	m := *eg.a.Validation.MultipleOf
+	lo, hi := 1.0, 10.0
+	if eg.hasMinMaxValidation() {
+		min, max := eg.minMax()
+		switch {
+		case !math.IsInf(min, 1) && !math.IsInf(max, -1):
+			lo, hi = math.Ceil(min/m), math.Floor(max/m)
+		case !math.IsInf(min, 1):
+			lo = math.Ceil(min / m)
+			hi = lo + 9
+		default:
+			hi = math.Floor(max / m)
+			lo = hi - 9
+		}
+	}
+	k := lo
+	if span := hi - lo; span > 0 {
+		if span > maxAttempts {
+			span = maxAttempts
+		}
+		k += float64(eg.r.Int() % (int(span) + 1))
+	}
+	if IsInteger(eg.a.Type) {
+		return int(k * m)
+	}
+	return k * m
+}
+
+// exampleNumber returns the float64 value of a numerical example.
+func exampleNumber(example interface{}) (float64, bool) {
+	v := reflect.ValueOf(example)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
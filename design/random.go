@@ -74,6 +74,38 @@ func (r *RandomGenerator) Float64() float64 {
 	return r.rand.Float64()
 }
 
+// Int32 produces a random non-negative int32 value.
+func (r *RandomGenerator) Int32() int32 {
+	return r.rand.Int31()
+}
+
+// Int64 produces a random non-negative int64 value.
+func (r *RandomGenerator) Int64() int64 {
+	return r.rand.Int63()
+}
+
+// Uint32 produces a random uint32 value.
+func (r *RandomGenerator) Uint32() uint32 {
+	return r.rand.Uint32()
+}
+
+// Float32 produces a random float32 value.
+func (r *RandomGenerator) Float32() float32 {
+	return r.rand.Float32()
+}
+
+// Date produces a random date with no time component.
+func (r *RandomGenerator) Date() time.Time {
+	return r.DateTime().Truncate(24 * time.Hour)
+}
+
+// Bytes produces a random byte slice of up to 32 bytes.
+func (r *RandomGenerator) Bytes() []byte {
+	b := make([]byte, r.rand.Intn(32)+1)
+	r.rand.Read(b)
+	return b
+}
+
 // File produces a random file.
 func (r *RandomGenerator) File() string {
 	return fmt.Sprintf("%sjpg", r.faker.Sentence(1, false))
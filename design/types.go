@@ -10,13 +10,16 @@
 package design
 
 import (
+	"encoding/base64"
 	"fmt"
+	"math"
 	"mime"
 	"reflect"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/satori/go.uuid"
 )
@@ -58,6 +61,12 @@ type (
 		// ToHash returns the underlying hash map if any (i.e. if IsHash returns true),
 		// nil otherwise.
 		ToHash() *Hash
+		// IsUnion returns true if the underlying type is a union, a user type which is
+		// a union or a media type whose type is a union.
+		IsUnion() bool
+		// ToUnion returns the underlying union if any (i.e. if IsUnion returns true),
+		// nil otherwise.
+		ToUnion() *Union
 		// CanHaveDefault returns whether the data type can have a default value.
 		CanHaveDefault() bool
 		// IsCompatible checks whether val has a Go type that is
@@ -106,6 +115,15 @@ type (
 	// HashVal is the value of a hash used to specify the default value.
 	HashVal map[interface{}]interface{}
 
+	// Union is the type for a JSON object that may be any one of a set of user types. The
+	// value of the discriminator attribute identifies the actual type.
+	Union struct {
+		// Discriminator is the name of the attribute whose value identifies the type.
+		Discriminator string
+		// Types lists the user types that make up the union.
+		Types []*UserTypeDefinition
+	}
+
 	// UserTypeDefinition is the type for user defined types that are not media types
 	// (e.g. payload types).
 	UserTypeDefinition struct {
@@ -165,6 +183,24 @@ const (
 	MediaTypeKind
 	// FileKind represents a file.
 	FileKind
+	// UnionKind represents a JSON object that may be one of a set of user types.
+	UnionKind
+	// Int32Kind represents a JSON integer that fits in a signed 32-bit integer.
+	Int32Kind
+	// Int64Kind represents a JSON integer that fits in a signed 64-bit integer.
+	Int64Kind
+	// UInt32Kind represents a JSON integer that fits in an unsigned 32-bit integer.
+	UInt32Kind
+	// UInt64Kind represents a JSON integer that fits in an unsigned 64-bit integer.
+	UInt64Kind
+	// Float32Kind represents a JSON number that fits in a single precision float.
+	Float32Kind
+	// Float64Kind represents a JSON number that fits in a double precision float.
+	Float64Kind
+	// DateKind represents a JSON string that is parsed as a Go goa.Date
+	DateKind
+	// BytesKind represents a base64 encoded JSON string that is decoded as a Go []byte.
+	BytesKind
 )
 
 const (
@@ -193,6 +229,33 @@ const (
 
 	// File is the type for a file. This type can only be used in a multipart definition.
 	File = Primitive(FileKind)
+
+	// Int32 is the type for a JSON integer parsed as a Go int32.
+	Int32 = Primitive(Int32Kind)
+
+	// Int64 is the type for a JSON integer parsed as a Go int64.
+	Int64 = Primitive(Int64Kind)
+
+	// UInt32 is the type for a JSON integer parsed as a Go uint32.
+	UInt32 = Primitive(UInt32Kind)
+
+	// UInt64 is the type for a JSON integer parsed as a Go uint64.
+	UInt64 = Primitive(UInt64Kind)
+
+	// Float32 is the type for a JSON number parsed as a Go float32.
+	Float32 = Primitive(Float32Kind)
+
+	// Float64 is the type for a JSON number parsed as a Go float64.
+	Float64 = Primitive(Float64Kind)
+
+	// Date is the type for a JSON string parsed as a Go goa.Date.
+	// Date expects an RFC3339 full-date formatted value (e.g. "2006-01-02").
+	Date = Primitive(DateKind)
+
+	// Bytes is the type for a JSON string parsed as a Go []byte.
+	// Bytes expects a standard base64 encoded value, length validations apply to the decoded
+	// bytes.
+	Bytes = Primitive(BytesKind)
 )
 
 // DataType implementation
@@ -205,11 +268,11 @@ func (p Primitive) Name() string {
 	switch p {
 	case Boolean:
 		return "boolean"
-	case Integer:
+	case Integer, Int32, Int64, UInt32, UInt64:
 		return "integer"
-	case Number:
+	case Number, Float32, Float64:
 		return "number"
-	case String, DateTime, UUID:
+	case String, DateTime, UUID, Date, Bytes:
 		return "string"
 	case Any:
 		return "any"
@@ -244,30 +307,92 @@ func (p Primitive) ToArray() *Array { return nil }
 // ToHash returns nil.
 func (p Primitive) ToHash() *Hash { return nil }
 
+// IsUnion returns false.
+func (p Primitive) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (p Primitive) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns whether the primitive can have a default value.
 func (p Primitive) CanHaveDefault() (ok bool) {
 	switch p {
-	case Boolean, Integer, Number, String, DateTime:
+	case Boolean, Integer, Number, String, DateTime, Int32, Int64, UInt32, UInt64, Float32, Float64, Date:
 		ok = true
 	}
 	return
 }
 
+// IsInteger returns true if the given type is one of the integer primitive types.
+func IsInteger(t DataType) bool {
+	switch t.Kind() {
+	case IntegerKind, Int32Kind, Int64Kind, UInt32Kind, UInt64Kind:
+		return true
+	}
+	return false
+}
+
+// IsNumber returns true if the given type is one of the integer or floating point number
+// primitive types.
+func IsNumber(t DataType) bool {
+	switch t.Kind() {
+	case NumberKind, Float32Kind, Float64Kind:
+		return true
+	}
+	return IsInteger(t)
+}
+
+// Bounds returns the smallest and largest values that can be represented by the sized number
+// primitive type. ok is false if p is not a sized number type.
+func (p Primitive) Bounds() (min, max float64, ok bool) {
+	switch p {
+	case Int32:
+		return math.MinInt32, math.MaxInt32, true
+	case Int64:
+		return math.MinInt64, math.MaxInt64, true
+	case UInt32:
+		return 0, math.MaxUint32, true
+	case UInt64:
+		return 0, math.MaxUint64, true
+	case Float32:
+		return -math.MaxFloat32, math.MaxFloat32, true
+	case Float64:
+		return -math.MaxFloat64, math.MaxFloat64, true
+	}
+	return 0, 0, false
+}
+
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	switch p {
+	case Boolean, Integer, Number, String, DateTime, UUID, Any, File,
+		Int32, Int64, UInt32, UInt64, Float32, Float64, Date, Bytes:
+	default:
 		panic("unknown primitive type") // bug
 	}
 	if p == Any {
 		return true
 	}
-	switch val.(type) {
+	switch v := val.(type) {
 	case bool:
 		return p == Boolean
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return p == Integer || p == Number
+		if !IsNumber(p) {
+			return false
+		}
+		if min, max, ok := p.Bounds(); ok {
+			f := reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float()
+			return f >= min && f <= max
+		}
+		return true
 	case float32, float64:
-		return p == Number
+		if !IsNumber(p) || IsInteger(p) {
+			return false
+		}
+		if min, max, ok := p.Bounds(); ok {
+			f := reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float()
+			return f >= min && f <= max
+		}
+		return true
 	case string:
 		if p == String {
 			return true
@@ -280,6 +405,16 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 			_, err := uuid.FromString(val.(string))
 			return err == nil
 		}
+		if p == Date {
+			_, err := time.Parse(goa.DateLayout, val.(string))
+			return err == nil
+		}
+		if p == Bytes {
+			_, err := base64.StdEncoding.DecodeString(val.(string))
+			return err == nil
+		}
+	case []byte:
+		return p == Bytes
 	}
 	return false
 }
@@ -306,6 +441,20 @@ func (p Primitive) GenerateExample(r *RandomGenerator, seen []string) interface{
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r, seen)
 	case File:
 		return r.File()
+	case Int32:
+		return int(r.Int32())
+	case Int64, UInt64:
+		return int(r.Int64())
+	case UInt32:
+		return int(r.Uint32())
+	case Float32:
+		return float64(r.Float32())
+	case Float64:
+		return r.Float64()
+	case Date:
+		return r.Date().Format(goa.DateLayout) // Generate string so the value is JSON marshaled properly
+	case Bytes:
+		return base64.StdEncoding.EncodeToString(r.Bytes()) // Generate string so the value is JSON marshaled properly
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -345,6 +494,12 @@ func (a *Array) ToArray() *Array { return a }
 // ToHash returns nil.
 func (a *Array) ToHash() *Hash { return nil }
 
+// IsUnion returns false.
+func (a *Array) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (a *Array) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns true if the array type can have a default value.
 // The array type can have a default value only if the element type can
 // have a default value.
@@ -418,6 +573,12 @@ func (o Object) ToArray() *Array { return nil }
 // ToHash returns nil.
 func (o Object) ToHash() *Hash { return nil }
 
+// IsUnion returns false.
+func (o Object) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (o Object) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns false.
 func (o Object) CanHaveDefault() bool { return false }
 
@@ -484,6 +645,12 @@ func (h *Hash) ToArray() *Array { return nil }
 // ToHash returns the underlying hash map.
 func (h *Hash) ToHash() *Hash { return h }
 
+// IsUnion returns false.
+func (h *Hash) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (h *Hash) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns true if the hash type can have a default value.
 // The hash type can have a default value only if both the key type and
 // the element type can have a default value.
@@ -528,6 +695,94 @@ func (h *Hash) MakeMap(m map[interface{}]interface{}) interface{} {
 	return hash.Interface()
 }
 
+// Kind implements DataKind.
+func (u *Union) Kind() Kind { return UnionKind }
+
+// Name returns the type name.
+func (u *Union) Name() string { return "union" }
+
+// IsPrimitive returns false.
+func (u *Union) IsPrimitive() bool { return false }
+
+// HasAttributes returns true.
+func (u *Union) HasAttributes() bool { return true }
+
+// IsObject returns false.
+func (u *Union) IsObject() bool { return false }
+
+// IsArray returns false.
+func (u *Union) IsArray() bool { return false }
+
+// IsHash returns false.
+func (u *Union) IsHash() bool { return false }
+
+// ToObject returns nil.
+func (u *Union) ToObject() Object { return nil }
+
+// ToArray returns nil.
+func (u *Union) ToArray() *Array { return nil }
+
+// ToHash returns nil.
+func (u *Union) ToHash() *Hash { return nil }
+
+// IsUnion returns true.
+func (u *Union) IsUnion() bool { return true }
+
+// ToUnion returns the underlying union.
+func (u *Union) ToUnion() *Union { return u }
+
+// CanHaveDefault returns false.
+func (u *Union) CanHaveDefault() bool { return false }
+
+// IsCompatible returns true if val is compatible with one of the union types.
+func (u *Union) IsCompatible(val interface{}) bool {
+	for _, t := range u.Types {
+		if t.IsCompatible(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateExample returns a random value of one of the union types.
+func (u *Union) GenerateExample(r *RandomGenerator, seen []string) interface{} {
+	if len(u.Types) == 0 {
+		return nil
+	}
+	t := u.Types[r.Int()%len(u.Types)]
+	ex, ok := t.GenerateExample(r, seen).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	res := make(map[string]interface{}, len(ex)+1)
+	for k, v := range ex {
+		res[k] = v
+	}
+	res[u.Discriminator] = u.DiscriminatorValue(t)
+	return res
+}
+
+// DiscriminatorValue returns the value of the discriminator attribute that identifies the
+// given union type. This is the single value listed in the Enum validation of the type
+// discriminator attribute if any, the name of the type otherwise.
+func (u *Union) DiscriminatorValue(t *UserTypeDefinition) string {
+	if o := t.ToObject(); o != nil {
+		if att, ok := o[u.Discriminator]; ok && att.Validation != nil && len(att.Validation.Values) == 1 {
+			return fmt.Sprintf("%v", att.Validation.Values[0])
+		}
+	}
+	return t.TypeName
+}
+
+// DiscriminatorValues returns the discriminator values of all the union types in order.
+func (u *Union) DiscriminatorValues() []string {
+	values := make([]string, len(u.Types))
+	for i, t := range u.Types {
+		values[i] = u.DiscriminatorValue(t)
+	}
+	return values
+}
+
 // AttributeIterator is the type of the function given to IterateAttributes.
 type AttributeIterator func(string, *AttributeDefinition) error
 
@@ -595,6 +850,14 @@ func UserTypes(dt DataType) map[string]*UserTypeDefinition {
 		types := map[string]*UserTypeDefinition{actual.TypeName: actual.UserTypeDefinition}
 		actual.Walk(collect(types))
 		return types
+	case *Union:
+		types := make(map[string]*UserTypeDefinition)
+		for _, t := range actual.Types {
+			for n, ut := range UserTypes(t) {
+				types[n] = ut
+			}
+		}
+		return types
 	default:
 		panic("unknown type") // bug
 	}
@@ -636,6 +899,12 @@ func hasFile(dt DataType, seen map[string]struct{}) bool {
 				return true
 			}
 		}
+	case dt.IsUnion():
+		for _, t := range dt.ToUnion().Types {
+			if hasFile(t, seen) {
+				return true
+			}
+		}
 	default:
 		panic("unknown type") // bug
 	}
@@ -713,6 +982,12 @@ func (u *UserTypeDefinition) ToArray() *Array { return u.Type.ToArray() }
 // ToHash calls ToHash on the user type underlying data type.
 func (u *UserTypeDefinition) ToHash() *Hash { return u.Type.ToHash() }
 
+// IsUnion calls IsUnion on the user type underlying data type.
+func (u *UserTypeDefinition) IsUnion() bool { return u.Type != nil && u.Type.IsUnion() }
+
+// ToUnion calls ToUnion on the user type underlying data type.
+func (u *UserTypeDefinition) ToUnion() *Union { return u.Type.ToUnion() }
+
 // CanHaveDefault calls CanHaveDefault on the user type underlying data type.
 func (u *UserTypeDefinition) CanHaveDefault() bool { return u.Type.CanHaveDefault() }
 
@@ -728,6 +1003,7 @@ func (u *UserTypeDefinition) Finalize() {
 			u.AttributeDefinition.Inherit(bat)
 		}
 	}
+	u.setEnumTypeNames()
 
 	u.GenerateExample(Design.RandomGenerator(), nil)
 }
@@ -754,7 +1030,8 @@ func (m *MediaTypeDefinition) IsError() bool {
 		panic("invalid media type identifier " + m.Identifier) // bug
 	}
 	delete(params, "view")
-	return mime.FormatMediaType(base, params) == ErrorMedia.Identifier
+	id := mime.FormatMediaType(base, params)
+	return id == ErrorMedia.Identifier || id == ProblemMedia.Identifier
 }
 
 // ComputeViews returns the media type views recursing as necessary if the media type is a
@@ -824,6 +1101,54 @@ func (m *MediaTypeDefinition) Project(view string) (*MediaTypeDefinition, *UserT
 	return m.projectSingle(view, canonical)
 }
 
+// projectConditionals removes the attributes missing from the given view object from the
+// conditional required validations.
+func projectConditionals(val *dslengine.ValidationDefinition, viewObj Object) {
+	inView := func(names []string) []string {
+		var res []string
+		for _, n := range names {
+			if _, ok := viewObj[n]; ok {
+				res = append(res, n)
+			}
+		}
+		return res
+	}
+	var requiredIf []*dslengine.RequiredIfDefinition
+	for _, r := range val.RequiredIf {
+		if _, ok := viewObj[r.Attribute]; !ok {
+			continue
+		}
+		if required := inView(r.Required); len(required) > 0 {
+			requiredIf = append(requiredIf, &dslengine.RequiredIfDefinition{
+				Attribute: r.Attribute,
+				Value:     r.Value,
+				Required:  required,
+			})
+		}
+	}
+	val.RequiredIf = requiredIf
+	var deps map[string][]string
+	for n, required := range val.DependentRequired {
+		if _, ok := viewObj[n]; !ok {
+			continue
+		}
+		if required = inView(required); len(required) > 0 {
+			if deps == nil {
+				deps = make(map[string][]string)
+			}
+			deps[n] = required
+		}
+	}
+	val.DependentRequired = deps
+	var exclusive [][]string
+	for _, group := range val.MutuallyExclusive {
+		if names := inView(group); len(names) > 1 {
+			exclusive = append(exclusive, names)
+		}
+	}
+	val.MutuallyExclusive = exclusive
+}
+
 func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTypeDefinition, links *UserTypeDefinition, err error) {
 	v, ok := m.Views[view]
 	if !ok {
@@ -843,6 +1168,7 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 		}
 		val = m.Validation.Dup()
 		val.Required = required
+		projectConditionals(val, viewObj)
 	}
 
 	// Compute description
@@ -1065,6 +1391,12 @@ func walk(at *AttributeDefinition, walker func(*AttributeDefinition) error, seen
 		return walkUt(actual)
 	case *MediaTypeDefinition:
 		return walkUt(actual.UserTypeDefinition)
+	case *Union:
+		for _, t := range actual.Types {
+			if err := walk(&AttributeDefinition{Type: t}, walker, seen); err != nil {
+				return err
+			}
+		}
 	default:
 		panic("unknown attribute type") // bug
 	}
@@ -1076,15 +1408,15 @@ func toReflectType(dtype DataType) reflect.Type {
 	switch dtype.Kind() {
 	case BooleanKind:
 		return reflect.TypeOf(true)
-	case IntegerKind:
+	case IntegerKind, Int32Kind, Int64Kind, UInt32Kind, UInt64Kind:
 		return reflect.TypeOf(int(0))
-	case NumberKind:
+	case NumberKind, Float32Kind, Float64Kind:
 		return reflect.TypeOf(float64(0))
-	case UUIDKind, StringKind:
+	case UUIDKind, StringKind, DateKind, BytesKind:
 		return reflect.TypeOf("")
 	case DateTimeKind:
 		return reflect.TypeOf(time.Time{})
-	case ObjectKind, UserTypeKind, MediaTypeKind:
+	case ObjectKind, UserTypeKind, MediaTypeKind, UnionKind:
 		return reflect.TypeOf(map[string]interface{}{})
 	case ArrayKind:
 		return reflect.SliceOf(toReflectType(dtype.ToArray().ElemType.Type))
@@ -2,6 +2,7 @@ package design_test
 
 import (
 	"errors"
+	"math"
 	"mime"
 	"sync"
 
@@ -602,4 +603,173 @@ var _ = Describe("GenerateExample", func() {
 			Ω(h.GenerateExample(rand, nil)).Should(BeAssignableToTypeOf(map[string]string{"foo": "bar"}))
 		})
 	})
+
+	Context("Given an integer attribute with exclusive bounds and a multiple of validation", func() {
+		It("generates a valid example", func() {
+			min, max, m := 10.0, 50.0, 5.0
+			att := &AttributeDefinition{
+				Type: Integer,
+				Validation: &dslengine.ValidationDefinition{
+					ExclusiveMinimum: &min,
+					ExclusiveMaximum: &max,
+					MultipleOf:       &m,
+				},
+			}
+			for i := 0; i < 10; i++ {
+				att.Example = nil
+				ex := att.GenerateExample(NewRandomGenerator(string(rune('a'+i))), nil)
+				Ω(ex).Should(BeAssignableToTypeOf(0))
+				Ω(ex.(int)).Should(BeNumerically(">", 10))
+				Ω(ex.(int)).Should(BeNumerically("<", 50))
+				Ω(ex.(int) % 5).Should(Equal(0))
+			}
+		})
+	})
+
+	Context("Given an array attribute with a unique items validation", func() {
+		It("generates an example with distinct elements", func() {
+			min := 3
+			att := &AttributeDefinition{
+				Type: &Array{ElemType: &AttributeDefinition{Type: Integer}},
+				Validation: &dslengine.ValidationDefinition{
+					MinLength:   &min,
+					UniqueItems: true,
+				},
+			}
+			ex := att.GenerateExample(NewRandomGenerator("foo"), nil)
+			Ω(ex).Should(BeAssignableToTypeOf([]int{}))
+			elems := ex.([]int)
+			Ω(len(elems)).Should(BeNumerically(">=", 3))
+			seen := make(map[int]bool)
+			for _, e := range elems {
+				Ω(seen).ShouldNot(HaveKey(e))
+				seen[e] = true
+			}
+		})
+	})
+
+	Context("Given a hash attribute with a min properties validation", func() {
+		It("generates an example with enough entries", func() {
+			min := 2
+			att := &AttributeDefinition{
+				Type: &Hash{
+					KeyType:  &AttributeDefinition{Type: String},
+					ElemType: &AttributeDefinition{Type: Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{MinProperties: &min},
+			}
+			ex := att.GenerateExample(NewRandomGenerator("foo"), nil)
+			Ω(ex).Should(BeAssignableToTypeOf(map[string]int{}))
+			Ω(len(ex.(map[string]int))).Should(BeNumerically(">=", 2))
+		})
+	})
+})
+
+var _ = Describe("IsCompatible", func() {
+	var t Primitive
+	var val interface{}
+	var compatible bool
+
+	JustBeforeEach(func() {
+		compatible = t.IsCompatible(val)
+	})
+
+	Context("with an int32 value in range", func() {
+		BeforeEach(func() {
+			t = Int32
+			val = 42
+		})
+
+		It("is compatible", func() {
+			Ω(compatible).Should(BeTrue())
+		})
+	})
+
+	Context("with an int32 value out of range", func() {
+		BeforeEach(func() {
+			t = Int32
+			val = math.MaxInt32 + 1
+		})
+
+		It("is not compatible", func() {
+			Ω(compatible).Should(BeFalse())
+		})
+	})
+
+	Context("with a negative uint32 value", func() {
+		BeforeEach(func() {
+			t = UInt32
+			val = -1
+		})
+
+		It("is not compatible", func() {
+			Ω(compatible).Should(BeFalse())
+		})
+	})
+
+	Context("with a float value for an integer type", func() {
+		BeforeEach(func() {
+			t = Int64
+			val = 1.5
+		})
+
+		It("is not compatible", func() {
+			Ω(compatible).Should(BeFalse())
+		})
+	})
+
+	Context("with an integer value for a float32 type", func() {
+		BeforeEach(func() {
+			t = Float32
+			val = 3
+		})
+
+		It("is compatible", func() {
+			Ω(compatible).Should(BeTrue())
+		})
+	})
+
+	Context("with a valid date", func() {
+		BeforeEach(func() {
+			t = Date
+			val = "2017-02-28"
+		})
+
+		It("is compatible", func() {
+			Ω(compatible).Should(BeTrue())
+		})
+	})
+
+	Context("with an invalid date", func() {
+		BeforeEach(func() {
+			t = Date
+			val = "2017-02-30"
+		})
+
+		It("is not compatible", func() {
+			Ω(compatible).Should(BeFalse())
+		})
+	})
+
+	Context("with a base64 encoded string", func() {
+		BeforeEach(func() {
+			t = Bytes
+			val = "Z29h"
+		})
+
+		It("is compatible", func() {
+			Ω(compatible).Should(BeTrue())
+		})
+	})
+
+	Context("with a string that is not base64 encoded", func() {
+		BeforeEach(func() {
+			t = Bytes
+			val = "goa!"
+		})
+
+		It("is not compatible", func() {
+			Ω(compatible).Should(BeFalse())
+		})
+	})
 })
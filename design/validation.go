@@ -131,6 +131,24 @@ func (a *APIDefinition) Validate() error {
 		verr.Merge(r.Validate())
 		return nil
 	})
+	webhooks := make(map[string]bool)
+	a.IterateWebhooks(func(w *WebhookDefinition) error {
+		if webhooks[w.Name] {
+			verr.Add(w, "webhook %s is defined more than once", w.Name)
+		}
+		webhooks[w.Name] = true
+		verr.Merge(w.Validate())
+		return nil
+	})
+	errs := make(map[string]bool)
+	a.IterateErrors(func(e *ErrorDefinition) error {
+		if errs[e.Name] {
+			verr.Add(e, "error %s is defined more than once", e.Name)
+		}
+		errs[e.Name] = true
+		verr.Merge(e.Validate())
+		return nil
+	})
 	for _, dec := range a.Consumes {
 		verr.Merge(dec.Validate())
 	}
@@ -225,6 +243,9 @@ func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Params != nil {
 		verr.Merge(r.Params.Validate("resource parameters", r))
 	}
+	if r.Cookies != nil {
+		verr.Merge(validateCookies(r.Cookies, r))
+	}
 	for _, origin := range r.Origins {
 		verr.Merge(origin.Validate())
 	}
@@ -341,12 +362,17 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 		}
 	}
 	verr.Merge(a.ValidateParams())
+	if a.Cookies != nil {
+		verr.Merge(validateCookies(a.Cookies, a))
+	}
 	if a.Payload != nil {
 		verr.Merge(a.Payload.Validate("action payload", a))
 		if HasFile(a.Payload.Type) && a.PayloadMultipart != true {
 			verr.Add(a, "Payload %s contains an invalid type, action payloads cannot contain a file", a.Payload.TypeName)
 		}
 	}
+	verr.Merge(a.validateMessages())
+	verr.Merge(a.validateAsync())
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
 	}
@@ -366,6 +392,10 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 					continue
 				}
 			}
+			if p.Type.IsHash() && p.ParamStyle() == ParamStyleDeepObject {
+				// Element types are checked by ValidateParams
+				continue
+			}
 			verr.Add(a, "Param %s has an invalid type, action params must be primitives or arrays of primitives", n)
 		}
 	}
@@ -373,6 +403,44 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	return verr.AsError()
 }
 
+// Validate checks that the webhook defines a payload that can be sent in a request body.
+func (w *WebhookDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if w.Name == "" {
+		verr.Add(w, "webhook name cannot be empty")
+	}
+	if w.Payload == nil {
+		verr.Add(w, "webhook must define a payload")
+	} else {
+		verr.Merge(w.Payload.Validate("webhook payload", w))
+		if !w.Payload.IsObject() {
+			verr.Add(w, "webhook payload must be an object")
+		}
+		if HasFile(w.Payload.Type) {
+			verr.Add(w, "webhook payload cannot contain a file")
+		}
+	}
+	for _, r := range w.Responses {
+		verr.Merge(r.Validate())
+	}
+	return verr
+}
+
+// Validate checks that the error status can be rendered and that its metadata is an object.
+func (e *ErrorDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if e.Name == "" {
+		verr.Add(e, "error name cannot be empty")
+	}
+	if e.Status < 400 || e.ResponseName() == "" {
+		verr.Add(e, "invalid error status %d, must be a 4xx or 5xx HTTP status", e.Status)
+	}
+	if e.Meta != nil && !e.Meta.IsObject() {
+		verr.Add(e, "error meta type must be an object")
+	}
+	return verr
+}
+
 // Validate checks the file server is properly initialized.
 func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -398,6 +466,97 @@ func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	return verr.AsError()
 }
 
+// validateCookies checks the given request cookies are all of primitive types.
+func validateCookies(cookies *AttributeDefinition, parent dslengine.Definition) *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	for n, c := range cookies.Type.ToObject() {
+		if !c.Type.IsPrimitive() || HasFile(c.Type) {
+			verr.Add(parent, "Cookie %s has an invalid type, cookies must be primitives", n)
+		}
+	}
+	verr.Merge(cookies.Validate("cookies", parent))
+	return verr
+}
+
+// validateMessages checks that the inbound and outbound message types are only defined on
+// websocket actions and describe objects.
+func (a *ActionDefinition) validateMessages() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	kinds := []string{"inbound", "outbound"}
+	for i, m := range []DataType{a.InboundMessage, a.OutboundMessage} {
+		if m == nil {
+			continue
+		}
+		kind := kinds[i]
+		if !a.WebSocket() {
+			verr.Add(a, "%s message defined on an action that does not use the ws or wss schemes", kind)
+		}
+		if !m.IsObject() {
+			verr.Add(a, "%s message type must be an object", kind)
+		}
+		if HasFile(m) {
+			verr.Add(a, "%s message type cannot contain a file", kind)
+		}
+	}
+	return verr
+}
+
+// validateAsync checks that the status media type of a long-running action describes the job
+// state.
+func (a *ActionDefinition) validateAsync() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if a.Async == nil {
+		return verr
+	}
+	if a.WebSocket() {
+		verr.Add(a, "long running action cannot use the ws or wss schemes")
+	}
+	mt := Design.MediaTypeWithIdentifier(a.Async.StatusMediaType)
+	if mt == nil {
+		verr.Add(a, "unknown long running status media type %s", a.Async.StatusMediaType)
+		return verr
+	}
+	state, ok := mt.Type.ToObject()["state"]
+	if !ok || state.Type.Kind() != StringKind || !mt.IsRequired("state") {
+		verr.Add(a, `long running status media type %s must define a required "state" string attribute`, mt.Identifier)
+	}
+	return verr
+}
+
+// validateParamStyle checks that the serialization style of the given parameter is consistent
+// with its type and location.
+func validateParamStyle(a *ActionDefinition, n string, p *AttributeDefinition, wcs []string) *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if p.Style == "" && p.Explode == nil {
+		return verr
+	}
+	for _, wc := range wcs {
+		if wc == n {
+			verr.Add(a, "parameter %s is a path parameter, Style and Explode only apply to query string parameters", n)
+			return verr
+		}
+	}
+	switch p.ParamStyle() {
+	case ParamStyleDeepObject:
+		// Objects are reported by ValidateParams.
+		h := p.Type.ToHash()
+		if h == nil && p.Type.Kind() != ObjectKind || h != nil && (h.KeyType.Type.Kind() != StringKind || h.ElemType.Type.Kind() != StringKind) {
+			verr.Add(a, "parameter %s must be a hash of strings to strings to use the deepObject style", n)
+		}
+		if !p.IsExploded() {
+			verr.Add(a, "parameter %s cannot use the deepObject style with Explode(false)", n)
+		}
+	case ParamStyleSpaceDelimited, ParamStylePipeDelimited:
+		if !p.Type.IsArray() {
+			verr.Add(a, "parameter %s must be an array to use the %s style", n, p.Style)
+		}
+		if p.IsExploded() {
+			verr.Add(a, "parameter %s cannot use the %s style with Explode(true)", n, p.Style)
+		}
+	}
+	return verr
+}
+
 // ValidateParams checks the action parameters (make sure they have names, members and types).
 func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -432,11 +591,14 @@ func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 		} else if p.Type == nil {
 			verr.Add(a, "type of parameter %s cannot be nil", n)
 		}
-		if p.Type.Kind() == ObjectKind {
+		if p.Type.Kind() == ObjectKind && p.ParamStyle() == ParamStyleDeepObject {
+			verr.Add(a, `parameter %s cannot be an object, the deepObject style only supports parameters of type HashOf(String, String)`, n)
+		} else if p.Type.Kind() == ObjectKind {
 			verr.Add(a, `parameter %s cannot be an object, only action payloads may be of type object`, n)
-		} else if p.Type.Kind() == HashKind {
+		} else if p.Type.Kind() == HashKind && p.ParamStyle() != ParamStyleDeepObject {
 			verr.Add(a, `parameter %s cannot be a hash, only action payloads may be of type hash`, n)
 		}
+		verr.Merge(validateParamStyle(a, n, p, wcs))
 		ctx := fmt.Sprintf("parameter %s", n)
 		verr.Merge(p.Validate(ctx, a))
 	}
@@ -446,6 +608,57 @@ func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 	return verr.AsError()
 }
 
+// validateConditionals makes sure the conditional required validations of the object attribute a
+// refer to existing fields.
+func (a *AttributeDefinition) validateConditionals(ctx string, parent dslengine.Definition) *dslengine.ValidationErrors {
+	if a.Validation == nil {
+		return nil
+	}
+	verr := new(dslengine.ValidationErrors)
+	o := a.Type.ToObject()
+	exist := func(kind string, names ...string) bool {
+		ok := true
+		for _, n := range names {
+			if _, found := o[n]; !found {
+				verr.Add(parent, `%s%s field "%s" does not exist`, ctx, kind, n)
+				ok = false
+			}
+		}
+		return ok
+	}
+	for _, r := range a.Validation.RequiredIf {
+		if exist("condition", r.Attribute) {
+			att := o[r.Attribute]
+			if k := att.Type.Kind(); k != BooleanKind && k != StringKind && !IsNumber(att.Type) {
+				verr.Add(parent, `%scondition field "%s" must be a boolean, integer, number or string`, ctx, r.Attribute)
+			} else if !att.Type.IsCompatible(r.Value) {
+				verr.Add(parent, `%scondition value %#v is incompatible with the type of field "%s"`, ctx, r.Value, r.Attribute)
+			}
+		}
+		exist("conditionally required", r.Required...)
+	}
+	names := make([]string, 0, len(a.Validation.DependentRequired))
+	for n := range a.Validation.DependentRequired {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		exist("dependency", n)
+		exist("dependent required", a.Validation.DependentRequired[n]...)
+	}
+	for _, group := range a.Validation.MutuallyExclusive {
+		if !exist("mutually exclusive", group...) {
+			continue
+		}
+		for _, n := range group {
+			if a.IsRequired(n) || a.HasDefaultValue(n) {
+				verr.Add(parent, `%smutually exclusive field "%s" cannot be required or have a default value`, ctx, n)
+			}
+		}
+	}
+	return verr.AsError()
+}
+
 // validated keeps track of validated attributes to handle cyclical definitions.
 var validated = make(map[*AttributeDefinition]bool)
 
@@ -481,6 +694,13 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			verr.Add(parent, "%sdefault value %#v is not one of the accepted values: %#v", ctx, a.DefaultValue, a.Validation.Values)
 		}
 	}
+	if a.IsNullable() {
+		if !a.Type.IsPrimitive() || a.Type.Kind() == FileKind {
+			verr.Add(parent, "%sonly attributes of primitive types other than file can be nullable", ctx)
+		} else if a.DefaultValue != nil {
+			verr.Add(parent, "%snullable attributes cannot have a default value", ctx)
+		}
+	}
 	o := a.Type.ToObject()
 	if o != nil {
 		for _, n := range a.AllRequired() {
@@ -495,10 +715,13 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 				verr.Add(parent, `%srequired field "%s" does not exist`, ctx, n)
 			}
 		}
+		verr.Merge(a.validateConditionals(ctx, parent))
 		for n, att := range o {
 			ctx = fmt.Sprintf("field %s", n)
 			verr.Merge(att.Validate(ctx, parent))
 		}
+	} else if u, ok := a.Type.(*Union); ok {
+		verr.Merge(u.validate(ctx, parent))
 	} else {
 		if a.Type.IsArray() {
 			elemType := a.Type.ToArray().ElemType
@@ -509,13 +732,80 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 	return verr.AsError()
 }
 
-// Validate checks that the response definition is consistent: its status is set and the media
-// type definition if any is valid.
+// validate checks that the union definition is consistent: it has a discriminator and its types
+// are objects that define the discriminator as a string attribute with distinct values.
+func (u *Union) validate(ctx string, parent dslengine.Definition) *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if u.Discriminator == "" {
+		verr.Add(parent, "%sunion discriminator cannot be empty", ctx)
+	}
+	if len(u.Types) == 0 {
+		verr.Add(parent, "%sunion must list at least one type", ctx)
+	}
+	values := make(map[string]string)
+	for _, t := range u.Types {
+		o := t.ToObject()
+		if o == nil {
+			verr.Add(parent, "%sunion type %s must be an object", ctx, t.TypeName)
+			continue
+		}
+		if u.Discriminator == "" {
+			continue
+		}
+		att, ok := o[u.Discriminator]
+		if !ok {
+			verr.Add(parent, `%sunion type %s does not define discriminator attribute "%s"`, ctx, t.TypeName, u.Discriminator)
+			continue
+		}
+		if att.Type.Kind() != StringKind {
+			verr.Add(parent, `%sdiscriminator attribute "%s" of union type %s must be a string`, ctx, u.Discriminator, t.TypeName)
+			continue
+		}
+		v := u.DiscriminatorValue(t)
+		if other, ok := values[v]; ok {
+			verr.Add(parent, `%sunion types %s and %s use the same discriminator value "%s"`, ctx, other, t.TypeName, v)
+			continue
+		}
+		values[v] = t.TypeName
+	}
+	return verr.AsError()
+}
+
+// Validate checks that the response definition is consistent: its status is set, the media
+// type definition if any is valid and its headers and cookies are primitives with distinct names.
 func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
 	if r.Headers != nil {
+		for n, h := range r.Headers.Type.ToObject() {
+			if !h.Type.IsPrimitive() || HasFile(h.Type) {
+				verr.Add(r, "Header %s has an invalid type, response headers must be primitives", n)
+			}
+		}
 		verr.Merge(r.Headers.Validate("response headers", r))
 	}
+	if r.Cookies != nil {
+		verr.Merge(validateCookies(r.Cookies, r))
+		if r.Headers != nil {
+			for n := range r.Cookies.Type.ToObject() {
+				for h := range r.Headers.Type.ToObject() {
+					if strings.EqualFold(n, h) {
+						verr.Add(r, "Cookie %s has the same name as header %s", n, h)
+					}
+				}
+			}
+		}
+	}
+	if r.Stream != nil {
+		if r.Type != nil {
+			verr.Add(r, "response cannot define both a body type and an event stream")
+		}
+		if r.MediaType != EventStreamMediaType {
+			verr.Add(r, "event stream responses must use the %s media type, got %s", EventStreamMediaType, r.MediaType)
+		}
+		if HasFile(r.Stream) {
+			verr.Add(r, "event stream data cannot contain files")
+		}
+	}
 	if r.Status == 0 {
 		verr.Add(r, "response status not defined")
 	}
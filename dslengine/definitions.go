@@ -1,6 +1,9 @@
 package dslengine
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+)
 
 type (
 
@@ -75,6 +78,9 @@ type (
 		Name string
 		// Trait DSL
 		DSLFunc func()
+		// Trait DSL of traits that take parameters, a function whose arguments are given
+		// by the trait users
+		ParamsDSLFunc interface{}
 	}
 
 	// ValidationDefinition contains validation rules for an attribute.
@@ -82,6 +88,8 @@ type (
 		// Values represents an enum validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor76.
 		Values []interface{}
+		// ValueDescriptions contains the descriptions of the enum values indexed by value.
+		ValueDescriptions map[interface{}]string
 		// Format represents a format validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor104.
 		Format string
@@ -94,15 +102,54 @@ type (
 		// Maximum represents a maximum value validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor17.
 		Maximum *float64
+		// ExclusiveMinimum represents an exclusive minimum value validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor21.
+		ExclusiveMinimum *float64
+		// ExclusiveMaximum represents an exclusive maximum value validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor17.
+		ExclusiveMaximum *float64
+		// MultipleOf represents a multiple of value validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor14.
+		MultipleOf *float64
 		// MinLength represents an minimum length validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor29.
 		MinLength *int
 		// MaxLength represents an maximum length validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor26.
 		MaxLength *int
+		// UniqueItems represents a unique items validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor49.
+		UniqueItems bool
+		// MinProperties represents a minimum properties validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor57.
+		MinProperties *int
+		// MaxProperties represents a maximum properties validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor54.
+		MaxProperties *int
 		// Required list the required fields of object attributes as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor61.
 		Required []string
+		// RequiredIf lists the fields of object attributes that are required when another
+		// field has a given value.
+		RequiredIf []*RequiredIfDefinition
+		// DependentRequired maps the names of fields of object attributes to the names of
+		// the fields that are required when they are set, see
+		// http://json-schema.org/latest/json-schema-validation.html#anchor70.
+		DependentRequired map[string][]string
+		// MutuallyExclusive lists groups of fields of object attributes, at most one field
+		// of each group may be set.
+		MutuallyExclusive [][]string
+	}
+
+	// RequiredIfDefinition represents a conditional required validation: the Required fields
+	// must be set when the field named Attribute is set to Value.
+	RequiredIfDefinition struct {
+		// Attribute is the name of the field the condition applies to.
+		Attribute string
+		// Value is the value that makes the fields required.
+		Value interface{}
+		// Required lists the names of the conditionally required fields.
+		Required []string
 	}
 )
 
@@ -119,6 +166,83 @@ func (t *TraitDefinition) DSL() func() {
 	return t.DSLFunc
 }
 
+// Bind returns the trait DSL with the given arguments applied. It returns an error if the number
+// of arguments does not match the number of trait parameters or if an argument is not assignable to
+// the type of the corresponding parameter. Numerical arguments are converted to the type of numerical
+// parameters.
+func (t *TraitDefinition) Bind(args ...interface{}) (func(), error) {
+	if t.ParamsDSLFunc == nil {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("too many arguments given to %s, expected 0 but got %d",
+				t.Context(), len(args))
+		}
+		return t.DSLFunc, nil
+	}
+	typ := reflect.TypeOf(t.ParamsDSLFunc)
+	num := typ.NumIn()
+	if typ.IsVariadic() {
+		num--
+	}
+	if len(args) < num {
+		return nil, fmt.Errorf("missing arguments for %s, expected %d but got %d",
+			t.Context(), num, len(args))
+	}
+	if len(args) > num && !typ.IsVariadic() {
+		return nil, fmt.Errorf("too many arguments given to %s, expected %d but got %d",
+			t.Context(), num, len(args))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var pt reflect.Type
+		if i < num {
+			pt = typ.In(i)
+		} else {
+			pt = typ.In(num).Elem()
+		}
+		v, ok := traitArg(arg, pt)
+		if !ok {
+			return nil, fmt.Errorf("invalid argument %#v at position %d for %s, expected a value of type %s",
+				arg, i+1, t.Context(), pt)
+		}
+		in[i] = v
+	}
+	f := reflect.ValueOf(t.ParamsDSLFunc)
+	return func() { f.Call(in) }, nil
+}
+
+// traitArg returns the value of the trait argument arg for a parameter of type typ.
+func traitArg(arg interface{}, typ reflect.Type) (reflect.Value, bool) {
+	if arg == nil {
+		switch typ.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(typ), true
+		}
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(arg)
+	if v.Type().AssignableTo(typ) {
+		return v, true
+	}
+	if isNumber(v.Kind()) && isNumber(typ.Kind()) {
+		// Make sure the conversion does not lose information, e.g. 1.5 as int. Conversions
+		// between floating point numbers are always accepted.
+		c := v.Convert(typ)
+		if typ.Kind() >= reflect.Float32 && v.Kind() >= reflect.Float32 {
+			return c, true
+		}
+		f := reflect.TypeOf(float64(0))
+		if c.Convert(f).Float() == v.Convert(f).Float() {
+			return c, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// isNumber returns true if k is the kind of an integer or floating point number.
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
 // Context returns the generic definition name used in error messages.
 func (v *ValidationDefinition) Context() string {
 	return "validation"
@@ -129,6 +253,9 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.Values == nil {
 		v.Values = other.Values
 	}
+	if v.ValueDescriptions == nil {
+		v.ValueDescriptions = dupValueDescriptions(other.ValueDescriptions)
+	}
 	if v.Format == "" {
 		v.Format = other.Format
 	}
@@ -147,7 +274,32 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.MaxLength == nil || (other.MaxLength != nil && *v.MaxLength < *other.MaxLength) {
 		v.MaxLength = other.MaxLength
 	}
+	if v.ExclusiveMinimum == nil || (other.ExclusiveMinimum != nil && *v.ExclusiveMinimum > *other.ExclusiveMinimum) {
+		v.ExclusiveMinimum = other.ExclusiveMinimum
+	}
+	if v.ExclusiveMaximum == nil || (other.ExclusiveMaximum != nil && *v.ExclusiveMaximum < *other.ExclusiveMaximum) {
+		v.ExclusiveMaximum = other.ExclusiveMaximum
+	}
+	if v.MultipleOf == nil {
+		v.MultipleOf = other.MultipleOf
+	}
+	v.UniqueItems = v.UniqueItems || other.UniqueItems
+	if v.MinProperties == nil || (other.MinProperties != nil && *v.MinProperties > *other.MinProperties) {
+		v.MinProperties = other.MinProperties
+	}
+	if v.MaxProperties == nil || (other.MaxProperties != nil && *v.MaxProperties < *other.MaxProperties) {
+		v.MaxProperties = other.MaxProperties
+	}
 	v.AddRequired(other.Required)
+	for _, r := range other.RequiredIf {
+		v.AddRequiredIf(r)
+	}
+	for n, deps := range other.DependentRequired {
+		v.AddDependentRequired(n, deps)
+	}
+	for _, names := range other.MutuallyExclusive {
+		v.AddMutuallyExclusive(names)
+	}
 }
 
 // AddRequired merges the required fields from other into v
@@ -166,6 +318,45 @@ func (v *ValidationDefinition) AddRequired(required []string) {
 	}
 }
 
+// AddRequiredIf merges the conditional required validation r into v.
+func (v *ValidationDefinition) AddRequiredIf(r *RequiredIfDefinition) {
+	for _, rr := range v.RequiredIf {
+		if reflect.DeepEqual(r, rr) {
+			return
+		}
+	}
+	v.RequiredIf = append(v.RequiredIf, r)
+}
+
+// AddDependentRequired merges the fields required when the field name is set into v.
+func (v *ValidationDefinition) AddDependentRequired(name string, required []string) {
+	if v.DependentRequired == nil {
+		v.DependentRequired = make(map[string][]string)
+	}
+	for _, r := range required {
+		found := false
+		for _, rr := range v.DependentRequired[name] {
+			if r == rr {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.DependentRequired[name] = append(v.DependentRequired[name], r)
+		}
+	}
+}
+
+// AddMutuallyExclusive merges the group of mutually exclusive fields names into v.
+func (v *ValidationDefinition) AddMutuallyExclusive(names []string) {
+	for _, g := range v.MutuallyExclusive {
+		if reflect.DeepEqual(names, g) {
+			return
+		}
+	}
+	v.MutuallyExclusive = append(v.MutuallyExclusive, names)
+}
+
 // HasRequiredOnly returns true if the validation only has the Required field with a non-zero value.
 func (v *ValidationDefinition) HasRequiredOnly() bool {
 	if len(v.Values) > 0 {
@@ -177,19 +368,51 @@ func (v *ValidationDefinition) HasRequiredOnly() bool {
 	if (v.Minimum != nil) || (v.Maximum != nil) || (v.MaxLength != nil) {
 		return false
 	}
+	if (v.ExclusiveMinimum != nil) || (v.ExclusiveMaximum != nil) || (v.MultipleOf != nil) {
+		return false
+	}
+	if v.UniqueItems || (v.MinProperties != nil) || (v.MaxProperties != nil) {
+		return false
+	}
+	if len(v.RequiredIf) > 0 || len(v.DependentRequired) > 0 || len(v.MutuallyExclusive) > 0 {
+		return false
+	}
 	return true
 }
 
-// Dup makes a shallow dup of the validation.
+// Dup makes a shallow dup of the validation. The enum value descriptions are copied as
+// EnumValue modifies them in place.
 func (v *ValidationDefinition) Dup() *ValidationDefinition {
 	return &ValidationDefinition{
-		Values:    v.Values,
-		Format:    v.Format,
-		Pattern:   v.Pattern,
-		Minimum:   v.Minimum,
-		Maximum:   v.Maximum,
-		MinLength: v.MinLength,
-		MaxLength: v.MaxLength,
-		Required:  v.Required,
+		Values:            v.Values,
+		ValueDescriptions: dupValueDescriptions(v.ValueDescriptions),
+		Format:            v.Format,
+		Pattern:           v.Pattern,
+		Minimum:           v.Minimum,
+		Maximum:           v.Maximum,
+		ExclusiveMinimum:  v.ExclusiveMinimum,
+		ExclusiveMaximum:  v.ExclusiveMaximum,
+		MultipleOf:        v.MultipleOf,
+		MinLength:         v.MinLength,
+		MaxLength:         v.MaxLength,
+		UniqueItems:       v.UniqueItems,
+		MinProperties:     v.MinProperties,
+		MaxProperties:     v.MaxProperties,
+		Required:          v.Required,
+		RequiredIf:        v.RequiredIf,
+		DependentRequired: v.DependentRequired,
+		MutuallyExclusive: v.MutuallyExclusive,
+	}
+}
+
+// dupValueDescriptions returns a copy of the given enum value descriptions.
+func dupValueDescriptions(descs map[interface{}]string) map[interface{}]string {
+	if descs == nil {
+		return nil
+	}
+	res := make(map[interface{}]string, len(descs))
+	for v, desc := range descs {
+		res[v] = desc
 	}
+	return res
 }
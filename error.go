@@ -32,8 +32,10 @@ package goa
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 )
 
@@ -122,6 +124,21 @@ type (
 		Detail string `json:"detail" yaml:"detail" xml:"detail" form:"detail"`
 		// Meta contains additional key/value pairs useful to clients.
 		Meta map[string]interface{} `json:"meta,omitempty" yaml:"meta,omitempty" xml:"meta,omitempty" form:"meta,omitempty"`
+		// Violations lists the validation rules that the request or response data does not
+		// satisfy.
+		Violations []*Violation `json:"violations,omitempty" yaml:"violations,omitempty" xml:"violations,omitempty" form:"violations,omitempty"`
+	}
+
+	// Violation describes a validation rule that a value does not satisfy.
+	Violation struct {
+		// Pointer is the RFC 6901 JSON pointer to the value in the request or response body,
+		// e.g. "/items/2/price". The pointer of parameters and headers consists of their
+		// name, e.g. "/limit".
+		Pointer string `json:"pointer" yaml:"pointer" xml:"pointer" form:"pointer"`
+		// Rule is the name of the validation rule, e.g. "required", "maximum" or "pattern".
+		Rule string `json:"rule" yaml:"rule" xml:"rule" form:"rule"`
+		// Limit is the value defined by the rule, e.g. the maximum value or the pattern.
+		Limit interface{} `json:"limit,omitempty" yaml:"limit,omitempty" xml:"limit,omitempty" form:"limit,omitempty"`
 	}
 )
 
@@ -153,7 +170,11 @@ func NewErrorClass(code string, status int) ErrorClass {
 			}
 			meta[fmt.Sprintf("%v", k)] = v
 		}
-		return &ErrorResponse{ID: newErrorID(), Code: code, Status: status, Detail: msg, Meta: meta}
+		e := &ErrorResponse{ID: newErrorID(), Code: code, Status: status, Detail: msg, Meta: meta}
+		if wrapped, ok := message.(*ErrorResponse); ok {
+			e.Violations = wrapped.Violations
+		}
+		return e
 	}
 }
 
@@ -166,33 +187,60 @@ func MissingPayloadError() error {
 // defined in the design.
 func InvalidParamTypeError(name string, val interface{}, expected string) error {
 	msg := fmt.Sprintf("invalid value %#v for parameter %#v, must be a %s", val, name, expected)
-	return ErrInvalidRequest(msg, "param", name, "value", val, "expected", expected)
+	return withViolation(ErrInvalidRequest(msg, "param", name, "value", val, "expected", expected), "param."+name, "type", expected)
 }
 
 // MissingParamError is the error produced for requests that are missing path or querystring
 // parameters.
 func MissingParamError(name string) error {
 	msg := fmt.Sprintf("missing required parameter %#v", name)
-	return ErrInvalidRequest(msg, "name", name)
+	return withViolation(ErrInvalidRequest(msg, "name", name), "param."+name, "required", nil)
 }
 
 // InvalidAttributeTypeError is the error produced when the type of payload field does not match
 // the type defined in the design.
 func InvalidAttributeTypeError(ctx string, val interface{}, expected string) error {
 	msg := fmt.Sprintf("type of %s must be %s but got value %#v", ctx, expected, val)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", expected)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", expected), ctx, "type", expected)
 }
 
 // MissingAttributeError is the error produced when a request payload is missing a required field.
 func MissingAttributeError(ctx, name string) error {
 	msg := fmt.Sprintf("attribute %#v of %s is missing and required", name, ctx)
-	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx)
+	return withViolation(ErrInvalidRequest(msg, "attribute", name, "parent", ctx), ctx+"."+name, "required", nil)
+}
+
+// MissingConditionalAttributeError is the error produced when a request payload is missing an
+// attribute that the design requires when another attribute has a given value.
+func MissingConditionalAttributeError(ctx, name, condition string, value interface{}) error {
+	msg := fmt.Sprintf("attribute %#v of %s is missing and required when %#v is %#v", name, ctx, condition, value)
+	return withViolation(ErrInvalidRequest(msg, "attribute", name, "parent", ctx, "condition", condition, "value", value), ctx+"."+name, "required", condition)
+}
+
+// MissingDependentAttributeError is the error produced when a request payload is missing an
+// attribute that the design requires when another attribute is set.
+func MissingDependentAttributeError(ctx, name, dependency string) error {
+	msg := fmt.Sprintf("attribute %#v of %s is missing and required when %#v is set", name, ctx, dependency)
+	return withViolation(ErrInvalidRequest(msg, "attribute", name, "parent", ctx, "dependency", dependency), ctx+"."+name, "dependentRequired", dependency)
+}
+
+// MutuallyExclusiveAttributesError is the error produced when a request payload sets more than
+// one of attributes that the design defines as mutually exclusive.
+func MutuallyExclusiveAttributesError(ctx string, names []string) error {
+	msg := fmt.Sprintf("at most one of the attributes %q of %s may be set", names, ctx)
+	return withViolation(ErrInvalidRequest(msg, "attributes", names, "parent", ctx), ctx, "mutuallyExclusive", names)
 }
 
 // MissingHeaderError is the error produced when a request is missing a required header.
 func MissingHeaderError(name string) error {
 	msg := fmt.Sprintf("missing required HTTP header %#v", name)
-	return ErrInvalidRequest(msg, "name", name)
+	return withViolation(ErrInvalidRequest(msg, "name", name), "header."+name, "required", nil)
+}
+
+// MissingCookieError is the error produced when a request is missing a required cookie.
+func MissingCookieError(name string) error {
+	msg := fmt.Sprintf("missing required HTTP cookie %#v", name)
+	return withViolation(ErrInvalidRequest(msg, "name", name), "cookie."+name, "required", nil)
 }
 
 // InvalidEnumValueError is the error produced when the value of a parameter or payload field does
@@ -203,43 +251,80 @@ func InvalidEnumValueError(ctx string, val interface{}, allowed []interface{}) e
 		elems[i] = fmt.Sprintf("%#v", a)
 	}
 	msg := fmt.Sprintf("value of %s must be one of %s but got value %#v", ctx, strings.Join(elems, ", "), val)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", strings.Join(elems, ", "))
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", strings.Join(elems, ", ")), ctx, "enum", allowed)
 }
 
 // InvalidFormatError is the error produced when the value of a parameter or payload field does not
 // match the format validation defined in the design.
 func InvalidFormatError(ctx, target string, format Format, formatError error) error {
 	msg := fmt.Sprintf("%s must be formatted as a %s but got value %#v, %s", ctx, format, target, formatError.Error())
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "expected", format, "error", formatError.Error())
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "expected", format, "error", formatError.Error()), ctx, "format", format)
 }
 
 // InvalidPatternError is the error produced when the value of a parameter or payload field does
 // not match the pattern validation defined in the design.
 func InvalidPatternError(ctx, target string, pattern string) error {
 	msg := fmt.Sprintf("%s must match the regexp %#v but got value %#v", ctx, pattern, target)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "regexp", pattern)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "regexp", pattern), ctx, "pattern", pattern)
 }
 
 // InvalidRangeError is the error produced when the value of a parameter or payload field does
 // not match the range validation defined in the design. value may be a int or a float64.
 func InvalidRangeError(ctx string, target interface{}, value interface{}, min bool) error {
-	comp := "greater than or equal to"
+	comp, rule := "greater than or equal to", "minimum"
 	if !min {
-		comp = "less than or equal to"
+		comp, rule = "less than or equal to", "maximum"
 	}
 	msg := fmt.Sprintf("%s must be %s %v but got value %#v", ctx, comp, value, target)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "comp", comp, "expected", value)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "comp", comp, "expected", value), ctx, rule, value)
 }
 
 // InvalidLengthError is the error produced when the value of a parameter or payload field does
 // not match the length validation defined in the design.
 func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool) error {
-	comp := "greater than or equal to"
+	comp, rule := "greater than or equal to", "minLength"
 	if !min {
-		comp = "less than or equal to"
+		comp, rule = "less than or equal to", "maxLength"
 	}
 	msg := fmt.Sprintf("length of %s must be %s %d but got value %#v (len=%d)", ctx, comp, value, target, ln)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value), ctx, rule, value)
+}
+
+// InvalidExclusiveRangeError is the error produced when the value of a parameter or payload field
+// does not match the exclusive range validation defined in the design. value may be a int or a
+// float64.
+func InvalidExclusiveRangeError(ctx string, target interface{}, value interface{}, min bool) error {
+	comp, rule := "greater than", "exclusiveMinimum"
+	if !min {
+		comp, rule = "less than", "exclusiveMaximum"
+	}
+	msg := fmt.Sprintf("%s must be %s %v but got value %#v", ctx, comp, value, target)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "comp", comp, "expected", value), ctx, rule, value)
+}
+
+// InvalidMultipleOfError is the error produced when the value of a parameter or payload field is
+// not a multiple of the value defined in the design. value may be a int or a float64.
+func InvalidMultipleOfError(ctx string, target interface{}, value interface{}) error {
+	msg := fmt.Sprintf("%s must be a multiple of %v but got value %#v", ctx, value, target)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "multipleOf", value), ctx, "multipleOf", value)
+}
+
+// InvalidUniqueItemsError is the error produced when the elements of a parameter or payload field
+// are not unique while the design requires them to be.
+func InvalidUniqueItemsError(ctx string, target interface{}) error {
+	msg := fmt.Sprintf("elements of %s must be unique but got value %#v", ctx, target)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target), ctx, "uniqueItems", nil)
+}
+
+// InvalidPropertiesCountError is the error produced when the number of entries of a parameter or
+// payload field does not match the min or max properties validation defined in the design.
+func InvalidPropertiesCountError(ctx string, target interface{}, count, value int, min bool) error {
+	comp, rule := "greater than or equal to", "minProperties"
+	if !min {
+		comp, rule = "less than or equal to", "maxProperties"
+	}
+	msg := fmt.Sprintf("number of properties of %s must be %s %d but got value %#v (count=%d)", ctx, comp, value, target, count)
+	return withViolation(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "count", count, "comp", comp, "expected", value), ctx, rule, value)
 }
 
 // NoAuthMiddleware is the error produced when goa is unable to lookup a auth middleware for a
@@ -275,6 +360,40 @@ func (e *ErrorResponse) ResponseStatus() int { return e.Status }
 // Token is the unique error occurrence identifier.
 func (e *ErrorResponse) Token() string { return e.ID }
 
+// DecodeMeta decodes the error metadata into v, the metadata is converted using its JSON
+// representation.
+func (e *ErrorResponse) DecodeMeta(v interface{}) error {
+	b, err := json.Marshal(e.Meta)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// MetaKeyvals returns the key/value pairs of the JSON object that represents v sorted by key. It
+// is used to create errors with typed metadata using error classes. MetaKeyvals returns nil if v
+// is not represented by a JSON object.
+func MetaKeyvals(v interface{}) []interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	keyvals := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		keyvals = append(keyvals, k, m[k])
+	}
+	return keyvals
+}
+
 // MergeErrors updates an error by merging another into it. It first converts other into a
 // ServiceError if not already one - producing an internal error in that case. The merge algorithm
 // is:
@@ -330,9 +449,79 @@ func MergeErrors(err, other error) error {
 	for k, v := range o.Meta {
 		e.Meta[k] = v
 	}
+	e.Violations = append(e.Violations, o.Violations...)
 	return e
 }
 
+// PrefixViolations prepends the JSON pointer of the given validation context to the pointers of
+// the violations of err. The generated code uses it to make the violations reported by the
+// Validate methods of nested types relative to the enclosing value. PrefixViolations returns err.
+func PrefixViolations(err error, ctx string) error {
+	if e, ok := err.(*ErrorResponse); ok {
+		prefix := contextPointer(ctx)
+		for _, v := range e.Violations {
+			v.Pointer = prefix + v.Pointer
+		}
+	}
+	return err
+}
+
+// IndexViolations replaces the last "*" reference token of the pointers of the violations of err
+// with the given array index or map key. The generated code uses it to report the position of the
+// array and map elements that fail to validate. IndexViolations returns err.
+func IndexViolations(err error, index interface{}) error {
+	e, ok := err.(*ErrorResponse)
+	if !ok {
+		return err
+	}
+	token := escapePointerToken(fmt.Sprintf("%v", index))
+	for _, v := range e.Violations {
+		tokens := strings.Split(v.Pointer, "/")
+		for i := len(tokens) - 1; i > 0; i-- {
+			if tokens[i] == "*" {
+				tokens[i] = token
+				v.Pointer = strings.Join(tokens, "/")
+				break
+			}
+		}
+	}
+	return err
+}
+
+// withViolation records the violation of the given rule by the value of the given validation
+// context in err if err was created by an error class. It returns err.
+func withViolation(err error, ctx, rule string, limit interface{}) error {
+	if e, ok := err.(*ErrorResponse); ok {
+		e.Violations = append(e.Violations, &Violation{Pointer: contextPointer(ctx), Rule: rule, Limit: limit})
+	}
+	return err
+}
+
+// contextPointer returns the JSON pointer corresponding to the given validation context, e.g.
+// "/items/*/price" for "raw.items[*].price". The first token of the context is its root, e.g.
+// "raw", "param" or "header", and is omitted so that the pointer of "raw" is the empty string.
+func contextPointer(ctx string) string {
+	var tokens []string
+	for _, s := range strings.Split(ctx, ".") {
+		for _, t := range strings.Split(s, "[") {
+			tokens = append(tokens, strings.TrimSuffix(t, "]"))
+		}
+	}
+	var pointer string
+	for _, t := range tokens[1:] {
+		if t == "" {
+			continue
+		}
+		pointer += "/" + escapePointerToken(t)
+	}
+	return pointer
+}
+
+// escapePointerToken escapes the "~" and "/" characters of a JSON pointer reference token.
+func escapePointerToken(t string) string {
+	return strings.Replace(strings.Replace(t, "~", "~0", -1), "/", "~1", -1)
+}
+
 func asServiceError(err error) ServiceError {
 	e, ok := err.(ServiceError)
 	if !ok {
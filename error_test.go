@@ -30,6 +30,34 @@ var _ = Describe("Error", func() {
 		Ω(err).ShouldNot(HaveOccurred())
 		Ω(string(b)).Should(Equal(`{"id":"foo","code":"invalid","status":400,"detail":"error","meta":{"what":42}}`))
 	})
+
+	It("decodes its metadata", func() {
+		var m struct {
+			What int `json:"what"`
+		}
+		Ω(gerr.DecodeMeta(&m)).ShouldNot(HaveOccurred())
+		Ω(m.What).Should(Equal(42))
+	})
+})
+
+var _ = Describe("MetaKeyvals", func() {
+	type meta struct {
+		ID     int     `json:"id"`
+		Reason *string `json:"reason,omitempty"`
+	}
+
+	It("returns the key/value pairs of the JSON object sorted by key", func() {
+		reason := "gone"
+		keyvals := MetaKeyvals(&meta{ID: 1, Reason: &reason})
+		Ω(keyvals).Should(Equal([]interface{}{"id", 1.0, "reason", "gone"}))
+		err := NewErrorClass("not_found", 404)("not found", keyvals...)
+		Ω(err.(*ErrorResponse).Meta).Should(Equal(map[string]interface{}{"id": 1.0, "reason": "gone"}))
+	})
+
+	It("returns nil for values that are not JSON objects", func() {
+		Ω(MetaKeyvals(42)).Should(BeEmpty())
+		Ω(MetaKeyvals((*meta)(nil))).Should(BeEmpty())
+	})
 })
 
 var _ = Describe("InvalidParamTypeError", func() {
@@ -106,6 +134,62 @@ var _ = Describe("MissingAttributeError", func() {
 	})
 })
 
+var _ = Describe("MissingConditionalAttributeError", func() {
+	var valErr error
+	ctx := "ctx"
+	name := "card_number"
+
+	JustBeforeEach(func() {
+		valErr = MissingConditionalAttributeError(ctx, name, "payment_type", "card")
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(`required when "payment_type" is "card"`))
+		Ω(err.Meta).Should(HaveKeyWithValue("attribute", name))
+	})
+})
+
+var _ = Describe("MissingDependentAttributeError", func() {
+	var valErr error
+	ctx := "ctx"
+	name := "billing_address"
+
+	JustBeforeEach(func() {
+		valErr = MissingDependentAttributeError(ctx, name, "credit_card")
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(`required when "credit_card" is set`))
+		Ω(err.Meta).Should(HaveKeyWithValue("dependency", "credit_card"))
+	})
+})
+
+var _ = Describe("MutuallyExclusiveAttributesError", func() {
+	var valErr error
+	ctx := "ctx"
+	names := []string{"email", "phone"}
+
+	JustBeforeEach(func() {
+		valErr = MutuallyExclusiveAttributesError(ctx, names)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(`["email" "phone"]`))
+	})
+})
+
 var _ = Describe("MissingHeaderError", func() {
 	var valErr error
 	name := "param"
@@ -318,6 +402,105 @@ var _ = Describe("InvalidLengthError", func() {
 	})
 })
 
+var _ = Describe("InvalidExclusiveRangeError", func() {
+	const ctx = "ctx"
+	const target = 42
+	const value = 42
+
+	var min bool
+	var valErr error
+
+	JustBeforeEach(func() {
+		valErr = InvalidExclusiveRangeError(ctx, target, value, min)
+	})
+
+	Context("with a minimum", func() {
+		BeforeEach(func() {
+			min = true
+		})
+
+		It("creates a http error", func() {
+			Ω(valErr).ShouldNot(BeNil())
+			Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+			err := valErr.(*ErrorResponse)
+			Ω(err.Detail).Should(ContainSubstring(ctx))
+			Ω(err.Detail).Should(ContainSubstring("must be greater than 42"))
+		})
+	})
+
+	Context("with a maximum", func() {
+		BeforeEach(func() {
+			min = false
+		})
+
+		It("creates a http error", func() {
+			Ω(valErr).ShouldNot(BeNil())
+			err := valErr.(*ErrorResponse)
+			Ω(err.Detail).Should(ContainSubstring("must be less than 42"))
+		})
+	})
+})
+
+var _ = Describe("InvalidMultipleOfError", func() {
+	const ctx = "ctx"
+	const target = 7
+	const value = 2
+
+	var valErr error
+
+	JustBeforeEach(func() {
+		valErr = InvalidMultipleOfError(ctx, target, value)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("must be a multiple of 2 but got value 7"))
+	})
+})
+
+var _ = Describe("InvalidUniqueItemsError", func() {
+	const ctx = "ctx"
+	var target = []string{"a", "a"}
+
+	var valErr error
+
+	JustBeforeEach(func() {
+		valErr = InvalidUniqueItemsError(ctx, target)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", target)))
+	})
+})
+
+var _ = Describe("InvalidPropertiesCountError", func() {
+	const ctx = "ctx"
+	const value = 2
+	var target = map[string]int{"a": 1}
+
+	var valErr error
+
+	JustBeforeEach(func() {
+		valErr = InvalidPropertiesCountError(ctx, target, len(target), value, true)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("greater than or equal to 2"))
+		Ω(err.Detail).Should(ContainSubstring("(count=1)"))
+	})
+})
+
 // MergeableErrorResponse contains the details of a error response.
 // It implements ServiceMergeableError.
 type MergeableErrorResponse struct {
@@ -601,3 +784,69 @@ var _ = Describe("Merge", func() {
 	})
 
 })
+
+var _ = Describe("Violations", func() {
+	It("records the violated rule", func() {
+		err := InvalidRangeError("raw.items[*].price", 200, 100, false).(*ErrorResponse)
+		Ω(err.Violations).Should(Equal([]*Violation{{Pointer: "/items/*/price", Rule: "maximum", Limit: 100}}))
+	})
+
+	It("uses the name of parameters as pointer", func() {
+		err := MissingParamError("limit").(*ErrorResponse)
+		Ω(err.Violations).Should(Equal([]*Violation{{Pointer: "/limit", Rule: "required"}}))
+	})
+
+	It("keep the name of parameters and headers that are also context roots", func() {
+		err := MergeErrors(MissingParamError("type"), MissingHeaderError("response"))
+		Ω(err.(*ErrorResponse).Violations).Should(Equal([]*Violation{
+			{Pointer: "/type", Rule: "required"},
+			{Pointer: "/response", Rule: "required"},
+		}))
+	})
+
+	It("resolve the index of array parameter elements", func() {
+		err := IndexViolations(InvalidRangeError("param.ids[*]", 0, 1, true), 2)
+		Ω(err.(*ErrorResponse).Violations[0].Pointer).Should(Equal("/ids/2"))
+	})
+
+	It("resolve the key of deepObject parameter values", func() {
+		err := IndexViolations(InvalidEnumValueError("param.filter[*]", "x", []interface{}{"open"}), "status")
+		Ω(err.(*ErrorResponse).Violations[0].Pointer).Should(Equal("/filter/status"))
+	})
+
+	It("escapes the pointer reference tokens", func() {
+		err := MissingAttributeError("raw", "a/b~c").(*ErrorResponse)
+		Ω(err.Violations[0].Pointer).Should(Equal("/a~1b~0c"))
+	})
+
+	It("are merged", func() {
+		err := MergeErrors(MissingAttributeError("raw", "name"), InvalidLengthError("raw.tags", []string{}, 0, 1, true))
+		Ω(err.(*ErrorResponse).Violations).Should(Equal([]*Violation{
+			{Pointer: "/name", Rule: "required"},
+			{Pointer: "/tags", Rule: "minLength", Limit: 1},
+		}))
+	})
+
+	It("are prefixed with the pointer of the enclosing value", func() {
+		err := PrefixViolations(MissingAttributeError("response", "price"), "raw.items[*]")
+		err = IndexViolations(err, 2)
+		Ω(err.(*ErrorResponse).Violations[0].Pointer).Should(Equal("/items/2/price"))
+	})
+
+	It("resolve the innermost array index first", func() {
+		err := IndexViolations(MissingAttributeError("raw.items[*].tags[*]", "name"), 3)
+		err = IndexViolations(err, 1)
+		Ω(err.(*ErrorResponse).Violations[0].Pointer).Should(Equal("/items/1/tags/3/name"))
+	})
+
+	It("are kept when the error is wrapped by an error class", func() {
+		err := ErrBadRequest(MissingAttributeError("raw", "name")).(*ErrorResponse)
+		Ω(err.Violations).Should(Equal([]*Violation{{Pointer: "/name", Rule: "required"}}))
+	})
+
+	It("are rendered in the error response", func() {
+		b, err := json.Marshal(MissingAttributeError("raw", "name"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(ContainSubstring(`"violations":[{"pointer":"/name","rule":"required"}]`))
+	})
+})
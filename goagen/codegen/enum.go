@@ -0,0 +1,80 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// EnumTypeName returns the name of the generated type that enumerates the values of the given
+// attribute, the empty string if no type is generated for the attribute, see
+// design.AttributeDefinition.EnumTypeName.
+func EnumTypeName(att *design.AttributeDefinition) string {
+	if n := att.EnumTypeName(); n != "" {
+		return Goify(n, true)
+	}
+	return ""
+}
+
+// EnumValueNames returns the names of the constants generated for the values of the enum type
+// of the given attribute in the order the values are defined.
+func EnumValueNames(att *design.AttributeDefinition) []string {
+	typeName := EnumTypeName(att)
+	values := att.Validation.Values
+	names := make([]string, len(values))
+	seen := make(map[string]bool, len(values))
+	for i, v := range values {
+		s := fmt.Sprintf("%v", v)
+		if design.IsNumber(att.Type) {
+			s = strings.NewReplacer("-", "Minus", ".", "Point").Replace(s)
+		}
+		name := typeName + Goify(s, true)
+		if seen[name] {
+			name = fmt.Sprintf("%s%d", name, i)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// EnumTypes returns the attributes of all the user types, media types and action payloads defined
+// in the API whose enum values are enumerated by a generated type. The returned attributes are
+// unique with respect to the name of the generated type and are sorted by that name.
+func EnumTypes(api *design.APIDefinition) []*design.AttributeDefinition {
+	seen := make(map[string]*design.AttributeDefinition)
+	collect := func(att *design.AttributeDefinition) error {
+		if n := EnumTypeName(att); n != "" {
+			seen[n] = att
+		}
+		return nil
+	}
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		return ut.Walk(collect)
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		return mt.Walk(collect)
+	})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload != nil {
+				return a.Payload.Walk(collect)
+			}
+			return nil
+		})
+	})
+	names := make([]string, len(seen))
+	i := 0
+	for n := range seen {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	res := make([]*design.AttributeDefinition, len(names))
+	for i, n := range names {
+		res[i] = seen[n]
+	}
+	return res
+}
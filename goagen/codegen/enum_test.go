@@ -0,0 +1,61 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EnumValueNames", func() {
+	var values []interface{}
+	var typ design.DataType
+	var names []string
+
+	JustBeforeEach(func() {
+		descs := make(map[interface{}]string, len(values))
+		for _, v := range values {
+			descs[v] = "desc"
+		}
+		att := &design.AttributeDefinition{
+			Type:       typ,
+			Validation: &dslengine.ValidationDefinition{Values: values, ValueDescriptions: descs},
+			Metadata:   dslengine.MetadataDefinition{"struct:enum:name": []string{"bottle_color"}},
+		}
+		names = codegen.EnumValueNames(att)
+	})
+
+	Context("with string values", func() {
+		BeforeEach(func() {
+			typ = design.String
+			values = []interface{}{"red", "white", "sparkling-rosé"}
+		})
+
+		It("prefixes the goified values with the type name", func() {
+			Ω(names).Should(Equal([]string{"BottleColorRed", "BottleColorWhite", "BottleColorSparklingRosé"}))
+		})
+	})
+
+	Context("with number values", func() {
+		BeforeEach(func() {
+			typ = design.Number
+			values = []interface{}{-1.5, 1.5, 2.0}
+		})
+
+		It("spells out the sign and decimal point", func() {
+			Ω(names).Should(Equal([]string{"BottleColorMinus1Point5", "BottleColor1Point5", "BottleColor2"}))
+		})
+	})
+
+	Context("with values that goify to the same name", func() {
+		BeforeEach(func() {
+			typ = design.String
+			values = []interface{}{"a b", "a-b"}
+		})
+
+		It("disambiguates the names with the value index", func() {
+			Ω(names).Should(Equal([]string{"BottleColorAB", "BottleColorAB1"}))
+		})
+	})
+})
@@ -69,9 +69,12 @@ func (f *Finalizer) recurse(root, att *design.AttributeDefinition, target string
 					"field":      n,
 					"catt":       catt,
 					"depth":      depth,
-					"isDatetime": catt.Type == design.DateTime,
+					"isDatetime": catt.Type == design.DateTime || catt.Type == design.Date,
 					"defaultVal": PrintVal(catt.Type, catt.DefaultValue),
 				}
+				if tname := EnumTypeName(catt); tname != "" {
+					data["defaultVal"] = fmt.Sprintf("%s(%s)", tname, data["defaultVal"])
+				}
 				if !first {
 					buf.WriteByte('\n')
 				} else {
@@ -121,8 +124,18 @@ func PrintVal(t design.DataType, val interface{}) string {
 				v = float64(i)
 			}
 			s = fmt.Sprintf("%f", v)
+		case design.Float32, design.Float64:
+			v := val
+			if i, ok := val.(int); ok {
+				v = float64(i)
+			}
+			s = fmt.Sprintf("%s(%f)", GoNativeType(t), v)
+		case design.Int32, design.Int64, design.UInt32, design.UInt64:
+			s = fmt.Sprintf("%s(%s)", GoNativeType(t), s)
 		case design.DateTime:
 			s = fmt.Sprintf("time.Parse(time.RFC3339, %s)", s)
+		case design.Date:
+			s = fmt.Sprintf("goa.ParseDate(%s)", s)
 		}
 		return s
 	case t.IsHash():
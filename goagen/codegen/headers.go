@@ -0,0 +1,40 @@
+package codegen
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ResponseHeaders returns an object attribute whose fields are the headers and cookies declared by
+// the given response, nil if the response declares neither.
+func ResponseHeaders(resp *design.ResponseDefinition) *design.AttributeDefinition {
+	var (
+		obj      = make(design.Object)
+		required []string
+	)
+	for _, att := range []*design.AttributeDefinition{resp.Headers, resp.Cookies} {
+		if att == nil {
+			continue
+		}
+		for n, h := range att.Type.ToObject() {
+			obj[n] = h
+		}
+		if att.Validation != nil {
+			required = append(required, att.Validation.Required...)
+		}
+	}
+	if len(obj) == 0 {
+		return nil
+	}
+	res := &design.AttributeDefinition{Type: obj}
+	if len(required) > 0 {
+		res.Validation = &dslengine.ValidationDefinition{Required: required}
+	}
+	return res
+}
+
+// ResponseHeadersTypeName returns the name of the type generated for the headers and cookies of the
+// given action response, e.g. "ShowBottleOKHeaders".
+func ResponseHeadersTypeName(action, resource, response string) string {
+	return Goify(action, true) + Goify(resource, true) + Goify(response, true) + "Headers"
+}
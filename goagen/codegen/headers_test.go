@@ -0,0 +1,57 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResponseHeaders", func() {
+	var resp *design.ResponseDefinition
+	var headers *design.AttributeDefinition
+
+	BeforeEach(func() {
+		resp = &design.ResponseDefinition{Name: "OK", Status: 200}
+	})
+
+	JustBeforeEach(func() {
+		headers = codegen.ResponseHeaders(resp)
+	})
+
+	Context("with a response declaring no header nor cookie", func() {
+		It("returns nil", func() {
+			Ω(headers).Should(BeNil())
+		})
+	})
+
+	Context("with a response declaring headers and cookies", func() {
+		BeforeEach(func() {
+			resp.Headers = &design.AttributeDefinition{
+				Type:       design.Object{"Location": {Type: design.String}},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"Location"}},
+			}
+			resp.Cookies = &design.AttributeDefinition{
+				Type:       design.Object{"session": {Type: design.String}, "count": {Type: design.Integer}},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"session"}},
+			}
+		})
+
+		It("merges the headers and cookies", func() {
+			Ω(headers).ShouldNot(BeNil())
+			Ω(headers.Type.ToObject()).Should(HaveLen(3))
+			Ω(headers.Type.ToObject()).Should(HaveKey("Location"))
+			Ω(headers.Type.ToObject()).Should(HaveKey("session"))
+			Ω(headers.IsRequired("Location")).Should(BeTrue())
+			Ω(headers.IsRequired("session")).Should(BeTrue())
+			Ω(headers.IsRequired("count")).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("ResponseHeadersTypeName", func() {
+	It("concatenates the goified action, resource and response names", func() {
+		Ω(codegen.ResponseHeadersTypeName("show", "bottle", "OK")).Should(Equal("ShowBottleOKHeaders"))
+	})
+})
@@ -0,0 +1,128 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// NullableTypeName returns the name of the generated type that wraps the values of nullable
+// attributes of the given primitive type, e.g. "NullableString".
+func NullableTypeName(t design.DataType) string {
+	switch t.Kind() {
+	case design.BooleanKind:
+		return "NullableBool"
+	case design.IntegerKind:
+		return "NullableInt"
+	case design.NumberKind, design.Float64Kind:
+		return "NullableFloat64"
+	case design.StringKind:
+		return "NullableString"
+	case design.DateTimeKind:
+		return "NullableTime"
+	case design.UUIDKind:
+		return "NullableUUID"
+	case design.AnyKind:
+		return "NullableAny"
+	case design.Int32Kind:
+		return "NullableInt32"
+	case design.Int64Kind:
+		return "NullableInt64"
+	case design.UInt32Kind:
+		return "NullableUInt32"
+	case design.UInt64Kind:
+		return "NullableUInt64"
+	case design.Float32Kind:
+		return "NullableFloat32"
+	case design.DateKind:
+		return "NullableDate"
+	case design.BytesKind:
+		return "NullableBytes"
+	default:
+		panic("goa bug: no nullable type for " + t.Name())
+	}
+}
+
+// NullableTypes returns the primitive types of all the nullable attributes defined in the API
+// user types, media types and action payloads. The returned types are unique with respect to the
+// name of the wrapper type generated for them and are sorted by that name.
+func NullableTypes(api *design.APIDefinition) []design.Primitive {
+	seen := make(map[string]design.Primitive)
+	collect := func(att *design.AttributeDefinition) error {
+		if att.IsNullable() {
+			if p, ok := att.Type.(design.Primitive); ok {
+				seen[NullableTypeName(p)] = p
+			}
+		}
+		return nil
+	}
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		return ut.Walk(collect)
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		return mt.Walk(collect)
+	})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload != nil {
+				return a.Payload.Walk(collect)
+			}
+			return nil
+		})
+	})
+	names := make([]string, len(seen))
+	i := 0
+	for n := range seen {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	res := make([]design.Primitive, len(names))
+	for i, n := range names {
+		res[i] = seen[n]
+	}
+	return res
+}
+
+// NullableMarshaler returns the code of the MarshalJSON method of the public struct generated for
+// the given attribute with the given type name. The method omits the optional nullable fields that
+// are not set, it returns an empty string if the attribute has no such field.
+func NullableMarshaler(att *design.AttributeDefinition, typeName string) string {
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return ""
+	}
+	if ds, ok := att.Type.(design.DataStructure); ok {
+		att = ds.Definition()
+	}
+	var fields, sets []string
+	obj.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		if !catt.IsNullable() || att.IsRequired(n) {
+			return nil
+		}
+		name := n
+		if tag, ok := catt.Metadata["struct:tag:json"]; ok && len(tag) > 0 {
+			name = tag[0]
+		}
+		field := GoifyAtt(catt, n, true)
+		fields = append(fields, fmt.Sprintf("\t\t%s *%s `json:\"%s,omitempty\"`", field, NullableTypeName(catt.Type), name))
+		sets = append(sets, fmt.Sprintf("\tif v.%s.Set || v.%s.Null {\n\t\tenc.%s = &v.%s\n\t}", field, field, field, field))
+		return nil
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`// MarshalJSON encodes the %[1]s instance, the nullable attributes that are not set are omitted.
+func (v %[1]s) MarshalJSON() ([]byte, error) {
+	type alias %[1]s
+	enc := struct {
+		alias
+%[2]s
+	}{alias: alias(v)}
+%[3]s
+	return json.Marshal(enc)
+}
+`, typeName, strings.Join(fields, "\n"), strings.Join(sets, "\n"))
+}
@@ -53,11 +53,17 @@ func RecursivePublicizer(att *design.AttributeDefinition, source, target string,
 			att = ds.Definition()
 		}
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			if catt.IsNullable() {
+				publication := fmt.Sprintf("%s%s.%s = %s.%s",
+					Tabs(depth), target, Goify(n, true), source, Goify(n, true))
+				publications = append(publications, publication)
+				return nil
+			}
 			publication := Publicizer(
 				catt,
 				fmt.Sprintf("%s.%s", source, Goify(n, true)),
 				fmt.Sprintf("%s.%s", target, Goify(n, true)),
-				catt.Type.IsPrimitive() && !att.IsPrimitivePointer(n) && !att.IsInterface(n),
+				catt.Type.IsPrimitive() && !att.IsPrimitivePointer(n) && !att.IsInterface(n) && !att.IsBytes(n),
 				depth+1,
 				false,
 			)
@@ -100,6 +106,9 @@ func Publicizer(att *design.AttributeDefinition, sourceField, targetField string
 		} else {
 			publication = RunTemplate(simplePublicizeT, data)
 		}
+	case att.Type.IsUnion():
+		// Unions only have a public type
+		publication = RunTemplate(simplePublicizeT, data)
 	case att.Type.IsHash():
 		if att.Type.HasAttributes() {
 			h := att.Type.ToHash()
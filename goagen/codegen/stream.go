@@ -0,0 +1,15 @@
+package codegen
+
+import "github.com/goadesign/goa/design"
+
+// StreamEventTypeName returns the name of the type generated for the events streamed by the given
+// action response, e.g. "WatchBottleOKEvent".
+func StreamEventTypeName(action, resource, response string) string {
+	return Goify(action, true) + Goify(resource, true) + Goify(response, true) + "Event"
+}
+
+// StreamDataType returns the type of the data of the events streamed by the given response. Media
+// types are rendered using their default view.
+func StreamDataType(resp *design.ResponseDefinition) (design.DataType, error) {
+	return DefaultViewType(resp.Stream)
+}
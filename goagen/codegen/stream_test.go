@@ -0,0 +1,68 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StreamDataType", func() {
+	var resp *design.ResponseDefinition
+	var dt design.DataType
+
+	JustBeforeEach(func() {
+		var err error
+		dt, err = codegen.StreamDataType(resp)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with a primitive stream type", func() {
+		BeforeEach(func() {
+			resp = &design.ResponseDefinition{Name: "OK", Stream: design.String}
+		})
+
+		It("returns the type", func() {
+			Ω(dt).Should(Equal(design.String))
+		})
+	})
+
+	Context("with a media type stream type", func() {
+		BeforeEach(func() {
+			mt := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"id":   {Type: design.Integer},
+							"body": {Type: design.String},
+						},
+					},
+					TypeName: "Event",
+				},
+				Identifier: "application/vnd.event",
+			}
+			mt.Views = map[string]*design.ViewDefinition{
+				"default": {
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"id": {Type: design.String}},
+					},
+					Name:   "default",
+					Parent: mt,
+				},
+			}
+			resp = &design.ResponseDefinition{Name: "OK", Stream: mt}
+		})
+
+		It("returns the default view projection", func() {
+			Ω(dt).ShouldNot(BeNil())
+			Ω(dt.ToObject()).Should(HaveLen(1))
+			Ω(dt.ToObject()).Should(HaveKey("id"))
+		})
+	})
+})
+
+var _ = Describe("StreamEventTypeName", func() {
+	It("concatenates the goified action, resource and response names", func() {
+		Ω(codegen.StreamEventTypeName("watch", "bottle", "OK")).Should(Equal("WatchBottleOKEvent"))
+	})
+})
@@ -70,23 +70,26 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 			return tname[0]
 		}
 	}
+	if tname := EnumTypeName(def); tname != "" {
+		return tname
+	}
 	t := def.Type
 	switch actual := t.(type) {
 	case design.Primitive:
 		return GoTypeName(t, nil, tabs, private)
 	case *design.Array:
 		d := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
-		if actual.ElemType.Type.IsObject() {
+		if actual.ElemType.Type.IsObject() || actual.ElemType.Type.IsUnion() {
 			d = "*" + d
 		}
 		return "[]" + d
 	case *design.Hash:
 		keyDef := GoTypeDef(actual.KeyType, tabs, jsonTags, private)
-		if actual.KeyType.Type.IsObject() {
+		if actual.KeyType.Type.IsObject() || actual.KeyType.Type.IsUnion() {
 			keyDef = "*" + keyDef
 		}
 		elemDef := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
-		if actual.ElemType.Type.IsObject() {
+		if actual.ElemType.Type.IsObject() || actual.ElemType.Type.IsUnion() {
 			elemDef = "*" + elemDef
 		}
 		return fmt.Sprintf("map[%s]%s", keyDef, elemDef)
@@ -96,6 +99,8 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 		return GoTypeName(actual, actual.AllRequired(), tabs, private)
 	case *design.MediaTypeDefinition:
 		return GoTypeName(actual, actual.AllRequired(), tabs, private)
+	case *design.Union:
+		return GoTypeName(actual, nil, tabs, private)
 	default:
 		panic("goa bug: unknown data structure type")
 	}
@@ -116,7 +121,10 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 		WriteTabs(&buffer, tabs+1)
 		field := obj[name]
 		typedef := GoTypeDef(field, tabs+1, jsonTags, private)
-		if (private && field.Type.IsPrimitive() && !def.IsInterface(name)) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
+		if field.IsNullable() {
+			// The wrapper type records whether the value is absent or explicitly null.
+			typedef = NullableTypeName(field.Type)
+		} else if (private && field.Type.IsPrimitive() && !def.IsInterface(name) && !def.IsBytes(name)) || field.Type.IsObject() || field.Type.IsUnion() || def.IsPrimitivePointer(name) {
 			typedef = "*" + typedef
 		}
 		fname := GoifyAtt(field, name, true)
@@ -180,7 +188,7 @@ func GoTypeRef(t design.DataType, required []string, tabs int, private bool) str
 			return "error"
 		}
 	}
-	if t.IsObject() {
+	if t.IsObject() || t.IsUnion() {
 		return "*" + tname
 	}
 	return tname
@@ -212,17 +220,40 @@ func GoTypeName(t design.DataType, required []string, tabs int, private bool) st
 			GoTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs+1, private),
 		)
 	case *design.UserTypeDefinition:
+		if actual.IsUnion() {
+			// Unions only have a public type
+			return Goify(actual.TypeName, true)
+		}
 		return Goify(actual.TypeName, !private)
 	case *design.MediaTypeDefinition:
 		if actual.IsError() {
 			return "error"
 		}
+		if actual.IsUnion() {
+			return Goify(actual.TypeName, true)
+		}
 		return Goify(actual.TypeName, !private)
+	case *design.Union:
+		return "interface{}"
 	default:
 		panic(fmt.Sprintf("goa bug: unknown type %#v", actual))
 	}
 }
 
+// DefaultViewType returns the type generated for the given data type when it is rendered outside
+// of a response: the projection of media types using their default view, the type itself otherwise.
+func DefaultViewType(dt design.DataType) (design.DataType, error) {
+	mt, ok := dt.(*design.MediaTypeDefinition)
+	if !ok {
+		return dt, nil
+	}
+	projected, _, err := mt.Project(design.DefaultView)
+	if err != nil {
+		return nil, err
+	}
+	return projected, nil
+}
+
 // GoNativeType returns the Go built-in type from which instances of t can be initialized.
 func GoNativeType(t design.DataType) string {
 	switch actual := t.(type) {
@@ -244,6 +275,22 @@ func GoNativeType(t design.DataType) string {
 			return "interface{}"
 		case design.FileKind:
 			return "multipart.FileHeader"
+		case design.Int32Kind:
+			return "int32"
+		case design.Int64Kind:
+			return "int64"
+		case design.UInt32Kind:
+			return "uint32"
+		case design.UInt64Kind:
+			return "uint64"
+		case design.Float32Kind:
+			return "float32"
+		case design.Float64Kind:
+			return "float64"
+		case design.DateKind:
+			return "goa.Date"
+		case design.BytesKind:
+			return "[]byte"
 		default:
 			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 		}
@@ -257,6 +304,8 @@ func GoNativeType(t design.DataType) string {
 		return GoNativeType(actual.Type)
 	case *design.UserTypeDefinition:
 		return GoNativeType(actual.Type)
+	case *design.Union:
+		return "interface{}"
 	default:
 		panic(fmt.Sprintf("goa bug: unknown type %#v", actual))
 	}
@@ -191,6 +191,80 @@ var _ = Describe("code generation", func() {
 				st = codegen.GoTypeDef(att, 0, true, false)
 			})
 
+			Context("of nullable primitive types", func() {
+				BeforeEach(func() {
+					object = Object{
+						"foo": &AttributeDefinition{Type: String, Metadata: dslengine.MetadataDefinition{"nullable": nil}},
+					}
+					required = nil
+				})
+
+				It("produces the nullable wrapper type", func() {
+					expected := "struct {\n" +
+						"	Foo NullableString `form:\"foo,omitempty\" json:\"foo,omitempty\" yaml:\"foo,omitempty\" xml:\"foo,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
+			Context("of primitive types with described enum values", func() {
+				BeforeEach(func() {
+					object = Object{
+						"foo": &AttributeDefinition{
+							Type:       String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"a"}, ValueDescriptions: map[interface{}]string{"a": "A"}},
+							Metadata:   dslengine.MetadataDefinition{"struct:enum:name": []string{"bar_foo"}},
+						},
+					}
+					required = nil
+				})
+
+				It("produces a pointer to the enum type", func() {
+					expected := "struct {\n" +
+						"	Foo *BarFoo `form:\"foo,omitempty\" json:\"foo,omitempty\" yaml:\"foo,omitempty\" xml:\"foo,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
+			Context("of bytes type", func() {
+				BeforeEach(func() {
+					object = Object{
+						"data": &AttributeDefinition{Type: Bytes},
+					}
+					required = nil
+				})
+
+				It("produces a non pointer byte slice field", func() {
+					expected := "struct {\n" +
+						"	Data []byte `form:\"data,omitempty\" json:\"data,omitempty\" yaml:\"data,omitempty\" xml:\"data,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
+			Context("of sized number and date types", func() {
+				BeforeEach(func() {
+					object = Object{
+						"a": &AttributeDefinition{Type: Int32},
+						"b": &AttributeDefinition{Type: UInt64},
+						"c": &AttributeDefinition{Type: Float32},
+						"d": &AttributeDefinition{Type: Date},
+					}
+					required = nil
+				})
+
+				It("produces the struct go code", func() {
+					expected := "struct {\n" +
+						"	A *int32 `form:\"a,omitempty\" json:\"a,omitempty\" yaml:\"a,omitempty\" xml:\"a,omitempty\"`\n" +
+						"	B *uint64 `form:\"b,omitempty\" json:\"b,omitempty\" yaml:\"b,omitempty\" xml:\"b,omitempty\"`\n" +
+						"	C *float32 `form:\"c,omitempty\" json:\"c,omitempty\" yaml:\"c,omitempty\" xml:\"c,omitempty\"`\n" +
+						"	D *goa.Date `form:\"d,omitempty\" json:\"d,omitempty\" yaml:\"d,omitempty\" xml:\"d,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
 			Context("of primitive types", func() {
 				BeforeEach(func() {
 					object = Object{
@@ -468,6 +542,22 @@ var _ = Describe("code generation", func() {
 				st = codegen.GoTypeDef(att, 0, true, true)
 			})
 
+			Context("of nullable primitive types", func() {
+				BeforeEach(func() {
+					object = Object{
+						"foo": &AttributeDefinition{Type: Int32, Metadata: dslengine.MetadataDefinition{"nullable": nil}},
+					}
+					required = nil
+				})
+
+				It("produces the nullable wrapper type", func() {
+					expected := "struct {\n" +
+						"	Foo NullableInt32 `form:\"foo,omitempty\" json:\"foo,omitempty\" yaml:\"foo,omitempty\" xml:\"foo,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
 			Context("of primitive types", func() {
 				BeforeEach(func() {
 					object = Object{
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -12,12 +14,18 @@ import (
 )
 
 var (
-	enumValT     *template.Template
-	formatValT   *template.Template
-	patternValT  *template.Template
-	minMaxValT   *template.Template
-	lengthValT   *template.Template
-	requiredValT *template.Template
+	enumValT        *template.Template
+	formatValT      *template.Template
+	patternValT     *template.Template
+	minMaxValT      *template.Template
+	multipleOfValT  *template.Template
+	lengthValT      *template.Template
+	uniqueItemsValT *template.Template
+	propertiesValT  *template.Template
+	requiredValT    *template.Template
+	requiredIfValT  *template.Template
+	dependentValT   *template.Template
+	exclusiveValT   *template.Template
 )
 
 //  init instantiates the templates.
@@ -43,12 +51,30 @@ func init() {
 	if minMaxValT, err = template.New("minMax").Funcs(fm).Parse(minMaxValTmpl); err != nil {
 		panic(err)
 	}
+	if multipleOfValT, err = template.New("multipleOf").Funcs(fm).Parse(multipleOfValTmpl); err != nil {
+		panic(err)
+	}
 	if lengthValT, err = template.New("length").Funcs(fm).Parse(lengthValTmpl); err != nil {
 		panic(err)
 	}
+	if uniqueItemsValT, err = template.New("uniqueItems").Funcs(fm).Parse(uniqueItemsValTmpl); err != nil {
+		panic(err)
+	}
+	if propertiesValT, err = template.New("properties").Funcs(fm).Parse(propertiesValTmpl); err != nil {
+		panic(err)
+	}
 	if requiredValT, err = template.New("required").Funcs(fm).Parse(requiredValTmpl); err != nil {
 		panic(err)
 	}
+	if requiredIfValT, err = template.New("requiredIf").Funcs(fm).Parse(requiredIfValTmpl); err != nil {
+		panic(err)
+	}
+	if dependentValT, err = template.New("dependent").Funcs(fm).Parse(dependentValTmpl); err != nil {
+		panic(err)
+	}
+	if exclusiveValT, err = template.New("exclusive").Funcs(fm).Parse(exclusiveValTmpl); err != nil {
+		panic(err)
+	}
 }
 
 // Validator is the code generator for the 'Validate' type methods.
@@ -56,6 +82,7 @@ type Validator struct {
 	arrayValT *template.Template
 	hashValT  *template.Template
 	userValT  *template.Template
+	unionValT *template.Template
 	seen      map[string]*bytes.Buffer
 }
 
@@ -86,6 +113,10 @@ func NewValidator() *Validator {
 	if err != nil {
 		panic(err)
 	}
+	v.unionValT, err = template.New("union").Funcs(fm).Parse(unionValTmpl)
+	if err != nil {
+		panic(err)
+	}
 	return v
 }
 
@@ -120,8 +151,9 @@ func (v *Validator) arrayValCode(att *design.AttributeDefinition, nonzero, requi
 		case *design.UserTypeDefinition, *design.MediaTypeDefinition:
 			// For user and media types, call the Validate method
 			val = RunTemplate(v.userValT, map[string]interface{}{
-				"depth":  depth + 2,
-				"target": "e",
+				"depth":   depth + 2,
+				"target":  "e",
+				"context": context + "[*]",
 			})
 			val = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), val, Tabs(depth+1))
 		}
@@ -165,8 +197,9 @@ func (v *Validator) hashValCode(att *design.AttributeDefinition, nonzero, requir
 		case *design.UserTypeDefinition, *design.MediaTypeDefinition:
 			// For user and media types, call the Validate method
 			keyVal = RunTemplate(v.userValT, map[string]interface{}{
-				"depth":  depth + 2,
-				"target": "k",
+				"depth":   depth + 2,
+				"target":  "k",
+				"context": context + "[*]",
 			})
 			keyVal = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), keyVal, Tabs(depth+1))
 		}
@@ -177,8 +210,9 @@ func (v *Validator) hashValCode(att *design.AttributeDefinition, nonzero, requir
 		case *design.UserTypeDefinition, *design.MediaTypeDefinition:
 			// For user and media types, call the Validate method
 			elemVal = RunTemplate(v.userValT, map[string]interface{}{
-				"depth":  depth + 2,
-				"target": "e",
+				"depth":   depth + 2,
+				"target":  "e",
+				"context": context + "[*]",
 			})
 			elemVal = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), elemVal, Tabs(depth+1))
 		}
@@ -201,6 +235,24 @@ func (v *Validator) hashValCode(att *design.AttributeDefinition, nonzero, requir
 	return buf.Bytes()
 }
 
+func (v *Validator) unionValCode(u *design.Union, target, context string, depth int) []byte {
+	var types []map[string]interface{}
+	for _, t := range u.Types {
+		types = append(types, map[string]interface{}{
+			"name":     GoTypeName(t, nil, 0, false),
+			"validate": hasValidations(t, false),
+		})
+	}
+	data := map[string]interface{}{
+		"types":         types,
+		"discriminator": u.Discriminator,
+		"context":       context,
+		"target":        target,
+		"depth":         depth,
+	}
+	return []byte(RunTemplate(v.unionValT, data))
+}
+
 func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) *bytes.Buffer {
 	var (
 		buf   = new(bytes.Buffer)
@@ -246,6 +298,8 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 		buf.Write(v.arrayValCode(att, nonzero, required, hasDefault, target, context, depth, private))
 	} else if h := att.Type.ToHash(); h != nil {
 		buf.Write(v.hashValCode(att, nonzero, required, hasDefault, target, context, depth, private))
+	} else if u := att.Type.ToUnion(); u != nil {
+		buf.Write(v.unionValCode(u, target, context, depth))
 	} else {
 		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
 		if validation != "" {
@@ -257,42 +311,21 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 
 func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, target, context string, depth int, private bool) string {
 	var validation string
+	if catt.IsNullable() {
+		// Validate the wrapped value only when it is present and not null.
+		field := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true))
+		validation = v.recurse(catt, false, true, false, field+".Value", fmt.Sprintf("%s.%s", context, n), depth+1, false).String()
+		if validation == "" {
+			return ""
+		}
+		return fmt.Sprintf("%sif %s.Set && !%s.Null {\n%s\n%s}", Tabs(depth), field, field, validation, Tabs(depth))
+	}
 	if ds, ok := catt.Type.(design.DataStructure); ok {
-		// We need to check empirically whether there are validations to be
-		// generated, we can't just generate and check whether something was
-		// generated to avoid infinite recursions.
-		hasValidations := false
-		done := errors.New("done")
-		ds.Walk(func(a *design.AttributeDefinition) error {
-			if a.Validation != nil {
-				if private {
-					hasValidations = true
-					return done
-				}
-				// For public data structures there is a case where
-				// there is validation but no actual validation
-				// code: if the validation is a required validation
-				// that applies to attributes that cannot be nil or
-				// empty string i.e. primitive types other than
-				// string.
-				if !a.Validation.HasRequiredOnly() {
-					hasValidations = true
-					return done
-				}
-				for _, name := range a.Validation.Required {
-					att := a.Type.ToObject()[name]
-					if att != nil && (!att.Type.IsPrimitive() || att.Type.Kind() == design.StringKind) {
-						hasValidations = true
-						return done
-					}
-				}
-			}
-			return nil
-		})
-		if hasValidations {
+		if hasValidations(ds, private) {
 			validation = RunTemplate(v.userValT, map[string]interface{}{
-				"depth":  depth,
-				"target": fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
+				"depth":   depth,
+				"target":  fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
+				"context": fmt.Sprintf("%s.%s", context, n),
 			})
 		}
 	} else {
@@ -312,7 +345,7 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 		).String()
 	}
 	if validation != "" {
-		if catt.Type.IsObject() {
+		if catt.Type.IsObject() || catt.Type.IsUnion() {
 			validation = fmt.Sprintf("%sif %s.%s != nil {\n%s\n%s}",
 				Tabs(depth), target, GoifyAtt(catt, n, true), validation, Tabs(depth))
 		}
@@ -320,6 +353,53 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 	return validation
 }
 
+// hasValidations returns true if validation code is generated for the given data structure.
+func hasValidations(ds design.DataStructure, private bool) bool {
+	// We need to check empirically whether there are validations to be
+	// generated, we can't just generate and check whether something was
+	// generated to avoid infinite recursions.
+	res := false
+	done := errors.New("done")
+	ds.Walk(func(a *design.AttributeDefinition) error {
+		if a.Type.IsUnion() {
+			// Unions always validate the presence of a value.
+			res = true
+			return done
+		}
+		if a.Validation != nil {
+			if private {
+				res = true
+				return done
+			}
+			// For public data structures there is a case where
+			// there is validation but no actual validation
+			// code: if the validation is a required validation
+			// that applies to attributes that cannot be nil or
+			// empty string i.e. primitive types other than
+			// string.
+			if !a.Validation.HasRequiredOnly() {
+				res = true
+				return done
+			}
+			for _, name := range a.Validation.Required {
+				att := a.Type.ToObject()[name]
+				if att != nil && (!att.Type.IsPrimitive() || att.Type.Kind() == design.StringKind || att.IsNullable()) {
+					res = true
+					return done
+				}
+			}
+		}
+		return nil
+	})
+	return res
+}
+
+// HasValidateMethod returns true if the Go type generated for the given user type or media type
+// defines a Validate method.
+func HasValidateMethod(ds design.DataStructure) bool {
+	return NewValidator().Code(ds.Definition(), false, false, false, "ut", "type", 1, false) != ""
+}
+
 // ValidationChecker produces Go code that runs the validation defined in the given attribute
 // definition against the content of the variable named target recursively.
 // context is used to keep track of recursion to produce helpful error messages in case of type
@@ -333,9 +413,13 @@ func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDe
 	}
 	t := target
 	isPointer := private || (!required && !hasDefault && !nonzero)
-	if isPointer && att.Type.IsPrimitive() {
+	if isPointer && att.Type.IsPrimitive() && att.Type.Kind() != design.BytesKind {
 		t = "*" + t
 	}
+	if EnumTypeName(att) != "" {
+		// Validate the underlying value of generated enum types
+		t = fmt.Sprintf("%s(%s)", GoNativeType(att.Type), t)
+	}
 	data := map[string]interface{}{
 		"attribute": att,
 		"isPointer": private || isPointer,
@@ -374,29 +458,55 @@ func validationsCode(att *design.AttributeDefinition, data map[string]interface{
 		}
 	}
 	if min := validation.Minimum; min != nil {
-		if att.Type == design.Integer {
-			data["min"] = renderInteger(*min)
-		} else {
-			data["min"] = fmt.Sprintf("%f", *min)
-		}
+		data["min"] = renderNumber(att.Type, *min)
 		data["isMin"] = true
+		data["exclusive"] = false
 		delete(data, "max")
 		if val := RunTemplate(minMaxValT, data); val != "" {
 			res = append(res, val)
 		}
 	}
 	if max := validation.Maximum; max != nil {
-		if att.Type == design.Integer {
-			data["max"] = renderInteger(*max)
-		} else {
-			data["max"] = fmt.Sprintf("%f", *max)
+		data["max"] = renderNumber(att.Type, *max)
+		data["isMin"] = false
+		data["exclusive"] = false
+		delete(data, "min")
+		if val := RunTemplate(minMaxValT, data); val != "" {
+			res = append(res, val)
 		}
+	}
+	if min := validation.ExclusiveMinimum; min != nil {
+		data["min"] = renderNumber(att.Type, *min)
+		data["isMin"] = true
+		data["exclusive"] = true
+		delete(data, "max")
+		if val := RunTemplate(minMaxValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if max := validation.ExclusiveMaximum; max != nil {
+		data["max"] = renderNumber(att.Type, *max)
 		data["isMin"] = false
+		data["exclusive"] = true
 		delete(data, "min")
 		if val := RunTemplate(minMaxValT, data); val != "" {
 			res = append(res, val)
 		}
 	}
+	if multipleOf := validation.MultipleOf; multipleOf != nil {
+		if design.IsInteger(att.Type) {
+			data["multipleOf"] = renderInteger(*multipleOf)
+			data["integer"] = true
+		} else {
+			// Use the shortest representation that preserves the value, "%f" would
+			// truncate small factors to 0.
+			data["multipleOf"] = strconv.FormatFloat(*multipleOf, 'g', -1, 64)
+			data["integer"] = false
+		}
+		if val := RunTemplate(multipleOfValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if minLength := validation.MinLength; minLength != nil {
 		data["minLength"] = minLength
 		data["isMinLength"] = true
@@ -413,6 +523,25 @@ func validationsCode(att *design.AttributeDefinition, data map[string]interface{
 			res = append(res, val)
 		}
 	}
+	if validation.UniqueItems {
+		if val := RunTemplate(uniqueItemsValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if minProperties := validation.MinProperties; minProperties != nil {
+		data["properties"] = *minProperties
+		data["isMinProperties"] = true
+		if val := RunTemplate(propertiesValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if maxProperties := validation.MaxProperties; maxProperties != nil {
+		data["properties"] = *maxProperties
+		data["isMinProperties"] = false
+		if val := RunTemplate(propertiesValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if required := validation.Required; len(required) > 0 {
 		var val string
 		for i, r := range required {
@@ -424,9 +553,130 @@ func validationsCode(att *design.AttributeDefinition, data map[string]interface{
 		}
 		res = append(res, val)
 	}
+	res = append(res, conditionalsCode(att, data)...)
+	return
+}
+
+// conditionalsCode produces the code that runs the conditional required validations of the
+// object attribute att.
+func conditionalsCode(att *design.AttributeDefinition, data map[string]interface{}) (res []string) {
+	validation := att.Validation
+	target, _ := data["target"].(string)
+	private, _ := data["private"].(bool)
+	missing := func(names []string) []map[string]string {
+		var checks []map[string]string
+		for _, n := range names {
+			// Fields that are always set need no check.
+			if _, unset := fieldSetCode(att, n, target, private); unset != "" {
+				checks = append(checks, map[string]string{"name": n, "missing": unset})
+			}
+		}
+		return checks
+	}
+	for _, r := range validation.RequiredIf {
+		checks := missing(r.Required)
+		if len(checks) == 0 {
+			continue
+		}
+		data["condition"] = fieldEqualCode(att, r.Attribute, target, private, r.Value)
+		data["attName"] = r.Attribute
+		data["value"] = fmt.Sprintf("%#v", r.Value)
+		data["checks"] = checks
+		res = append(res, RunTemplate(requiredIfValT, data))
+	}
+	names := make([]string, 0, len(validation.DependentRequired))
+	for n := range validation.DependentRequired {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		checks := missing(validation.DependentRequired[n])
+		if len(checks) == 0 {
+			continue
+		}
+		data["set"], _ = fieldSetCode(att, n, target, private)
+		data["attName"] = n
+		data["checks"] = checks
+		res = append(res, RunTemplate(dependentValT, data))
+	}
+	for _, group := range validation.MutuallyExclusive {
+		var set []string
+		for _, n := range group {
+			if s, _ := fieldSetCode(att, n, target, private); s != "" {
+				set = append(set, s)
+			}
+		}
+		var pairs []string
+		for i, s := range set {
+			for _, o := range set[i+1:] {
+				pairs = append(pairs, s+" && "+o)
+			}
+		}
+		if len(pairs) == 0 {
+			continue
+		}
+		data["condition"] = strings.Join(pairs, " || ")
+		data["names"] = fmt.Sprintf("%#v", group)
+		res = append(res, RunTemplate(exclusiveValT, data))
+	}
 	return
 }
 
+// fieldSetCode returns the Go expressions that test whether the struct field generated for the
+// child attribute n of the object attribute att is set and unset respectively. It returns empty
+// strings if the field cannot be unset, e.g. for required primitive fields of public structs.
+func fieldSetCode(att *design.AttributeDefinition, n, target string, private bool) (set, unset string) {
+	catt := att.Type.ToObject()[n]
+	field := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true))
+	switch {
+	case catt.IsNullable():
+		return field + ".Set", "!" + field + ".Set"
+	case private || !catt.Type.IsPrimitive() || att.IsPrimitivePointer(n) ||
+		att.IsInterface(n) || att.IsBytes(n):
+		return field + " != nil", field + " == nil"
+	case catt.Type.Kind() == design.StringKind:
+		return field + ` != ""`, field + ` == ""`
+	}
+	return "", ""
+}
+
+// fieldEqualCode returns the Go expression that tests whether the struct field generated for the
+// primitive child attribute n of the object attribute att is set to val.
+func fieldEqualCode(att *design.AttributeDefinition, n, target string, private bool, val interface{}) string {
+	catt := att.Type.ToObject()[n]
+	field := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true))
+	value := field
+	switch {
+	case catt.IsNullable():
+		value = field + ".Value"
+	case private || att.IsPrimitivePointer(n):
+		value = "*" + field
+	}
+	cond := fmt.Sprintf("%s == %#v", value, val)
+	if b, ok := val.(bool); ok {
+		cond = value
+		if !b {
+			cond = "!" + value
+		}
+	}
+	if catt.IsNullable() {
+		cond = fmt.Sprintf("!%s.Null && %s", field, cond)
+	}
+	if set, _ := fieldSetCode(att, n, target, private); set != "" && set != field+` != ""` {
+		cond = fmt.Sprintf("%s && %s", set, cond)
+	}
+	return cond
+}
+
+// renderNumber renders a max or min value using the integer or the float representation
+// depending on the attribute type.
+func renderNumber(t design.DataType, f float64) string {
+	if design.IsInteger(t) {
+		return renderInteger(f)
+	}
+	return fmt.Sprintf("%f", f)
+}
+
 // renderInteger renders a max or min value properly, taking into account
 // overflows due to casting from a float value.
 func renderInteger(f float64) string {
@@ -481,18 +731,33 @@ func constant(formatName string) string {
 }
 
 const (
-	arrayValTmpl = `{{ tabs .depth }}for _, e := range {{ .target }} {
+	arrayValTmpl = `{{ tabs .depth }}for i, e := range {{ .target }} {
+{{ tabs .depth }}	outer := err
+{{ tabs .depth }}	err = nil
 {{ .validation }}
+{{ tabs .depth }}	err = goa.MergeErrors(outer, goa.IndexViolations(err, i))
 {{ tabs .depth }}}`
 
-	hashValTmpl = `{{ tabs .depth }}for {{ if .keyValidation }}k{{ else }}_{{ end }}, {{ if .elemValidation }}e{{ else }}_{{ end }} := range {{ .target }} {
+	hashValTmpl = `{{ tabs .depth }}for k, {{ if .elemValidation }}e{{ else }}_{{ end }} := range {{ .target }} {
+{{ tabs .depth }}	outer := err
+{{ tabs .depth }}	err = nil
 {{- if .keyValidation }}
 {{ .keyValidation }}{{ end }}{{ if .elemValidation }}
 {{ .elemValidation }}{{ end }}
+{{ tabs .depth }}	err = goa.MergeErrors(outer, goa.IndexViolations(err, k))
 {{ tabs .depth }}}`
 
 	userValTmpl = `{{ tabs .depth }}if err2 := {{ .target }}.Validate(); err2 != nil {
-{{ tabs .depth }}	err = goa.MergeErrors(err, err2)
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.PrefixViolations(err2, ` + "`" + `{{ .context }}` + "`" + `))
+{{ tabs .depth }}}`
+
+	unionValTmpl = `{{ tabs .depth }}switch v := {{ .target }}.Value.(type) {
+{{ range .types }}{{ tabs $.depth }}case *{{ .name }}:{{ if .validate }}
+{{ tabs $.depth }}	if err2 := v.Validate(); err2 != nil {
+{{ tabs $.depth }}		err = goa.MergeErrors(err, goa.PrefixViolations(err2, ` + "`" + `{{ $.context }}` + "`" + `))
+{{ tabs $.depth }}	}{{ end }}
+{{ end }}{{ tabs .depth }}case nil:
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ .context }}` + "`" + `, "{{ .discriminator }}"))
 {{ tabs .depth }}}`
 
 	enumValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
@@ -518,11 +783,18 @@ const (
 
 	minMaxValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
-{{ end }}{{ tabs .depth }}	if {{ .targetVal }} {{ if .isMin }}<{{ else }}>{{ end }} {{ if .isMin }}{{ .min }}{{ else }}{{ .max }}{{ end }} {
-{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
+{{ end }}{{ tabs .depth }}	if {{ .targetVal }} {{ if .isMin }}<{{ else }}>{{ end }}{{ if .exclusive }}={{ end }} {{ if .isMin }}{{ .min }}{{ else }}{{ .max }}{{ end }} {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.{{ if .exclusive }}InvalidExclusiveRangeError{{ else }}InvalidRangeError{{ end }}(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
+	multipleOfValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs $depth }}if {{ if .integer }}{{ .targetVal }}%{{ .multipleOf }} != 0{{ else }}!goa.ValidateMultipleOf(float64({{ .targetVal }}), {{ .multipleOf }}){{ end }} {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ .multipleOf }}))
+{{ tabs $depth }}}{{ if .isPointer }}
+{{ tabs .depth }}}{{ end }}`
+
 	lengthValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ $target := or (and (or (or .array .hash) .nonzero) .target) .targetVal }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
@@ -531,10 +803,41 @@ const (
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
+	uniqueItemsValTmpl = `{{ tabs .depth }}if !goa.ValidateUniqueItems({{ .target }}) {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `{{ .context }}` + "`" + `, {{ .target }}))
+{{ tabs .depth }}}`
+
+	propertiesValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs $depth }}if len({{ .target }}) {{ if .isMinProperties }}<{{ else }}>{{ end }} {{ .properties }} {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidPropertiesCountError(` + "`" + `{{ .context }}` + "`" + `, {{ .target }}, len({{ .target }}), {{ .properties }}, {{ .isMinProperties }}))
+{{ tabs $depth }}}{{ if .isPointer }}
+{{ tabs .depth }}}{{ end }}`
+
 	requiredValTmpl = `{{ $att := index $.attribute.Type.ToObject .required }}{{/*
-*/}}{{ if and (not $.private) (eq $att.Type.Kind 4) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == "" {
+*/}}{{ if $att.IsNullable }}{{ tabs $.depth }}if !{{ $.target }}.{{ goifyAtt $att .required true }}.Set {
+{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{ .required }}"))
+{{ tabs $.depth }}}{{ else if and (not $.private) (eq $att.Type.Kind 4) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == "" {
 {{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{  .required  }}"))
 {{ tabs $.depth }}}{{ else if or $.private (not $att.Type.IsPrimitive) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == nil {
 {{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{ .required }}"))
 {{ tabs $.depth }}}{{ end }}`
+
+	requiredIfValTmpl = `{{ tabs .depth }}if {{ .condition }} {
+{{ range .checks }}{{ tabs $.depth }}	if {{ .missing }} {
+{{ tabs $.depth }}		err = goa.MergeErrors(err, goa.MissingConditionalAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{ .name }}", "{{ $.attName }}", {{ $.value }}))
+{{ tabs $.depth }}	}
+{{ end }}{{ tabs .depth }}}`
+
+	dependentValTmpl = `{{ $depth := or (and .set (add .depth 1)) .depth }}{{/*
+*/}}{{ if .set }}{{ tabs .depth }}if {{ .set }} {
+{{ end }}{{ range $i, $c := .checks }}{{ if $i }}
+{{ end }}{{ tabs $depth }}if {{ $c.missing }} {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.MissingDependentAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{ $c.name }}", "{{ $.attName }}"))
+{{ tabs $depth }}}{{ end }}{{ if .set }}
+{{ tabs .depth }}}{{ end }}`
+
+	exclusiveValTmpl = `{{ tabs .depth }}if {{ .condition }} {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.MutuallyExclusiveAttributesError(` + "`" + `{{ .context }}` + "`" + `, {{ .names }}))
+{{ tabs .depth }}}`
 )
@@ -101,6 +101,97 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of exclusive min value 0", func() {
+				BeforeEach(func() {
+					attType = design.Integer
+					min := 0.0
+					validation = &dslengine.ValidationDefinition{
+						ExclusiveMinimum: &min,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(exclusiveMinValCode))
+				})
+			})
+
+			Context("of exclusive max value 1.5", func() {
+				BeforeEach(func() {
+					attType = design.Number
+					max := 1.5
+					validation = &dslengine.ValidationDefinition{
+						ExclusiveMaximum: &max,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(exclusiveMaxValCode))
+				})
+			})
+
+			Context("of integer multiple of 5", func() {
+				BeforeEach(func() {
+					attType = design.Integer
+					m := 5.0
+					validation = &dslengine.ValidationDefinition{
+						MultipleOf: &m,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(integerMultipleOfValCode))
+				})
+			})
+
+			Context("of number multiple of 0.01", func() {
+				BeforeEach(func() {
+					attType = design.Float32
+					m := 0.01
+					validation = &dslengine.ValidationDefinition{
+						MultipleOf: &m,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(numberMultipleOfValCode))
+				})
+			})
+
+			Context("of array unique items", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.String,
+						},
+					}
+					validation = &dslengine.ValidationDefinition{
+						UniqueItems: true,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(uniqueItemsValCode))
+				})
+			})
+
+			Context("of hash min and max properties", func() {
+				BeforeEach(func() {
+					attType = &design.Hash{
+						KeyType:  &design.AttributeDefinition{Type: design.String},
+						ElemType: &design.AttributeDefinition{Type: design.String},
+					}
+					min, max := 1, 3
+					validation = &dslengine.ValidationDefinition{
+						MinProperties: &min,
+						MaxProperties: &max,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(propertiesValCode))
+				})
+			})
+
 			Context("of array min length 1", func() {
 				BeforeEach(func() {
 					attType = &design.Array{
@@ -217,6 +308,30 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of object with conditional required validations", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"kind":    &design.AttributeDefinition{Type: design.String},
+						"express": &design.AttributeDefinition{Type: design.Boolean},
+						"number":  &design.AttributeDefinition{Type: design.String},
+						"email":   &design.AttributeDefinition{Type: design.String},
+						"phone":   &design.AttributeDefinition{Type: design.String},
+					}
+					validation = &dslengine.ValidationDefinition{
+						RequiredIf: []*dslengine.RequiredIfDefinition{
+							{Attribute: "kind", Value: "card", Required: []string{"number"}},
+							{Attribute: "express", Value: true, Required: []string{"phone"}},
+						},
+						DependentRequired: map[string][]string{"email": {"kind"}},
+						MutuallyExclusive: [][]string{{"email", "phone"}},
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(conditionalsValCode))
+				})
+			})
+
 			Context("of embedded object", func() {
 				var catt, ccatt *design.AttributeDefinition
 
@@ -332,6 +447,35 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of union", func() {
+				BeforeEach(func() {
+					cat := &design.UserTypeDefinition{
+						TypeName: "Cat",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"kind":  &design.AttributeDefinition{Type: design.String},
+								"lives": &design.AttributeDefinition{Type: design.Integer},
+							},
+							Validation: &dslengine.ValidationDefinition{Required: []string{"kind"}},
+						},
+					}
+					dog := &design.UserTypeDefinition{
+						TypeName: "Dog",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"kind": &design.AttributeDefinition{Type: design.String},
+							},
+						},
+					}
+					attType = &design.Union{Discriminator: "kind", Types: []*design.UserTypeDefinition{cat, dog}}
+					validation = nil
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(unionValCode))
+				})
+			})
+
 			Context("with a custom type metadata", func() {
 				JustBeforeEach(func() {
 					att.Metadata = map[string][]string{"struct:field:type": {"foo"}}
@@ -351,6 +495,29 @@ var _ = Describe("validation code generation", func() {
 			})
 		})
 	})
+
+	Describe("HasValidateMethod", func() {
+		It("returns true for types with validations", func() {
+			ut := &design.UserTypeDefinition{
+				TypeName: "Foo",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:       design.Object{"foo": &design.AttributeDefinition{Type: design.String}},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"foo"}},
+				},
+			}
+			Ω(codegen.HasValidateMethod(ut)).Should(BeTrue())
+		})
+
+		It("returns false for types without validations", func() {
+			ut := &design.UserTypeDefinition{
+				TypeName: "Foo",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"foo": &design.AttributeDefinition{Type: design.String}},
+				},
+			}
+			Ω(codegen.HasValidateMethod(ut)).Should(BeFalse())
+		})
+	})
 })
 
 const (
@@ -384,37 +551,107 @@ const (
 		}
 	}`
 
+	exclusiveMinValCode = `	if val != nil {
+		if *val <= 0 {
+			err = goa.MergeErrors(err, goa.InvalidExclusiveRangeError(` + "`" + `context` + "`" + `, *val, 0, true))
+		}
+	}`
+
+	exclusiveMaxValCode = `	if val != nil {
+		if *val >= 1.500000 {
+			err = goa.MergeErrors(err, goa.InvalidExclusiveRangeError(` + "`" + `context` + "`" + `, *val, 1.500000, false))
+		}
+	}`
+
+	integerMultipleOfValCode = `	if val != nil {
+		if *val%5 != 0 {
+			err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`" + `context` + "`" + `, *val, 5))
+		}
+	}`
+
+	numberMultipleOfValCode = `	if val != nil {
+		if !goa.ValidateMultipleOf(float64(*val), 0.01) {
+			err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`" + `context` + "`" + `, *val, 0.01))
+		}
+	}`
+
+	uniqueItemsValCode = `	if !goa.ValidateUniqueItems(val) {
+		err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `context` + "`" + `, val))
+	}`
+
+	propertiesValCode = `	if val != nil {
+		if len(val) < 1 {
+			err = goa.MergeErrors(err, goa.InvalidPropertiesCountError(` + "`" + `context` + "`" + `, val, len(val), 1, true))
+		}
+	}
+	if val != nil {
+		if len(val) > 3 {
+			err = goa.MergeErrors(err, goa.InvalidPropertiesCountError(` + "`" + `context` + "`" + `, val, len(val), 3, false))
+		}
+	}`
+
+	conditionalsValCode = `	if val.Kind != nil && *val.Kind == "card" {
+		if val.Number == nil {
+			err = goa.MergeErrors(err, goa.MissingConditionalAttributeError(` + "`" + `context` + "`" + `, "number", "kind", "card"))
+		}
+	}
+	if val.Express != nil && *val.Express {
+		if val.Phone == nil {
+			err = goa.MergeErrors(err, goa.MissingConditionalAttributeError(` + "`" + `context` + "`" + `, "phone", "express", true))
+		}
+	}
+	if val.Email != nil {
+		if val.Kind == nil {
+			err = goa.MergeErrors(err, goa.MissingDependentAttributeError(` + "`" + `context` + "`" + `, "kind", "email"))
+		}
+	}
+	if val.Email != nil && val.Phone != nil {
+		err = goa.MergeErrors(err, goa.MutuallyExclusiveAttributesError(` + "`" + `context` + "`" + `, []string{"email", "phone"}))
+	}`
+
 	arrayMinLengthValCode = `	if val != nil {
 		if len(val) < 1 {
 			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `context` + "`" + `, val, len(val), 1, true))
 		}
 	}`
 
-	arrayElementsValCode = `	for _, e := range val {
+	arrayElementsValCode = `	for i, e := range val {
+		outer := err
+		err = nil
 		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, e); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, e, ` + "`" + `.*` + "`" + `))
 		}
+		err = goa.MergeErrors(outer, goa.IndexViolations(err, i))
 	}`
 
 	hashKeyElemValCode = `	for k, e := range val {
+		outer := err
+		err = nil
 		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, k); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, k, ` + "`" + `.*` + "`" + `))
 		}
 		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, e); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, e, ` + "`" + `.*` + "`" + `))
 		}
+		err = goa.MergeErrors(outer, goa.IndexViolations(err, k))
 	}`
 
 	hashKeyValCode = `	for k, _ := range val {
+		outer := err
+		err = nil
 		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, k); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, k, ` + "`" + `.*` + "`" + `))
 		}
+		err = goa.MergeErrors(outer, goa.IndexViolations(err, k))
 	}`
 
-	hashElemValCode = `	for _, e := range val {
+	hashElemValCode = `	for k, e := range val {
+		outer := err
+		err = nil
 		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, e); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, e, ` + "`" + `.*` + "`" + `))
 		}
+		err = goa.MergeErrors(outer, goa.IndexViolations(err, k))
 	}`
 
 	stringMinLengthValCode = `	if val != nil {
@@ -462,11 +699,24 @@ const (
 		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context`" + `, "foo"))
 	}`
 
-	utRequiredCode = `	for _, e := range val.Foo {
+	utRequiredCode = `	for i, e := range val.Foo {
+		outer := err
+		err = nil
 		if e != nil {
 			if err2 := e.Validate(); err2 != nil {
-				err = goa.MergeErrors(err, err2)
+				err = goa.MergeErrors(err, goa.PrefixViolations(err2, ` + "`context.foo[*]`" + `))
 			}
 		}
+		err = goa.MergeErrors(outer, goa.IndexViolations(err, i))
+	}`
+
+	unionValCode = `	switch v := val.Value.(type) {
+	case *Cat:
+		if err2 := v.Validate(); err2 != nil {
+			err = goa.MergeErrors(err, goa.PrefixViolations(err2, ` + "`context`" + `))
+		}
+	case *Dog:
+	case nil:
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context`" + `, "kind"))
 	}`
 )
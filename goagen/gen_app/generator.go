@@ -95,6 +95,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateSecurity(); err != nil {
 		return nil, err
 	}
+	if err := g.generateErrors(); err != nil {
+		return nil, err
+	}
 	if err := g.generateHrefs(); err != nil {
 		return nil, err
 	}
@@ -104,6 +107,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateUserTypes(); err != nil {
 		return nil, err
 	}
+	if err := g.generateWebhooks(); err != nil {
+		return nil, err
+	}
 	if !g.NoTest {
 		if err := g.generateResourceTest(); err != nil {
 			return nil, err
@@ -144,6 +150,7 @@ func (g *Generator) generateContexts() (err error) {
 	}()
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/base64"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("strconv"),
@@ -153,6 +160,7 @@ func (g *Generator) generateContexts() (err error) {
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 		codegen.SimpleImport("context"),
+		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
 	g.API.IterateResources(func(r *design.ResourceDefinition) error {
 		return r.IterateActions(func(a *design.ActionDefinition) error {
@@ -202,11 +210,15 @@ func (g *Generator) generateContexts() (err error) {
 				Payload:      a.Payload,
 				Params:       params,
 				Headers:      headers,
+				Cookies:      a.AllCookies(),
 				Routes:       a.Routes,
 				Responses:    non101,
 				API:          g.API,
 				DefaultPkg:   g.Target,
 				Security:     a.Security,
+				Inbound:      a.InboundMessage,
+				Outbound:     a.OutboundMessage,
+				Async:        a.Async != nil,
 			}
 			return ctxWr.Execute(&ctxData)
 		})
@@ -236,6 +248,7 @@ func (g *Generator) generateControllers() (err error) {
 	}()
 	title := fmt.Sprintf("%s: Application Controllers", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/base64"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("context"),
@@ -317,6 +330,7 @@ func (g *Generator) generateControllers() (err error) {
 				"PayloadOptional":  a.PayloadOptional,
 				"PayloadMultipart": a.PayloadMultipart,
 				"Security":         a.Security,
+				"Deprecation":      a.Deprecation,
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
@@ -373,6 +387,48 @@ func (g *Generator) generateSecurity() (err error) {
 	return
 }
 
+// generateErrors generates the error classes and constructors of the errors declared in the
+// design.
+func (g *Generator) generateErrors() (err error) {
+	var errs []*design.ErrorDefinition
+	g.API.IterateErrors(func(e *design.ErrorDefinition) error {
+		errs = append(errs, e)
+		return nil
+	})
+	if len(errs) == 0 {
+		return nil
+	}
+
+	var (
+		errFile string
+		errWr   *ErrorsWriter
+	)
+	{
+		errFile = filepath.Join(g.OutDir, "errors.go")
+		errWr, err = NewErrorsWriter(errFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		errWr.Close()
+		if err == nil {
+			err = errWr.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Errors", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	if err = errWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, errFile)
+	err = errWr.Execute(errs)
+
+	return
+}
+
 // generateHrefs iterates through the API resources and generates the href factory methods.
 func (g *Generator) generateHrefs() (err error) {
 	var (
@@ -445,6 +501,7 @@ func (g *Generator) generateMediaTypes() (err error) {
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
@@ -461,7 +518,7 @@ func (g *Generator) generateMediaTypes() (err error) {
 		if mt.IsError() {
 			return nil
 		}
-		if mt.Type.IsObject() || mt.Type.IsArray() {
+		if mt.Type.IsObject() || mt.Type.IsArray() || mt.Type.IsUnion() {
 			return mtWr.Execute(mt)
 		}
 		return nil
@@ -491,6 +548,7 @@ func (g *Generator) generateUserTypes() (err error) {
 	}()
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("mime/multipart"),
 		codegen.SimpleImport("time"),
@@ -508,5 +566,62 @@ func (g *Generator) generateUserTypes() (err error) {
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
 		return utWr.Execute(t)
 	})
+	if err != nil {
+		return
+	}
+	err = utWr.ExecuteNullableTypes(g.API)
+	if err != nil {
+		return
+	}
+	err = utWr.ExecuteEnumTypes(g.API)
 	return
 }
+
+// generateWebhooks iterates through the API webhooks and generates the data structures and
+// functions used to send them.
+func (g *Generator) generateWebhooks() (err error) {
+	var hasWebhooks bool
+	g.API.IterateWebhooks(func(*design.WebhookDefinition) error {
+		hasWebhooks = true
+		return nil
+	})
+	if !hasWebhooks {
+		return nil
+	}
+	var (
+		whFile string
+		whWr   *WebhooksWriter
+	)
+	{
+		whFile = filepath.Join(g.OutDir, "webhooks.go")
+		whWr, err = NewWebhooksWriter(whFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		whWr.Close()
+		if err == nil {
+			err = whWr.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Webhooks", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	g.API.IterateWebhooks(func(wh *design.WebhookDefinition) error {
+		imports = codegen.AttributeImports(wh.Payload.AttributeDefinition, imports, nil)
+		return nil
+	})
+	if err = whWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, whFile)
+	return g.API.IterateWebhooks(whWr.Execute)
+}
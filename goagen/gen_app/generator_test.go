@@ -2,13 +2,16 @@ package genapp_test
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_app"
@@ -17,6 +20,10 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// dslAPI is the API definition registered with the DSL engine, the tests that define the design
+// with struct literals replace design.Design.
+var dslAPI = design.Design
+
 var _ = Describe("Generate", func() {
 	var workspace *codegen.Workspace
 	var outDir string
@@ -272,8 +279,77 @@ var _ = Describe("Generate", func() {
 			})
 		})
 	})
+
+	Context("with a union payload", func() {
+		BeforeEach(func() {
+			design.Design = dslAPI
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("union API")
+			})
+			cat := apidsl.Type("Cat", func() {
+				apidsl.Attribute("kind", design.String, func() {
+					apidsl.EnumValue("cat", "A cat")
+				})
+				apidsl.Attribute("lives", design.Integer)
+				apidsl.Required("kind")
+			})
+			dog := apidsl.Type("Dog", func() {
+				apidsl.Attribute("kind", design.String)
+				apidsl.Attribute("breed", design.String)
+			})
+			pet := apidsl.Type("Pet", func() {
+				apidsl.OneOf("kind", cat, dog)
+			})
+			apidsl.Resource("pets", func() {
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST("/pets"))
+					apidsl.Payload(pet)
+					apidsl.Response(design.NoContent)
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("sets the discriminator when marshaling the union", func() {
+			Ω(genErr).Should(BeNil())
+			pkg, err := workspace.NewPackage("roundtrip")
+			Ω(err).ShouldNot(HaveOccurred())
+			main := fmt.Sprintf(unionRoundTripCode, filepath.Base(outDir))
+			Ω(ioutil.WriteFile(filepath.Join(pkg.Abs(), "main.go"), []byte(main), 0644)).Should(Succeed())
+			bin, err := pkg.Compile("roundtrip")
+			Ω(err).ShouldNot(HaveOccurred())
+			out, err := exec.Command(bin).CombinedOutput()
+			Ω(err).ShouldNot(HaveOccurred(), string(out))
+			Ω(string(out)).Should(Equal(`{"kind":"cat"} *app.Cat` + "\n" + `{"kind":"Dog"} *app.Dog` + "\n"))
+		})
+	})
 })
 
+const unionRoundTripCode = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"%s/app"
+)
+
+func main() {
+	for _, v := range []app.PetValue{&app.Cat{}, &app.Dog{}} {
+		b, err := json.Marshal(app.Pet{Value: v})
+		if err != nil {
+			panic(err)
+		}
+		var p app.Pet
+		if err := json.Unmarshal(b, &p); err != nil {
+			panic(err)
+		}
+		fmt.Printf("%%s %%T\n", b, p.Value)
+	}
+}
+`
+
 var _ = Describe("NewGenerator", func() {
 	var generator *genapp.Generator
 
@@ -43,6 +43,7 @@ type TestMethod struct {
 	Params            []*ObjectType
 	QueryParams       []*ObjectType
 	Headers           []*ObjectType
+	Cookies           []*ObjectType
 	Payload           *ObjectType
 	reservedNames     map[string]bool
 }
@@ -63,6 +64,8 @@ type ObjectType struct {
 	Type        string
 	Pointer     string
 	Validatable bool
+	Delimiter   string
+	DeepObject  bool
 }
 
 func (g *Generator) generateResourceTest() error {
@@ -70,7 +73,8 @@ func (g *Generator) generateResourceTest() error {
 		return nil
 	}
 	funcs := template.FuncMap{
-		"isSlice": isSlice,
+		"isSlice":      isSlice,
+		"setParamData": setParamData,
 	}
 	testTmpl := template.Must(template.New("test").Funcs(funcs).Parse(testTmpl))
 	outDir, err := makeTestDir(g, g.API.Name)
@@ -162,6 +166,7 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 		path                                         []*ObjectType
 		query                                        []*ObjectType
 		header                                       []*ObjectType
+		cookie                                       []*ObjectType
 		returnType                                   *ObjectType
 		payload                                      *ObjectType
 	)
@@ -188,10 +193,10 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 		validate := g.validator.Code(p.AttributeDefinition, false, false, false, "payload", "raw", 1, false)
 		returnType = &ObjectType{}
 		returnType.Type = tmp
-		if p.IsObject() && !p.IsError() {
+		if (p.IsObject() || p.IsUnion()) && !p.IsError() {
 			returnType.Pointer = "*"
 		}
-		returnType.Validatable = validate != ""
+		returnType.Validatable = validate != "" && !p.IsError()
 	}
 
 	comment = "runs the method " + actionName + " of the given controller with the given parameters"
@@ -207,6 +212,7 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 	path = pathParams(action, route)
 	query = queryParams(action)
 	header = headers(action, resource.Headers)
+	cookie = cookies(action)
 
 	if action.Payload != nil {
 		payload = &ObjectType{}
@@ -230,16 +236,17 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 		Params:            path,
 		QueryParams:       query,
 		Headers:           header,
+		Cookies:           cookie,
 		Payload:           payload,
 		ReturnType:        returnType,
-		ReturnsErrorMedia: mediaType == design.ErrorMedia,
+		ReturnsErrorMedia: mediaType != nil && mediaType.IsError(),
 		ControllerName:    fmt.Sprintf("%s.%sController", g.Target, ctrlName),
 		ContextVarName:    fmt.Sprintf("%sCtx", varName),
 		ContextType:       fmt.Sprintf("%s.New%s%sContext", g.Target, actionName, ctrlName),
 		RouteVerb:         route.Verb,
 		Status:            response.Status,
 		FullPath:          goPathFormat(route.FullPath()),
-		reservedNames:     reservedNames(path, query, header, payload, returnType),
+		reservedNames:     reservedNames(path, query, header, cookie, payload, returnType),
 	}
 }
 
@@ -279,6 +286,25 @@ func headers(action *design.ActionDefinition, headers *design.AttributeDefinitio
 	return objs
 }
 
+// cookies builds the template data structure needed to properly render the code for setting
+// the cookies for the given action.
+func cookies(action *design.ActionDefinition) []*ObjectType {
+	cks := action.AllCookies()
+	if cks == nil {
+		return nil
+	}
+	var names []string
+	for name := range cks.Type.ToObject() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	objs := make([]*ObjectType, len(names))
+	for i, name := range names {
+		objs[i] = attToObject(name, cks, cks.Type.ToObject()[name])
+	}
+	return objs
+}
+
 // queryParams returns the query string params for the given action.
 func queryParams(action *design.ActionDefinition) []*ObjectType {
 	var qparams []string
@@ -288,7 +314,14 @@ func queryParams(action *design.ActionDefinition) []*ObjectType {
 		}
 	}
 	sort.Strings(qparams)
-	return paramFromNames(action, qparams)
+	params := paramFromNames(action, qparams)
+	obj := action.Params.Type.ToObject()
+	for _, p := range params {
+		att := obj[p.Label]
+		p.Delimiter = att.ParamDelimiter()
+		p.DeepObject = att.Type.IsHash()
+	}
+	return params
 }
 
 func paramFromNames(action *design.ActionDefinition, names []string) (params []*ObjectType) {
@@ -299,7 +332,7 @@ func paramFromNames(action *design.ActionDefinition, names []string) (params []*
 	return
 }
 
-func reservedNames(params, queryParams, headers []*ObjectType, payload, returnType *ObjectType) map[string]bool {
+func reservedNames(params, queryParams, headers, cookies []*ObjectType, payload, returnType *ObjectType) map[string]bool {
 	var names = make(map[string]bool)
 	for _, param := range params {
 		names[param.Name] = true
@@ -310,6 +343,9 @@ func reservedNames(params, queryParams, headers []*ObjectType, payload, returnTy
 	for _, header := range headers {
 		names[header.Name] = true
 	}
+	for _, cookie := range cookies {
+		names[cookie.Name] = true
+	}
 	if payload != nil {
 		names[payload.Name] = true
 	}
@@ -345,6 +381,12 @@ func isSlice(typeName string) bool {
 	return strings.HasPrefix(typeName, "[]")
 }
 
+// setParamData returns the data given to the "setParam" template to set the values of the given
+// query string parameter in the url.Values variable with the given name.
+func setParamData(param *ObjectType, values string) map[string]interface{} {
+	return map[string]interface{}{"Param": param, "Values": values}
+}
+
 var convertParamTmpl = `{{ if eq .Type "string" }}		sliceVal := []string{ {{ if .Pointer }}*{{ end }}{{ .Name }}}{{/*
 */}}{{ else if eq .Type "int" }}		sliceVal := []string{strconv.Itoa({{ if .Pointer }}*{{ end }}{{ .Name }})}{{/*
 */}}{{ else if eq .Type "[]string" }}		sliceVal := {{ .Name }}{{/*
@@ -355,7 +397,14 @@ var convertParamTmpl = `{{ if eq .Type "string" }}		sliceVal := []string{ {{ if
 */}}{{ else if eq .Type "time.Time" }}		sliceVal := []string{ {{ if .Pointer }}(*{{ end }}{{ .Name }}{{ if .Pointer }}){{ end }}.Format(time.RFC3339)}{{/*
 */}}{{ else }}		sliceVal := []string{fmt.Sprintf("%v", {{ if .Pointer }}*{{ end }}{{ .Name }})}{{ end }}`
 
-var testTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}` + `
+// setParamTmpl generates the code that sets the values of a query string parameter in a
+// url.Values according to the parameter serialization style.
+var setParamTmpl = `{{ if .Param.DeepObject }}		for k, v := range {{ .Param.Name }} {
+			{{ .Values }}[{{ printf "%q" .Param.Label }}+"["+k+"]"] = []string{v}
+		}{{ else }}{{ template "convertParam" .Param }}
+		{{ .Values }}[{{ printf "%q" .Param.Label }}] = {{ if .Param.Delimiter }}[]string{strings.Join(sliceVal, {{ printf "%q" .Param.Delimiter }})}{{ else }}sliceVal{{ end }}{{ end }}`
+
+var testTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}` + `{{ define "setParam" }}` + setParamTmpl + `{{ end }}` + `
 {{ range $test := . }}
 // {{ $test.Name }} {{ $test.Comment }}
 // If ctx is nil then context.Background() is used.
@@ -364,6 +413,7 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 */}}{{ range $param := $test.Params }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
 */}}{{ range $param := $test.QueryParams }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
 */}}{{ range $header := $test.Headers }}, {{ $header.Name }} {{ $header.Pointer }}{{ $header.Type }}{{ end }}{{/*
+*/}}{{ range $cookie := $test.Cookies }}, {{ $cookie.Name }} {{ $cookie.Pointer }}{{ $cookie.Type }}{{ end }}{{/*
 */}}{{ if $test.Payload }}, {{ $test.Payload.Name }} {{ $test.Payload.Pointer }}{{ $test.Payload.Type }}{{ end }}){{/*
 */}} (http.ResponseWriter{{ if $test.ReturnType }}, {{ $test.ReturnType.Pointer }}{{ $test.ReturnType.Type }}{{ end }}) {
 	// Setup service
@@ -398,8 +448,7 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 	{{ $rw := $test.Escape "rw" }}{{ $rw }} := httptest.NewRecorder()
 {{ $query := $test.Escape "query" }}{{ if $test.QueryParams}}	{{ $query }} := url.Values{}
 {{ range $param := $test.QueryParams }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
-{{ template "convertParam" $param }}
-		{{ $query }}[{{ printf "%q" $param.Label }}] = sliceVal
+{{ template "setParam" (setParamData $param $query) }}
 	}
 {{ end }}{{ end }}	{{ $u := $test.Escape "u" }}{{ $u }}:= &url.URL{
 		Path: fmt.Sprintf({{ printf "%q" $test.FullPath }}{{ range $param := $test.Params }}, {{ $param.Name }}{{ end }}),
@@ -413,11 +462,14 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 {{ template "convertParam" $header }}
 		{{ $req }}.Header[{{ printf "%q" $header.Label }}] = sliceVal
 	}
+{{ end }}{{ range $cookie := $test.Cookies }}{{ if $cookie.Pointer }}	if {{ $cookie.Name }} != nil {{ end }}{
+{{ template "convertParam" $cookie }}
+		{{ $req }}.AddCookie(&http.Cookie{Name: {{ printf "%q" $cookie.Label }}, Value: sliceVal[0]})
+	}
 {{ end }} {{ $prms := $test.Escape "prms" }}{{ $prms }} := url.Values{}
 {{ range $param := $test.Params }}	{{ $prms }}["{{ $param.Label }}"] = []string{fmt.Sprintf("%v",{{ $param.Name}})}
 {{ end }}{{ range $param := $test.QueryParams }}{{ if $param.Pointer }} if {{ $param.Name }} != nil {{ end }} {
-{{ template "convertParam" $param }}
-		{{ $prms }}[{{ printf "%q" $param.Label }}] = sliceVal
+{{ template "setParam" (setParamData $param $prms) }}
 	}
 {{ end }}	if ctx == nil {
 		ctx = context.Background()
@@ -46,6 +46,11 @@ type (
 		SecurityTmpl *template.Template
 	}
 
+	// ErrorsWriter generate code for the errors declared in the design with "Error".
+	ErrorsWriter struct {
+		*codegen.SourceFile
+	}
+
 	// ResourcesWriter generate code for a goa application resources.
 	// Resources are data structures initialized by the application handlers and passed to controller
 	// actions.
@@ -71,6 +76,12 @@ type (
 		Validator    *codegen.Validator
 	}
 
+	// WebhooksWriter generate code for the webhooks sent by a goa application.
+	// Webhooks are defined in the DSL with "Webhook".
+	WebhooksWriter struct {
+		*UserTypesWriter
+	}
+
 	// ContextTemplateData contains all the information used by the template to render the context
 	// code for an action.
 	ContextTemplateData struct {
@@ -80,18 +91,32 @@ type (
 		Params       *design.AttributeDefinition
 		Payload      *design.UserTypeDefinition
 		Headers      *design.AttributeDefinition
+		Cookies      *design.AttributeDefinition
 		Routes       []*design.RouteDefinition
 		Responses    map[string]*design.ResponseDefinition
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		Inbound      design.DataType // Type of the messages received by a websocket action
+		Outbound     design.DataType // Type of the messages sent by a websocket action
+		Async        bool            // Async is true if the action starts a long-running operation
+	}
+
+	// ResponseHeaderData describes a header or cookie written by a generated response method.
+	ResponseHeaderData struct {
+		Name     string // Header or cookie name
+		Field    string // Name of the struct field holding the value
+		Pointer  bool   // Whether the struct field is a pointer
+		Value    string // Go expression converting the field value to a string
+		IsCookie bool   // Whether the value is written as a cookie
+		Cookie   string // Go code initializing the http.Cookie fields taken from the design
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
 	ControllerTemplateData struct {
 		API            *design.APIDefinition          // API definition
 		Resource       string                         // Lower case plural resource name, e.g. "bottles"
-		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Routes", "Context" and "Unmarshal"
+		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Routes", "Context", "Unmarshal" and "Deprecation"
 		FileServers    []*design.FileServerDefinition // File servers
 		Encoders       []*EncoderTemplateData         // Encoder data
 		Decoders       []*EncoderTemplateData         // Decoder data
@@ -217,6 +242,19 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 		"isPathParam":        data.IsPathParam,
 		"valueTypeOf":        valueTypeOf,
 		"fromString":         fromString,
+		"convertTo":          convertTo,
+		"isBoolean":          isKind(design.BooleanKind),
+		"isInteger":          isKind(design.IntegerKind),
+		"isNumber":           isKind(design.NumberKind),
+		"isString":           isKind(design.StringKind),
+		"isDateTime":         isKind(design.DateTimeKind),
+		"isUUID":             isKind(design.UUIDKind),
+		"isAny":              isKind(design.AnyKind),
+		"isArray":            isKind(design.ArrayKind),
+		"isFile":             isKind(design.FileKind),
+		"isBytes":            isKind(design.BytesKind),
+		"isDate":             isKind(design.DateKind),
+		"isSizedNumber":      isSizedNumber,
 	}
 	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
 		return err
@@ -239,18 +277,63 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			}
 		}
 	}
+	if data.Inbound != nil || data.Outbound != nil {
+		if err := w.executeMessages(data); err != nil {
+			return err
+		}
+	}
+	if data.Async {
+		if err := w.ExecuteTemplate("async", ctxAsyncT, nil, data); err != nil {
+			return err
+		}
+	}
+	lastEventID := false
 	return data.IterateResponses(func(resp *design.ResponseDefinition) error {
 		respData := map[string]interface{}{
 			"Context":  data,
 			"Response": resp,
 		}
+		if resp.IsStream() {
+			dt, err := codegen.StreamDataType(resp)
+			if err != nil {
+				return err
+			}
+			respData["EventType"] = codegen.StreamEventTypeName(data.ActionName, data.ResourceName, resp.Name)
+			respData["DataType"] = dt
+			respData["LastEventID"] = !lastEventID
+			lastEventID = true
+			return w.ExecuteTemplate("stream", ctxStreamRespT, nil, respData)
+		}
+		headers := codegen.ResponseHeaders(resp)
+		if headers != nil {
+			respData["Headers"] = headers
+			respData["HeadersType"] = codegen.ResponseHeadersTypeName(data.ActionName, data.ResourceName, resp.Name)
+			respData["HeaderFields"] = responseHeaderFields(resp, headers)
+			respData["Validation"] = w.Validator.Code(headers, false, false, false, "h", "response", 1, false)
+			if err := w.ExecuteTemplate("responseHeaders", ctxRespHeadersT, nil, respData); err != nil {
+				return err
+			}
+		}
+		// withHeaders generates the response method that writes the headers and cookies if any.
+		withHeaders := func(name, param, arg string) error {
+			if headers == nil {
+				return nil
+			}
+			respData["RespName"] = name
+			respData["Param"] = param
+			respData["Arg"] = arg
+			return w.ExecuteTemplate("responseWithHeaders", ctxRespWithHeadersT, nil, respData)
+		}
 		var mt *design.MediaTypeDefinition
 		if resp.Type != nil {
 			var ok bool
 			if mt, ok = resp.Type.(*design.MediaTypeDefinition); !ok {
 				respData["Type"] = resp.Type
 				respData["ContentType"] = resp.MediaType
-				return w.ExecuteTemplate("response", ctxTRespT, nil, respData)
+				if err := w.ExecuteTemplate("response", ctxTRespT, nil, respData); err != nil {
+					return err
+				}
+				return withHeaders(codegen.Goify(resp.Name, true), "r "+codegen.GoTypeRef(resp.Type, nil, 0, false), "r")
 			}
 		} else {
 			mt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
@@ -286,13 +369,116 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				if err := w.ExecuteTemplate("response", ctxMTRespT, fn, respData); err != nil {
 					return err
 				}
+				param := "r " + codegen.GoTypeRef(projected, projected.AllRequired(), 0, false)
+				if err := withHeaders(respData["RespName"].(string), param, "r"); err != nil {
+					return err
+				}
+				if mt == design.JobMedia {
+					if err := w.ExecuteTemplate("jobResponse", ctxJobRespT, nil, respData); err != nil {
+						return err
+					}
+				}
 			}
 			return nil
 		}
-		return w.ExecuteTemplate("response", ctxNoMTRespT, nil, respData)
+		if err := w.ExecuteTemplate("response", ctxNoMTRespT, nil, respData); err != nil {
+			return err
+		}
+		if resp.MediaType != "" {
+			return withHeaders(codegen.Goify(resp.Name, true), "resp []byte", "resp")
+		}
+		return withHeaders(codegen.Goify(resp.Name, true), "", "")
 	})
 }
 
+// executeMessages writes the helpers that receive and send the typed messages of a websocket
+// action.
+func (w *ContextsWriter) executeMessages(data *ContextTemplateData) error {
+	msgData := map[string]interface{}{"Context": data}
+	for _, m := range []struct {
+		name string
+		dt   design.DataType
+	}{{"Inbound", data.Inbound}, {"Outbound", data.Outbound}} {
+		if m.dt == nil {
+			continue
+		}
+		dt, err := codegen.DefaultViewType(m.dt)
+		if err != nil {
+			return err
+		}
+		msgData[m.name] = dt
+		if ds, ok := dt.(design.DataStructure); ok {
+			msgData["Validate"+m.name] = codegen.HasValidateMethod(ds)
+		}
+	}
+	return w.ExecuteTemplate("messages", ctxMessagesT, nil, msgData)
+}
+
+// responseHeaderFields returns the data used to render the code that writes the given response
+// headers and cookies. headers is the attribute returned by codegen.ResponseHeaders.
+func responseHeaderFields(resp *design.ResponseDefinition, headers *design.AttributeDefinition) []*ResponseHeaderData {
+	obj := headers.Type.ToObject()
+	names := make([]string, len(obj))
+	i := 0
+	for n := range obj {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	fields := make([]*ResponseHeaderData, len(names))
+	for i, n := range names {
+		att := obj[n]
+		field := codegen.GoifyAtt(att, n, true)
+		pointer := headers.IsPrimitivePointer(n)
+		value := "h." + field
+		if pointer {
+			value = "*" + value
+		}
+		data := &ResponseHeaderData{
+			Name:    n,
+			Field:   field,
+			Pointer: pointer,
+			Value:   toString(att, value),
+		}
+		if resp.Cookies != nil {
+			if _, ok := resp.Cookies.Type.ToObject()[n]; ok {
+				data.IsCookie = true
+				data.Cookie = cookieFields(att)
+			}
+		}
+		fields[i] = data
+	}
+	return fields
+}
+
+// cookieFields returns the Go code initializing the fields of a http.Cookie value with the
+// cookie attributes defined in the design, e.g. `, Secure: true, HttpOnly: true`.
+func cookieFields(att *design.AttributeDefinition) string {
+	var fields []string
+	if v, ok := att.Metadata["cookie:path"]; ok && len(v) > 0 {
+		fields = append(fields, fmt.Sprintf("Path: %q", v[0]))
+	}
+	if v, ok := att.Metadata["cookie:domain"]; ok && len(v) > 0 {
+		fields = append(fields, fmt.Sprintf("Domain: %q", v[0]))
+	}
+	if v, ok := att.Metadata["cookie:max-age"]; ok && len(v) > 0 {
+		fields = append(fields, "MaxAge: "+v[0])
+	}
+	if _, ok := att.Metadata["cookie:secure"]; ok {
+		fields = append(fields, "Secure: true")
+	}
+	if _, ok := att.Metadata["cookie:http-only"]; ok {
+		fields = append(fields, "HttpOnly: true")
+	}
+	if v, ok := att.Metadata["cookie:same-site"]; ok && len(v) > 0 {
+		fields = append(fields, "SameSite: http.SameSite"+v[0]+"Mode")
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(fields, ", ")
+}
+
 // NewControllersWriter returns a handlers code writer.
 // Handlers provide the glue between the underlying request data and the user controller.
 func NewControllersWriter(filename string) (*ControllersWriter, error) {
@@ -341,6 +527,19 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 			"validationCode": w.Validator.Code,
 			"valueTypeOf":    valueTypeOf,
 			"fromString":     fromString,
+			"convertTo":      convertTo,
+			"isBoolean":      isKind(design.BooleanKind),
+			"isInteger":      isKind(design.IntegerKind),
+			"isNumber":       isKind(design.NumberKind),
+			"isString":       isKind(design.StringKind),
+			"isDateTime":     isKind(design.DateTimeKind),
+			"isUUID":         isKind(design.UUIDKind),
+			"isAny":          isKind(design.AnyKind),
+			"isArray":        isKind(design.ArrayKind),
+			"isFile":         isKind(design.FileKind),
+			"isBytes":        isKind(design.BytesKind),
+			"isDate":         isKind(design.DateKind),
+			"isSizedNumber":  isSizedNumber,
 		}
 		if err := w.ExecuteTemplate("unmarshal", unmarshalT, fn, d); err != nil {
 			return err
@@ -364,6 +563,20 @@ func (w *SecurityWriter) Execute(schemes []*design.SecuritySchemeDefinition) err
 	return w.ExecuteTemplate("security_schemes", securitySchemesT, nil, schemes)
 }
 
+// NewErrorsWriter returns an errors code writer.
+func NewErrorsWriter(filename string) (*ErrorsWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &ErrorsWriter{SourceFile: file}, nil
+}
+
+// Execute writes the error classes and the constructors of the given errors.
+func (w *ErrorsWriter) Execute(errs []*design.ErrorDefinition) error {
+	return w.ExecuteTemplate("errors", errorsT, nil, errs)
+}
+
 // NewResourcesWriter returns a contexts code writer.
 // Resources provide the glue between the underlying request data and the user controller.
 func NewResourcesWriter(filename string) (*ResourcesWriter, error) {
@@ -403,6 +616,9 @@ func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 		if err != nil {
 			return err
 		}
+		if p.IsUnion() {
+			return w.ExecuteTemplate("union", unionTypeT, fn, newUnionData(p, p.Identifier, "response"))
+		}
 		return w.ExecuteTemplate("mediatype", mediaTypeT, fn, p)
 	})
 	if err != nil {
@@ -436,9 +652,128 @@ func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 		"finalizeCode":   w.Finalizer.Code,
 		"validationCode": w.Validator.Code,
 	}
+	if t.IsUnion() {
+		return w.ExecuteTemplate("union", unionTypeT, fn, newUnionData(t, "", "type"))
+	}
 	return w.ExecuteTemplate("types", userTypeT, fn, t)
 }
 
+// ExecuteNullableTypes writes the code for the types that wrap the values of the API nullable
+// attributes.
+func (w *UserTypesWriter) ExecuteNullableTypes(api *design.APIDefinition) error {
+	for _, t := range codegen.NullableTypes(api) {
+		data := map[string]interface{}{
+			"Name": codegen.NullableTypeName(t),
+			"Type": t,
+		}
+		if err := w.ExecuteTemplate("nullable", nullableTypeT, nil, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExecuteEnumTypes writes the code for the types that enumerate the values of the API attributes
+// whose enum values are described.
+func (w *UserTypesWriter) ExecuteEnumTypes(api *design.APIDefinition) error {
+	for _, att := range codegen.EnumTypes(api) {
+		names := codegen.EnumValueNames(att)
+		values := make([]map[string]interface{}, len(names))
+		for i, v := range att.Validation.Values {
+			values[i] = map[string]interface{}{
+				"Name":        names[i],
+				"Value":       fmt.Sprintf("%#v", v),
+				"Description": att.Validation.ValueDescriptions[v],
+			}
+		}
+		data := map[string]interface{}{
+			"Name":        codegen.EnumTypeName(att),
+			"Description": att.Description,
+			"Type":        att.Type,
+			"Names":       names,
+			"Values":      values,
+		}
+		if err := w.ExecuteTemplate("enum", enumTypeT, nil, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewWebhooksWriter returns a webhooks code writer.
+func NewWebhooksWriter(filename string) (*WebhooksWriter, error) {
+	w, err := NewUserTypesWriter(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhooksWriter{UserTypesWriter: w}, nil
+}
+
+// ExecutePayload writes the code for the webhook payload type unless it is a user type defined
+// in the API.
+func (w *WebhooksWriter) ExecutePayload(wh *design.WebhookDefinition) error {
+	if ut, ok := design.Design.Types[wh.Payload.TypeName]; ok && ut == wh.Payload {
+		return nil
+	}
+	return w.UserTypesWriter.Execute(wh.Payload)
+}
+
+// Execute writes the code for the webhook payload type and the function that sends the webhook.
+func (w *WebhooksWriter) Execute(wh *design.WebhookDefinition) error {
+	if err := w.ExecutePayload(wh); err != nil {
+		return err
+	}
+	return w.ExecuteTemplate("webhook", webhookT, nil, NewWebhookTemplateData(wh))
+}
+
+// NewWebhookTemplateData returns the data given to the templates that render the code sending
+// and receiving the given webhook.
+func NewWebhookTemplateData(wh *design.WebhookDefinition) map[string]interface{} {
+	var statuses []int
+	wh.IterateResponses(func(r *design.ResponseDefinition) error {
+		statuses = append(statuses, r.Status)
+		return nil
+	})
+	sort.Ints(statuses)
+	privateValidation := codegen.NewValidator().Code(wh.Payload.AttributeDefinition, false, false, false, "ut", "request", 1, true)
+	return map[string]interface{}{
+		"Name":            wh.Name,
+		"Description":     wh.Description,
+		"FuncName":        codegen.Goify(wh.Name, true),
+		"Payload":         wh.Payload,
+		"TypeRef":         codegen.GoTypeRef(wh.Payload, nil, 0, false),
+		"PrivateTypeName": codegen.GoTypeName(wh.Payload, wh.Payload.AllRequired(), 0, true),
+		"Validate":        codegen.HasValidateMethod(wh.Payload),
+		"PrivateValidate": privateValidation != "",
+		"Statuses":        statuses,
+	}
+}
+
+// newUnionData is a helper function that creates a map that can be given to the "Union" template.
+func newUnionData(t design.DataStructure, identifier, context string) map[string]interface{} {
+	u := t.Definition().Type.ToUnion()
+	variants := make([]map[string]interface{}, len(u.Types))
+	for i, vt := range u.Types {
+		disc := vt.Type.ToObject()[u.Discriminator]
+		variants[i] = map[string]interface{}{
+			"Name":      codegen.GoTypeName(vt, nil, 0, false),
+			"Value":     u.DiscriminatorValue(vt),
+			"Field":     codegen.GoifyAtt(disc, u.Discriminator, true),
+			"FieldType": codegen.GoTypeDef(disc, 0, false, false),
+			"Pointer":   vt.IsPrimitivePointer(u.Discriminator),
+		}
+	}
+	return map[string]interface{}{
+		"Type":          t,
+		"Attribute":     t.Definition(),
+		"Identifier":    identifier,
+		"Discriminator": u.Discriminator,
+		"Variants":      variants,
+		"Values":        u.DiscriminatorValues(),
+		"Context":       context,
+	}
+}
+
 // newCoerceData is a helper function that creates a map that can be given to the "Coerce" template.
 func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, pkg string, depth int) map[string]interface{} {
 	return map[string]interface{}{
@@ -472,6 +807,9 @@ func valueTypeOf(prefix string, att *design.AttributeDefinition) string {
 		return prefix + "float"
 	case design.StringKind:
 		return prefix + "string"
+	case design.Int32Kind, design.Int64Kind, design.UInt32Kind, design.UInt64Kind,
+		design.Float32Kind, design.Float64Kind, design.DateKind, design.BytesKind:
+		return prefix + codegen.GoNativeType(att.Type)
 	case design.ArrayKind:
 		return valueTypeOf(prefix+"[]", arrayAttribute(att))
 	case design.HashKind:
@@ -490,6 +828,22 @@ func fromString(att *design.AttributeDefinition, varName string) string {
 		return "strconv.Atoi(" + varName + ")"
 	case design.NumberKind:
 		return "strconv.ParseFloat(" + varName + ")"
+	case design.Float64Kind:
+		return "strconv.ParseFloat(" + varName + ", 64)"
+	case design.Float32Kind:
+		return "strconv.ParseFloat(" + varName + ", 32)"
+	case design.Int32Kind:
+		return "strconv.ParseInt(" + varName + ", 10, 32)"
+	case design.Int64Kind:
+		return "strconv.ParseInt(" + varName + ", 10, 64)"
+	case design.UInt32Kind:
+		return "strconv.ParseUint(" + varName + ", 10, 32)"
+	case design.UInt64Kind:
+		return "strconv.ParseUint(" + varName + ", 10, 64)"
+	case design.DateKind:
+		return "goa.ParseDate(" + varName + ")"
+	case design.BytesKind:
+		return "base64.StdEncoding.DecodeString(" + varName + ")"
 	case design.StringKind:
 		return varName + ", (error)(nil)"
 	case design.ArrayKind:
@@ -499,6 +853,62 @@ func fromString(att *design.AttributeDefinition, varName string) string {
 	return "(" + valueTypeOf("", att) + ")(nil), (error)(nil)"
 }
 
+// toString returns the go code expression that converts the varName value of the go type defined
+// in the attribute to a string.
+func toString(att *design.AttributeDefinition, varName string) string {
+	switch att.Type.Kind() {
+	case design.BooleanKind:
+		return "strconv.FormatBool(" + varName + ")"
+	case design.IntegerKind:
+		return "strconv.Itoa(" + varName + ")"
+	case design.NumberKind, design.Float64Kind:
+		return "strconv.FormatFloat(" + varName + ", 'f', -1, 64)"
+	case design.Float32Kind:
+		return "strconv.FormatFloat(float64(" + varName + "), 'f', -1, 32)"
+	case design.Int32Kind, design.Int64Kind:
+		return "strconv.FormatInt(int64(" + varName + "), 10)"
+	case design.UInt32Kind, design.UInt64Kind:
+		return "strconv.FormatUint(uint64(" + varName + "), 10)"
+	case design.DateTimeKind:
+		return strings.TrimPrefix(varName, "*") + ".Format(time.RFC3339)"
+	case design.UUIDKind, design.DateKind:
+		return strings.TrimPrefix(varName, "*") + ".String()"
+	case design.BytesKind:
+		return "base64.StdEncoding.EncodeToString(" + varName + ")"
+	case design.StringKind:
+		return varName
+	}
+	return "fmt.Sprintf(\"%v\", " + varName + ")"
+}
+
+// convertTo returns the go code expression that converts the value produced by fromString for
+// the given attribute to the attribute go type. The strconv functions used to parse sized numbers
+// always return 64 bits values.
+func convertTo(att *design.AttributeDefinition, varName string) string {
+	switch att.Type.Kind() {
+	case design.Int32Kind, design.UInt32Kind, design.Float32Kind:
+		return codegen.GoNativeType(att.Type) + "(" + varName + ")"
+	}
+	return varName
+}
+
+// isKind returns a template function that tests whether an attribute type is of the given kind.
+func isKind(k design.Kind) func(*design.AttributeDefinition) bool {
+	return func(att *design.AttributeDefinition) bool {
+		return att.Type.Kind() == k
+	}
+}
+
+// isSizedNumber returns true if the attribute type is a fixed size integer or float.
+func isSizedNumber(att *design.AttributeDefinition) bool {
+	switch att.Type.Kind() {
+	case design.Int32Kind, design.Int64Kind, design.UInt32Kind, design.UInt64Kind,
+		design.Float32Kind, design.Float64Kind:
+		return true
+	}
+	return false
+}
+
 const (
 	// ctxT generates the code for the context data type.
 	// template input: *ContextTemplateData
@@ -509,7 +919,9 @@ type {{ .Name }} struct {
 	*goa.RequestData
 {{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}{{ if not ($.HasParamAndHeader $name) }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Headers.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
-{{ end }}{{ end }}{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
+{{ end }}{{ end }}{{ end }}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}{{/*
+*/}}	{{ goifyatt $att $name true }} {{ if ($.Cookies.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
+{{ end }}{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ if .Payload }}	Payload {{ gotyperef .Payload nil 0 false }}
 {{ end }}}
@@ -517,7 +929,7 @@ type {{ .Name }} struct {
 	// coerceT generates the code that coerces the generic deserialized
 	// data to the actual type.
 	// template input: map[string]interface{} as returned by newCoerceData
-	coerceT = `{{ if eq .Attribute.Type.Kind 1 }}{{/*
+	coerceT = `{{ if isBoolean .Attribute }}{{/*
 
 */}}{{/* BooleanType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
@@ -527,7 +939,7 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "boolean"))
 {{ tabs .Depth }}}
-{{ else if eq .Attribute.Type.Kind 2 }}{{/*
+{{ else if isInteger .Attribute }}{{/*
 
 */}}{{/* IntegerType */}}{{/*
 */}}{{ $tmp := tempvar }}{{/*
@@ -539,7 +951,7 @@ type {{ .Name }} struct {
 {{ end }}{{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "integer"))
 {{ tabs .Depth }}}
-{{ else if eq .Attribute.Type.Kind 3 }}{{/*
+{{ else if isNumber .Attribute }}{{/*
 
 */}}{{/* NumberType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
@@ -549,11 +961,11 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "number"))
 {{ tabs .Depth }}}
-{{ else if eq .Attribute.Type.Kind 4 }}{{/*
+{{ else if isString .Attribute }}{{/*
 
 */}}{{/* StringType */}}{{/*
 */}}{{ tabs .Depth }}{{ .Pkg }} = {{ if .Pointer }}&{{ end }}raw{{ goify .Name true }}
-{{ else if eq .Attribute.Type.Kind 5 }}{{/*
+{{ else if isDateTime .Attribute }}{{/*
 
 */}}{{/* DateTimeType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
@@ -563,7 +975,7 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "datetime"))
 {{ tabs .Depth }}}
-{{ else if eq .Attribute.Type.Kind 6 }}{{/*
+{{ else if isUUID .Attribute }}{{/*
 
 */}}{{/* UUIDType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
@@ -573,29 +985,48 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "uuid"))
 {{ tabs .Depth }}}
-{{ else if eq .Attribute.Type.Kind 7 }}{{/*
+{{ else if isAny .Attribute }}{{/*
 
 */}}{{/* AnyType */}}{{/*
 */}}{{ if .Pointer }}{{ $tmp := tempvar }}{{ tabs .Depth }}{{ $tmp }} := interface{}(raw{{ goify .Name true }})
 {{ tabs .Depth }}{{ .Pkg }} = &{{ $tmp }}
 {{ else }}{{ tabs .Depth }}{{ .Pkg }} = raw{{ goify .Name true }}{{/*
 */}}{{ end }}
-{{ else if eq .Attribute.Type.Kind 8 }}{{/*
+{{ else if isArray .Attribute }}{{/*
 
 */}}{{/* ArrayType */}}{{/*
 */}}{{ tabs .Depth }}tmp{{ goify .Name true }} := make({{ valueTypeOf "" .Attribute }}, len(raw{{ goify .Name true }}))
 {{ tabs .Depth }}for i := 0; i < len(raw{{ goify .Name true }}); i++ {
-{{ if eq (arrayAttribute .Attribute).Type.Kind 4 }}{{ tabs .Depth}}	tmp := raw{{ goify .Name true }}[i]{{ else }}{{/*
+{{ if isString (arrayAttribute .Attribute) }}{{ tabs .Depth}}	tmp := raw{{ goify .Name true }}[i]{{ else }}{{/*
 */}}{{ tabs .Depth }}	tmp, err2 := {{ fromString (arrayAttribute .Attribute) (printf "raw%s[i]" (goify .Name true)) }}
 {{ tabs .Depth }}	if err2 != nil {
 {{ tabs .Depth }}		err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "{{ valueTypeOf "" .Attribute }}"))
 {{ tabs .Depth }}		break
 {{ tabs .Depth }}	}{{ end }}
-{{ tabs .Depth}}	tmp{{ goify .Name true }}[i] = tmp
+{{ tabs .Depth}}	tmp{{ goify .Name true }}[i] = {{ convertTo (arrayAttribute .Attribute) "tmp" }}
 {{ tabs .Depth}}}
 {{ tabs .Depth }}{{ .Pkg }} = tmp{{ goify .Name true }}{{/*
 */}}
-{{ else if eq .Attribute.Type.Kind 13 }}{{/*
+{{ else if or (isSizedNumber .Attribute) (isDate .Attribute) }}{{/*
+
+*/}}{{/* Int32, Int64, UInt32, UInt64, Float32, Float64 and Date types */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := {{ fromString .Attribute (printf "raw%s" (goify .Name true)) }}; err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := {{ convertTo .Attribute .VarName }}
+{{ tabs .Depth }}	{{ .Pkg }} = &{{ $varName }}
+{{ else }}{{ tabs .Depth }}	{{ .Pkg }} = {{ convertTo .Attribute .VarName }}
+{{ end }}{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "{{ valueTypeOf "" .Attribute }}"))
+{{ tabs .Depth }}}
+{{ else if isBytes .Attribute }}{{/*
+
+*/}}{{/* BytesType */}}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := base64.StdEncoding.DecodeString(raw{{ goify .Name true }}); err2 == nil {
+{{ tabs .Depth }}	{{ .Pkg }} = {{ .VarName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "bytes"))
+{{ tabs .Depth }}}
+{{ else if isFile .Attribute }}{{/*
 
 */}}{{/* FileType */}}{{/*
 */}}{{ tabs .Depth }}if err2 == nil {
@@ -624,7 +1055,7 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	} else {
 {{ else }}	if len(header{{ goify $name true }}) > 0 {
 {{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}		req.Params["{{ $name }}"] = header{{ goify $name true }}
-{{ if eq (arrayAttribute $att).Type.Kind 4 }}		headers := header{{ goify $name true }}
+{{ if isString (arrayAttribute $att) }}		headers := header{{ goify $name true }}
 {{ else }}		headers := make({{ gotypedef $att 2 true false }}, len(header{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range header{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Headers.IsPrimitivePointer $name) "headers[i]" 3) }}{{/*
@@ -633,14 +1064,49 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 {{ else }}		raw{{ goify $name true}} := header{{ goify $name true}}[0]
 		req.Params["{{ $name }}"] = []string{raw{{ goify $name true }}}
 {{ template "Coerce" (newCoerceData $name $att ($.Headers.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
-*/}}{{ $validation := validationChecker $att ($.Headers.IsNonZero $name) ($.Headers.IsRequired $name) ($.Headers.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
+*/}}{{ $validation := validationChecker $att ($.Headers.IsNonZero $name) ($.Headers.IsRequired $name) ($.Headers.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) (printf "header.%s" $name) 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
 {{ end }}	}
 {{ end }}{{ end }}{{/* if .Headers }}{{/*
 
-*/}}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
-*/}}	param{{ goify $name true }} := req.Params["{{ $name }}"]
-{{ $mustValidate := $.MustValidate $name }}{{ if $mustValidate }}	if len(param{{ goify $name true }}) == 0 {
+*/}}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}{{/*
+*/}}	if cookie{{ goify $name true }}, err2 := r.Cookie("{{ $name }}"); err2 == nil {
+		raw{{ goify $name true }} := cookie{{ goify $name true }}.Value
+{{ template "Coerce" (newCoerceData $name $att ($.Cookies.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{/*
+*/}}{{ $validation := validationChecker $att ($.Cookies.IsNonZero $name) ($.Cookies.IsRequired $name) ($.Cookies.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) (printf "cookie.%s" $name) 2 false }}{{/*
+*/}}{{ if $validation }}{{ $validation }}
+{{ end }}	}{{ if $.Cookies.IsRequired $name }} else {
+		err = goa.MergeErrors(err, goa.MissingCookieError("{{ $name }}"))
+	}{{ else if $.Cookies.HasDefaultValue $name }} else {
+		{{ printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}
+	}{{ end }}
+{{ end }}{{ end }}{{/* if .Cookies }}{{/*
+
+*/}}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{ if $att.Type.IsHash }}{{/*
+*/}}	param{{ goify $name true }} := make({{ gotypedef $att 1 true false }})
+	for k, v := range req.Params {
+		if len(v) > 0 && strings.HasPrefix(k, "{{ $name }}[") && strings.HasSuffix(k, "]") {
+			param{{ goify $name true }}[k[{{ len $name }}+1:len(k)-1]] = v[0]
+		}
+	}
+{{ if $.Params.IsRequired $name }}	if len(param{{ goify $name true }}) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}"))
+	} else {
+{{ else }}	if len(param{{ goify $name true }}) > 0 {
+{{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = param{{ goify $name true }}
+{{ $validation := validationChecker $att.Type.ToHash.ElemType true true false "param" (printf "param.%s[*]" $name) 3 false }}{{/*
+*/}}{{ if $validation }}		for k, param := range {{ printf "rctx.%s" (goifyatt $att $name true) }} {
+			outer := err
+			err = nil
+{{ $validation }}
+			err = goa.MergeErrors(outer, goa.IndexViolations(err, k))
+		}
+{{ end }}	}
+{{ else }}	param{{ goify $name true }} := req.Params["{{ $name }}"]
+{{ $delim := $att.ParamDelimiter }}{{ if $delim }}	if len(param{{ goify $name true }}) > 0 {
+		param{{ goify $name true }} = strings.Split(param{{ goify $name true }}[0], {{ printf "%q" $delim }})
+	}
+{{ end }}{{ $mustValidate := $.MustValidate $name }}{{ if $mustValidate }}	if len(param{{ goify $name true }}) == 0 {
 		{{ if $.Params.HasDefaultValue $name }}{{printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}{{else}}{{/*
 */}}err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}")){{end}}
 	} else {
@@ -648,7 +1114,7 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 		{{printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}
 	} else {
 {{ else }}	if len(param{{ goify $name true }}) > 0 {
-{{ end }}{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}		params := param{{ goify $name true }}
+{{ end }}{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if isString (arrayAttribute $att) }}		params := param{{ goify $name true }}
 {{ else }}		params := make({{ gotypedef $att 2 true false }}, len(param{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Params.IsPrimitivePointer $name) "params[i]" 3) }}{{/*
@@ -656,13 +1122,17 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 {{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = params
 {{ else }}		raw{{ goify $name true}} := param{{ goify $name true}}[0]
 {{ template "Coerce" (newCoerceData $name $att ($.Params.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
-*/}}{{ if $att.Type.IsArray }}{{ $validation := validationChecker (arrayAttribute $att) true true false "param" (printf "%s[0]" $name) 2 false }}{{/*
-*/}}{{ if $validation }}for _, param := range {{ printf "rctx.%s" (goifyatt $att $name true) }} {
-	{{ $validation }}
-	}{{ end }}{{/*
-*/}}{{ else }}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
+*/}}{{ if $att.Type.IsArray }}{{ $validation := validationChecker (arrayAttribute $att) true true false "param" (printf "param.%s[*]" $name) 3 false }}{{/*
+*/}}{{ if $validation }}		for i, param := range {{ printf "rctx.%s" (goifyatt $att $name true) }} {
+			outer := err
+			err = nil
+{{ $validation }}
+			err = goa.MergeErrors(outer, goa.IndexViolations(err, i))
+		}
+{{ end }}{{/*
+*/}}{{ else }}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) (printf "param.%s" $name) 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}{{ end }}{{ end }}	}
-{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
+{{ end }}{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
 }
 `
 
@@ -676,7 +1146,7 @@ func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Proje
 {{ if .Projected.Type.IsArray }}	if r == nil {
 		r = {{ gotyperef .Projected .Projected.AllRequired 0 false }}{}
 	}
-{{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ end }}	return ctx.ResponseData.Service.{{ if .Projected.IsError }}SendError{{ else }}Send{{ end }}(ctx.Context, {{ .Response.Status }}, r)
 }
 `
 
@@ -689,6 +1159,40 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .
 	}
 	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
+`
+
+	// ctxRespHeadersT generates the type holding the headers and cookies of a response.
+	// template input: map[string]interface{}
+	ctxRespHeadersT = `
+// {{ .HeadersType }} holds the headers and cookies of the {{ .Context.ResourceName }} {{ .Context.ActionName }} {{ .Response.Name }} response.
+type {{ .HeadersType }} {{ gotypedef .Headers 0 false false }}
+{{ if .Validation }}
+// Validate runs the validation rules defined in the design.
+func (h *{{ .HeadersType }}) Validate() (err error) {
+{{ .Validation }}
+	return
+}
+{{ end }}`
+
+	// ctxRespWithHeadersT generates the response method that writes the headers and cookies
+	// before sending the response.
+	// template input: map[string]interface{}
+	ctxRespWithHeadersT = `
+// {{ .RespName }}WithHeaders sends a HTTP response with status code {{ .Response.Status }} after writing the given headers and cookies.
+// It returns an internal error without sending the response if a required header or cookie is missing.
+func (ctx *{{ .Context.Name }}) {{ .RespName }}WithHeaders({{ if .Param }}{{ .Param }}, {{ end }}h *{{ .HeadersType }}) error {
+	if h == nil {
+		h = &{{ .HeadersType }}{}
+	}
+{{ if .Validation }}	if err := h.Validate(); err != nil {
+		return goa.ErrInternal(err)
+	}
+{{ end }}{{ range .HeaderFields }}{{ if .Pointer }}	if h.{{ .Field }} != nil {
+	{{ end }}{{ if .IsCookie }}	http.SetCookie(ctx.ResponseData, &http.Cookie{Name: "{{ .Name }}", Value: {{ .Value }}{{ .Cookie }}})
+{{ else }}	ctx.ResponseData.Header().Set("{{ .Name }}", {{ .Value }})
+{{ end }}{{ if .Pointer }}	}
+{{ end }}{{ end }}	return ctx.{{ .RespName }}({{ .Arg }})
+}
 `
 
 	// ctxNoMTRespT generates the response helpers for responses with no known media type.
@@ -704,6 +1208,105 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.
 	return err{{ else }}
 	return nil{{ end }}
 }
+`
+
+	// ctxAsyncT generates the response helper of actions that start long-running operations.
+	// template input: *ContextTemplateData
+	ctxAsyncT = `
+// AcceptedJob sends a HTTP response with status code 202 whose Location header points to the
+// status of the given job.
+func (ctx *{{ .Name }}) AcceptedJob(job *goa.Job) error {
+	ctx.ResponseData.Header().Set("Location", goa.JobStatusPath(ctx.Request.URL.Path, job.ID))
+	ctx.ResponseData.WriteHeader(202)
+	return nil
+}
+`
+
+	// ctxJobRespT generates the response helper for responses using the built-in job media type.
+	// template input: map[string]interface{}
+	ctxJobRespT = `
+// {{ .RespName }}Job sends a HTTP response with status code {{ .Response.Status }} describing the given job.
+func (ctx *{{ .Context.Name }}) {{ .RespName }}Job(job *goa.Job) error {
+	r := &{{ gotypename .Projected .Projected.AllRequired 0 false }}{ID: job.ID, State: string(job.State), Result: job.Result}
+	if job.Error != "" {
+		r.Error = &job.Error
+	}
+	return ctx.{{ .RespName }}(r)
+}
+`
+
+	// ctxMessagesT generates the helpers that receive and send the typed messages of websocket
+	// actions.
+	// template input: map[string]interface{}
+	ctxMessagesT = `{{ if .Inbound }}
+// ReceiveMessage reads the next message sent by the client on the given websocket connection{{ if .ValidateInbound }} and
+// validates it{{ end }}.
+func (ctx *{{ .Context.Name }}) ReceiveMessage(ws *websocket.Conn) ({{ gotyperef .Inbound nil 0 false }}, error) {
+	var msg {{ gotypename .Inbound nil 0 false }}
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		return nil, err
+	}
+{{ if .ValidateInbound }}	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+{{ end }}	return &msg, nil
+}
+{{ end }}{{ if .Outbound }}
+// SendMessage {{ if .ValidateOutbound }}validates the given message and sends it{{ else }}sends the given message{{ end }} to the client on the given
+// websocket connection.
+func (ctx *{{ .Context.Name }}) SendMessage(ws *websocket.Conn, msg {{ gotyperef .Outbound nil 0 false }}) error {
+	if msg == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+{{ if .ValidateOutbound }}	if err := msg.Validate(); err != nil {
+		return err
+	}
+{{ end }}	return websocket.JSON.Send(ws, msg)
+}
+{{ end }}`
+
+	// ctxStreamRespT generates the event type and response helper for responses streaming
+	// Server-Sent Events.
+	// template input: map[string]interface{}
+	ctxStreamRespT = `
+// {{ .EventType }} is an event streamed by the {{ .Context.ResourceName }} {{ .Context.ActionName }} {{ .Response.Name }} response.
+type {{ .EventType }} struct {
+	// ID identifies the event, clients reconnecting to the stream send the ID of the last event
+	// they received in the Last-Event-ID header.
+	ID string
+	// Name is the optional event type.
+	Name string
+	// Data is the event data.
+	Data {{ gotyperef .DataType nil 0 false }}
+}
+{{ if .LastEventID }}
+// LastEventID returns the ID of the last event received by a client reconnecting to the stream,
+// the empty string for new connections.
+func (ctx *{{ .Context.Name }}) LastEventID() string {
+	return goa.LastEventID(ctx.Context)
+}
+{{ end }}
+// {{ goify .Response.Name true }}Stream sends a HTTP response with status code {{ .Response.Status }} and streams the events received on the
+// given channel using Server-Sent Events. It returns when the channel is closed or the request is cancelled.
+func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}Stream(events <-chan *{{ .EventType }}) error {
+	stream, err := goa.NewEventStream(ctx.Context, {{ .Response.Status }})
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(ev.ID, ev.Name, ev.Data); err != nil {
+				return err
+			}
+		}
+	}
+}
 `
 
 	// payloadT generates the payload type definition GoGenerator
@@ -733,7 +1336,7 @@ func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Publicize()
 // {{ gotypename .Payload nil 0 false }} is the {{ .ResourceName }} {{ .ActionName }} action payload.
 type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
 
-{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}// Validate runs the validation rules defined in the design.
+{{ nullableMarshaler .Payload.AttributeDefinition (gotypename .Payload nil 0 false) }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}// Validate runs the validation rules defined in the design.
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
@@ -754,7 +1357,10 @@ type {{ .Resource }}Controller interface {
 	serviceT = `
 // initService sets up the service encoders, decoders and mux.
 func initService(service *goa.Service) {
-	// Setup encoders and decoders
+{{ if .API.ProblemDetails }}	// Render errors as RFC 7807 problem details
+	service.ProblemDetails = true
+
+{{ end }}	// Setup encoders and decoders
 {{ range .Encoders }}{{/*
 */}}	service.Encoder.Register({{ .PackageName }}.{{ .Function }}, "{{ join .MIMETypes "\", \"" }}")
 {{ end }}{{ range .Decoders }}{{/*
@@ -780,7 +1386,10 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 */}}	service.Mux.Handle("OPTIONS", {{ printf "%q" . }}, ctrl.MuxHandler("preflight", handle{{ $res }}Origin(cors.HandlePreflight()), nil))
 {{ end }}{{ end }}{{ range .Actions }}{{ $action := . }}
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
-		// Check if there was an error loading the request
+{{ with .Deprecation }}		// Announce the action deprecation
+		rw.Header().Set("Deprecation", {{ printf "%q" .DeprecationHeader }})
+{{ with .SunsetHeader }}		rw.Header().Set("Sunset", {{ printf "%q" . }})
+{{ end }}{{ end }}		// Check if there was an error loading the request
 		if err := goa.ContextError(ctx); err != nil {
 			return err
 		}
@@ -850,7 +1459,7 @@ func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Reque
 	{{ if .PayloadMultipart}}var err error
 	var payload {{ gotypename .Payload nil 1 true }}
 {{ $o := .Payload.ToObject }}{{ range $name, $att := $o -}}
-	{{ if eq $att.Type.Kind 13 }}	_, raw{{ goify $name true }}, err2 := req.FormFile("{{ $name }}"){{ else if eq $att.Type.Kind 8 }}{{/*
+	{{ if isFile $att }}	_, raw{{ goify $name true }}, err2 := req.FormFile("{{ $name }}"){{ else if isArray $att }}{{/*
 */}}	raw{{ goify $name true }} := req.Form["{{ $name }}[]"]{{ else }}{{/*
 */}}	raw{{ goify $name true }} := req.FormValue("{{ $name }}"){{ end }}
 {{ template "Coerce" (newCoerceData $name $att true (printf "payload.%s" (goifyatt $att $name true)) 1) }}{{ end }}{{/*
@@ -860,7 +1469,10 @@ func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Reque
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
-	payload.Finalize(){{ end }}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
+	payload.Finalize(){{ end }}{{ else if .Payload.IsUnion }}payload := &{{ gotypename .Payload nil 1 false }}{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
 	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
 	}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}{{ if $validation }}
@@ -892,7 +1504,7 @@ func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} i
 // Identifier: {{ .Identifier }}{{ $typeName := gotypename . .AllRequired 0 false }}
 type {{ $typeName }} {{ gotypedef . 0 true false }}
 
-{{ $validation := validationCode .AttributeDefinition false false false "mt" "response" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} media type instance.
+{{ nullableMarshaler .AttributeDefinition $typeName }}{{ $validation := validationCode .AttributeDefinition false false false "mt" "response" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} media type instance.
 func (mt {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
@@ -909,6 +1521,125 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
 }{{ end }}
+`
+
+	// unionTypeT generates the code for a user type or media type whose type is a union.
+	// template input: map[string]interface{}
+	unionTypeT = `// {{ gotypedesc .Type true }}{{ $typeName := gotypename .Type nil 0 false }}{{ if .Identifier }}
+//
+// Identifier: {{ .Identifier }}{{ end }}
+type {{ $typeName }} struct {
+	// Value is the actual value, identified by the "{{ .Discriminator }}" attribute.
+	Value {{ $typeName }}Value
+}
+
+// {{ $typeName }}Value is implemented by the types that make up the {{ $typeName }} union.
+type {{ $typeName }}Value interface {
+	is{{ $typeName }}()
+}
+{{ range .Variants }}
+func (*{{ .Name }}) is{{ $typeName }}() {}
+{{ end }}
+// MarshalJSON encodes the actual value of the {{ $typeName }} union. The "{{ .Discriminator }}" attribute
+// is set to the value that identifies the actual type.
+func (u {{ $typeName }}) MarshalJSON() ([]byte, error) {
+	switch v := u.Value.(type) {
+{{ range .Variants }}	case *{{ .Name }}:
+		if v == nil {
+			break
+		}
+		val := *v
+{{ if .Pointer }}		disc := {{ if eq .FieldType "string" }}{{ printf "%q" .Value }}{{ else }}{{ .FieldType }}({{ printf "%q" .Value }}){{ end }}
+		val.{{ .Field }} = &disc
+{{ else }}		val.{{ .Field }} = {{ printf "%q" .Value }}
+{{ end }}		return json.Marshal(&val)
+{{ end }}	}
+	return json.Marshal(u.Value)
+}
+
+// UnmarshalJSON decodes the {{ $typeName }} union using the value of the "{{ .Discriminator }}"
+// attribute to select the actual type.
+func (u *{{ $typeName }}) UnmarshalJSON(data []byte) error {
+	var d struct {
+		Discriminator *string ` + "`" + `json:"{{ .Discriminator }}"` + "`" + `
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	if d.Discriminator == nil {
+		return goa.MissingAttributeError(` + "`" + `{{ .Context }}` + "`" + `, "{{ .Discriminator }}")
+	}
+	switch *d.Discriminator {
+{{ range .Variants }}	case {{ printf "%q" .Value }}:
+		var v {{ .Name }}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = &v
+{{ end }}	default:
+		return goa.InvalidEnumValueError(` + "`" + `{{ .Context }}.{{ .Discriminator }}` + "`" + `, *d.Discriminator, []interface{}{ {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }} })
+	}
+	return nil
+}
+
+// Validate validates the {{ $typeName }} union instance.
+func (u *{{ $typeName }}) Validate() (err error) {
+{{ validationCode .Attribute false false false "u" .Context 1 false }}
+	return
+}
+`
+
+	// enumTypeT generates the code for the type that enumerates the values of an attribute.
+	// template input: map[string]interface{}
+	enumTypeT = `// {{ .Name }} enumerates the allowed values of an attribute of type {{ gonative .Type }}.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+type {{ .Name }} {{ gonative .Type }}
+
+const ({{ range .Values }}
+{{ if .Description }}	{{ comment (printf "%s: %s" .Name .Description) }}
+{{ end }}	{{ .Name }} {{ $.Name }} = {{ .Value }}{{ end }}
+)
+
+// Valid returns true if the value is one of the {{ .Name }} values.
+func (e {{ .Name }}) Valid() bool {
+	switch e {
+	case {{ join .Names ", " }}:
+		return true
+	}
+	return false
+}
+`
+
+	// nullableTypeT generates the code for the type that wraps the values of nullable attributes.
+	// template input: map[string]interface{}
+	nullableTypeT = `// {{ .Name }} wraps the {{ gonative .Type }} value of a nullable attribute and records whether
+// the attribute was set and whether it was explicitly set to null.
+type {{ .Name }} struct {
+	// Set is true if the attribute is present, including when its value is null.
+	Set bool
+	// Null is true if the attribute value is null.
+	Null bool
+	// Value is the attribute value when Null is false.
+	Value {{ gonative .Type }}
+}
+
+// MarshalJSON encodes the attribute value or null.
+func (n {{ .Name }}) MarshalJSON() ([]byte, error) {
+	if n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON records that the attribute is present and decodes its value.
+func (n *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	*n = {{ .Name }}{Set: true}
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
 `
 
 	// userTypeT generates the code for a user type.
@@ -934,13 +1665,42 @@ func (ut {{ gotyperef . .AllRequired 0 true }}) Publicize() {{ gotyperef . .AllR
 
 // {{ gotypedesc . true }}
 type {{ $typeName }} {{ gotypedef . 0 true false }}
-{{ $validation := validationCode .AttributeDefinition false false false "ut" "type" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} type instance.
+{{ nullableMarshaler .AttributeDefinition $typeName }}{{ $validation := validationCode .AttributeDefinition false false false "ut" "type" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} type instance.
 func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
 }{{ end }}
 `
 
+	// webhookT generates the code for the function that sends a webhook.
+	// template input: map[string]interface{}
+	webhookT = `{{ $funcName := printf "Send%sWebhook" .FuncName }}// {{ $funcName }} sends the {{ .Name }} webhook to the given URL.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func {{ $funcName }}(ctx context.Context, sender *goa.WebhookSender, url string, payload {{ .TypeRef }}) error {
+	if payload == nil {
+		return fmt.Errorf("missing {{ .Name }} webhook payload")
+	}
+{{ if .Validate }}	if err := payload.Validate(); err != nil {
+		return err
+	}
+{{ end }}	return sender.Send(ctx, url, {{ printf "%q" .Name }}, payload{{ range .Statuses }}, {{ . }}{{ end }})
+}
+`
+
+	// errorsT generates the code for the errors declared in the design.
+	// template input: []*design.ErrorDefinition
+	errorsT = `var (
+{{ range . }}	// Err{{ goify .Name true }} is the class of the {{ .Name }} errors.{{ if .Description }}
+	{{ comment .Description }}{{ end }}
+	Err{{ goify .Name true }} = goa.NewErrorClass({{ printf "%q" .Name }}, {{ .Status }})
+{{ end }})
+{{ range . }}{{ $name := goify .Name true }}
+// New{{ $name }}Error creates a new {{ .Name }} error with the given message and metadata.
+func New{{ $name }}Error(message interface{}, {{ if .Meta }}meta {{ gotyperef .Meta nil 0 false }}{{ else }}keyvals ...interface{}{{ end }}) error {
+	return Err{{ $name }}(message, {{ if .Meta }}goa.MetaKeyvals(meta){{ else }}keyvals{{ end }}...)
+}
+{{ end }}`
+
 	// securitySchemesT generates the code for the security module.
 	// template input: []*design.SecuritySchemeDefinition
 	securitySchemesT = `
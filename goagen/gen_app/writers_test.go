@@ -50,19 +50,25 @@ var _ = Describe("ContextsWriter", func() {
 		})
 
 		Context("with data", func() {
-			var params, headers *design.AttributeDefinition
+			var params, headers, cookies *design.AttributeDefinition
 			var payload *design.UserTypeDefinition
 			var responses map[string]*design.ResponseDefinition
 			var routes []*design.RouteDefinition
+			var inbound, outbound design.DataType
+			var async bool
 
 			var data *genapp.ContextTemplateData
 
 			BeforeEach(func() {
 				params = nil
 				headers = nil
+				cookies = nil
 				payload = nil
 				responses = nil
 				routes = nil
+				inbound = nil
+				outbound = nil
+				async = false
 				data = nil
 			})
 
@@ -74,8 +80,12 @@ var _ = Describe("ContextsWriter", func() {
 					Params:       params,
 					Payload:      payload,
 					Headers:      headers,
+					Cookies:      cookies,
 					Responses:    responses,
 					Routes:       routes,
+					Inbound:      inbound,
+					Outbound:     outbound,
+					Async:        async,
 					API:          design.Design,
 					DefaultPkg:   "",
 				}
@@ -689,6 +699,120 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a pipe delimited int array param", func() {
+				BeforeEach(func() {
+					arrayParam := &design.AttributeDefinition{
+						Type:  &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+						Style: design.ParamStylePipeDelimited,
+					}
+					params = &design.AttributeDefinition{
+						Type: design.Object{"param": arrayParam},
+					}
+				})
+
+				It("writes the code splitting the param value", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(intArrayContext))
+					Ω(written).Should(ContainSubstring(delimitedArrayContextFactory))
+				})
+			})
+
+			Context("with sized number, date and bytes params", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"count": &design.AttributeDefinition{Type: design.Int32},
+							"ratio": &design.AttributeDefinition{Type: design.Float64},
+							"day":   &design.AttributeDefinition{Type: design.Date},
+							"blob":  &design.AttributeDefinition{Type: design.Bytes},
+						},
+					}
+				})
+
+				It("writes the code parsing the param values", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(sizedContextFactory))
+				})
+			})
+
+			Context("with an int array param with validated elements", func() {
+				BeforeEach(func() {
+					min := 1.0
+					elem := &design.AttributeDefinition{
+						Type:       design.Integer,
+						Validation: &dslengine.ValidationDefinition{Minimum: &min},
+					}
+					params = &design.AttributeDefinition{
+						Type: design.Object{"param": &design.AttributeDefinition{Type: &design.Array{ElemType: elem}}},
+					}
+				})
+
+				It("writes the code reporting the index of the invalid elements", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(validatedArrayContextFactory))
+				})
+			})
+
+			Context("with a deepObject hash param", func() {
+				BeforeEach(func() {
+					hashParam := &design.AttributeDefinition{
+						Type: &design.Hash{
+							KeyType:  &design.AttributeDefinition{Type: design.String},
+							ElemType: &design.AttributeDefinition{Type: design.String},
+						},
+						Style: design.ParamStyleDeepObject,
+					}
+					params = &design.AttributeDefinition{
+						Type:       design.Object{"filter": hashParam},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"filter"}},
+					}
+				})
+
+				It("writes the code collecting the param keys", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(hashContext))
+					Ω(written).Should(ContainSubstring(hashContextFactory))
+				})
+
+				Context("with validated values", func() {
+					BeforeEach(func() {
+						params.Type.ToObject()["filter"].Type.ToHash().ElemType.Validation = &dslengine.ValidationDefinition{
+							Values: []interface{}{"open", "closed"},
+						}
+					})
+
+					It("writes the code reporting the key of the invalid values", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(validatedHashContextFactory))
+					})
+				})
+			})
+
 			Context("with an param using a reserved keyword as name", func() {
 				BeforeEach(func() {
 					intParam := &design.AttributeDefinition{Type: design.Integer}
@@ -790,6 +914,164 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a required string cookie", func() {
+				BeforeEach(func() {
+					cookies = &design.AttributeDefinition{
+						Type: design.Object{
+							"session": &design.AttributeDefinition{Type: design.String},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"session"}},
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(strCookieContext))
+					Ω(written).Should(ContainSubstring(strCookieContextFactory))
+				})
+			})
+
+			Context("with a response declaring headers and cookies", func() {
+				BeforeEach(func() {
+					responses = map[string]*design.ResponseDefinition{
+						"NoContent": {
+							Name:   "NoContent",
+							Status: 204,
+							Headers: &design.AttributeDefinition{
+								Type: design.Object{
+									"Location": &design.AttributeDefinition{Type: design.String},
+								},
+								Validation: &dslengine.ValidationDefinition{Required: []string{"Location"}},
+							},
+							Cookies: &design.AttributeDefinition{
+								Type: design.Object{
+									"count": &design.AttributeDefinition{
+										Type: design.Integer,
+										Metadata: dslengine.MetadataDefinition{
+											"cookie:max-age": []string{"60"},
+											"cookie:secure":  []string{"true"},
+										},
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("writes the response headers type and response method", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(respHeadersType))
+					Ω(written).Should(ContainSubstring(respWithHeaders))
+				})
+			})
+
+			Context("with a response streaming events", func() {
+				BeforeEach(func() {
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:      "OK",
+							Status:    200,
+							MediaType: design.EventStreamMediaType,
+							Stream:    design.String,
+						},
+					}
+				})
+
+				It("writes the event type and stream response method", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(streamResp))
+					Ω(written).ShouldNot(ContainSubstring("OK(resp []byte)"))
+				})
+			})
+
+			Context("with websocket messages", func() {
+				BeforeEach(func() {
+					inbound = &design.UserTypeDefinition{
+						TypeName: "ChatMessage",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"text": &design.AttributeDefinition{Type: design.String},
+							},
+							Validation: &dslengine.ValidationDefinition{Required: []string{"text"}},
+						},
+					}
+					outbound = &design.UserTypeDefinition{
+						TypeName: "ChatEvent",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"text": &design.AttributeDefinition{Type: design.String},
+							},
+						},
+					}
+				})
+
+				It("writes the message helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(wsMessages))
+				})
+			})
+
+			Context("with a long running operation", func() {
+				BeforeEach(func() {
+					async = true
+				})
+
+				It("writes the accepted job response method", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(acceptedJob))
+				})
+			})
+
+			Context("with a response using the job media type", func() {
+				BeforeEach(func() {
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:      "OK",
+							Status:    200,
+							MediaType: design.JobMediaIdentifier,
+						},
+					}
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(design.JobMediaIdentifier): design.JobMedia,
+					}
+				})
+
+				It("writes the job response method", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(okJob))
+				})
+			})
+
 			Context("with a string header and param with the same name", func() {
 				BeforeEach(func() {
 					str := &design.AttributeDefinition{Type: design.String}
@@ -1631,7 +1913,179 @@ var _ = Describe("UserTypesWriter", func() {
 	})
 })
 
+var _ = Describe("ErrorsWriter", func() {
+	var writer *genapp.ErrorsWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("app")
+		Ω(err).ShouldNot(HaveOccurred())
+		src, err := pkg.CreateSourceFile("test.go")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer src.Close()
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewErrorsWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with errors", func() {
+		var errs []*design.ErrorDefinition
+
+		BeforeEach(func() {
+			info := &design.UserTypeDefinition{
+				TypeName:            "OrderInfo",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+			}
+			errs = []*design.ErrorDefinition{
+				{Name: "order_not_found", Status: 404, Description: "The order does not exist", Meta: info},
+				{Name: "rate_limited", Status: 429},
+			}
+		})
+
+		It("writes the error classes and constructors", func() {
+			err := writer.Execute(errs)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(declaredErrors))
+		})
+
+		Context("with a multiline description", func() {
+			BeforeEach(func() {
+				errs[0].Description = "The order does not exist\nor was deleted"
+			})
+
+			It("comments all the description lines", func() {
+				err := writer.Execute(errs)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring("// The order does not exist\n// or was deleted\n"))
+			})
+		})
+	})
+})
+
+var _ = Describe("WebhooksWriter", func() {
+	var writer *genapp.WebhooksWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("app")
+		Ω(err).ShouldNot(HaveOccurred())
+		src, err := pkg.CreateSourceFile("test.go")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer src.Close()
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewWebhooksWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a webhook", func() {
+		var webhook *design.WebhookDefinition
+
+		BeforeEach(func() {
+			dslengine.Reset()
+			webhook = &design.WebhookDefinition{
+				Name: "order_created",
+				Payload: &design.UserTypeDefinition{
+					TypeName: "OrderCreatedWebhookPayload",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"id": &design.AttributeDefinition{Type: design.Integer},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+					},
+				},
+				Responses: map[string]*design.ResponseDefinition{
+					"NoContent": {Name: "NoContent", Status: 204},
+					"OK":        {Name: "OK", Status: 200},
+				},
+			}
+		})
+
+		It("writes the payload type and the sender", func() {
+			err := writer.Execute(webhook)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("type OrderCreatedWebhookPayload struct"))
+			Ω(written).Should(ContainSubstring(webhookSender))
+		})
+
+		Context("with a multiline description", func() {
+			BeforeEach(func() {
+				webhook.Description = "Sent when an order is created\nor imported"
+			})
+
+			It("comments all the description lines", func() {
+				err := writer.Execute(webhook)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring("// Sent when an order is created\n// or imported\nfunc SendOrderCreatedWebhook("))
+			})
+		})
+	})
+})
+
 const (
+	declaredErrors = `var (
+	// ErrOrderNotFound is the class of the order_not_found errors.
+	// The order does not exist
+	ErrOrderNotFound = goa.NewErrorClass("order_not_found", 404)
+	// ErrRateLimited is the class of the rate_limited errors.
+	ErrRateLimited = goa.NewErrorClass("rate_limited", 429)
+)
+
+// NewOrderNotFoundError creates a new order_not_found error with the given message and metadata.
+func NewOrderNotFoundError(message interface{}, meta *OrderInfo) error {
+	return ErrOrderNotFound(message, goa.MetaKeyvals(meta)...)
+}
+
+// NewRateLimitedError creates a new rate_limited error with the given message and metadata.
+func NewRateLimitedError(message interface{}, keyvals ...interface{}) error {
+	return ErrRateLimited(message, keyvals...)
+}
+`
+
+	webhookSender = `// SendOrderCreatedWebhook sends the order_created webhook to the given URL.
+func SendOrderCreatedWebhook(ctx context.Context, sender *goa.WebhookSender, url string, payload *OrderCreatedWebhookPayload) error {
+	if payload == nil {
+		return fmt.Errorf("missing order_created webhook payload")
+	}
+	return sender.Send(ctx, url, "order_created", payload, 200, 204)
+}
+`
+
 	emptyContext = `
 type ListBottleContext struct {
 	context.Context
@@ -1877,6 +2331,67 @@ func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Ser
 	}
 	return &rctx, err
 }
+`
+
+	strCookieContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Session string
+}
+`
+
+	strCookieContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	if cookieSession, err2 := r.Cookie("session"); err2 == nil {
+		rawSession := cookieSession.Value
+		rctx.Session = rawSession
+	} else {
+		err = goa.MergeErrors(err, goa.MissingCookieError("session"))
+	}
+	return &rctx, err
+}
+`
+
+	respHeadersType = `
+// ListBottlesNoContentHeaders holds the headers and cookies of the bottles list NoContent response.
+type ListBottlesNoContentHeaders struct {
+	Location string
+	Count *int
+}
+
+// Validate runs the validation rules defined in the design.
+func (h *ListBottlesNoContentHeaders) Validate() (err error) {
+	if h.Location == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `response` + "`" + `, "Location"))
+	}
+	return
+}
+`
+
+	respWithHeaders = `
+// NoContentWithHeaders sends a HTTP response with status code 204 after writing the given headers and cookies.
+// It returns an internal error without sending the response if a required header or cookie is missing.
+func (ctx *ListBottleContext) NoContentWithHeaders(h *ListBottlesNoContentHeaders) error {
+	if h == nil {
+		h = &ListBottlesNoContentHeaders{}
+	}
+	if err := h.Validate(); err != nil {
+		return goa.ErrInternal(err)
+	}
+	ctx.ResponseData.Header().Set("Location", h.Location)
+	if h.Count != nil {
+		http.SetCookie(ctx.ResponseData, &http.Cookie{Name: "count", Value: strconv.Itoa(*h.Count), MaxAge: 60, Secure: true})
+	}
+	return ctx.NoContent()
+}
 `
 
 	strHeaderParamContextFactory = `
@@ -2186,6 +2701,256 @@ func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Ser
 	}
 	return &rctx, err
 }
+`
+
+	streamResp = `
+// ListBottlesOKEvent is an event streamed by the bottles list OK response.
+type ListBottlesOKEvent struct {
+	// ID identifies the event, clients reconnecting to the stream send the ID of the last event
+	// they received in the Last-Event-ID header.
+	ID string
+	// Name is the optional event type.
+	Name string
+	// Data is the event data.
+	Data string
+}
+
+// LastEventID returns the ID of the last event received by a client reconnecting to the stream,
+// the empty string for new connections.
+func (ctx *ListBottleContext) LastEventID() string {
+	return goa.LastEventID(ctx.Context)
+}
+
+// OKStream sends a HTTP response with status code 200 and streams the events received on the
+// given channel using Server-Sent Events. It returns when the channel is closed or the request is cancelled.
+func (ctx *ListBottleContext) OKStream(events <-chan *ListBottlesOKEvent) error {
+	stream, err := goa.NewEventStream(ctx.Context, 200)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(ev.ID, ev.Name, ev.Data); err != nil {
+				return err
+			}
+		}
+	}
+}
+`
+
+	acceptedJob = `
+// AcceptedJob sends a HTTP response with status code 202 whose Location header points to the
+// status of the given job.
+func (ctx *ListBottleContext) AcceptedJob(job *goa.Job) error {
+	ctx.ResponseData.Header().Set("Location", goa.JobStatusPath(ctx.Request.URL.Path, job.ID))
+	ctx.ResponseData.WriteHeader(202)
+	return nil
+}
+`
+
+	okJob = `
+// OKJob sends a HTTP response with status code 200 describing the given job.
+func (ctx *ListBottleContext) OKJob(job *goa.Job) error {
+	r := &GoaJob{ID: job.ID, State: string(job.State), Result: job.Result}
+	if job.Error != "" {
+		r.Error = &job.Error
+	}
+	return ctx.OK(r)
+}
+`
+
+	wsMessages = `
+// ReceiveMessage reads the next message sent by the client on the given websocket connection and
+// validates it.
+func (ctx *ListBottleContext) ReceiveMessage(ws *websocket.Conn) (*ChatMessage, error) {
+	var msg ChatMessage
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		return nil, err
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// SendMessage sends the given message to the client on the given
+// websocket connection.
+func (ctx *ListBottleContext) SendMessage(ws *websocket.Conn, msg *ChatEvent) error {
+	if msg == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+	return websocket.JSON.Send(ws, msg)
+}
+`
+
+	sizedContextFactory = `
+	paramBlob := req.Params["blob"]
+	if len(paramBlob) > 0 {
+		rawBlob := paramBlob[0]
+		if blob, err2 := base64.StdEncoding.DecodeString(rawBlob); err2 == nil {
+			rctx.Blob = blob
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("blob", rawBlob, "bytes"))
+		}
+	}
+	paramCount := req.Params["count"]
+	if len(paramCount) > 0 {
+		rawCount := paramCount[0]
+		if count, err2 := strconv.ParseInt(rawCount, 10, 32); err2 == nil {
+			tmp1 := int32(count)
+			rctx.Count = &tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("count", rawCount, "int32"))
+		}
+	}
+	paramDay := req.Params["day"]
+	if len(paramDay) > 0 {
+		rawDay := paramDay[0]
+		if day, err2 := goa.ParseDate(rawDay); err2 == nil {
+			tmp2 := day
+			rctx.Day = &tmp2
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("day", rawDay, "goa.Date"))
+		}
+	}
+	paramRatio := req.Params["ratio"]
+	if len(paramRatio) > 0 {
+		rawRatio := paramRatio[0]
+		if ratio, err2 := strconv.ParseFloat(rawRatio, 64); err2 == nil {
+			tmp3 := ratio
+			rctx.Ratio = &tmp3
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("ratio", rawRatio, "float64"))
+		}
+	}
+`
+
+	delimitedArrayContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		paramParam = strings.Split(paramParam[0], "|")
+	}
+	if len(paramParam) > 0 {
+		params := make([]int, len(paramParam))
+		for i, rawParam := range paramParam {
+			if param, err2 := strconv.Atoi(rawParam); err2 == nil {
+				params[i] = param
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
+			}
+		}
+		rctx.Param = params
+	}
+	return &rctx, err
+}
+`
+
+	validatedArrayContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		params := make([]int, len(paramParam))
+		for i, rawParam := range paramParam {
+			if param, err2 := strconv.Atoi(rawParam); err2 == nil {
+				params[i] = param
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
+			}
+		}
+		rctx.Param = params
+		for i, param := range rctx.Param {
+			outer := err
+			err = nil
+				if param < 1 {
+				err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `param.param[*]` + "`" + `, param, 1, true))
+			}
+			err = goa.MergeErrors(outer, goa.IndexViolations(err, i))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	validatedHashContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramFilter := make(map[string]string)
+	for k, v := range req.Params {
+		if len(v) > 0 && strings.HasPrefix(k, "filter[") && strings.HasSuffix(k, "]") {
+			paramFilter[k[6+1:len(k)-1]] = v[0]
+		}
+	}
+	if len(paramFilter) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("filter"))
+	} else {
+		rctx.Filter = paramFilter
+		for k, param := range rctx.Filter {
+			outer := err
+			err = nil
+			if !(param == "open" || param == "closed") {
+				err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `param.filter[*]` + "`" + `, param, []interface{}{"open", "closed"}))
+			}
+			err = goa.MergeErrors(outer, goa.IndexViolations(err, k))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	hashContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Filter map[string]string
+}
+`
+
+	hashContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramFilter := make(map[string]string)
+	for k, v := range req.Params {
+		if len(v) > 0 && strings.HasPrefix(k, "filter[") && strings.HasSuffix(k, "]") {
+			paramFilter[k[6+1:len(k)-1]] = v[0]
+		}
+	}
+	if len(paramFilter) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("filter"))
+	} else {
+		rctx.Filter = paramFilter
+	}
+	return &rctx, err
+}
 `
 
 	intArrayDefaultContextFactory = `
@@ -113,6 +113,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	funcs["joinRouteParams"] = joinRouteParams
 	funcs["routes"] = routes
 	funcs["flagType"] = flagType
+	funcs["flagDesc"] = flagDesc
 	funcs["defaultVal"] = defaultVal
 	funcs["cmdFieldType"] = cmdFieldTypeString
 	funcs["formatExample"] = formatExample
@@ -126,6 +127,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	registerTmpl := template.Must(template.New("register").Funcs(funcs).Parse(registerTmpl))
 
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/base64"),
 		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("log"),
@@ -240,9 +242,16 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 				"Resource":        action.Parent,
 				"Package":         g.Target,
 				"HasMultiContent": len(g.API.Consumes) > 1,
+				"IsStream":        hasStreamResponse(action),
 			}
 			var err error
 			if action.WebSocket() {
+				if data["Inbound"], err = codegen.DefaultViewType(action.InboundMessage); err != nil {
+					return err
+				}
+				if data["Outbound"], err = codegen.DefaultViewType(action.OutboundMessage); err != nil {
+					return err
+				}
 				err = commandsTmplWS.Execute(file, data)
 			} else {
 				err = commandsTmpl.Execute(file, data)
@@ -299,6 +308,16 @@ func joinRouteParams(action *design.ActionDefinition, att *design.AttributeDefin
 	return strings.Join(elems, ", ")
 }
 
+// hasStreamResponse returns true if one of the action responses streams Server-Sent Events.
+func hasStreamResponse(action *design.ActionDefinition) bool {
+	for _, resp := range action.Responses {
+		if resp.IsStream() {
+			return true
+		}
+	}
+	return false
+}
+
 // joinNames is a code generation helper function that generates a string built from concatenating
 // the keys of the given attribute type (assuming it's an object).
 func joinNames(useNil bool, atts ...*design.AttributeDefinition) string {
@@ -321,7 +340,9 @@ func joinNames(useNil bool, atts ...*design.AttributeDefinition) string {
 		for _, n := range keys {
 			a := obj[n]
 			field := fmt.Sprintf("cmd.%s", codegen.Goify(n, true))
-			if !a.Type.IsArray() && !att.IsRequired(n) && !att.IsNonZero(n) {
+			if a.Type.IsHash() {
+				// Hash flags are passed as is
+			} else if !a.Type.IsArray() && !att.IsRequired(n) && !att.IsNonZero(n) {
 				if useNil {
 					field = flagTypeVal(a, n, field)
 				} else {
@@ -352,7 +373,8 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 		return `intFlagVal("` + key + `", ` + field + ")"
 	case design.String:
 		return `stringFlagVal("` + key + `", ` + field + ")"
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any,
+		design.Int32, design.Int64, design.UInt32, design.UInt64, design.Float32, design.Float64, design.Date, design.Bytes:
 		return "%s"
 	default:
 		return "&" + field
@@ -365,7 +387,10 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 // %s maps to specialTypeResult.Temps
 func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 	switch a.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Bytes:
+		return "%s"
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any,
+		design.Int32, design.Int64, design.UInt32, design.UInt64, design.Float32, design.Float64, design.Date:
 		return "*%s"
 	default:
 		return field
@@ -377,7 +402,8 @@ func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 // %s maps to specialTypeResult.Temps
 func flagTypeArrayVal(a *design.AttributeDefinition, field string) string {
 	switch a.Type.ToArray().ElemType.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any,
+		design.Int32, design.Int64, design.UInt32, design.UInt64, design.Float32, design.Float64, design.Date, design.Bytes:
 		return "%s"
 	}
 	return field
@@ -433,8 +459,22 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 			if !a.Type.IsArray() {
 				nilVal = `""`
 				switch a.Type {
-				case design.Number:
+				case design.Number, design.Float64:
 					typeHandler = "float64Val"
+				case design.Float32:
+					typeHandler = "float32Val"
+				case design.Int32:
+					typeHandler = "int32Val"
+				case design.Int64:
+					typeHandler = "int64Val"
+				case design.UInt32:
+					typeHandler = "uint32Val"
+				case design.UInt64:
+					typeHandler = "uint64Val"
+				case design.Date:
+					typeHandler = "dateVal"
+				case design.Bytes:
+					typeHandler = "bytesVal"
 				case design.Boolean:
 					typeHandler = "boolVal"
 				case design.UUID:
@@ -448,8 +488,22 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 			} else if a.Type.IsArray() {
 				nilVal = "nil"
 				switch a.Type.ToArray().ElemType.Type {
-				case design.Number:
+				case design.Number, design.Float64:
 					typeHandler = "float64Array"
+				case design.Float32:
+					typeHandler = "float32Array"
+				case design.Int32:
+					typeHandler = "int32Array"
+				case design.Int64:
+					typeHandler = "int64Array"
+				case design.UInt32:
+					typeHandler = "uint32Array"
+				case design.UInt64:
+					typeHandler = "uint64Array"
+				case design.Date:
+					typeHandler = "dateArray"
+				case design.Bytes:
+					typeHandler = "bytesArray"
 				case design.Boolean:
 					typeHandler = "boolArray"
 				case design.UUID:
@@ -554,6 +608,33 @@ func signerArgs(sec *design.SecuritySchemeDefinition) string {
 	}
 }
 
+// flagDesc returns the flag help text for the given attribute definition. The text lists the
+// descriptions of the attribute enum values and the attribute deprecation if any.
+func flagDesc(att *design.AttributeDefinition) string {
+	var notes []string
+	if att.HasEnumDescriptions() {
+		values := make([]string, len(att.Validation.Values))
+		for i, v := range att.Validation.Values {
+			values[i] = fmt.Sprintf("%v", v)
+			if desc := att.Validation.ValueDescriptions[v]; desc != "" {
+				values[i] += ": " + desc
+			}
+		}
+		notes = append(notes, "Allowed values: "+strings.Join(values, ", "))
+	}
+	if att.Deprecation != nil {
+		notes = append(notes, att.Deprecation.String())
+	}
+	if len(notes) == 0 {
+		return att.Description
+	}
+	desc := strings.Join(notes, "; ")
+	if att.Description != "" {
+		desc = att.Description + " (" + desc + ")"
+	}
+	return desc
+}
+
 // flagType returns the flag type for the given (basic type) attribute definition.
 func flagType(att *design.AttributeDefinition) string {
 	switch att.Type.Kind() {
@@ -571,15 +652,23 @@ func flagType(att *design.AttributeDefinition) string {
 		return "String"
 	case design.AnyKind:
 		return "String"
+	case design.Int32Kind, design.Int64Kind, design.UInt32Kind, design.UInt64Kind,
+		design.Float32Kind, design.Float64Kind, design.DateKind, design.BytesKind:
+		return "String"
 	case design.ArrayKind:
 		switch att.Type.ToArray().ElemType.Type.Kind() {
 		case design.NumberKind:
 			return "StringSlice"
 		case design.BooleanKind:
 			return "StringSlice"
+		case design.Int32Kind, design.Int64Kind, design.UInt32Kind, design.UInt64Kind,
+			design.Float32Kind, design.Float64Kind, design.DateKind, design.BytesKind:
+			return "StringSlice"
 		default:
 			return flagType(att.Type.(*design.Array).ElemType) + "Slice"
 		}
+	case design.HashKind:
+		return "StringToString"
 	case design.UserTypeKind:
 		return flagType(att.Type.(*design.UserTypeDefinition).AttributeDefinition)
 	case design.MediaTypeKind:
@@ -696,7 +785,9 @@ func new{{ goify $security.SchemeName true }}Signer({{ signerSignature $security
 {{ end }}{{ end }}
 `
 
-const commandTypesTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Name (title (kebabCase .Parent.Name))) true }}	// {{ $cmdName }} is the command line data structure for the {{ .Name }} action of {{ .Parent.Name }}
+const commandTypesTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Name (title (kebabCase .Parent.Name))) true }}	// {{ $cmdName }} is the command line data structure for the {{ .Name }} action of {{ .Parent.Name }}{{ with .Deprecation }}
+	//
+	// Deprecated: the {{ $.Name }} action of {{ $.Parent.Name }} is {{ . }}.{{ end }}
 	{{ $cmdName }} struct {
 {{ if .Payload }}		Payload string
 		ContentType string
@@ -706,6 +797,8 @@ const commandTypesTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Name (titl
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false}}
 {{ end }}{{ end }}{{ $headers := .Headers }}{{ if $headers }}{{ range $name, $att := $headers.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false}}
+{{ end }}{{ end }}{{ $cookies := .AllCookies }}{{ if $cookies }}{{ range $name, $att := $cookies.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
+{{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false}}
 {{ end }}{{ end }}		PrettyPrint bool
 	}
 
@@ -722,7 +815,8 @@ const downloadCommandType = `// DownloadCommand is the command line data structu
 const commandsTmplWS = `
 {{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title (kebabCase .Resource.Name))) true }}// Run establishes a websocket connection for the {{ $cmdName }} command.
 func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
-	var path string
+{{ with .Action.Deprecation }}	fmt.Fprintln(os.Stderr, {{ printf "%q" (printf "warning: the %s action of %s is %s" $.Action.Name $.Action.Parent.Name .) }})
+{{ end }}	var path string
 	if len(args) > 0 {
 		path = args[0]
 	} else {
@@ -730,16 +824,26 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ else }}{{ $pparams := defaultRouteParams .Action }}	path = fmt.Sprintf({{ printf "%q" (defaultRouteTemplate .Action)}}, {{ joinRouteParams .Action $pparams }})
 {{ end }}	}
 	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
-	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
+	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers .Action.AllCookies }}{{ $specialTypeResult.Output }}
 	ws, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{/*
-	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }})
+	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers .Action.AllCookies }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }})
 	if err != nil {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
 	}
-	go goaclient.WSWrite(ws)
-	goaclient.WSRead(ws)
-
+{{ if .Inbound }}	go goaclient.WSWriteMessages(func(line []byte) error {
+		var msg {{ gotyperefext .Inbound 2 .Package }}
+		if err := json.Unmarshal(line, &msg); err != nil {
+			return err
+		}
+		return c.Send{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}Message(ws, &msg)
+	})
+{{ else }}	go goaclient.WSWrite(ws)
+{{ end }}{{ if .Outbound }}	goaclient.WSReadMessages(func() (interface{}, error) {
+		return c.Receive{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}Message(ws)
+	})
+{{ else }}	goaclient.WSRead(ws)
+{{ end }}
 	return nil
 }
 `
@@ -800,20 +904,25 @@ func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Cl
 {{ end }}{{ $pparams := defaultRouteParams .Action }}{{ if $pparams }}{{ range $pname, $pparam := $pparams.Type.ToObject }}{{ $tmp := goify $pname false }}{{/*
 */}}{{ if not $pparam.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $pparam.Type false }}
 {{ end }}	cc.Flags().{{ flagType $pparam }}Var(&cmd.{{ goify $pname true }}, "{{ $pname }}", {{/*
-*/}}{{ if $pparam.DefaultValue }}{{ defaultVal $pparam }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $pparam.Description }}` + "`" + `)
+*/}}{{ if $pparam.DefaultValue }}{{ defaultVal $pparam }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks (flagDesc $pparam) }}` + "`" + `)
 {{ end }}{{ end }}{{ $params := .Action.QueryParams }}{{ if $params }}{{ range $name, $param := $params.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
 */}}{{ if not $param.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $param.Type false }}
 {{ end }}	cc.Flags().{{ flagType $param }}Var(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
-*/}}{{ if $param.DefaultValue }}{{ defaultVal $param }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $param.Description }}` + "`" + `)
+*/}}{{ if $param.DefaultValue }}{{ defaultVal $param }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks (flagDesc $param) }}` + "`" + `)
 {{ end }}{{ end }}{{ $headers := .Action.Headers }}{{ if $headers }}{{ range $name, $header := $headers.Type.ToObject }}{{/*
 */}} cc.Flags().StringVar(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
-*/}}{{ if $header.DefaultValue }}{{ defaultVal $header }}{{ else }}""{{ end }}, ` + "`" + `{{ escapeBackticks $header.Description }}` + "`" + `)
+*/}}{{ if $header.DefaultValue }}{{ defaultVal $header }}{{ else }}""{{ end }}, ` + "`" + `{{ escapeBackticks (flagDesc $header) }}` + "`" + `)
+{{ end }}{{ end }}{{ $cookies := .Action.AllCookies }}{{ if $cookies }}{{ range $name, $cookie := $cookies.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
+*/}}{{ if not $cookie.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $cookie.Type false }}
+{{ end }}	cc.Flags().{{ flagType $cookie }}Var(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
+*/}}{{ if $cookie.DefaultValue }}{{ defaultVal $cookie }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks (flagDesc $cookie) }}` + "`" + `)
 {{ end }}{{ end }}}`
 
 const commandsTmpl = `
 {{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title (kebabCase .Resource.Name))) true }}// Run makes the HTTP request corresponding to the {{ $cmdName }} command.
 func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
-	var path string
+{{ with .Action.Deprecation }}	fmt.Fprintln(os.Stderr, {{ printf "%q" (printf "warning: the %s action of %s is %s" $.Action.Name $.Action.Parent.Name .) }})
+{{ end }}	var path string
 	if len(args) > 0 {
 		path = args[0]
 	} else {
@@ -829,17 +938,17 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ end }}		}
 	}
 {{ end }}	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
-	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
+	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers .Action.AllCookies }}{{ $specialTypeResult.Output }}
 	resp, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{ if .Action.Payload }}, {{/*
-	*/}}{{ if or .Action.Payload.Type.IsObject .Action.Payload.IsPrimitive }}&{{ end }}payload{{ else }}{{ end }}{{/*
-	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }}{{/*
+	*/}}{{ if or .Action.Payload.Type.IsObject .Action.Payload.Type.IsUnion .Action.Payload.IsPrimitive }}&{{ end }}payload{{ else }}{{ end }}{{/*
+	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers .Action.AllCookies }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }}{{/*
 	*/}}{{ if and .Action.Payload .HasMultiContent }}, cmd.ContentType{{ end }})
 	if err != nil {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
 	}
 
-	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	goaclient.Handle{{ if .IsStream }}EventStream{{ else }}Response{{ end }}(c.Client, resp, cmd.PrettyPrint)
 	return nil
 }
 `
@@ -1032,4 +1141,164 @@ func boolArray(ins []string) ([]bool, error) {
 		vals = append(vals, *val)
 	}
 	return vals, nil
+}
+
+func int32Val(val string) (*int32, error) {
+	v, err := strconv.ParseInt(val, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	t := int32(v)
+	return &t, nil
+}
+
+func int32Array(ins []string) ([]int32, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []int32
+	for _, id := range ins {
+		val, err := int32Val(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func int64Val(val string) (*int64, error) {
+	t, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func int64Array(ins []string) ([]int64, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []int64
+	for _, id := range ins {
+		val, err := int64Val(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func uint32Val(val string) (*uint32, error) {
+	v, err := strconv.ParseUint(val, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	t := uint32(v)
+	return &t, nil
+}
+
+func uint32Array(ins []string) ([]uint32, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []uint32
+	for _, id := range ins {
+		val, err := uint32Val(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func uint64Val(val string) (*uint64, error) {
+	t, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func uint64Array(ins []string) ([]uint64, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []uint64
+	for _, id := range ins {
+		val, err := uint64Val(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func float32Val(val string) (*float32, error) {
+	v, err := strconv.ParseFloat(val, 32)
+	if err != nil {
+		return nil, err
+	}
+	t := float32(v)
+	return &t, nil
+}
+
+func float32Array(ins []string) ([]float32, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []float32
+	for _, id := range ins {
+		val, err := float32Val(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func dateVal(val string) (*goa.Date, error) {
+	t, err := goa.ParseDate(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func dateArray(ins []string) ([]goa.Date, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.Date
+	for _, id := range ins {
+		val, err := dateVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func bytesVal(val string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(val)
+}
+
+func bytesArray(ins []string) ([][]byte, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals [][]byte
+	for _, id := range ins {
+		val, err := bytesVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return vals, nil
 }`
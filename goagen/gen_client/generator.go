@@ -154,6 +154,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 			"join":               join,
 			"joinStrings":        strings.Join,
 			"multiComment":       multiComment,
+			"nullableMarshaler":  codegen.NullableMarshaler,
 			"pathParams":         pathParams,
 			"pathTemplate":       pathTemplate,
 			"signerType":         signerType,
@@ -304,6 +305,12 @@ func (g *Generator) generateClientResources(pkgDir, clientPkg string, funcs temp
 	if err := g.generateUserTypes(pkgDir); err != nil {
 		return err
 	}
+	if err := g.generateWebhooks(pkgDir, funcs); err != nil {
+		return err
+	}
+	if err := g.generateErrors(pkgDir, funcs); err != nil {
+		return err
+	}
 
 	return g.generateMediaTypes(pkgDir, funcs)
 }
@@ -332,6 +339,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 	}()
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/base64"),
 		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
@@ -347,6 +355,8 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	title := fmt.Sprintf("%s: %s Resource Client", g.API.Context(), res.Name)
@@ -456,40 +466,12 @@ func (g *Generator) generateFileServer(file *codegen.SourceFile, fs *design.File
 
 func (g *Generator) generateActionClient(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
 	var (
-		params        []string
-		names         []string
-		queryParams   []*paramData
-		headers       []*paramData
+		params        = newClientParams(action)
 		signer        string
 		clientsTmpl   = template.Must(template.New("clients").Funcs(funcs).Parse(clientsTmpl))
 		requestsTmpl  = template.Must(template.New("requests").Funcs(funcs).Parse(requestsTmpl))
 		clientsWSTmpl = template.Must(template.New("clientsws").Funcs(funcs).Parse(clientsWSTmpl))
 	)
-	if action.Payload != nil {
-		params = append(params, "payload "+codegen.GoTypeRef(action.Payload, action.Payload.AllRequired(), 1, false))
-		names = append(names, "payload")
-	}
-
-	initParamsScoped := func(att *design.AttributeDefinition) []*paramData {
-		reqData, optData := initParams(att)
-
-		sort.Sort(byParamName(reqData))
-		sort.Sort(byParamName(optData))
-
-		// Update closure
-		for _, p := range reqData {
-			names = append(names, p.VarName)
-			params = append(params, p.VarName+" "+cmdFieldType(p.Attribute.Type, false))
-		}
-		for _, p := range optData {
-			names = append(names, p.VarName)
-			params = append(params, p.VarName+" "+cmdFieldType(p.Attribute.Type, p.Attribute.Type.IsPrimitive()))
-		}
-		return append(reqData, optData...)
-	}
-	queryParams = initParamsScoped(action.QueryParams)
-	headers = initParamsScoped(action.Headers)
-
 	if action.Security != nil {
 		signer = codegen.Goify(action.Security.Scheme.SchemeName, true)
 	}
@@ -497,6 +479,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Name               string
 		ResourceName       string
 		Description        string
+		Deprecation        *design.DeprecationDefinition
 		Routes             []*design.RouteDefinition
 		Payload            *design.UserTypeDefinition
 		PayloadMultipart   bool
@@ -509,30 +492,201 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Signer             string
 		QueryParams        []*paramData
 		Headers            []*paramData
+		Cookies            []*paramData
 	}{
 		Name:               action.Name,
 		ResourceName:       action.Parent.Name,
 		Description:        action.Description,
+		Deprecation:        action.Deprecation,
 		Routes:             action.Routes,
 		Payload:            action.Payload,
 		PayloadMultipart:   action.PayloadMultipart,
 		HasPayload:         action.Payload != nil,
 		HasMultiContent:    len(design.Design.Consumes) > 1,
 		DefaultContentType: design.Design.Consumes[0].MIMETypes[0],
-		Params:             strings.Join(params, ", "),
-		ParamNames:         strings.Join(names, ", "),
+		Params:             strings.Join(params.Params, ", "),
+		ParamNames:         strings.Join(params.Names, ", "),
 		CanonicalScheme:    action.CanonicalScheme(),
 		Signer:             signer,
-		QueryParams:        queryParams,
-		Headers:            headers,
+		QueryParams:        params.QueryParams,
+		Headers:            params.Headers,
+		Cookies:            params.Cookies,
 	}
 	if action.WebSocket() {
-		return clientsWSTmpl.Execute(file, data)
+		if err := clientsWSTmpl.Execute(file, data); err != nil {
+			return err
+		}
+		return g.generateWSMessages(action, file, funcs)
 	}
 	if err := clientsTmpl.Execute(file, data); err != nil {
 		return err
 	}
-	return requestsTmpl.Execute(file, data)
+	if err := requestsTmpl.Execute(file, data); err != nil {
+		return err
+	}
+	if err := g.generateResponseHeaders(action, file, funcs); err != nil {
+		return err
+	}
+	if err := g.generateResponseStreams(action, file, funcs); err != nil {
+		return err
+	}
+	return g.generateWaitForCompletion(action, file, funcs)
+}
+
+// generateWaitForCompletion generates the helper that polls the status of the jobs started by a
+// long-running action until they complete.
+func (g *Generator) generateWaitForCompletion(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+	if action.Async == nil || action.Async.StatusAction == nil {
+		return nil
+	}
+	mt := design.Design.MediaTypeWithIdentifier(action.Async.StatusMediaType)
+	if mt == nil {
+		return fmt.Errorf("unknown status media type %s", action.Async.StatusMediaType)
+	}
+	projected, _, err := mt.Project(design.DefaultView)
+	if err != nil {
+		return err
+	}
+	status := action.Async.StatusAction
+	params := newClientParams(status)
+	if status.Payload != nil && len(design.Design.Consumes) > 1 {
+		params.Params = append(params.Params, "contentType string")
+		params.Names = append(params.Names, "contentType")
+	}
+	waitTmpl := template.Must(template.New("wait").Funcs(funcs).Parse(waitForCompletionTmpl))
+	data := map[string]interface{}{
+		"Name":         action.Name,
+		"ResourceName": action.Parent.Name,
+		"StatusName":   status.Name,
+		"StatusType":   typeName(projected),
+		"StateField":   codegen.GoifyAtt(projected.Type.ToObject()["state"], "state", true),
+		"Params":       strings.Join(params.Params, ", "),
+		"ParamNames":   strings.Join(params.Names, ", "),
+	}
+	return waitTmpl.Execute(file, data)
+}
+
+// generateWSMessages generates the helpers that send and receive the typed messages of a websocket
+// action.
+func (g *Generator) generateWSMessages(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+	if action.InboundMessage == nil && action.OutboundMessage == nil {
+		return nil
+	}
+	wsMessagesTmpl := template.Must(template.New("wsMessages").Funcs(funcs).Parse(wsMessagesTmpl))
+	data := map[string]interface{}{
+		"Name":         action.Name,
+		"ResourceName": action.Parent.Name,
+	}
+	for _, m := range []struct {
+		name string
+		dt   design.DataType
+	}{{"Inbound", action.InboundMessage}, {"Outbound", action.OutboundMessage}} {
+		if m.dt == nil {
+			continue
+		}
+		dt, err := codegen.DefaultViewType(m.dt)
+		if err != nil {
+			return err
+		}
+		data[m.name] = dt
+		if ds, ok := dt.(design.DataStructure); ok {
+			data["Validate"+m.name] = codegen.HasValidateMethod(ds)
+		}
+	}
+	return wsMessagesTmpl.Execute(file, data)
+}
+
+// generateResponseHeaders generates the types and decoding functions for the headers and cookies
+// declared by the action responses.
+func (g *Generator) generateResponseHeaders(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+	respHeadersTmpl := template.Must(template.New("respHeaders").Funcs(funcs).Parse(respHeadersTmpl))
+	names := make([]string, len(action.Responses))
+	i := 0
+	for n := range action.Responses {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		resp := action.Responses[n]
+		headers := codegen.ResponseHeaders(resp)
+		if headers == nil {
+			continue
+		}
+		var cookies design.Object
+		if resp.Cookies != nil {
+			cookies = resp.Cookies.Type.ToObject()
+		}
+		obj := headers.Type.ToObject()
+		keys := make([]string, len(obj))
+		j := 0
+		for k := range obj {
+			keys[j] = k
+			j++
+		}
+		sort.Strings(keys)
+		fields := make([]*headerData, len(keys))
+		for j, k := range keys {
+			att := obj[k]
+			_, isCookie := cookies[k]
+			fields[j] = &headerData{
+				Name:     k,
+				Field:    codegen.GoifyAtt(att, k, true),
+				Pointer:  headers.IsPrimitivePointer(k),
+				Required: headers.IsRequired(k),
+				IsCookie: isCookie,
+				Parse:    fromString("raw", "v", att),
+				TypeName: expectedTypeName(att),
+			}
+		}
+		data := map[string]interface{}{
+			"TypeName":     codegen.ResponseHeadersTypeName(action.Name, action.Parent.Name, resp.Name),
+			"ActionName":   action.Name,
+			"ResourceName": action.Parent.Name,
+			"ResponseName": resp.Name,
+			"Headers":      headers,
+			"HasCookies":   len(cookies) > 0,
+			"Fields":       fields,
+		}
+		if err := respHeadersTmpl.Execute(file, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateResponseStreams generates the event types and iterators for the action responses
+// streaming Server-Sent Events.
+func (g *Generator) generateResponseStreams(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+	respStreamTmpl := template.Must(template.New("respStream").Funcs(funcs).Parse(respStreamTmpl))
+	names := make([]string, len(action.Responses))
+	i := 0
+	for n := range action.Responses {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		resp := action.Responses[n]
+		if !resp.IsStream() {
+			continue
+		}
+		dt, err := codegen.StreamDataType(resp)
+		if err != nil {
+			return err
+		}
+		data := map[string]interface{}{
+			"TypeName":     codegen.StreamEventTypeName(action.Name, action.Parent.Name, resp.Name),
+			"ActionName":   action.Name,
+			"ResourceName": action.Parent.Name,
+			"ResponseName": resp.Name,
+			"DataType":     dt,
+		}
+		if err := respStreamTmpl.Execute(file, data); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // fileServerMethod returns the name of the client method for downloading assets served by the given
@@ -591,6 +745,7 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (e
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("time"),
@@ -605,7 +760,7 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (e
 	}
 	g.genfiles = append(g.genfiles, mtFile)
 	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
-		if (mt.Type.IsObject() || mt.Type.IsArray()) && !mt.IsError() {
+		if (mt.Type.IsObject() || mt.Type.IsArray() || mt.Type.IsUnion()) && !mt.IsError() {
 			if err := mtWr.Execute(mt); err != nil {
 				return err
 			}
@@ -615,6 +770,10 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (e
 			if err != nil {
 				return err
 			}
+			if p.IsError() && g.API.ProblemDetails {
+				_, err := mtWr.Write([]byte(problemDecodeCode))
+				return err
+			}
 			return typeDecodeTmpl.Execute(mtWr.SourceFile, p)
 		})
 		return err
@@ -645,6 +804,7 @@ func (g *Generator) generateUserTypes(pkgDir string) (err error) {
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
@@ -666,9 +826,107 @@ func (g *Generator) generateUserTypes(pkgDir string) (err error) {
 		}
 		return utWr.Execute(t)
 	})
+	if err != nil {
+		return
+	}
+	err = utWr.ExecuteNullableTypes(g.API)
+	if err != nil {
+		return
+	}
+	err = utWr.ExecuteEnumTypes(g.API)
 	return
 }
 
+// generateWebhooks generates the payload types of the API webhooks and the functions that verify
+// and decode the webhook requests.
+func (g *Generator) generateWebhooks(pkgDir string, funcs template.FuncMap) (err error) {
+	var hasWebhooks bool
+	g.API.IterateWebhooks(func(*design.WebhookDefinition) error {
+		hasWebhooks = true
+		return nil
+	})
+	if !hasWebhooks {
+		return nil
+	}
+	var (
+		whFile string
+		whWr   *genapp.WebhooksWriter
+	)
+	{
+		whFile = filepath.Join(pkgDir, "webhooks.go")
+		whWr, err = genapp.NewWebhooksWriter(whFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		whWr.Close()
+		if err == nil {
+			err = whWr.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Webhooks", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
+	}
+	g.API.IterateWebhooks(func(wh *design.WebhookDefinition) error {
+		imports = codegen.AttributeImports(wh.Payload.AttributeDefinition, imports, nil)
+		return nil
+	})
+	if err = whWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, whFile)
+	decodeTmpl := template.Must(template.New("webhook").Funcs(funcs).Parse(decodeWebhookTmpl))
+	return g.API.IterateWebhooks(func(wh *design.WebhookDefinition) error {
+		if err := whWr.ExecutePayload(wh); err != nil {
+			return err
+		}
+		return decodeTmpl.Execute(whWr, genapp.NewWebhookTemplateData(wh))
+	})
+}
+
+// generateErrors generates the types of the errors declared in the design and the client method
+// that decodes them.
+func (g *Generator) generateErrors(pkgDir string, funcs template.FuncMap) (err error) {
+	var errs []*design.ErrorDefinition
+	g.API.IterateErrors(func(e *design.ErrorDefinition) error {
+		errs = append(errs, e)
+		return nil
+	})
+	if len(errs) == 0 {
+		return nil
+	}
+	errFile := filepath.Join(pkgDir, "errors.go")
+	file, err := codegen.SourceFileFor(errFile)
+	if err != nil {
+		return
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Errors", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	if err = file.WriteHeader(title, g.Target, imports); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, errFile)
+	errorsTmpl := template.Must(template.New("errors").Funcs(funcs).Parse(errorsTmpl))
+	return errorsTmpl.Execute(file, errs)
+}
+
 // join is a code generation helper function that generates a function signature built from
 // concatenating the properties (name type) of the given attribute type (assuming it's an object).
 // join accepts an optional slice of strings which indicates the order in which the parameters
@@ -748,7 +1006,7 @@ func decodeGoTypeName(t design.DataType, required []string, tabs int, private bo
 // cmdFieldType computes the Go type name used to store command flags of the given design type.
 func cmdFieldType(t design.DataType, point bool) string {
 	var pointer, suffix string
-	if point && !t.IsArray() {
+	if point && !t.IsArray() && !t.IsHash() && t.Kind() != design.BytesKind {
 		pointer = "*"
 	}
 	suffix = codegen.GoNativeType(t)
@@ -758,12 +1016,12 @@ func cmdFieldType(t design.DataType, point bool) string {
 // cmdFieldTypeString computes the Go type name used to store command flags of the given design type. Complex types are String
 func cmdFieldTypeString(t design.DataType, point bool) string {
 	var pointer, suffix string
-	if point && !t.IsArray() {
+	if point && !t.IsArray() && !t.IsHash() {
 		pointer = "*"
 	}
-	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
+	if isKindOf(t, stringFlagKinds...) {
 		suffix = "string"
-	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
+	} else if isArrayOfType(t, stringFlagKinds...) {
 		suffix = "[]string"
 	} else {
 		suffix = codegen.GoNativeType(t)
@@ -771,19 +1029,30 @@ func cmdFieldTypeString(t design.DataType, point bool) string {
 	return pointer + suffix
 }
 
-func isArrayOfType(array design.DataType, kinds ...design.Kind) bool {
-	if !array.IsArray() {
-		return false
-	}
-	kind := array.ToArray().ElemType.Type.Kind()
-	for _, t := range kinds {
-		if t == kind {
+// stringFlagKinds lists the kinds of the types whose values are read from string command flags.
+var stringFlagKinds = []design.Kind{
+	design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind,
+	design.Int32Kind, design.Int64Kind, design.UInt32Kind, design.UInt64Kind,
+	design.Float32Kind, design.Float64Kind, design.DateKind, design.BytesKind,
+}
+
+func isKindOf(t design.DataType, kinds ...design.Kind) bool {
+	kind := t.Kind()
+	for _, k := range kinds {
+		if k == kind {
 			return true
 		}
 	}
 	return false
 }
 
+func isArrayOfType(array design.DataType, kinds ...design.Kind) bool {
+	if !array.IsArray() {
+		return false
+	}
+	return isKindOf(array.ToArray().ElemType.Type, kinds...)
+}
+
 // template used to produce code that serializes arrays of simple values into comma separated
 // strings.
 var arrayToStringTmpl *template.Template
@@ -809,6 +1078,18 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 			return fmt.Sprintf("%s := fmt.Sprintf(\"%%v\", %s)", target, name)
 		case design.FileKind:
 			return fmt.Sprintf("%s := fmt.Sprintf(\"%%v\", %s)", target, name)
+		case design.Int32Kind, design.Int64Kind:
+			return fmt.Sprintf("%s := strconv.FormatInt(int64(%s), 10)", target, name)
+		case design.UInt32Kind, design.UInt64Kind:
+			return fmt.Sprintf("%s := strconv.FormatUint(uint64(%s), 10)", target, name)
+		case design.Float32Kind:
+			return fmt.Sprintf("%s := strconv.FormatFloat(float64(%s), 'f', -1, 32)", target, name)
+		case design.Float64Kind:
+			return fmt.Sprintf("%s := strconv.FormatFloat(%s, 'f', -1, 64)", target, name)
+		case design.DateKind:
+			return fmt.Sprintf("%s := %s.String()", target, strings.Replace(name, "*", "", -1)) // remove pointer if present
+		case design.BytesKind:
+			return fmt.Sprintf("%s := base64.StdEncoding.EncodeToString(%s)", target, name)
 		default:
 			panic("unknown primitive type")
 		}
@@ -824,6 +1105,56 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 	}
 }
 
+// fromString generates Go code that parses the string held by the variable name into a value of
+// the type of the given primitive attribute held by the variable target. The generated code also
+// declares the variable err2 holding the parsing error. fromString returns an empty string if the
+// string value can be used as is.
+func fromString(name, target string, att *design.AttributeDefinition) string {
+	switch att.Type.Kind() {
+	case design.BooleanKind:
+		return fmt.Sprintf("%s, err2 := strconv.ParseBool(%s)", target, name)
+	case design.IntegerKind:
+		return fmt.Sprintf("%s, err2 := strconv.Atoi(%s)", target, name)
+	case design.NumberKind, design.Float64Kind:
+		return fmt.Sprintf("%s, err2 := strconv.ParseFloat(%s, 64)", target, name)
+	case design.Float32Kind:
+		return fmt.Sprintf("%s64, err2 := strconv.ParseFloat(%s, 32)\n%s := float32(%s64)", target, name, target, target)
+	case design.Int32Kind:
+		return fmt.Sprintf("%s64, err2 := strconv.ParseInt(%s, 10, 32)\n%s := int32(%s64)", target, name, target, target)
+	case design.Int64Kind:
+		return fmt.Sprintf("%s, err2 := strconv.ParseInt(%s, 10, 64)", target, name)
+	case design.UInt32Kind:
+		return fmt.Sprintf("%s64, err2 := strconv.ParseUint(%s, 10, 32)\n%s := uint32(%s64)", target, name, target, target)
+	case design.UInt64Kind:
+		return fmt.Sprintf("%s, err2 := strconv.ParseUint(%s, 10, 64)", target, name)
+	case design.DateTimeKind:
+		return fmt.Sprintf("%s, err2 := time.Parse(time.RFC3339, %s)", target, name)
+	case design.UUIDKind:
+		return fmt.Sprintf("%s, err2 := uuid.FromString(%s)", target, name)
+	case design.DateKind:
+		return fmt.Sprintf("%s, err2 := goa.ParseDate(%s)", target, name)
+	case design.BytesKind:
+		return fmt.Sprintf("%s, err2 := base64.StdEncoding.DecodeString(%s)", target, name)
+	}
+	return ""
+}
+
+// expectedTypeName returns the name of the attribute type used in the errors reporting invalid
+// values.
+func expectedTypeName(att *design.AttributeDefinition) string {
+	switch att.Type.Kind() {
+	case design.DateTimeKind:
+		return "datetime"
+	case design.UUIDKind:
+		return "uuid"
+	case design.DateKind:
+		return "date"
+	case design.BytesKind:
+		return "bytes"
+	}
+	return att.Type.Name()
+}
+
 // defaultPath returns the first route path for the given action that does not take any wildcard,
 // empty string if none.
 func defaultPath(action *design.ActionDefinition) string {
@@ -879,7 +1210,7 @@ func toValueTypeName(varName, name string, att *design.AttributeDefinition) stri
 	if att == nil {
 		return varName
 	}
-	if att.IsRequired(name) {
+	if att.IsRequired(name) || att.IsBytes(name) {
 		return varName
 	}
 	return "*" + varName
@@ -913,7 +1244,9 @@ func initParams(att *design.AttributeDefinition) ([]*paramData, []*paramData) {
 			if q.Type.IsArray() {
 				param.IsArray = true
 				param.ElemAttribute = q.Type.ToArray().ElemType
+				param.Delimiter = q.ParamDelimiter()
 			}
+			param.IsHash = q.Type.IsHash()
 			param.MustToString = true
 			param.ValueName = varName
 			param.CheckNil = true
@@ -928,6 +1261,47 @@ func initParams(att *design.AttributeDefinition) ([]*paramData, []*paramData) {
 	return reqParamData, optParamData
 }
 
+// clientParams describes the parameters of the client methods generated for an action that
+// follow the request path.
+type clientParams struct {
+	Params      []string // Parameter declarations
+	Names       []string // Parameter names
+	QueryParams []*paramData
+	Headers     []*paramData
+	Cookies     []*paramData
+}
+
+// newClientParams computes the parameters of the client methods generated for the given action:
+// the payload followed by the query string parameters, headers and cookies, required parameters
+// first.
+func newClientParams(action *design.ActionDefinition) *clientParams {
+	var params clientParams
+	if action.Payload != nil {
+		params.Params = append(params.Params, "payload "+codegen.GoTypeRef(action.Payload, action.Payload.AllRequired(), 1, false))
+		params.Names = append(params.Names, "payload")
+	}
+	initParamsScoped := func(att *design.AttributeDefinition) []*paramData {
+		reqData, optData := initParams(att)
+
+		sort.Sort(byParamName(reqData))
+		sort.Sort(byParamName(optData))
+
+		for _, p := range reqData {
+			params.Names = append(params.Names, p.VarName)
+			params.Params = append(params.Params, p.VarName+" "+cmdFieldType(p.Attribute.Type, false))
+		}
+		for _, p := range optData {
+			params.Names = append(params.Names, p.VarName)
+			params.Params = append(params.Params, p.VarName+" "+cmdFieldType(p.Attribute.Type, p.Attribute.Type.IsPrimitive()))
+		}
+		return append(reqData, optData...)
+	}
+	params.QueryParams = initParamsScoped(action.QueryParams)
+	params.Headers = initParamsScoped(action.Headers)
+	params.Cookies = initParamsScoped(action.AllCookies())
+	return &params
+}
+
 // paramData is the data structure holding the information needed to generate query params and
 // headers handling code.
 type paramData struct {
@@ -938,9 +1312,23 @@ type paramData struct {
 	ElemAttribute *design.AttributeDefinition
 	MustToString  bool
 	IsArray       bool
+	IsHash        bool
+	Delimiter     string
 	CheckNil      bool
 }
 
+// headerData is the data structure holding the information needed to generate the code that
+// decodes a response header or cookie.
+type headerData struct {
+	Name     string
+	Field    string
+	Pointer  bool
+	Required bool
+	IsCookie bool
+	Parse    string
+	TypeName string
+}
+
 type byParamName []*paramData
 
 func (b byParamName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -957,13 +1345,200 @@ const (
 
 	payloadTmpl = `// {{ gotypename .Payload nil 0 false }} is the {{ .Parent.Name }} {{ .Name }} action payload.
 type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
-`
+{{ with nullableMarshaler .Payload.AttributeDefinition (gotypename .Payload nil 0 false) }}
+{{ . }}{{ end }}`
 
 	typeDecodeTmpl = `{{ $typeName := typeName . }}{{ $funcName := printf "Decode%s" $typeName }}// {{ $funcName }} decodes the {{ $typeName }} instance encoded in resp body.
 func (c *Client) {{ $funcName }}(resp *http.Response) ({{ decodegotyperef . .AllRequired 0 false }}, error) {
 	var decoded {{ decodegotypename . .AllRequired 0 false }}
 	err := c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type"))
-	return {{ if .IsObject }}&{{ end }}decoded, err
+	return {{ if or .IsObject .IsUnion }}&{{ end }}decoded, err
+}
+`
+
+	problemDecodeCode = `// DecodeErrorResponse decodes the ErrorResponse instance encoded as RFC 7807 problem details in resp
+// body.
+func (c *Client) DecodeErrorResponse(resp *http.Response) (*goa.ErrorResponse, error) {
+	var decoded goa.ProblemDetails
+	err := c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type"))
+	return decoded.ErrorResponse(), err
+}
+`
+
+	respHeadersTmpl = `{{ $typeName := .TypeName }}// {{ $typeName }} holds the headers and cookies of the {{ .ResourceName }} {{ .ActionName }} {{ .ResponseName }} response.
+type {{ $typeName }} {{ gotypedef .Headers 0 false false }}
+
+// Decode{{ $typeName }} decodes the headers and cookies of the {{ .ResourceName }} {{ .ActionName }} {{ .ResponseName }} response.
+func (c *Client) Decode{{ $typeName }}(resp *http.Response) (*{{ $typeName }}, error) {
+	var (
+		h   {{ $typeName }}
+		err error
+	)
+{{ if .HasCookies }}	cookies := make(map[string]string)
+	for _, cookie := range resp.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+{{ end }}{{ range .Fields }}	if raw{{ if .IsCookie }}, ok := cookies["{{ .Name }}"]; ok{{ else }} := resp.Header.Get("{{ .Name }}"); raw != ""{{ end }} {
+{{ if .Parse }}		{{ .Parse }}
+		if err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw, "{{ .TypeName }}"))
+		} else {
+			h.{{ .Field }} = {{ if .Pointer }}&{{ end }}v
+		}
+{{ else }}		h.{{ .Field }} = {{ if .Pointer }}&{{ end }}raw
+{{ end }}	}{{ if .Required }} else {
+		err = goa.MergeErrors(err, goa.Missing{{ if .IsCookie }}Cookie{{ else }}Header{{ end }}Error("{{ .Name }}"))
+	}{{ end }}
+{{ end }}	return &h, err
+}
+`
+
+	respStreamTmpl = `{{ $typeName := .TypeName }}// {{ $typeName }} is an event streamed by the {{ .ResourceName }} {{ .ActionName }} {{ .ResponseName }} response.
+type {{ $typeName }} struct {
+	// ID identifies the event.
+	ID string
+	// Name is the optional event type.
+	Name string
+	// Data is the decoded event data.
+	Data {{ gotyperef .DataType nil 0 false }}
+}
+
+// {{ $typeName }}s iterates over the events streamed by the {{ .ResourceName }} {{ .ActionName }} {{ .ResponseName }} response.
+type {{ $typeName }}s struct {
+	client  *Client
+	reader  *goaclient.EventReader
+	current *{{ $typeName }}
+	err     error
+}
+
+// New{{ $typeName }}s returns an iterator over the events streamed in the body of the {{ .ResourceName }} {{ .ActionName }} {{ .ResponseName }} response.
+// The iterator must be closed once done.
+func (c *Client) New{{ $typeName }}s(resp *http.Response) *{{ $typeName }}s {
+	return &{{ $typeName }}s{client: c, reader: goaclient.NewEventReader(resp.Body)}
+}
+
+// Next blocks until the next event is received and decodes it. It returns false once the stream
+// ends or if reading or decoding an event fails, see Err.
+func (it *{{ $typeName }}s) Next() bool {
+	it.current = nil
+	if it.err != nil {
+		return false
+	}
+	ev, err := it.reader.Next()
+	if err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+		return false
+	}
+	var decoded {{ gotypename .DataType nil 0 false }}
+	if err := it.client.Decoder.Decode(&decoded, bytes.NewReader(ev.Data), "application/json"); err != nil {
+		it.err = err
+		return false
+	}
+	it.current = &{{ $typeName }}{ID: ev.ID, Name: ev.Name, Data: {{ if or .DataType.IsObject .DataType.IsUnion }}&{{ end }}decoded}
+	return true
+}
+
+// Event returns the event decoded by the last call to Next.
+func (it *{{ $typeName }}s) Event() *{{ $typeName }} {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration if any.
+func (it *{{ $typeName }}s) Err() error {
+	return it.err
+}
+
+// LastEventID returns the ID of the last event received, requests reconnecting to the stream
+// should send it in the Last-Event-ID header to resume it.
+func (it *{{ $typeName }}s) LastEventID() string {
+	return it.reader.LastEventID()
+}
+
+// Close closes the response body.
+func (it *{{ $typeName }}s) Close() error {
+	return it.reader.Close()
+}
+`
+
+	waitForCompletionTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{/*
+*/}}// WaitFor{{ $funcName }}Completion polls the status of the job started by the {{ .Name }} action of the
+// {{ .ResourceName }} resource until the job succeeds or fails. resp is the 202 Accepted response of the action.{{ if .Params }}
+// The other arguments are given to each {{ goify (printf "%s%s" .StatusName (title .ResourceName)) true }} request.{{ end }}
+// The delay between polls grows as dictated by goaclient.DefaultBackoff.
+func (c *Client) WaitFor{{ $funcName }}Completion(ctx context.Context, resp *http.Response{{ if .Params }}, {{ .Params }}{{ end }}) (*{{ .StatusType }}, error) {
+	path, err := goaclient.JobStatusPath(resp)
+	if err != nil {
+		return nil, err
+	}
+	var status *{{ .StatusType }}
+	err = goaclient.WaitForCompletion(ctx, goaclient.DefaultBackoff, func(ctx context.Context) (bool, error) {
+		resp, err := c.{{ goify (printf "%s%s" .StatusName (title .ResourceName)) true }}(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }})
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return false, fmt.Errorf("unexpected job status response: %s", resp.Status)
+		}
+		status, err = c.Decode{{ .StatusType }}(resp)
+		if err != nil {
+			return false, err
+		}
+		return goa.JobState(status.{{ .StateField }}).Done(), nil
+	})
+	return status, err
+}
+`
+
+	errorsTmpl = `{{ range . }}{{ $name := goify .Name true }}
+// {{ $name }}Error is the {{ .Name }} error returned by the API.{{ if .Description }}
+{{ multiComment .Description }}{{ end }}
+type {{ $name }}Error struct {
+	*goa.ErrorResponse
+{{ if .Meta }}	// Details is the content of the error "meta" member.
+	Details {{ gotyperef .Meta nil 1 false }}
+{{ end }}}
+{{ end }}
+// DecodeError decodes the error rendered in the body of resp. It returns the error type declared in
+// the design that corresponds to the error code, e.g. *{{ goify (index . 0).Name true }}Error, or a
+// *goa.ErrorResponse if the error is not declared. Use errors.As to check for specific errors.
+func (c *Client) DecodeError(resp *http.Response) error {
+	e, err := c.DecodeErrorResponse(resp)
+	if err != nil {
+		return err
+	}
+	switch e.Code {
+{{ range . }}	case {{ printf "%q" .Name }}:
+		res := &{{ goify .Name true }}Error{ErrorResponse: e}
+{{ if .Meta }}		if err := e.DecodeMeta(&res.Details); err != nil {
+			return err
+		}
+{{ end }}		return res
+{{ end }}	}
+	return e
+}
+`
+
+	decodeWebhookTmpl = `{{ $funcName := printf "Decode%sWebhook" .FuncName }}{{/*
+*/}}// {{ $funcName }} verifies the signature of the given {{ .Name }} webhook request using the secret
+// shared with the API and decodes and validates its payload. The returned errors are goa errors
+// that receivers can use to build their responses.{{ if .Description }}
+{{ multiComment .Description }}{{ end }}
+func {{ $funcName }}(req *http.Request, secret []byte) ({{ .TypeRef }}, error) {
+	body, err := goa.VerifyWebhook(req, secret, goa.DefaultWebhookTolerance)
+	if err != nil {
+		return nil, err
+	}
+	var payload {{ .PrivateTypeName }}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, goa.ErrBadRequest(err)
+	}
+{{ if .PrivateValidate }}	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+{{ end }}	return payload.Publicize(), nil
 }
 `
 
@@ -979,7 +1554,9 @@ func {{ $funcName }}({{ pathParams .Route }}) string {
 
 	clientsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
 */}}{{ if $desc }}{{ multiComment $desc }}{{ else }}{{/*
-*/}}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}
+*/}}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ . }}.{{ end }}
 func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType string{{ end }}) (*http.Response, error) {
 	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType{{ end }})
 	if err != nil {
@@ -990,7 +1567,9 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 `
 
 	clientsWSTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
-*/}}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} establishes a websocket connection to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}
+*/}}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} establishes a websocket connection to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ . }}.{{ end }}
 func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}) (*websocket.Conn, error) {
 	scheme := c.Scheme
 	if scheme == "" {
@@ -1002,11 +1581,25 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	{{ end }}{{/*
 
 // ARRAY
-*/}}{{ if .IsArray }}		for _, p := range {{ .VarName }} {
+*/}}{{ if .IsArray }}{{ if .Delimiter }}	if len({{ .VarName }}) > 0 {
+		{{ $tmp := tempvar }}{{ $tmp }} := make([]string, len({{ .VarName }}))
+		for i, p := range {{ .VarName }} {
+			{{ $tmp2 := tempvar }}{{ toString "p" $tmp2 .ElemAttribute }}
+			{{ $tmp }}[i] = {{ $tmp2 }}
+		}
+		values.Set("{{ .Name }}", strings.Join({{ $tmp }}, {{ printf "%q" .Delimiter }}))
+	}
+{{ else }}		for _, p := range {{ .VarName }} {
 {{ if .MustToString }}{{ $tmp := tempvar }}			{{ toString "p" $tmp .ElemAttribute }}
 			values.Add("{{ .Name }}", {{ $tmp }})
 {{ else }}			values.Add("{{ .Name }}", {{ .ValueName }})
-{{ end }}}{{/*
+{{ end }}}{{ end }}{{/*
+
+// HASH
+*/}}{{ else if .IsHash }}	for k, v := range {{ .VarName }} {
+		values.Set("{{ .Name }}["+k+"]", v)
+	}
+{{/*
 
 // NON STRING
 */}}{{ else if .MustToString }}{{ $tmp := tempvar }}	{{ toString .ValueName $tmp .Attribute }}
@@ -1028,6 +1621,33 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 }
 `
 
+	wsMessagesTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ if .Inbound }}
+// Send{{ $funcName }}Message {{ if .ValidateInbound }}validates the given message and sends it{{ else }}sends the given message{{ end }} on a websocket
+// connection established with {{ $funcName }}.
+func (c *Client) Send{{ $funcName }}Message(ws *websocket.Conn, msg {{ gotyperef .Inbound nil 0 false }}) error {
+	if msg == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+{{ if .ValidateInbound }}	if err := msg.Validate(); err != nil {
+		return err
+	}
+{{ end }}	return websocket.JSON.Send(ws, msg)
+}
+{{ end }}{{ if .Outbound }}
+// Receive{{ $funcName }}Message reads the next message sent by the server on a websocket connection
+// established with {{ $funcName }}{{ if .ValidateOutbound }} and validates it{{ end }}.
+func (c *Client) Receive{{ $funcName }}Message(ws *websocket.Conn) ({{ gotyperef .Outbound nil 0 false }}, error) {
+	var msg {{ gotypename .Outbound nil 0 false }}
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		return nil, err
+	}
+{{ if .ValidateOutbound }}	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+{{ end }}	return &msg, nil
+}
+{{ end }}`
+
 	fsTmpl = `// {{ .Name }} downloads {{ if .DirName }}{{ .DirName }}files with the given filename{{ else }}{{ .FileName }}{{ end }} and writes it to the file dest.
 // It returns the number of bytes downloaded in case of success.
 func (c * Client) {{ .Name }}(ctx context.Context, {{ if .DirName }}filename, {{ end }}dest string) (int64, error) {
@@ -1127,11 +1747,25 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 {{ range .QueryParams }}{{/*
 
 // ARRAY
-*/}}{{ if .IsArray }}		for _, p := range {{ .VarName }} {
+*/}}{{ if .IsArray }}{{ if .Delimiter }}	if len({{ .VarName }}) > 0 {
+		{{ $tmp := tempvar }}{{ $tmp }} := make([]string, len({{ .VarName }}))
+		for i, p := range {{ .VarName }} {
+			{{ $tmp2 := tempvar }}{{ toString "p" $tmp2 .ElemAttribute }}
+			{{ $tmp }}[i] = {{ $tmp2 }}
+		}
+		values.Set("{{ .Name }}", strings.Join({{ $tmp }}, {{ printf "%q" .Delimiter }}))
+	}
+{{ else }}		for _, p := range {{ .VarName }} {
 {{ if .MustToString }}{{ $tmp := tempvar }}			{{ toString "p" $tmp .ElemAttribute }}
 			values.Add("{{ .Name }}", {{ $tmp }})
 {{ else }}			values.Add("{{ .Name }}", {{ .ValueName }})
 {{ end }}	 }
+{{ end }}{{/*
+
+// HASH
+*/}}{{ else if .IsHash }}	for k, v := range {{ .VarName }} {
+		values.Set("{{ .Name }}["+k+"]", v)
+	}
 {{/*
 
 // NON STRING
@@ -1164,7 +1798,12 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	header.Set("{{ .Name }}", {{ $tmp }}){{ else }}
 	header.Set("{{ .Name }}", {{ .ValueName }})
 {{ end }}{{ if .CheckNil }}	}{{ end }}
-{{ end }}{{ end }}{{ if .Signer }}	if c.{{ .Signer }}Signer != nil {
+{{ end }}{{ end }}{{ range .Cookies }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
+{{ end }}{{ if .MustToString }}{{ $tmp := tempvar }}	{{ toString .ValueName $tmp .Attribute }}
+	req.AddCookie(&http.Cookie{Name: "{{ .Name }}", Value: {{ $tmp }}}){{ else }}
+	req.AddCookie(&http.Cookie{Name: "{{ .Name }}", Value: {{ .ValueName }}})
+{{ end }}{{ if .CheckNil }}	}{{ end }}
+{{ end }}{{ if .Signer }}	if c.{{ .Signer }}Signer != nil {
 		if err := c.{{ .Signer }}Signer.Sign(req); err != nil {
 			return nil, err
 		}
@@ -2,13 +2,16 @@ package genclient_test
 
 import (
 	"bytes"
+	"fmt"
 	"html/template"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_client"
@@ -17,6 +20,10 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// dslAPI is the API definition registered with the DSL engine, the tests that define the design
+// with struct literals replace design.Design.
+var dslAPI = design.Design
+
 var _ = Describe("Generate", func() {
 	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_client/test_"
 
@@ -624,8 +631,187 @@ var _ = Describe("Generate", func() {
 			Ω(string(content)).Should(ContainSubstring("tmp_UUID := payload.UUID"))
 		})
 	})
+
+	Context("with an async action of a resource with headers and cookies", func() {
+		BeforeEach(func() {
+			design.Design = dslAPI
+			dslengine.Reset()
+			apidsl.API("testapi", func() {
+				apidsl.Title("async API")
+			})
+			apidsl.Resource("wines", func() {
+				apidsl.Headers(func() {
+					apidsl.Header("X-Tenant", design.String)
+					apidsl.Required("X-Tenant")
+				})
+				apidsl.Cookies(func() {
+					apidsl.Cookie("session", design.String)
+					apidsl.Cookie("locale", design.String)
+					apidsl.Required("session")
+				})
+				apidsl.Action("import", func() {
+					apidsl.Routing(apidsl.POST("/wines/import"))
+					apidsl.Async()
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("generates a wait helper that gives the job status request parameters", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "wines.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring("func (c *Client) ImportStatusWines(ctx context.Context, path string, session string, locale *string) (*http.Response, error) {"))
+			Ω(content).Should(ContainSubstring("func (c *Client) WaitForImportWinesCompletion(ctx context.Context, resp *http.Response, session string, locale *string) (*GoaJob, error) {"))
+			Ω(content).Should(ContainSubstring("resp, err := c.ImportStatusWines(ctx, path, session, locale)"))
+			pkg, err := workspace.NewPackage("async")
+			Ω(err).ShouldNot(HaveOccurred())
+			main := fmt.Sprintf(asyncCompileCode, filepath.Base(outDir))
+			Ω(ioutil.WriteFile(filepath.Join(pkg.Abs(), "main.go"), []byte(main), 0644)).Should(Succeed())
+			_, err = pkg.Compile("async")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a media type with a nullable attribute", func() {
+		BeforeEach(func() {
+			design.Design = dslAPI
+			dslengine.Reset()
+			apidsl.API("testapi", func() {
+				apidsl.Title("nullable API")
+			})
+			owner := apidsl.MediaType("application/vnd.owner", func() {
+				apidsl.TypeName("Owner")
+				apidsl.Attributes(func() {
+					apidsl.Attribute("name", design.String)
+					apidsl.Attribute("nick", design.String, func() {
+						apidsl.Nullable()
+					})
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("name")
+					apidsl.Attribute("nick")
+				})
+			})
+			apidsl.Resource("owners", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/owners"))
+					apidsl.Response(design.OK, owner)
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("decodes explicit nulls and absent values differently", func() {
+			Ω(genErr).Should(BeNil())
+			pkg, err := workspace.NewPackage("decode")
+			Ω(err).ShouldNot(HaveOccurred())
+			main := fmt.Sprintf(nullableDecodeCode, filepath.Base(outDir))
+			Ω(ioutil.WriteFile(filepath.Join(pkg.Abs(), "main.go"), []byte(main), 0644)).Should(Succeed())
+			bin, err := pkg.Compile("decode")
+			Ω(err).ShouldNot(HaveOccurred())
+			out, err := exec.Command(bin).CombinedOutput()
+			Ω(err).ShouldNot(HaveOccurred(), string(out))
+			Ω(string(out)).Should(Equal(
+				`{"name":"a"} false false ""` + "\n" +
+					`{"name":"a","nick":null} true true ""` + "\n" +
+					`{"name":"a","nick":"b"} true false "b"` + "\n"))
+		})
+	})
+
+	Context("with an error with a multiline description", func() {
+		BeforeEach(func() {
+			design.Design = dslAPI
+			dslengine.Reset()
+			apidsl.API("testapi", func() {
+				apidsl.Title("errors API")
+			})
+			apidsl.Resource("orders", func() {
+				apidsl.Error("order_not_found", func() {
+					apidsl.Status(404)
+					apidsl.Description("The order does not exist\nor was deleted")
+				})
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/orders"))
+					apidsl.Response(design.OK)
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("comments all the description lines", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "errors.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(
+				"// OrderNotFoundError is the order_not_found error returned by the API.\n" +
+					"// The order does not exist\n// or was deleted\ntype OrderNotFoundError struct"))
+		})
+	})
+
+	Context("with a webhook with a multiline description", func() {
+		BeforeEach(func() {
+			design.Design = dslAPI
+			dslengine.Reset()
+			apidsl.API("testapi", func() {
+				apidsl.Title("webhooks API")
+			})
+			apidsl.Resource("orders", func() {
+				apidsl.Webhook("order_created", func() {
+					apidsl.Description("Sent when an order is created\nor imported")
+					apidsl.Payload(func() {
+						apidsl.Member("id", design.Integer)
+					})
+					apidsl.Response(design.NoContent)
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("comments all the description lines", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "webhooks.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(
+				"// Sent when an order is created\n// or imported\nfunc DecodeOrderCreatedWebhook("))
+		})
+	})
 })
 
+const asyncCompileCode = `package main
+
+import "%s/client"
+
+var _ = (*client.Client).WaitForImportWinesCompletion
+
+func main() {}
+`
+
+const nullableDecodeCode = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"%s/client"
+)
+
+func main() {
+	for _, body := range []string{` + "`" + `{"name":"a"}` + "`" + `, ` + "`" + `{"name":"a","nick":null}` + "`" + `, ` + "`" + `{"name":"a","nick":"b"}` + "`" + `} {
+		var o client.Owner
+		if err := json.Unmarshal([]byte(body), &o); err != nil {
+			panic(err)
+		}
+		b, err := json.Marshal(&o)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%%s %%v %%v %%q\n", b, o.Nick.Set, o.Nick.Null, o.Nick.Value)
+	}
+}
+`
+
 var _ = Describe("NewGenerator", func() {
 	var generator *genclient.Generator
 
@@ -0,0 +1,497 @@
+package gendesignjson
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// kindNames lists the names of the kinds of the types that are not user types or media types.
+var kindNames = map[design.Kind]string{
+	design.BooleanKind:  "boolean",
+	design.IntegerKind:  "integer",
+	design.Int32Kind:    "int32",
+	design.Int64Kind:    "int64",
+	design.UInt32Kind:   "uint32",
+	design.UInt64Kind:   "uint64",
+	design.NumberKind:   "number",
+	design.Float32Kind:  "float32",
+	design.Float64Kind:  "float64",
+	design.StringKind:   "string",
+	design.DateTimeKind: "datetime",
+	design.DateKind:     "date",
+	design.UUIDKind:     "uuid",
+	design.BytesKind:    "bytes",
+	design.FileKind:     "file",
+	design.AnyKind:      "any",
+	design.ArrayKind:    "array",
+	design.HashKind:     "hash",
+	design.ObjectKind:   "object",
+	design.UnionKind:    "union",
+}
+
+// schemeKindNames lists the names of the security scheme kinds.
+var schemeKindNames = map[design.SecuritySchemeKind]string{
+	design.OAuth2SecurityKind:    "oauth2",
+	design.BasicAuthSecurityKind: "basic_auth",
+	design.APIKeySecurityKind:    "api_key",
+	design.JWTSecurityKind:       "jwt",
+}
+
+// builder builds the design JSON representation of an API definition. It records the user types
+// and media types as they are referenced.
+type builder struct {
+	api *design.APIDefinition
+	ir  *Design
+}
+
+// New returns the design JSON representation of the given finalized API definition.
+func New(api *design.APIDefinition) *Design {
+	b := &builder{api: api, ir: &Design{Format: Format, Version: FormatVersion}}
+	b.ir.API = b.apiDef()
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		b.userTypeRef(ut)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		b.mediaTypeRef(mt)
+		return nil
+	})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		if b.ir.Resources == nil {
+			b.ir.Resources = make(map[string]*Resource)
+		}
+		b.ir.Resources[r.Name] = b.resource(r)
+		return nil
+	})
+	return b.ir
+}
+
+func (b *builder) apiDef() *APIDef {
+	api := b.api
+	return &APIDef{
+		Name:            api.Name,
+		Title:           api.Title,
+		Description:     api.Description,
+		Version:         api.Version,
+		Host:            api.Host,
+		Schemes:         api.Schemes,
+		BasePath:        api.BasePath,
+		Params:          b.attribute(api.Params),
+		Consumes:        encodings(api.Consumes),
+		Produces:        encodings(api.Produces),
+		Origins:         origins(api.Origins),
+		TermsOfService:  api.TermsOfService,
+		Contact:         api.Contact,
+		License:         api.License,
+		Docs:            api.Docs,
+		Responses:       b.responses(api.Responses),
+		Metadata:        api.Metadata,
+		SecuritySchemes: securitySchemes(api.SecuritySchemes),
+		Security:        security(api.Security),
+		NoExamples:      api.NoExamples,
+		ProblemDetails:  api.ProblemDetails,
+		Webhooks:        b.webhooks(api.Webhooks),
+		Errors:          b.errors(api.Errors),
+	}
+}
+
+func (b *builder) resource(r *design.ResourceDefinition) *Resource {
+	res := &Resource{
+		Name:            r.Name,
+		Description:     r.Description,
+		Schemes:         r.Schemes,
+		BasePath:        r.BasePath,
+		Parent:          r.ParentName,
+		MediaType:       r.MediaType,
+		DefaultView:     r.DefaultViewName,
+		CanonicalAction: r.CanonicalActionName,
+		Params:          b.attribute(r.Params),
+		Headers:         b.attribute(r.Headers),
+		Cookies:         b.attribute(r.Cookies),
+		Responses:       b.responses(r.Responses),
+		Origins:         origins(r.Origins),
+		Metadata:        r.Metadata,
+		Security:        security(r.Security),
+		Deprecation:     deprecation(r.Deprecation),
+		Webhooks:        b.webhooks(r.Webhooks),
+		Errors:          b.errors(r.Errors),
+	}
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		if res.Actions == nil {
+			res.Actions = make(map[string]*Action)
+		}
+		res.Actions[a.Name] = b.action(a)
+		return nil
+	})
+	for _, fs := range r.FileServers {
+		res.FileServers = append(res.FileServers, &FileServer{
+			Description: fs.Description,
+			Docs:        fs.Docs,
+			FilePath:    fs.FilePath,
+			RequestPath: fs.RequestPath,
+			Metadata:    fs.Metadata,
+			Security:    security(fs.Security),
+		})
+	}
+	return res
+}
+
+func (b *builder) action(a *design.ActionDefinition) *Action {
+	act := &Action{
+		Name:             a.Name,
+		Description:      a.Description,
+		Docs:             a.Docs,
+		Schemes:          a.Schemes,
+		Params:           b.attribute(a.Params),
+		QueryParams:      b.attribute(a.QueryParams),
+		PayloadOptional:  a.PayloadOptional,
+		PayloadMultipart: a.PayloadMultipart,
+		Headers:          b.attribute(a.Headers),
+		Cookies:          b.attribute(a.Cookies),
+		InboundMessage:   b.typeRef(a.InboundMessage),
+		OutboundMessage:  b.typeRef(a.OutboundMessage),
+		Responses:        b.responses(a.Responses),
+		Metadata:         a.Metadata,
+		Security:         security(a.Security),
+		Deprecation:      deprecation(a.Deprecation),
+		Errors:           b.errors(a.Errors),
+	}
+	for _, r := range a.Routes {
+		act.Routes = append(act.Routes, &Route{
+			Method:   r.Verb,
+			Path:     r.Path,
+			FullPath: r.FullPath(),
+			Metadata: r.Metadata,
+		})
+	}
+	if a.Payload != nil {
+		act.Payload = b.userTypeRef(a.Payload).Name
+	}
+	if a.Async != nil {
+		act.Async = &Async{StatusMediaType: a.Async.StatusMediaType}
+		if a.Async.StatusAction != nil {
+			act.Async.StatusAction = a.Async.StatusAction.Name
+		}
+	}
+	return act
+}
+
+func (b *builder) responses(resps map[string]*design.ResponseDefinition) map[string]*Response {
+	if len(resps) == 0 {
+		return nil
+	}
+	res := make(map[string]*Response, len(resps))
+	for n, r := range resps {
+		res[n] = &Response{
+			Name:        r.Name,
+			Status:      r.Status,
+			Description: r.Description,
+			Type:        b.typeRef(r.Type),
+			MediaType:   r.MediaType,
+			View:        r.ViewName,
+			Headers:     b.attribute(r.Headers),
+			Cookies:     b.attribute(r.Cookies),
+			Stream:      b.typeRef(r.Stream),
+			Metadata:    r.Metadata,
+			Standard:    r.Standard,
+		}
+	}
+	return res
+}
+
+func (b *builder) webhooks(hooks map[string]*design.WebhookDefinition) map[string]*Webhook {
+	if len(hooks) == 0 {
+		return nil
+	}
+	res := make(map[string]*Webhook, len(hooks))
+	for n, w := range hooks {
+		hook := &Webhook{
+			Name:        w.Name,
+			Description: w.Description,
+			Responses:   b.responses(w.Responses),
+		}
+		if w.Payload != nil {
+			hook.Payload = b.userTypeRef(w.Payload).Name
+		}
+		res[n] = hook
+	}
+	return res
+}
+
+func (b *builder) errors(errs map[string]*design.ErrorDefinition) map[string]*Error {
+	if len(errs) == 0 {
+		return nil
+	}
+	res := make(map[string]*Error, len(errs))
+	for n, e := range errs {
+		err := &Error{Name: e.Name, Description: e.Description, Status: e.Status}
+		if e.Meta != nil {
+			err.Meta = b.userTypeRef(e.Meta).Name
+		}
+		res[n] = err
+	}
+	return res
+}
+
+// userTypeRef records the given user type if needed and returns its representation.
+func (b *builder) userTypeRef(ut *design.UserTypeDefinition) *Type {
+	ref := &Type{Kind: "user_type", Name: ut.TypeName}
+	types := &b.ir.InlineTypes
+	if b.api.Types[ut.TypeName] == ut {
+		types = &b.ir.Types
+	}
+	if _, ok := (*types)[ut.TypeName]; ok {
+		return ref
+	}
+	if *types == nil {
+		*types = make(map[string]*UserType)
+	}
+	// Record the type before building its attribute so that recursive types terminate.
+	t := &UserType{Name: ut.TypeName}
+	(*types)[ut.TypeName] = t
+	t.Attribute = b.attribute(ut.AttributeDefinition)
+	return ref
+}
+
+// mediaTypeRef records the given media type if needed and returns its representation.
+func (b *builder) mediaTypeRef(mt *design.MediaTypeDefinition) *Type {
+	id := design.CanonicalIdentifier(mt.Identifier)
+	ref := &Type{Kind: "media_type", Name: id}
+	types := &b.ir.InlineMediaTypes
+	if b.api.MediaTypes[id] == mt {
+		types = &b.ir.MediaTypes
+	}
+	if _, ok := (*types)[id]; ok {
+		return ref
+	}
+	if *types == nil {
+		*types = make(map[string]*MediaType)
+	}
+	t := &MediaType{
+		UserType:    &UserType{Name: mt.TypeName},
+		Identifier:  mt.Identifier,
+		ContentType: mt.ContentType,
+	}
+	(*types)[id] = t
+	t.Attribute = b.attribute(mt.AttributeDefinition)
+	if mt.Resource != nil {
+		t.Resource = mt.Resource.Name
+	}
+	for n, v := range mt.Views {
+		if t.Views == nil {
+			t.Views = make(map[string]*Attribute)
+		}
+		t.Views[n] = b.attribute(v.AttributeDefinition)
+	}
+	for n, l := range mt.Links {
+		if t.Links == nil {
+			t.Links = make(map[string]*Link)
+		}
+		t.Links[n] = &Link{View: l.View, URITemplate: l.URITemplate}
+	}
+	return ref
+}
+
+// typeRef returns the representation of the given data type, nil if dt is nil.
+func (b *builder) typeRef(dt design.DataType) *Type {
+	switch actual := dt.(type) {
+	case nil:
+		return nil
+	case *design.MediaTypeDefinition:
+		return b.mediaTypeRef(actual)
+	case *design.UserTypeDefinition:
+		return b.userTypeRef(actual)
+	case design.Object:
+		t := &Type{Kind: "object"}
+		for n, att := range actual {
+			if t.Attributes == nil {
+				t.Attributes = make(map[string]*Attribute)
+			}
+			t.Attributes[n] = b.attribute(att)
+		}
+		return t
+	case *design.Array:
+		return &Type{Kind: "array", Elem: b.attribute(actual.ElemType)}
+	case *design.Hash:
+		return &Type{Kind: "hash", Key: b.attribute(actual.KeyType), Elem: b.attribute(actual.ElemType)}
+	case *design.Union:
+		t := &Type{Kind: "union", Discriminator: actual.Discriminator}
+		for _, ut := range actual.Types {
+			t.Types = append(t.Types, b.userTypeRef(ut).Name)
+		}
+		return t
+	default:
+		return &Type{Kind: kindNames[dt.Kind()]}
+	}
+}
+
+// attribute returns the representation of the given attribute, nil if att is nil.
+func (b *builder) attribute(att *design.AttributeDefinition) *Attribute {
+	if att == nil {
+		return nil
+	}
+	res := &Attribute{
+		Type:        b.typeRef(att.Type),
+		Reference:   b.typeRef(att.Reference),
+		Description: att.Description,
+		Validation:  validation(att.Validation),
+		Metadata:    att.Metadata,
+		Default:     value(att.DefaultValue),
+		View:        att.View,
+		Deprecation: deprecation(att.Deprecation),
+		Style:       att.Style,
+		Explode:     att.Explode,
+	}
+	for _, base := range att.Bases {
+		res.Bases = append(res.Bases, b.typeRef(base))
+	}
+	if att.Example == "-" {
+		res.NoExample = true
+	} else {
+		res.Example = value(att.Example)
+	}
+	for n, ok := range att.NonZeroAttributes {
+		if ok {
+			res.NonZero = append(res.NonZero, n)
+		}
+	}
+	sort.Strings(res.NonZero)
+	return res
+}
+
+func validation(v *dslengine.ValidationDefinition) *Validation {
+	if v == nil {
+		return nil
+	}
+	res := &Validation{
+		Format:            v.Format,
+		Pattern:           v.Pattern,
+		Minimum:           v.Minimum,
+		Maximum:           v.Maximum,
+		ExclusiveMinimum:  v.ExclusiveMinimum,
+		ExclusiveMaximum:  v.ExclusiveMaximum,
+		MultipleOf:        v.MultipleOf,
+		MinLength:         v.MinLength,
+		MaxLength:         v.MaxLength,
+		UniqueItems:       v.UniqueItems,
+		MinProperties:     v.MinProperties,
+		MaxProperties:     v.MaxProperties,
+		Required:          v.Required,
+		DependentRequired: v.DependentRequired,
+		MutuallyExclusive: v.MutuallyExclusive,
+	}
+	for _, e := range v.Values {
+		res.Enum = append(res.Enum, value(e))
+	}
+	for val, desc := range v.ValueDescriptions {
+		res.EnumDescriptions = append(res.EnumDescriptions, &EnumDescription{Value: value(val), Description: desc})
+	}
+	sort.Slice(res.EnumDescriptions, func(i, j int) bool {
+		return fmt.Sprint(res.EnumDescriptions[i].Value) < fmt.Sprint(res.EnumDescriptions[j].Value)
+	})
+	for _, r := range v.RequiredIf {
+		res.RequiredIf = append(res.RequiredIf, &RequiredIf{Attribute: r.Attribute, Value: value(r.Value), Required: r.Required})
+	}
+	return res
+}
+
+// value converts the given default, example or enum value so that it may be serialized to JSON:
+// the keys of maps become strings and byte slices become base64 encoded strings.
+func value(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if bs, ok := v.([]byte); ok {
+		return base64.StdEncoding.EncodeToString(bs)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		res := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			res[fmt.Sprint(k.Interface())] = value(rv.MapIndex(k).Interface())
+		}
+		return res
+	case reflect.Slice, reflect.Array:
+		res := make([]interface{}, rv.Len())
+		for i := range res {
+			res[i] = value(rv.Index(i).Interface())
+		}
+		return res
+	}
+	return v
+}
+
+func encodings(encs []*design.EncodingDefinition) []*Encoding {
+	var res []*Encoding
+	for _, e := range encs {
+		res = append(res, &Encoding{
+			MIMETypes:   e.MIMETypes,
+			PackagePath: e.PackagePath,
+			Function:    e.Function,
+			Encoder:     e.Encoder,
+		})
+	}
+	return res
+}
+
+func origins(origins map[string]*design.CORSDefinition) map[string]*CORS {
+	if len(origins) == 0 {
+		return nil
+	}
+	res := make(map[string]*CORS, len(origins))
+	for n, o := range origins {
+		res[n] = &CORS{
+			Origin:      o.Origin,
+			Headers:     o.Headers,
+			Methods:     o.Methods,
+			Exposed:     o.Exposed,
+			MaxAge:      o.MaxAge,
+			Credentials: o.Credentials,
+			Regexp:      o.Regexp,
+		}
+	}
+	return res
+}
+
+func securitySchemes(schemes []*design.SecuritySchemeDefinition) []*SecurityScheme {
+	var res []*SecurityScheme
+	for _, s := range schemes {
+		res = append(res, &SecurityScheme{
+			Kind:             schemeKindNames[s.Kind],
+			Name:             s.SchemeName,
+			Type:             s.Type,
+			Description:      s.Description,
+			In:               s.In,
+			ParamName:        s.Name,
+			Scopes:           s.Scopes,
+			Flow:             s.Flow,
+			TokenURL:         s.TokenURL,
+			AuthorizationURL: s.AuthorizationURL,
+			Metadata:         s.Metadata,
+		})
+	}
+	return res
+}
+
+func security(s *design.SecurityDefinition) *Security {
+	if s == nil {
+		return nil
+	}
+	if s.Scheme == nil || s.Scheme.Kind == design.NoSecurityKind {
+		return &Security{None: true}
+	}
+	return &Security{Scheme: s.Scheme.SchemeName, Scopes: s.Scopes}
+}
+
+func deprecation(d *design.DeprecationDefinition) *Deprecation {
+	if d == nil {
+		return nil
+	}
+	return &Deprecation{Since: d.Since, Sunset: d.Sunset, Replacement: d.Replacement}
+}
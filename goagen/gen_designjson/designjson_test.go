@@ -0,0 +1,410 @@
+package gendesignjson_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_designjson"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+// cellarDesign describes an API that makes use of most of the DSL.
+func cellarDesign() {
+	API("cellar", func() {
+		Title("The virtual wine cellar")
+		Version("1.0")
+		Host("localhost:8080")
+		Scheme("http")
+		BasePath("/cellar")
+		Metadata("swagger:tag:cellar", "Cellar")
+		Origin("http://swagger.goa.design", func() {
+			Methods("GET", "POST")
+			MaxAge(600)
+		})
+		Params(func() {
+			Param("accountID", Integer, "Account ID", func() {
+				Minimum(1)
+			})
+		})
+	})
+	JWT := JWTSecurity("jwt", func() {
+		Header("Authorization")
+		Scope("api:read", "Read access")
+		Scope("api:write", "Write access")
+	})
+	Account := MediaType("application/vnd.account+json", func() {
+		Attributes(func() {
+			Attribute("id", Integer)
+			Attribute("href", String)
+			Attribute("name", String)
+			Required("id", "href")
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("href")
+			Attribute("name")
+		})
+		View("link", func() {
+			Attribute("id")
+			Attribute("href")
+		})
+	})
+	Rating := Type("Rating", func() {
+		Attribute("stars", Integer, func() {
+			Enum(1, 2, 3, 4, 5)
+			Default(3)
+		})
+		Attribute("ratio", Number, func() {
+			Minimum(0)
+			Maximum(1)
+			Example(0.5)
+		})
+		Attribute("tags", HashOf(String, Integer), func() {
+			Default(map[string]int{"sweet": 1})
+		})
+		Attribute("comment", String, func() {
+			MaxLength(200)
+			Pattern("^[a-z ]*$")
+		})
+		Required("stars")
+	})
+	Bottle := MediaType("application/vnd.bottle+json", func() {
+		Description("A bottle of wine")
+		Attributes(func() {
+			Attribute("id", Integer, "ID of bottle", func() {
+				Example(1)
+			})
+			Attribute("href", String, "API href of bottle")
+			Attribute("name", String, func() {
+				MinLength(2)
+			})
+			Attribute("vintage", Integer, func() {
+				Minimum(1900)
+				Maximum(2020)
+			})
+			Attribute("rating", Rating)
+			Attribute("account", Account)
+			Attribute("created_at", DateTime)
+			Required("id", "href", "name")
+		})
+		Links(func() {
+			Link("account")
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("href")
+			Attribute("name")
+			Attribute("rating")
+			Attribute("links")
+		})
+		View("tiny", func() {
+			Attribute("id")
+			Attribute("href")
+			Attribute("name")
+		})
+	})
+	Resource("bottle", func() {
+		BasePath("/bottles")
+		DefaultMedia(Bottle)
+		Security(JWT, func() {
+			Scope("api:read")
+		})
+		Action("list", func() {
+			Description("List the bottles")
+			Routing(GET(""))
+			Params(func() {
+				Param("years", ArrayOf(Integer))
+				Param("sort", String, func() {
+					Enum("name", "vintage")
+					Default("name")
+				})
+			})
+			Response(OK, func() {
+				Media(CollectionOf(Bottle, func() {
+					View("default")
+					View("tiny")
+				}), "tiny")
+			})
+			Response(Unauthorized)
+		})
+		Action("show", func() {
+			Description("Retrieve a bottle")
+			Routing(GET("/:bottleID"), GET("/named/:bottleID"))
+			Params(func() {
+				Param("bottleID", Integer)
+			})
+			Headers(func() {
+				Header("X-Trace", String)
+			})
+			Response(OK)
+			Response(NotFound)
+			Response(Unauthorized)
+		})
+		Action("create", func() {
+			Description("Create a bottle")
+			Routing(POST(""))
+			Security(JWT, func() {
+				Scope("api:write")
+			})
+			Payload(func() {
+				Member("name")
+				Member("vintage")
+				Member("rating")
+				Required("name")
+			})
+			Response(Created, func() {
+				Headers(func() {
+					Header("Location", String, func() {
+						Pattern("/bottles/[0-9]+")
+					})
+				})
+			})
+			Response(BadRequest, ErrorMedia)
+			Response(Unauthorized)
+		})
+		Action("ping", func() {
+			Description("Check the service health")
+			Routing(GET("/ping"))
+			NoSecurity()
+			Response(NoContent)
+		})
+	})
+	Resource("public", func() {
+		Files("/index.html", "public/index.html")
+	})
+}
+
+// run runs the cellar DSL and returns the resulting API definition.
+func run() *APIDefinition {
+	dslengine.Reset()
+	cellarDesign()
+	Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	return Design
+}
+
+// marshal serializes v to indented JSON.
+func marshal(v interface{}) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	Ω(err).ShouldNot(HaveOccurred())
+	return string(b)
+}
+
+var _ = Describe("New", func() {
+	var ir *gendesignjson.Design
+
+	BeforeEach(func() {
+		ir = gendesignjson.New(run())
+	})
+
+	It("records the format and version", func() {
+		Ω(ir.Format).Should(Equal("goa-design"))
+		Ω(ir.Version).Should(Equal(gendesignjson.FormatVersion))
+	})
+
+	It("describes the API", func() {
+		Ω(ir.API.Name).Should(Equal("cellar"))
+		Ω(ir.API.BasePath).Should(Equal("/cellar"))
+		Ω(ir.API.Metadata).Should(HaveKeyWithValue("swagger:tag:cellar", []string{"Cellar"}))
+		Ω(ir.API.Origins).Should(HaveKey("http://swagger.goa.design"))
+		Ω(ir.API.SecuritySchemes).Should(HaveLen(1))
+		Ω(ir.API.SecuritySchemes[0].Kind).Should(Equal("jwt"))
+		Ω(ir.API.SecuritySchemes[0].Scopes).Should(HaveLen(2))
+	})
+
+	It("describes the actions and their routes", func() {
+		Ω(ir.Resources).Should(HaveKey("bottle"))
+		show := ir.Resources["bottle"].Actions["show"]
+		Ω(show).ShouldNot(BeNil())
+		Ω(show.Routes).Should(HaveLen(2))
+		Ω(show.Routes[0].Method).Should(Equal("GET"))
+		Ω(show.Routes[0].Path).Should(Equal("/:bottleID"))
+		Ω(show.Routes[0].FullPath).Should(Equal("/cellar/bottles/:bottleID"))
+		Ω(show.Params.Type.Attributes).Should(HaveKey("bottleID"))
+		Ω(show.Params.Type.Attributes).Should(HaveKey("accountID"))
+		Ω(show.Responses).Should(HaveKey("OK"))
+		Ω(show.Responses["OK"].MediaType).Should(Equal("application/vnd.bottle+json"))
+		Ω(show.Responses["OK"].Standard).Should(BeTrue())
+	})
+
+	It("describes the security requirements", func() {
+		actions := ir.Resources["bottle"].Actions
+		Ω(actions["create"].Security).Should(Equal(&gendesignjson.Security{Scheme: "jwt", Scopes: []string{"api:write"}}))
+		Ω(actions["list"].Security).Should(Equal(&gendesignjson.Security{Scheme: "jwt", Scopes: []string{"api:read"}}))
+		Ω(actions["ping"].Security).Should(BeNil())
+	})
+
+	It("describes the types and their validations", func() {
+		Ω(ir.Types).Should(HaveKey("Rating"))
+		rating := ir.Types["Rating"]
+		Ω(rating.Type.Kind).Should(Equal("object"))
+		Ω(rating.Validation.Required).Should(Equal([]string{"stars"}))
+		stars := rating.Type.Attributes["stars"]
+		Ω(stars.Type.Kind).Should(Equal("integer"))
+		Ω(stars.Default).Should(Equal(3))
+		Ω(stars.Validation.Enum).Should(Equal([]interface{}{1, 2, 3, 4, 5}))
+		Ω(rating.Type.Attributes["comment"].Validation.Pattern).Should(Equal("^[a-z ]*$"))
+		Ω(rating.Type.Attributes["tags"].Default).Should(Equal(map[string]interface{}{"sweet": 1}))
+	})
+
+	It("describes the media types with their views and links", func() {
+		Ω(ir.MediaTypes).Should(HaveKey("application/vnd.bottle"))
+		bottle := ir.MediaTypes["application/vnd.bottle"]
+		Ω(bottle.Name).Should(Equal("Bottle"))
+		Ω(bottle.Identifier).Should(Equal("application/vnd.bottle+json"))
+		Ω(bottle.Views).Should(HaveKey("default"))
+		Ω(bottle.Views).Should(HaveKey("tiny"))
+		Ω(bottle.Views["tiny"].Type.Attributes).Should(HaveLen(3))
+		Ω(bottle.Links).Should(HaveKeyWithValue("account", &gendesignjson.Link{View: "link"}))
+		Ω(bottle.Type.Attributes["account"].Type).Should(Equal(&gendesignjson.Type{Kind: "media_type", Name: "application/vnd.account"}))
+	})
+
+	It("records the types used by the API that it does not declare", func() {
+		Ω(ir.InlineTypes).Should(HaveKey("CreateBottlePayload"))
+		Ω(ir.Resources["bottle"].Actions["create"].Payload).Should(Equal("CreateBottlePayload"))
+		Ω(ir.InlineTypes["CreateBottlePayload"].Validation.Required).Should(Equal([]string{"name"}))
+	})
+
+	It("describes the collection media types", func() {
+		Ω(ir.MediaTypes).Should(HaveKey("application/vnd.bottle; type=collection"))
+		collection := ir.MediaTypes["application/vnd.bottle; type=collection"]
+		Ω(collection.Name).Should(Equal("BottleCollection"))
+		Ω(collection.Type.Kind).Should(Equal("array"))
+		Ω(collection.Type.Elem.Type).Should(Equal(&gendesignjson.Type{Kind: "media_type", Name: "application/vnd.bottle"}))
+		Ω(collection.Views).Should(HaveLen(2))
+	})
+
+	It("produces the same document for the same design", func() {
+		Ω(marshal(gendesignjson.New(run()))).Should(Equal(marshal(ir)))
+	})
+})
+
+var _ = Describe("Load", func() {
+	var (
+		tmpDir string
+		path   string
+		orig   *APIDefinition
+		api    *APIDefinition
+		err    error
+	)
+
+	BeforeEach(func() {
+		var e error
+		tmpDir, e = ioutil.TempDir("", "designjson")
+		Ω(e).ShouldNot(HaveOccurred())
+		path = filepath.Join(tmpDir, "design.json")
+		orig = run()
+		Ω(ioutil.WriteFile(path, []byte(marshal(gendesignjson.New(orig))), 0644)).Should(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		api, err = gendesignjson.Load(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("makes the loaded API definition the current design", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(Design).Should(BeIdenticalTo(api))
+	})
+
+	It("produces the same design JSON", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		b, e := ioutil.ReadFile(path)
+		Ω(e).ShouldNot(HaveOccurred())
+		Ω(marshal(gendesignjson.New(api))).Should(Equal(string(b)))
+	})
+
+	It("converts the values to the Go types used by the DSL", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		rating := api.Types["Rating"].Type.ToObject()
+		Ω(rating["stars"].DefaultValue).Should(Equal(3))
+		Ω(rating["stars"].Validation.Values).Should(Equal([]interface{}{1, 2, 3, 4, 5}))
+		Ω(rating["ratio"].Example).Should(Equal(0.5))
+		Ω(rating["tags"].DefaultValue).Should(Equal(map[interface{}]interface{}{"sweet": 1}))
+	})
+
+	It("resolves the type and security scheme references", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		bottle := api.MediaTypes["application/vnd.bottle"]
+		Ω(bottle.Type.ToObject()["rating"].Type).Should(BeIdenticalTo(api.Types["Rating"]))
+		Ω(bottle.Links["account"].MediaType()).Should(BeIdenticalTo(api.MediaTypes["application/vnd.account"]))
+		create := api.Resources["bottle"].Actions["create"]
+		Ω(create.Parent).Should(BeIdenticalTo(api.Resources["bottle"]))
+		Ω(create.Security.Scheme).Should(BeIdenticalTo(api.SecuritySchemes[0]))
+		Ω(create.Responses["BadRequest"].Type).Should(BeIdenticalTo(ErrorMedia))
+	})
+
+	It("lets the generators produce the same outputs", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		// The YAML encoder supports the map[interface{}]interface{} values used by hash defaults.
+		generate := func(api *APIDefinition) (string, string) {
+			// The examples generated on demand depend on the state of the random generator.
+			api.NoExamples = true
+			ProjectedMediaTypes = make(MediaTypeRoot)
+			genschema.Definitions = make(map[string]*genschema.JSONSchema)
+			s, err := genswagger.New(api)
+			Ω(err).ShouldNot(HaveOccurred())
+			genschema.Definitions = make(map[string]*genschema.JSONSchema)
+			o, err := genopenapi.New(api)
+			Ω(err).ShouldNot(HaveOccurred())
+			sy, err := yaml.Marshal(s)
+			Ω(err).ShouldNot(HaveOccurred())
+			oy, err := yaml.Marshal(o)
+			Ω(err).ShouldNot(HaveOccurred())
+			return string(sy), string(oy)
+		}
+		loadedSwagger, loadedOpenAPI := generate(api)
+		// Loading updates the current design in place, run the DSL again to get the original.
+		origSwagger, origOpenAPI := generate(run())
+		Ω(loadedSwagger).Should(Equal(origSwagger))
+		Ω(loadedOpenAPI).Should(Equal(origOpenAPI))
+	})
+
+	Context("with a document using another version of the format", func() {
+		BeforeEach(func() {
+			ir := gendesignjson.New(orig)
+			ir.Version = gendesignjson.FormatVersion + 1
+			Ω(ioutil.WriteFile(path, []byte(marshal(ir)), 0644)).Should(Succeed())
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("version"))
+		})
+	})
+
+	Context("with a file that is not a design JSON document", func() {
+		BeforeEach(func() {
+			Ω(ioutil.WriteFile(path, []byte(`{"version": 1, "api": "cellar"}`), 0644)).Should(Succeed())
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(gendesignjson.IsDesignFile(path)).Should(BeFalse())
+		})
+	})
+
+	Context("with a reference to an unknown type", func() {
+		BeforeEach(func() {
+			ir := gendesignjson.New(orig)
+			ir.Resources["bottle"].Actions["create"].Payload = "Unknown"
+			Ω(ioutil.WriteFile(path, []byte(marshal(ir)), 0644)).Should(Succeed())
+		})
+
+		It("fails", func() {
+			Ω(err).Should(MatchError(`unknown type "Unknown"`))
+		})
+	})
+})
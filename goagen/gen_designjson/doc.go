@@ -0,0 +1,10 @@
+/*
+Package gendesignjson serializes finalized API designs into a stable, versioned and
+language-neutral JSON representation. The document captures the resources, actions, routes, types
+with their validations, media types with their views and links, security schemes and metadata so
+that generators can be written in any language without compiling the design package.
+Load reads such a document back into an API definition and makes it the current design, this
+makes it possible to run the goagen generators from a design JSON file instead of a design
+package.
+*/
+package gendesignjson
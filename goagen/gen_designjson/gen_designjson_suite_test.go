@@ -0,0 +1,13 @@
+package gendesignjson_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenDesignJSON(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenDesignJSON Suite")
+}
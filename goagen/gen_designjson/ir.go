@@ -0,0 +1,351 @@
+package gendesignjson
+
+import "github.com/goadesign/goa/design"
+
+const (
+	// Format is the value of the "format" field of design JSON documents.
+	Format = "goa-design"
+
+	// FormatVersion is the version of the design JSON format written by New. The version is
+	// incremented whenever a change to the format may break existing readers.
+	FormatVersion = 1
+)
+
+type (
+	// Design is the language-neutral representation of a finalized API design. It captures
+	// the API definition after the DSL has run and the design has been validated and
+	// finalized so that inherited and implicit definitions (base params, default responses,
+	// security requirements etc.) are already resolved.
+	//
+	// Types are referenced by name and media types by canonical identifier. Named definitions
+	// are indexed by name in JSON objects, the keys are sorted so that the same design always
+	// produces the same document.
+	Design struct {
+		// Format is always "goa-design".
+		Format string `json:"format"`
+		// Version is the format version.
+		Version int `json:"version"`
+		// API describes the API level definitions.
+		API *APIDef `json:"api"`
+		// Resources lists the API resources indexed by name.
+		Resources map[string]*Resource `json:"resources,omitempty"`
+		// Types lists the user types declared by the API indexed by name.
+		Types map[string]*UserType `json:"types,omitempty"`
+		// MediaTypes lists the media types declared by the API indexed by canonical
+		// identifier.
+		MediaTypes map[string]*MediaType `json:"media_types,omitempty"`
+		// InlineTypes lists the user types used by the API that it does not declare, for
+		// example payloads defined inline, indexed by name.
+		InlineTypes map[string]*UserType `json:"inline_types,omitempty"`
+		// InlineMediaTypes lists the media types used by the API that it does not declare,
+		// for example collections created with CollectionOf, indexed by canonical identifier.
+		InlineMediaTypes map[string]*MediaType `json:"inline_media_types,omitempty"`
+	}
+
+	// APIDef describes the API level definitions.
+	APIDef struct {
+		Name            string                    `json:"name"`
+		Title           string                    `json:"title,omitempty"`
+		Description     string                    `json:"description,omitempty"`
+		Version         string                    `json:"version,omitempty"`
+		Host            string                    `json:"host,omitempty"`
+		Schemes         []string                  `json:"schemes,omitempty"`
+		BasePath        string                    `json:"base_path,omitempty"`
+		Params          *Attribute                `json:"params,omitempty"`
+		Consumes        []*Encoding               `json:"consumes,omitempty"`
+		Produces        []*Encoding               `json:"produces,omitempty"`
+		Origins         map[string]*CORS          `json:"origins,omitempty"`
+		TermsOfService  string                    `json:"terms_of_service,omitempty"`
+		Contact         *design.ContactDefinition `json:"contact,omitempty"`
+		License         *design.LicenseDefinition `json:"license,omitempty"`
+		Docs            *design.DocsDefinition    `json:"docs,omitempty"`
+		Responses       map[string]*Response      `json:"responses,omitempty"`
+		Metadata        map[string][]string       `json:"metadata,omitempty"`
+		SecuritySchemes []*SecurityScheme         `json:"security_schemes,omitempty"`
+		Security        *Security                 `json:"security,omitempty"`
+		NoExamples      bool                      `json:"no_examples,omitempty"`
+		ProblemDetails  bool                      `json:"problem_details,omitempty"`
+		Webhooks        map[string]*Webhook       `json:"webhooks,omitempty"`
+		Errors          map[string]*Error         `json:"errors,omitempty"`
+	}
+
+	// Resource describes an API resource.
+	Resource struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description,omitempty"`
+		Schemes     []string `json:"schemes,omitempty"`
+		BasePath    string   `json:"base_path,omitempty"`
+		// Parent is the name of the parent resource if any.
+		Parent string `json:"parent,omitempty"`
+		// MediaType is the identifier of the resource default media type if any.
+		MediaType       string               `json:"media_type,omitempty"`
+		DefaultView     string               `json:"default_view,omitempty"`
+		CanonicalAction string               `json:"canonical_action,omitempty"`
+		Params          *Attribute           `json:"params,omitempty"`
+		Headers         *Attribute           `json:"headers,omitempty"`
+		Cookies         *Attribute           `json:"cookies,omitempty"`
+		Actions         map[string]*Action   `json:"actions,omitempty"`
+		FileServers     []*FileServer        `json:"file_servers,omitempty"`
+		Responses       map[string]*Response `json:"responses,omitempty"`
+		Origins         map[string]*CORS     `json:"origins,omitempty"`
+		Metadata        map[string][]string  `json:"metadata,omitempty"`
+		Security        *Security            `json:"security,omitempty"`
+		Deprecation     *Deprecation         `json:"deprecation,omitempty"`
+		Webhooks        map[string]*Webhook  `json:"webhooks,omitempty"`
+		Errors          map[string]*Error    `json:"errors,omitempty"`
+	}
+
+	// Action describes a resource action.
+	Action struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description,omitempty"`
+		Docs        *design.DocsDefinition `json:"docs,omitempty"`
+		Schemes     []string               `json:"schemes,omitempty"`
+		Routes      []*Route               `json:"routes,omitempty"`
+		// Params describes the path and query string parameters including the resource and
+		// API parameters.
+		Params *Attribute `json:"params,omitempty"`
+		// QueryParams describes the query string parameters.
+		QueryParams *Attribute `json:"query_params,omitempty"`
+		// Payload is the name of the request body type if any.
+		Payload          string     `json:"payload,omitempty"`
+		PayloadOptional  bool       `json:"payload_optional,omitempty"`
+		PayloadMultipart bool       `json:"payload_multipart,omitempty"`
+		Headers          *Attribute `json:"headers,omitempty"`
+		Cookies          *Attribute `json:"cookies,omitempty"`
+		// InboundMessage is the type of the messages sent by websocket clients if any.
+		InboundMessage *Type `json:"inbound_message,omitempty"`
+		// OutboundMessage is the type of the messages sent to websocket clients if any.
+		OutboundMessage *Type                `json:"outbound_message,omitempty"`
+		Responses       map[string]*Response `json:"responses,omitempty"`
+		Metadata        map[string][]string  `json:"metadata,omitempty"`
+		Security        *Security            `json:"security,omitempty"`
+		Deprecation     *Deprecation         `json:"deprecation,omitempty"`
+		Async           *Async               `json:"async,omitempty"`
+		Errors          map[string]*Error    `json:"errors,omitempty"`
+	}
+
+	// Route describes an action route.
+	Route struct {
+		Method string `json:"method"`
+		// Path is the route path relative to the resource base path.
+		Path string `json:"path"`
+		// FullPath is the complete route path including the API and resource base paths.
+		FullPath string              `json:"full_path"`
+		Metadata map[string][]string `json:"metadata,omitempty"`
+	}
+
+	// Async describes the job started by a long-running action.
+	Async struct {
+		// StatusMediaType is the identifier of the job media type.
+		StatusMediaType string `json:"status_media_type"`
+		// StatusAction is the name of the resource action that returns the job status.
+		StatusAction string `json:"status_action,omitempty"`
+	}
+
+	// Response describes a response.
+	Response struct {
+		Name        string `json:"name"`
+		Status      int    `json:"status"`
+		Description string `json:"description,omitempty"`
+		// Type is the type of the response body if any.
+		Type *Type `json:"type,omitempty"`
+		// MediaType is the response media type identifier if any.
+		MediaType string     `json:"media_type,omitempty"`
+		View      string     `json:"view,omitempty"`
+		Headers   *Attribute `json:"headers,omitempty"`
+		Cookies   *Attribute `json:"cookies,omitempty"`
+		// Stream is the type of the events of Server-Sent Events responses.
+		Stream   *Type               `json:"stream,omitempty"`
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Standard is true if the response is one of the built-in responses.
+		Standard bool `json:"standard,omitempty"`
+	}
+
+	// FileServer describes an endpoint serving static files.
+	FileServer struct {
+		Description string                 `json:"description,omitempty"`
+		Docs        *design.DocsDefinition `json:"docs,omitempty"`
+		FilePath    string                 `json:"file_path"`
+		RequestPath string                 `json:"request_path"`
+		Metadata    map[string][]string    `json:"metadata,omitempty"`
+		Security    *Security              `json:"security,omitempty"`
+	}
+
+	// Webhook describes a request sent by the API to its clients.
+	Webhook struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		// Payload is the name of the request body type if any.
+		Payload   string               `json:"payload,omitempty"`
+		Responses map[string]*Response `json:"responses,omitempty"`
+	}
+
+	// Error describes an error returned by the API.
+	Error struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Status      int    `json:"status,omitempty"`
+		// Meta is the name of the type describing the error metadata if any.
+		Meta string `json:"meta,omitempty"`
+	}
+
+	// CORS describes a CORS policy.
+	CORS struct {
+		Origin      string   `json:"origin"`
+		Headers     []string `json:"headers,omitempty"`
+		Methods     []string `json:"methods,omitempty"`
+		Exposed     []string `json:"exposed,omitempty"`
+		MaxAge      uint     `json:"max_age,omitempty"`
+		Credentials bool     `json:"credentials,omitempty"`
+		Regexp      bool     `json:"regexp,omitempty"`
+	}
+
+	// Encoding describes the encoders or decoders used for a set of MIME types.
+	Encoding struct {
+		MIMETypes   []string `json:"mime_types"`
+		PackagePath string   `json:"package_path,omitempty"`
+		Function    string   `json:"function,omitempty"`
+		Encoder     bool     `json:"encoder,omitempty"`
+	}
+
+	// SecurityScheme describes a security scheme.
+	SecurityScheme struct {
+		// Kind is one of "oauth2", "basic_auth", "api_key" or "jwt".
+		Kind             string              `json:"kind"`
+		Name             string              `json:"name"`
+		Type             string              `json:"type"`
+		Description      string              `json:"description,omitempty"`
+		In               string              `json:"in,omitempty"`
+		ParamName        string              `json:"param_name,omitempty"`
+		Scopes           map[string]string   `json:"scopes,omitempty"`
+		Flow             string              `json:"flow,omitempty"`
+		TokenURL         string              `json:"token_url,omitempty"`
+		AuthorizationURL string              `json:"authorization_url,omitempty"`
+		Metadata         map[string][]string `json:"metadata,omitempty"`
+	}
+
+	// Security describes a security requirement.
+	Security struct {
+		// Scheme is the name of the security scheme, empty if None is true.
+		Scheme string `json:"scheme,omitempty"`
+		// Scopes lists the required scopes.
+		Scopes []string `json:"scopes,omitempty"`
+		// None is true if the requirement disables security.
+		None bool `json:"none,omitempty"`
+	}
+
+	// Deprecation describes the deprecation of a definition.
+	Deprecation struct {
+		Since       string `json:"since,omitempty"`
+		Sunset      string `json:"sunset,omitempty"`
+		Replacement string `json:"replacement,omitempty"`
+	}
+
+	// UserType describes a user type.
+	UserType struct {
+		*Attribute
+		Name string `json:"name"`
+	}
+
+	// MediaType describes a media type.
+	MediaType struct {
+		*UserType
+		Identifier  string `json:"identifier"`
+		ContentType string `json:"content_type,omitempty"`
+		// Views lists the media type views indexed by name, each view is an object
+		// attribute listing the rendered attributes.
+		Views map[string]*Attribute `json:"views,omitempty"`
+		Links map[string]*Link      `json:"links,omitempty"`
+		// Resource is the name of the resource the media type is the default media type of.
+		Resource string `json:"resource,omitempty"`
+	}
+
+	// Link describes a media type link.
+	Link struct {
+		View        string `json:"view,omitempty"`
+		URITemplate string `json:"uri_template,omitempty"`
+	}
+
+	// Attribute describes an attribute: a type together with its validations and
+	// documentation.
+	Attribute struct {
+		Type *Type `json:"type,omitempty"`
+		// Reference is the type that provides default definitions for the attributes if any.
+		Reference *Type `json:"reference,omitempty"`
+		// Bases lists the types whose attributes are copied into the attribute type.
+		Bases       []*Type             `json:"bases,omitempty"`
+		Description string              `json:"description,omitempty"`
+		Validation  *Validation         `json:"validation,omitempty"`
+		Metadata    map[string][]string `json:"metadata,omitempty"`
+		Default     interface{}         `json:"default,omitempty"`
+		Example     interface{}         `json:"example,omitempty"`
+		// NoExample is true if no example must be generated for the attribute.
+		NoExample   bool         `json:"no_example,omitempty"`
+		View        string       `json:"view,omitempty"`
+		Deprecation *Deprecation `json:"deprecation,omitempty"`
+		Style       string       `json:"style,omitempty"`
+		Explode     *bool        `json:"explode,omitempty"`
+		// NonZero lists the names of the child attributes that are never zero.
+		NonZero []string `json:"non_zero,omitempty"`
+	}
+
+	// Type describes a data type.
+	Type struct {
+		// Kind is the type kind: one of the primitive types ("boolean", "integer", "int32",
+		// "int64", "uint32", "uint64", "number", "float32", "float64", "string",
+		// "datetime", "date", "uuid", "bytes", "file", "any"), "array", "hash", "object",
+		// "union", "user_type" or "media_type".
+		Kind string `json:"kind"`
+		// Name is the type name for user types and the canonical identifier for media
+		// types.
+		Name string `json:"name,omitempty"`
+		// Elem describes the elements of arrays and the values of hashes.
+		Elem *Attribute `json:"elem,omitempty"`
+		// Key describes the keys of hashes.
+		Key *Attribute `json:"key,omitempty"`
+		// Attributes lists the attributes of objects indexed by name.
+		Attributes map[string]*Attribute `json:"attributes,omitempty"`
+		// Discriminator is the name of the attribute that identifies the type of union
+		// values if any.
+		Discriminator string `json:"discriminator,omitempty"`
+		// Types lists the names of the user types a union value may be.
+		Types []string `json:"types,omitempty"`
+	}
+
+	// Validation describes the validations of an attribute.
+	Validation struct {
+		Enum              []interface{}       `json:"enum,omitempty"`
+		EnumDescriptions  []*EnumDescription  `json:"enum_descriptions,omitempty"`
+		Format            string              `json:"format,omitempty"`
+		Pattern           string              `json:"pattern,omitempty"`
+		Minimum           *float64            `json:"minimum,omitempty"`
+		Maximum           *float64            `json:"maximum,omitempty"`
+		ExclusiveMinimum  *float64            `json:"exclusive_minimum,omitempty"`
+		ExclusiveMaximum  *float64            `json:"exclusive_maximum,omitempty"`
+		MultipleOf        *float64            `json:"multiple_of,omitempty"`
+		MinLength         *int                `json:"min_length,omitempty"`
+		MaxLength         *int                `json:"max_length,omitempty"`
+		UniqueItems       bool                `json:"unique_items,omitempty"`
+		MinProperties     *int                `json:"min_properties,omitempty"`
+		MaxProperties     *int                `json:"max_properties,omitempty"`
+		Required          []string            `json:"required,omitempty"`
+		RequiredIf        []*RequiredIf       `json:"required_if,omitempty"`
+		DependentRequired map[string][]string `json:"dependent_required,omitempty"`
+		MutuallyExclusive [][]string          `json:"mutually_exclusive,omitempty"`
+	}
+
+	// EnumDescription describes an enum value.
+	EnumDescription struct {
+		Value       interface{} `json:"value"`
+		Description string      `json:"description"`
+	}
+
+	// RequiredIf describes a conditional required validation: the Required attributes must
+	// be set when the attribute named Attribute is set to Value.
+	RequiredIf struct {
+		Attribute string      `json:"attribute"`
+		Value     interface{} `json:"value"`
+		Required  []string    `json:"required"`
+	}
+)
@@ -0,0 +1,748 @@
+package gendesignjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ReadFile reads the design JSON document at the given path. It returns an error if the document
+// is not a design JSON document or if it uses a different version of the format.
+func ReadFile(path string) (*Design, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var d Design
+	dec := json.NewDecoder(bytes.NewReader(b))
+	// Decode numbers as json.Number so that integers can be told apart from floats.
+	dec.UseNumber()
+	if err := dec.Decode(&d); err != nil {
+		return nil, fmt.Errorf("invalid design JSON %s: %s", path, err)
+	}
+	if d.Format != Format {
+		return nil, fmt.Errorf("%s is not a design JSON document", path)
+	}
+	if d.Version != FormatVersion {
+		return nil, fmt.Errorf("design JSON %s uses version %d of the format, expected version %d", path, d.Version, FormatVersion)
+	}
+	return &d, nil
+}
+
+// IsDesignFile returns true if the file at the given path is a design JSON document.
+func IsDesignFile(path string) bool {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var header struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(b, &header) == nil && header.Format == Format
+}
+
+// Load reads the design JSON document at the given path and makes the API definition it
+// describes the current design so that generators may run against it as if it had been produced
+// by the DSL. The examples recorded in the document are used as is, the examples that generators
+// produce on demand (e.g. for parameters) may differ from the ones produced from the design package
+// as the random generator state is not part of the document.
+func Load(path string) (*design.APIDefinition, error) {
+	d, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	api, err := d.APIDefinition()
+	if err != nil {
+		return nil, err
+	}
+	if design.Design == nil {
+		design.Design = api
+		design.GeneratedMediaTypes = make(design.MediaTypeRoot)
+	} else {
+		// Update the design roots in place as the DSL engine holds on to them.
+		*design.Design = *api
+		design.GeneratedMediaTypes.Reset()
+	}
+	design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+	return design.Design, nil
+}
+
+// APIDefinition builds the finalized API definition described by the design JSON document.
+func (d *Design) APIDefinition() (*design.APIDefinition, error) {
+	if d.API == nil {
+		return nil, fmt.Errorf("missing API definition")
+	}
+	l := &loader{
+		ir:         d,
+		api:        design.NewAPIDefinition(),
+		types:      make(map[string]*design.UserTypeDefinition),
+		mediaTypes: make(map[string]*design.MediaTypeDefinition),
+	}
+	l.load()
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.api, nil
+}
+
+// loader builds an API definition from its design JSON representation. Types are created first
+// so that references can be resolved, the default and example values are converted last once all
+// the types are complete.
+type loader struct {
+	ir         *Design
+	api        *design.APIDefinition
+	types      map[string]*design.UserTypeDefinition
+	mediaTypes map[string]*design.MediaTypeDefinition
+	resources  map[string]*design.ResourceDefinition
+	values     []func()
+	err        error
+}
+
+// fail records the first error that occurs while loading.
+func (l *loader) fail(format string, args ...interface{}) {
+	if l.err == nil {
+		l.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (l *loader) load() {
+	l.declareTypes()
+	l.defineTypes()
+
+	ir, api := l.ir.API, l.api
+	api.Name = ir.Name
+	api.Title = ir.Title
+	api.Description = ir.Description
+	api.Version = ir.Version
+	api.Host = ir.Host
+	api.Schemes = ir.Schemes
+	api.BasePath = ir.BasePath
+	api.Params = l.attribute(ir.Params)
+	api.Consumes = loadEncodings(ir.Consumes)
+	api.Produces = loadEncodings(ir.Produces)
+	api.Origins = loadOrigins(ir.Origins, api)
+	api.TermsOfService = ir.TermsOfService
+	api.Contact = ir.Contact
+	api.License = ir.License
+	api.Docs = ir.Docs
+	api.Metadata = dslengine.MetadataDefinition(ir.Metadata)
+	for _, s := range ir.SecuritySchemes {
+		api.SecuritySchemes = append(api.SecuritySchemes, l.securityScheme(s))
+	}
+	api.Security = l.security(ir.Security)
+	api.NoExamples = ir.NoExamples
+	api.ProblemDetails = ir.ProblemDetails
+	api.Responses = l.responses(ir.Responses, api)
+	api.Webhooks = l.webhooks(ir.Webhooks, api)
+	api.Errors = l.errors(ir.Errors, api)
+
+	l.resources = make(map[string]*design.ResourceDefinition, len(l.ir.Resources))
+	for n := range l.ir.Resources {
+		l.resources[n] = &design.ResourceDefinition{Name: n}
+	}
+	for n, r := range l.ir.Resources {
+		l.resource(l.resources[n], r)
+	}
+	api.Resources = l.resources
+	l.linkMediaTypes()
+
+	for _, v := range l.values {
+		v()
+	}
+}
+
+// declareTypes creates the user types and media types so that they can be referenced. The
+// built-in media types are not recreated as generators rely on their identity.
+func (l *loader) declareTypes() {
+	declare := func(types map[string]*UserType, declared bool) {
+		for n := range types {
+			if _, ok := l.types[n]; ok {
+				continue
+			}
+			ut := &design.UserTypeDefinition{TypeName: n, AttributeDefinition: &design.AttributeDefinition{}}
+			l.types[n] = ut
+			if declared {
+				l.api.Types[n] = ut
+			}
+		}
+	}
+	declareMedia := func(types map[string]*MediaType, declared bool) {
+		for id, t := range types {
+			if _, ok := l.mediaTypes[id]; ok {
+				continue
+			}
+			mt := builtin(id)
+			if mt == nil {
+				mt = &design.MediaTypeDefinition{
+					UserTypeDefinition: &design.UserTypeDefinition{
+						TypeName:            t.Name,
+						AttributeDefinition: &design.AttributeDefinition{},
+					},
+					Identifier:  t.Identifier,
+					ContentType: t.ContentType,
+				}
+			} else if mt.ContentType == "" {
+				// Set by Finalize when the design is loaded from Go.
+				mt.ContentType = t.ContentType
+			}
+			l.mediaTypes[id] = mt
+			if declared {
+				l.api.MediaTypes[id] = mt
+			}
+		}
+	}
+	l.api.Types = make(map[string]*design.UserTypeDefinition, len(l.ir.Types))
+	l.api.MediaTypes = make(map[string]*design.MediaTypeDefinition, len(l.ir.MediaTypes))
+	declare(l.ir.Types, true)
+	declare(l.ir.InlineTypes, false)
+	declareMedia(l.ir.MediaTypes, true)
+	declareMedia(l.ir.InlineMediaTypes, false)
+}
+
+// defineTypes builds the attributes, views and links of the types created by declareTypes.
+func (l *loader) defineTypes() {
+	define := func(types map[string]*UserType, inline bool) {
+		for n, t := range types {
+			if _, ok := l.ir.Types[n]; ok && inline {
+				// The declared type with the same name takes precedence.
+				continue
+			}
+			if t.Attribute != nil {
+				l.define(l.types[n].AttributeDefinition, t.Attribute)
+			}
+		}
+	}
+	defineMedia := func(types map[string]*MediaType, inline bool) {
+		for id, t := range types {
+			if _, ok := l.ir.MediaTypes[id]; ok && inline || builtin(id) != nil {
+				continue
+			}
+			mt := l.mediaTypes[id]
+			if t.UserType != nil && t.Attribute != nil {
+				l.define(mt.AttributeDefinition, t.Attribute)
+			}
+			for n, v := range t.Views {
+				if mt.Views == nil {
+					mt.Views = make(map[string]*design.ViewDefinition)
+				}
+				mt.Views[n] = &design.ViewDefinition{AttributeDefinition: l.attribute(v), Name: n, Parent: mt}
+			}
+			for n, lk := range t.Links {
+				if mt.Links == nil {
+					mt.Links = make(map[string]*design.LinkDefinition)
+				}
+				mt.Links[n] = &design.LinkDefinition{Name: n, View: lk.View, URITemplate: lk.URITemplate, Parent: mt}
+			}
+		}
+	}
+	define(l.ir.Types, false)
+	define(l.ir.InlineTypes, true)
+	defineMedia(l.ir.MediaTypes, false)
+	defineMedia(l.ir.InlineMediaTypes, true)
+}
+
+// linkMediaTypes sets the resource of the media types that are the default media type of a
+// resource.
+func (l *loader) linkMediaTypes() {
+	link := func(types map[string]*MediaType) {
+		for id, t := range types {
+			if t.Resource == "" || builtin(id) != nil {
+				continue
+			}
+			r, ok := l.resources[t.Resource]
+			if !ok {
+				l.fail("media type %s: unknown resource %#v", id, t.Resource)
+				continue
+			}
+			l.mediaTypes[id].Resource = r
+		}
+	}
+	link(l.ir.MediaTypes)
+	link(l.ir.InlineMediaTypes)
+}
+
+// builtin returns the built-in media type with the given canonical identifier if any.
+func builtin(id string) *design.MediaTypeDefinition {
+	for _, mt := range []*design.MediaTypeDefinition{design.ErrorMedia, design.ProblemMedia, design.JobMedia} {
+		if design.CanonicalIdentifier(mt.Identifier) == id {
+			return mt
+		}
+	}
+	return nil
+}
+
+func (l *loader) resource(res *design.ResourceDefinition, r *Resource) {
+	res.Description = r.Description
+	res.Schemes = r.Schemes
+	res.BasePath = r.BasePath
+	res.ParentName = r.Parent
+	res.MediaType = r.MediaType
+	res.DefaultViewName = r.DefaultView
+	res.CanonicalActionName = r.CanonicalAction
+	res.Params = l.attribute(r.Params)
+	res.Headers = l.attribute(r.Headers)
+	res.Cookies = l.attribute(r.Cookies)
+	res.Responses = l.responses(r.Responses, res)
+	res.Origins = loadOrigins(r.Origins, res)
+	res.Metadata = dslengine.MetadataDefinition(r.Metadata)
+	res.Security = l.security(r.Security)
+	res.Deprecation = loadDeprecation(r.Deprecation)
+	res.Webhooks = l.webhooks(r.Webhooks, res)
+	res.Errors = l.errors(r.Errors, res)
+	res.Actions = make(map[string]*design.ActionDefinition, len(r.Actions))
+	for n, a := range r.Actions {
+		res.Actions[n] = l.action(res, a)
+	}
+	for n, a := range r.Actions {
+		if a.Async == nil || a.Async.StatusAction == "" {
+			continue
+		}
+		status, ok := res.Actions[a.Async.StatusAction]
+		if !ok {
+			l.fail("resource %#v action %#v: unknown status action %#v", res.Name, n, a.Async.StatusAction)
+			continue
+		}
+		res.Actions[n].Async.StatusAction = status
+	}
+	for _, fs := range r.FileServers {
+		res.FileServers = append(res.FileServers, &design.FileServerDefinition{
+			Parent:      res,
+			Description: fs.Description,
+			Docs:        fs.Docs,
+			FilePath:    fs.FilePath,
+			RequestPath: fs.RequestPath,
+			Metadata:    dslengine.MetadataDefinition(fs.Metadata),
+			Security:    l.security(fs.Security),
+		})
+	}
+}
+
+func (l *loader) action(res *design.ResourceDefinition, a *Action) *design.ActionDefinition {
+	act := &design.ActionDefinition{
+		Name:             a.Name,
+		Description:      a.Description,
+		Docs:             a.Docs,
+		Parent:           res,
+		Schemes:          a.Schemes,
+		Params:           l.attribute(a.Params),
+		QueryParams:      l.attribute(a.QueryParams),
+		PayloadOptional:  a.PayloadOptional,
+		PayloadMultipart: a.PayloadMultipart,
+		Headers:          l.attribute(a.Headers),
+		Cookies:          l.attribute(a.Cookies),
+		InboundMessage:   l.dataType(a.InboundMessage),
+		OutboundMessage:  l.dataType(a.OutboundMessage),
+		Metadata:         dslengine.MetadataDefinition(a.Metadata),
+		Security:         l.security(a.Security),
+		Deprecation:      loadDeprecation(a.Deprecation),
+	}
+	act.Responses = l.responses(a.Responses, act)
+	act.Errors = l.errors(a.Errors, act)
+	for _, r := range a.Routes {
+		act.Routes = append(act.Routes, &design.RouteDefinition{
+			Verb:     r.Method,
+			Path:     r.Path,
+			Parent:   act,
+			Metadata: dslengine.MetadataDefinition(r.Metadata),
+		})
+	}
+	if a.Payload != "" {
+		act.Payload = l.userType(a.Payload)
+	}
+	if a.Async != nil {
+		act.Async = &design.AsyncDefinition{StatusMediaType: a.Async.StatusMediaType}
+	}
+	return act
+}
+
+func (l *loader) responses(resps map[string]*Response, parent dslengine.Definition) map[string]*design.ResponseDefinition {
+	if resps == nil {
+		return nil
+	}
+	res := make(map[string]*design.ResponseDefinition, len(resps))
+	for n, r := range resps {
+		res[n] = &design.ResponseDefinition{
+			Name:        r.Name,
+			Status:      r.Status,
+			Description: r.Description,
+			Type:        l.dataType(r.Type),
+			MediaType:   r.MediaType,
+			ViewName:    r.View,
+			Headers:     l.attribute(r.Headers),
+			Cookies:     l.attribute(r.Cookies),
+			Stream:      l.dataType(r.Stream),
+			Parent:      parent,
+			Metadata:    dslengine.MetadataDefinition(r.Metadata),
+			Standard:    r.Standard,
+		}
+	}
+	return res
+}
+
+func (l *loader) webhooks(hooks map[string]*Webhook, parent dslengine.Definition) map[string]*design.WebhookDefinition {
+	if hooks == nil {
+		return nil
+	}
+	res := make(map[string]*design.WebhookDefinition, len(hooks))
+	for n, w := range hooks {
+		hook := &design.WebhookDefinition{Name: w.Name, Description: w.Description, Parent: parent}
+		if w.Payload != "" {
+			hook.Payload = l.userType(w.Payload)
+		}
+		hook.Responses = l.responses(w.Responses, hook)
+		res[n] = hook
+	}
+	return res
+}
+
+func (l *loader) errors(errs map[string]*Error, parent dslengine.Definition) map[string]*design.ErrorDefinition {
+	if errs == nil {
+		return nil
+	}
+	res := make(map[string]*design.ErrorDefinition, len(errs))
+	for n, e := range errs {
+		err := &design.ErrorDefinition{Name: e.Name, Description: e.Description, Status: e.Status, Parent: parent}
+		if e.Meta != "" {
+			err.Meta = l.userType(e.Meta)
+		}
+		res[n] = err
+	}
+	return res
+}
+
+func (l *loader) securityScheme(s *SecurityScheme) *design.SecuritySchemeDefinition {
+	var kind design.SecuritySchemeKind
+	for k, n := range schemeKindNames {
+		if n == s.Kind {
+			kind = k
+		}
+	}
+	if kind == 0 {
+		l.fail("security scheme %#v: unknown kind %#v", s.Name, s.Kind)
+	}
+	return &design.SecuritySchemeDefinition{
+		Kind:             kind,
+		SchemeName:       s.Name,
+		Type:             s.Type,
+		Description:      s.Description,
+		In:               s.In,
+		Name:             s.ParamName,
+		Scopes:           s.Scopes,
+		Flow:             s.Flow,
+		TokenURL:         s.TokenURL,
+		AuthorizationURL: s.AuthorizationURL,
+		Metadata:         dslengine.MetadataDefinition(s.Metadata),
+	}
+}
+
+func (l *loader) security(s *Security) *design.SecurityDefinition {
+	if s == nil {
+		return nil
+	}
+	if s.None {
+		return &design.SecurityDefinition{Scheme: &design.SecuritySchemeDefinition{Kind: design.NoSecurityKind}}
+	}
+	for _, scheme := range l.api.SecuritySchemes {
+		if scheme.SchemeName == s.Scheme {
+			return &design.SecurityDefinition{Scheme: scheme, Scopes: s.Scopes}
+		}
+	}
+	l.fail("unknown security scheme %#v", s.Scheme)
+	return nil
+}
+
+// userType returns the user type with the given name. Declared types take precedence over
+// inline types.
+func (l *loader) userType(name string) *design.UserTypeDefinition {
+	if ut, ok := l.api.Types[name]; ok {
+		return ut
+	}
+	if ut, ok := l.types[name]; ok {
+		return ut
+	}
+	l.fail("unknown type %#v", name)
+	return &design.UserTypeDefinition{TypeName: name, AttributeDefinition: &design.AttributeDefinition{}}
+}
+
+// dataType returns the data type described by t, nil if t is nil.
+func (l *loader) dataType(t *Type) design.DataType {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind {
+	case "user_type":
+		return l.userType(t.Name)
+	case "media_type":
+		if mt, ok := l.mediaTypes[t.Name]; ok {
+			return mt
+		}
+		l.fail("unknown media type %#v", t.Name)
+		return nil
+	case "object":
+		o := make(design.Object, len(t.Attributes))
+		for n, att := range t.Attributes {
+			o[n] = l.attribute(att)
+		}
+		return o
+	case "array":
+		return &design.Array{ElemType: l.attribute(t.Elem)}
+	case "hash":
+		return &design.Hash{KeyType: l.attribute(t.Key), ElemType: l.attribute(t.Elem)}
+	case "union":
+		u := &design.Union{Discriminator: t.Discriminator}
+		for _, n := range t.Types {
+			u.Types = append(u.Types, l.userType(n))
+		}
+		return u
+	}
+	for k, n := range kindNames {
+		if n == t.Kind && k != design.ArrayKind && k != design.HashKind && k != design.ObjectKind && k != design.UnionKind {
+			return design.Primitive(k)
+		}
+	}
+	l.fail("unknown type kind %#v", t.Kind)
+	return nil
+}
+
+// attribute returns the attribute definition described by a, nil if a is nil.
+func (l *loader) attribute(a *Attribute) *design.AttributeDefinition {
+	if a == nil {
+		return nil
+	}
+	att := &design.AttributeDefinition{}
+	l.define(att, a)
+	return att
+}
+
+// define initializes att with the definition described by a.
+func (l *loader) define(att *design.AttributeDefinition, a *Attribute) {
+	att.Type = l.dataType(a.Type)
+	att.Reference = l.dataType(a.Reference)
+	att.Description = a.Description
+	att.Metadata = dslengine.MetadataDefinition(a.Metadata)
+	att.View = a.View
+	att.Deprecation = loadDeprecation(a.Deprecation)
+	att.Style = a.Style
+	att.Explode = a.Explode
+	for _, base := range a.Bases {
+		att.Bases = append(att.Bases, l.dataType(base))
+	}
+	for _, n := range a.NonZero {
+		if att.NonZeroAttributes == nil {
+			att.NonZeroAttributes = make(map[string]bool)
+		}
+		att.NonZeroAttributes[n] = true
+	}
+	if a.NoExample {
+		att.Example = "-"
+	}
+	if v := a.Validation; v != nil {
+		att.Validation = &dslengine.ValidationDefinition{
+			Format:            v.Format,
+			Pattern:           v.Pattern,
+			Minimum:           v.Minimum,
+			Maximum:           v.Maximum,
+			ExclusiveMinimum:  v.ExclusiveMinimum,
+			ExclusiveMaximum:  v.ExclusiveMaximum,
+			MultipleOf:        v.MultipleOf,
+			MinLength:         v.MinLength,
+			MaxLength:         v.MaxLength,
+			UniqueItems:       v.UniqueItems,
+			MinProperties:     v.MinProperties,
+			MaxProperties:     v.MaxProperties,
+			Required:          v.Required,
+			DependentRequired: v.DependentRequired,
+			MutuallyExclusive: v.MutuallyExclusive,
+		}
+	}
+	// The values are converted once all the types are defined as converting them requires
+	// traversing the types.
+	l.values = append(l.values, func() {
+		att.DefaultValue = convert(att.Type, a.Default)
+		if !a.NoExample {
+			att.Example = convert(att.Type, a.Example)
+		}
+		v := a.Validation
+		if v == nil {
+			return
+		}
+		val := att.Validation
+		for _, e := range v.Enum {
+			val.Values = append(val.Values, convert(att.Type, e))
+		}
+		for _, e := range v.EnumDescriptions {
+			if val.ValueDescriptions == nil {
+				val.ValueDescriptions = make(map[interface{}]string)
+			}
+			val.ValueDescriptions[convert(att.Type, e.Value)] = e.Description
+		}
+		for _, r := range v.RequiredIf {
+			var dt design.DataType
+			if o := objectOf(att.Type); o != nil {
+				if child, ok := o[r.Attribute]; ok {
+					dt = child.Type
+				}
+			}
+			val.RequiredIf = append(val.RequiredIf, &dslengine.RequiredIfDefinition{
+				Attribute: r.Attribute,
+				Value:     convert(dt, r.Value),
+				Required:  r.Required,
+			})
+		}
+	})
+}
+
+// objectOf returns the object underlying the given type if any.
+func objectOf(dt design.DataType) design.Object {
+	if dt == nil || !dt.IsObject() {
+		return nil
+	}
+	return dt.ToObject()
+}
+
+// convert converts a value decoded from JSON so that it matches the Go values produced by the DSL
+// for the given type: integers are decoded as int, numbers as float64 and hashes as maps indexed
+// by interface{}.
+func convert(dt design.DataType, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch actual := dt.(type) {
+	case design.Primitive:
+		n, ok := v.(json.Number)
+		if !ok {
+			break
+		}
+		if design.IsInteger(actual) {
+			if i, err := strconv.Atoi(string(n)); err == nil {
+				return i
+			}
+			if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+				return u
+			}
+		}
+		if design.IsNumber(actual) {
+			if f, err := n.Float64(); err == nil {
+				return f
+			}
+		}
+	case *design.UserTypeDefinition:
+		return convert(actual.Type, v)
+	case *design.MediaTypeDefinition:
+		return convert(actual.Type, v)
+	case *design.Array:
+		s, ok := v.([]interface{})
+		if !ok {
+			break
+		}
+		res := make([]interface{}, len(s))
+		for i, e := range s {
+			res[i] = convert(actual.ElemType.Type, e)
+		}
+		return res
+	case *design.Hash:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		res := make(map[interface{}]interface{}, len(m))
+		for k, e := range m {
+			var key interface{} = k
+			if design.IsNumber(actual.KeyType.Type) {
+				key = convert(actual.KeyType.Type, json.Number(k))
+			} else if actual.KeyType.Type == design.Boolean {
+				if b, err := strconv.ParseBool(k); err == nil {
+					key = b
+				}
+			}
+			res[key] = convert(actual.ElemType.Type, e)
+		}
+		return res
+	case design.Object:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		res := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			if att, ok := actual[k]; ok {
+				res[k] = convert(att.Type, e)
+			} else {
+				res[k] = convert(nil, e)
+			}
+		}
+		return res
+	}
+	return generic(v)
+}
+
+// generic converts a value decoded from JSON whose type is not known: integers are decoded as
+// int and other numbers as float64.
+func generic(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case json.Number:
+		if i, err := strconv.Atoi(string(actual)); err == nil {
+			return i
+		}
+		f, _ := actual.Float64()
+		return f
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = generic(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[k] = generic(e)
+		}
+		return res
+	}
+	return v
+}
+
+func loadEncodings(encs []*Encoding) []*design.EncodingDefinition {
+	var res []*design.EncodingDefinition
+	for _, e := range encs {
+		res = append(res, &design.EncodingDefinition{
+			MIMETypes:   e.MIMETypes,
+			PackagePath: e.PackagePath,
+			Function:    e.Function,
+			Encoder:     e.Encoder,
+		})
+	}
+	return res
+}
+
+func loadOrigins(origins map[string]*CORS, parent dslengine.Definition) map[string]*design.CORSDefinition {
+	if origins == nil {
+		return nil
+	}
+	res := make(map[string]*design.CORSDefinition, len(origins))
+	for n, o := range origins {
+		res[n] = &design.CORSDefinition{
+			Parent:      parent,
+			Origin:      o.Origin,
+			Headers:     o.Headers,
+			Methods:     o.Methods,
+			Exposed:     o.Exposed,
+			MaxAge:      o.MaxAge,
+			Credentials: o.Credentials,
+			Regexp:      o.Regexp,
+		}
+	}
+	return res
+}
+
+func loadDeprecation(d *Deprecation) *design.DeprecationDefinition {
+	if d == nil {
+		return nil
+	}
+	return &design.DeprecationDefinition{Since: d.Since, Sunset: d.Sunset, Replacement: d.Replacement}
+}
@@ -0,0 +1,595 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Change describes a difference between two versions of an API design.
+type Change struct {
+	// Breaking is true if the change may break existing clients.
+	Breaking bool `json:"breaking"`
+	// Location identifies the resource or action affected by the change, e.g. "bottle#show".
+	Location string `json:"location"`
+	// Path is the path to the changed element in the action if any, e.g. "payload.name" or
+	// "responses.200.body.id".
+	Path string `json:"path,omitempty"`
+	// Message describes the change.
+	Message string `json:"message"`
+}
+
+// comparer accumulates the changes found while comparing two snapshots.
+type comparer struct {
+	old, new *Snapshot
+	changes  []*Change
+	// location is the location of the action being compared.
+	location string
+	// visiting records the pairs of types being compared to stop recursion.
+	visiting map[string]bool
+}
+
+// String returns a human friendly description of the change.
+func (c *Change) String() string {
+	where := c.Location
+	if c.Path != "" {
+		where += " " + c.Path
+	}
+	return where + ": " + c.Message
+}
+
+// Compare returns the changes made to the old snapshot to produce the new snapshot. Changes are
+// classified as breaking if they may break clients built against the old design: removed
+// resources, actions or routes, new required request attributes, tightened request validations,
+// removed or loosened response attributes, changed types or removed media type views.
+func Compare(old, new *Snapshot) []*Change {
+	c := &comparer{old: old, new: new, visiting: make(map[string]bool)}
+	for _, n := range names(old.Resources, new.Resources) {
+		o, nw := old.Resources[n], new.Resources[n]
+		c.location = n
+		switch {
+		case nw == nil:
+			c.add(true, "", "resource removed")
+		case o == nil:
+			c.add(false, "", "resource added")
+		default:
+			for _, an := range names(o.Actions, nw.Actions) {
+				c.location = n + "#" + an
+				c.action(o.Actions[an], nw.Actions[an])
+			}
+		}
+	}
+	return c.changes
+}
+
+// Breaking returns the breaking changes.
+func Breaking(changes []*Change) []*Change {
+	var res []*Change
+	for _, c := range changes {
+		if c.Breaking {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// action compares two versions of an action.
+func (c *comparer) action(old, new *Action) {
+	if new == nil {
+		c.add(true, "", "action removed")
+		return
+	}
+	if old == nil {
+		c.add(false, "", "action added")
+		return
+	}
+	c.routes(old.Routes, new.Routes)
+	c.attribute("params", renamed(orEmpty(old.Params), wildcards(old.Routes, new.Routes)), orEmpty(new.Params), true)
+	c.attribute("headers", orEmpty(old.Headers), orEmpty(new.Headers), true)
+	c.attribute("cookies", orEmpty(old.Cookies), orEmpty(new.Cookies), true)
+	c.payload(old, new)
+	c.body("inbound_message", old.InboundMessage, new.InboundMessage, true)
+	c.body("outbound_message", old.OutboundMessage, new.OutboundMessage, false)
+	c.responses(old.Responses, new.Responses)
+	c.security(old.Security, new.Security)
+}
+
+// routes compares the routes of two versions of an action. Routes that only differ by the names
+// of their wildcards are considered identical.
+func (c *comparer) routes(old, new []string) {
+	normalize := func(routes []string) map[string]string {
+		res := make(map[string]string, len(routes))
+		for _, r := range routes {
+			res[normalizeRoute(r)] = r
+		}
+		return res
+	}
+	o, n := normalize(old), normalize(new)
+	for _, k := range sortedKeys(o) {
+		if _, ok := n[k]; !ok {
+			c.add(true, "", fmt.Sprintf("route %s removed", o[k]))
+		}
+	}
+	for _, k := range sortedKeys(n) {
+		if _, ok := o[k]; !ok {
+			c.add(false, "", fmt.Sprintf("route %s added", n[k]))
+		}
+	}
+}
+
+// wildcards returns the new names of the route wildcards renamed between the two versions of an
+// action indexed by their old names.
+func wildcards(old, new []string) map[string]string {
+	res := make(map[string]string)
+	for _, o := range old {
+		for _, n := range new {
+			if normalizeRoute(o) != normalizeRoute(n) {
+				continue
+			}
+			ow := design.WildcardRegex.FindAllStringSubmatch(o, -1)
+			nw := design.WildcardRegex.FindAllStringSubmatch(n, -1)
+			for i := range ow {
+				if ow[i][1] != nw[i][1] {
+					res[ow[i][1]] = nw[i][1]
+				}
+			}
+		}
+	}
+	return res
+}
+
+// renamed returns a copy of the params object where the attributes named after the keys of
+// names are renamed to the corresponding values.
+func renamed(params *Attribute, names map[string]string) *Attribute {
+	if len(names) == 0 {
+		return params
+	}
+	res := *params
+	res.Attributes = make(map[string]*Attribute, len(params.Attributes))
+	for n, att := range params.Attributes {
+		if nn, ok := names[n]; ok {
+			n = nn
+		}
+		res.Attributes[n] = att
+	}
+	res.Required = make([]string, len(params.Required))
+	for i, n := range params.Required {
+		if nn, ok := names[n]; ok {
+			n = nn
+		}
+		res.Required[i] = n
+	}
+	return &res
+}
+
+// payload compares the request payloads of two versions of an action.
+func (c *comparer) payload(old, new *Action) {
+	switch {
+	case old.Payload == nil && new.Payload == nil:
+		return
+	case new.Payload == nil:
+		c.add(true, "payload", "removed")
+		return
+	case old.Payload == nil:
+		if new.PayloadOptional {
+			c.add(false, "payload", "optional payload added")
+		} else {
+			c.add(true, "payload", "required payload added")
+		}
+		return
+	}
+	if old.PayloadOptional && !new.PayloadOptional {
+		c.add(true, "payload", "now required")
+	} else if !old.PayloadOptional && new.PayloadOptional {
+		c.add(false, "payload", "now optional")
+	}
+	c.attribute("payload", old.Payload, new.Payload, true)
+}
+
+// responses compares the responses of two versions of an action. Removing a success or
+// redirect response breaks clients, removing an error response does not.
+func (c *comparer) responses(old, new map[string]*Response) {
+	for _, status := range names(old, new) {
+		o, n := old[status], new[status]
+		path := "responses." + status
+		code, _ := strconv.Atoi(status)
+		switch {
+		case n == nil:
+			c.add(code < 400, path, "removed")
+			continue
+		case o == nil:
+			c.add(false, path, "added")
+			continue
+		}
+		if o.MediaType != n.MediaType {
+			c.add(true, path, fmt.Sprintf("media type changed from %q to %q", o.MediaType, n.MediaType))
+		}
+		c.body(path+".body", o.Body, n.Body, false)
+		c.body(path+".stream", o.Stream, n.Stream, false)
+		c.attribute(path+".headers", orEmpty(o.Headers), orEmpty(n.Headers), false)
+	}
+}
+
+// body compares two versions of a request or response body, either of which may be nil.
+func (c *comparer) body(path string, old, new *Attribute, in bool) {
+	switch {
+	case old == nil && new == nil:
+	case new == nil:
+		c.add(true, path, "removed")
+	case old == nil:
+		c.add(in, path, "added")
+	default:
+		c.attribute(path, old, new, in)
+	}
+}
+
+// security compares the security requirements of two versions of an action.
+func (c *comparer) security(old, new *Security) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case new == nil:
+		c.add(false, "security", fmt.Sprintf("security scheme %q removed", old.Scheme))
+		return
+	case old == nil:
+		c.add(true, "security", fmt.Sprintf("security scheme %q added", new.Scheme))
+		return
+	}
+	if old.Scheme != new.Scheme {
+		c.add(true, "security", fmt.Sprintf("security scheme changed from %q to %q", old.Scheme, new.Scheme))
+		return
+	}
+	for _, s := range new.Scopes {
+		if !contains(old.Scopes, s) {
+			c.add(true, "security", fmt.Sprintf("scope %q now required", s))
+		}
+	}
+	for _, s := range old.Scopes {
+		if !contains(new.Scopes, s) {
+			c.add(false, "security", fmt.Sprintf("scope %q no longer required", s))
+		}
+	}
+}
+
+// attribute compares two versions of an attribute. in is true if the attribute describes
+// request data and false if it describes response data: tightening request validations breaks
+// clients sending requests while loosening response validations breaks clients reading
+// responses.
+func (c *comparer) attribute(path string, old, new *Attribute, in bool) {
+	if old.TypeName != "" && new.TypeName != "" {
+		key := fmt.Sprintf("%s:%s/%s:%s/%v", old.TypeName, old.View, new.TypeName, new.View, in)
+		if c.visiting[key] {
+			return
+		}
+		c.visiting[key] = true
+		defer delete(c.visiting, key)
+	}
+	o, err := c.old.resolve(old)
+	if err != nil {
+		c.add(true, path, err.Error())
+		return
+	}
+	n, err := c.new.resolve(new)
+	if err != nil {
+		c.add(true, path, err.Error())
+		return
+	}
+	if o.Type != n.Type {
+		c.add(true, path, fmt.Sprintf("type changed from %s to %s", o.Type, n.Type))
+		return
+	}
+	if o.Nullable && !n.Nullable {
+		c.add(in, path, "no longer nullable")
+	} else if !o.Nullable && n.Nullable {
+		c.add(!in, path, "now nullable")
+	}
+	c.validations(path, o, n, in)
+	switch o.Type {
+	case "object":
+		c.object(path, o, n, in)
+	case "array":
+		c.attribute(path+"[]", o.Elem, n.Elem, in)
+	case "hash":
+		c.attribute(path+".key", o.Key, n.Key, in)
+		c.attribute(path+".value", o.Elem, n.Elem, in)
+	case "union":
+		c.union(path, o, n, in)
+	}
+}
+
+// object compares the child attributes of two versions of an object.
+func (c *comparer) object(path string, old, new *Attribute, in bool) {
+	for _, n := range names(old.Attributes, new.Attributes) {
+		o, nw := old.Attributes[n], new.Attributes[n]
+		p := path + "." + n
+		wasRequired, isRequired := contains(old.Required, n), contains(new.Required, n)
+		switch {
+		case nw == nil:
+			c.add(true, p, "removed")
+		case o == nil:
+			if isRequired {
+				c.add(in, p, "required attribute added")
+			} else {
+				c.add(false, p, "attribute added")
+			}
+		default:
+			if !wasRequired && isRequired {
+				c.add(in, p, "now required")
+			} else if wasRequired && !isRequired {
+				c.add(!in, p, "no longer required")
+			}
+			c.attribute(p, o, nw, in)
+		}
+	}
+}
+
+// union compares the member types of two versions of a union.
+func (c *comparer) union(path string, old, new *Attribute, in bool) {
+	if old.Discriminator != new.Discriminator {
+		c.add(true, path, fmt.Sprintf("discriminator changed from %q to %q", old.Discriminator, new.Discriminator))
+	}
+	for _, t := range old.Union {
+		if !contains(new.Union, t) {
+			c.add(in, path, fmt.Sprintf("union type %s removed", t))
+		}
+	}
+	for _, t := range new.Union {
+		if !contains(old.Union, t) {
+			c.add(!in, path, fmt.Sprintf("union type %s added", t))
+		}
+	}
+}
+
+// validations compares the validations of two versions of an attribute.
+func (c *comparer) validations(path string, old, new *Attribute, in bool) {
+	tightened := func(msg string, args ...interface{}) { c.add(in, path, fmt.Sprintf(msg, args...)) }
+	loosened := func(msg string, args ...interface{}) { c.add(!in, path, fmt.Sprintf(msg, args...)) }
+
+	switch {
+	case old.Enum == nil && new.Enum != nil:
+		tightened("enum %s added", strings.Join(values(new.Enum), ", "))
+	case old.Enum != nil && new.Enum == nil:
+		loosened("enum removed")
+	case old.Enum != nil:
+		ov, nv := values(old.Enum), values(new.Enum)
+		for _, v := range ov {
+			if !contains(nv, v) {
+				tightened("enum value %s removed", v)
+			}
+		}
+		for _, v := range nv {
+			if !contains(ov, v) {
+				loosened("enum value %s added", v)
+			}
+		}
+	}
+
+	c.lowerBound(path, "minimum", old.Minimum, new.Minimum, in)
+	c.lowerBound(path, "exclusive minimum", old.ExclusiveMinimum, new.ExclusiveMinimum, in)
+	c.lowerBound(path, "minimum length", intf(old.MinLength), intf(new.MinLength), in)
+	c.lowerBound(path, "minimum number of properties", intf(old.MinProperties), intf(new.MinProperties), in)
+	c.upperBound(path, "maximum", old.Maximum, new.Maximum, in)
+	c.upperBound(path, "exclusive maximum", old.ExclusiveMaximum, new.ExclusiveMaximum, in)
+	c.upperBound(path, "maximum length", intf(old.MaxLength), intf(new.MaxLength), in)
+	c.upperBound(path, "maximum number of properties", intf(old.MaxProperties), intf(new.MaxProperties), in)
+
+	c.rule(path, "format", old.Format, new.Format, in)
+	c.rule(path, "pattern", old.Pattern, new.Pattern, in)
+	var om, nm string
+	if old.MultipleOf != nil {
+		om = fmtFloat(*old.MultipleOf)
+	}
+	if new.MultipleOf != nil {
+		nm = fmtFloat(*new.MultipleOf)
+	}
+	c.rule(path, "multiple of", om, nm, in)
+
+	if !old.UniqueItems && new.UniqueItems {
+		tightened("unique items validation added")
+	} else if old.UniqueItems && !new.UniqueItems {
+		loosened("unique items validation removed")
+	}
+	for _, cond := range new.Conditions {
+		if !contains(old.Conditions, cond) {
+			tightened("validation %q added", cond)
+		}
+	}
+	for _, cond := range old.Conditions {
+		if !contains(new.Conditions, cond) {
+			loosened("validation %q removed", cond)
+		}
+	}
+}
+
+// lowerBound compares two versions of a minimum validation.
+func (c *comparer) lowerBound(path, name string, old, new *float64, in bool) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		c.add(in, path, fmt.Sprintf("%s %s added", name, fmtFloat(*new)))
+	case new == nil:
+		c.add(!in, path, fmt.Sprintf("%s removed", name))
+	case *new > *old:
+		c.add(in, path, fmt.Sprintf("%s raised from %s to %s", name, fmtFloat(*old), fmtFloat(*new)))
+	case *new < *old:
+		c.add(!in, path, fmt.Sprintf("%s lowered from %s to %s", name, fmtFloat(*old), fmtFloat(*new)))
+	}
+}
+
+// upperBound compares two versions of a maximum validation.
+func (c *comparer) upperBound(path, name string, old, new *float64, in bool) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		c.add(in, path, fmt.Sprintf("%s %s added", name, fmtFloat(*new)))
+	case new == nil:
+		c.add(!in, path, fmt.Sprintf("%s removed", name))
+	case *new < *old:
+		c.add(in, path, fmt.Sprintf("%s lowered from %s to %s", name, fmtFloat(*old), fmtFloat(*new)))
+	case *new > *old:
+		c.add(!in, path, fmt.Sprintf("%s raised from %s to %s", name, fmtFloat(*old), fmtFloat(*new)))
+	}
+}
+
+// rule compares two versions of a validation that is either set or not. Changing the value of
+// the validation is always breaking as the new value may reject values accepted by the old.
+func (c *comparer) rule(path, name, old, new string, in bool) {
+	switch {
+	case old == new:
+	case old == "":
+		c.add(in, path, fmt.Sprintf("%s %q added", name, new))
+	case new == "":
+		c.add(!in, path, fmt.Sprintf("%s %q removed", name, old))
+	default:
+		c.add(true, path, fmt.Sprintf("%s changed from %q to %q", name, old, new))
+	}
+}
+
+// add records a change to the action being compared.
+func (c *comparer) add(breaking bool, path, msg string) {
+	c.changes = append(c.changes, &Change{Breaking: breaking, Location: c.location, Path: path, Message: msg})
+}
+
+// resolve returns the data structure of the given attribute: the attribute itself or the
+// definition of its user type or media type. Media types are projected using the attribute view
+// so that only the rendered attributes are taken into account.
+func (s *Snapshot) resolve(att *Attribute) (*Attribute, error) {
+	if att.TypeName == "" {
+		return att, nil
+	}
+	t, ok := s.Types[att.TypeName]
+	if !ok || t.Attribute == nil {
+		return nil, fmt.Errorf("unknown type %s", att.TypeName)
+	}
+	res := *t.Attribute
+	res.Nullable = res.Nullable || att.Nullable
+	if t.Views == nil {
+		return &res, nil
+	}
+	view := att.View
+	if view == "" {
+		view = "default"
+	}
+	if res.Type == "array" {
+		// Collection: render elements using the view
+		elem := *res.Elem
+		if elem.View == "" {
+			elem.View = view
+		}
+		res.Elem = &elem
+		return &res, nil
+	}
+	attrs, ok := t.Views[view]
+	if !ok {
+		return nil, fmt.Errorf("view %q of %s removed", view, att.TypeName)
+	}
+	res.Attributes = make(map[string]*Attribute, len(attrs))
+	res.Required = nil
+	for n, v := range attrs {
+		if n == "links" && len(t.Links) > 0 {
+			links := &Attribute{Type: "object", Attributes: make(map[string]*Attribute, len(t.Links))}
+			for ln, lv := range t.Links {
+				if la, ok := t.Attribute.Attributes[ln]; ok {
+					l := *la
+					l.View = lv
+					if l.View == "" {
+						l.View = "link"
+					}
+					links.Attributes[ln] = &l
+				}
+			}
+			res.Attributes[n] = links
+			continue
+		}
+		child, ok := t.Attribute.Attributes[n]
+		if !ok {
+			continue
+		}
+		if v != "" {
+			cp := *child
+			cp.View = v
+			child = &cp
+		}
+		res.Attributes[n] = child
+		if contains(t.Attribute.Required, n) {
+			res.Required = append(res.Required, n)
+		}
+	}
+	return &res, nil
+}
+
+// normalizeRoute removes the names of the route wildcards.
+func normalizeRoute(route string) string {
+	return design.WildcardRegex.ReplaceAllStringFunc(route, func(w string) string { return w[:2] })
+}
+
+// orEmpty returns att or an empty object if att is nil.
+func orEmpty(att *Attribute) *Attribute {
+	if att == nil {
+		return &Attribute{Type: "object"}
+	}
+	return att
+}
+
+// names returns the sorted union of the keys of the given maps.
+func names(maps ...interface{}) []string {
+	set := make(map[string]string)
+	for _, m := range maps {
+		for _, k := range reflect.ValueOf(m).MapKeys() {
+			set[k.String()] = k.String()
+		}
+	}
+	return sortedKeys(set)
+}
+
+// sortedKeys returns the sorted keys of m.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// values returns the JSON representations of the given enum values.
+func values(vals []interface{}) []string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		b, err := json.Marshal(v)
+		if err != nil {
+			b = []byte(fmt.Sprintf("%v", v))
+		}
+		res[i] = string(b)
+	}
+	return res
+}
+
+// contains returns true if vals contains v.
+func contains(vals []string, v string) bool {
+	for _, val := range vals {
+		if val == v {
+			return true
+		}
+	}
+	return false
+}
+
+// intf converts an int pointer to a float pointer.
+func intf(i *int) *float64 {
+	if i == nil {
+		return nil
+	}
+	f := float64(*i)
+	return &f
+}
+
+// fmtFloat formats f using the minimal number of digits.
+func fmtFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
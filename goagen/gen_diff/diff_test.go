@@ -0,0 +1,354 @@
+package gendiff_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_designjson"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// snapshot runs the given DSL and returns the snapshot of the resulting design.
+func snapshot(dsl func()) *gendiff.Snapshot {
+	dslengine.Reset()
+	dsl()
+	Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	return gendiff.NewSnapshot(Design)
+}
+
+// bottleDesign returns a DSL describing a bottle API, each option alters the design.
+func bottleDesign(opts map[string]bool) func() {
+	return func() {
+		API("cellar", func() {})
+		payload := Type("BottlePayload", func() {
+			Attribute("name", String, func() {
+				if opts["tight_name"] {
+					MaxLength(10)
+				} else {
+					MaxLength(20)
+				}
+			})
+			Attribute("color", String, func() {
+				if opts["narrow_color"] {
+					Enum("red", "white")
+				} else {
+					Enum("red", "white", "rose")
+				}
+			})
+			if opts["vintage"] {
+				Attribute("vintage", Integer)
+			}
+			if opts["year_string"] {
+				Attribute("year", String)
+			} else {
+				Attribute("year", Integer)
+			}
+			if opts["required_vintage"] {
+				Required("vintage")
+			}
+			Required("name")
+		})
+		bottle := MediaType("application/vnd.bottle+json", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("name", String)
+				Attribute("color", String, func() {
+					if opts["narrow_color"] {
+						Enum("red", "white")
+					} else {
+						Enum("red", "white", "rose")
+					}
+				})
+				Required("id", "name")
+			})
+			View("default", func() {
+				Attribute("id")
+				if !opts["no_name_view"] {
+					Attribute("name")
+				}
+				Attribute("color")
+			})
+			if !opts["no_tiny_view"] {
+				View("tiny", func() {
+					Attribute("id")
+				})
+			}
+		})
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			if !opts["no_show"] {
+				Action("show", func() {
+					if opts["rename_id"] {
+						Routing(GET("/:bottleID"))
+						Params(func() {
+							Param("bottleID", Integer)
+						})
+					} else {
+						Routing(GET("/:id"))
+						Params(func() {
+							Param("id", Integer)
+						})
+					}
+					Response(OK, bottle)
+				})
+			}
+			Action("list", func() {
+				Routing(GET(""))
+				Params(func() {
+					Param("sort", String)
+					if opts["required_sort"] {
+						Required("sort")
+					}
+				})
+				Response(OK, func() {
+					Media(CollectionOf(bottle), "tiny")
+				})
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(payload)
+				Response(Created)
+			})
+			if opts["delete"] {
+				Action("delete", func() {
+					Routing(DELETE("/:id"))
+					Response(NoContent)
+				})
+			}
+		})
+	}
+}
+
+var _ = Describe("Compare", func() {
+	var old, new map[string]bool
+	var changes []*gendiff.Change
+
+	BeforeEach(func() {
+		old = map[string]bool{}
+		new = map[string]bool{}
+	})
+
+	JustBeforeEach(func() {
+		o := snapshot(bottleDesign(old))
+		n := snapshot(bottleDesign(new))
+		changes = gendiff.Compare(o, n)
+	})
+
+	describe := func(changes []*gendiff.Change) []string {
+		res := make([]string, len(changes))
+		for i, c := range changes {
+			res[i] = c.String()
+			if c.Breaking {
+				res[i] = "breaking: " + res[i]
+			}
+		}
+		return res
+	}
+
+	Context("with identical designs", func() {
+		It("returns no change", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+
+	Context("with renamed route wildcards", func() {
+		BeforeEach(func() {
+			new["rename_id"] = true
+		})
+
+		It("reports no change", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+
+	Context("with a removed action", func() {
+		BeforeEach(func() {
+			new["no_show"] = true
+		})
+
+		It("reports a breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{"breaking: bottle#show: action removed"}))
+		})
+	})
+
+	Context("with an added action", func() {
+		BeforeEach(func() {
+			new["delete"] = true
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{"bottle#delete: action added"}))
+		})
+	})
+
+	Context("with a newly required param", func() {
+		BeforeEach(func() {
+			new["required_sort"] = true
+		})
+
+		It("reports a breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{"breaking: bottle#list params.sort: now required"}))
+		})
+	})
+
+	Context("with a param no longer required", func() {
+		BeforeEach(func() {
+			old["required_sort"] = true
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{"bottle#list params.sort: no longer required"}))
+		})
+	})
+
+	Context("with a narrowed enum", func() {
+		BeforeEach(func() {
+			new["narrow_color"] = true
+		})
+
+		It("breaks requests but not responses", func() {
+			Ω(describe(changes)).Should(Equal([]string{
+				"breaking: bottle#create payload.color: enum value \"rose\" removed",
+				"bottle#show responses.200.body.color: enum value \"rose\" removed",
+			}))
+		})
+	})
+
+	Context("with a widened enum", func() {
+		BeforeEach(func() {
+			old["narrow_color"] = true
+		})
+
+		It("breaks responses but not requests", func() {
+			Ω(describe(changes)).Should(Equal([]string{
+				"bottle#create payload.color: enum value \"rose\" added",
+				"breaking: bottle#show responses.200.body.color: enum value \"rose\" added",
+			}))
+		})
+	})
+
+	Context("with a changed type", func() {
+		BeforeEach(func() {
+			new["year_string"] = true
+		})
+
+		It("reports a breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{
+				"breaking: bottle#create payload.year: type changed from integer to string",
+			}))
+		})
+	})
+
+	Context("with a tightened validation", func() {
+		BeforeEach(func() {
+			new["tight_name"] = true
+		})
+
+		It("reports a breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{
+				"breaking: bottle#create payload.name: maximum length lowered from 20 to 10",
+			}))
+		})
+	})
+
+	Context("with a loosened validation", func() {
+		BeforeEach(func() {
+			old["tight_name"] = true
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{
+				"bottle#create payload.name: maximum length raised from 10 to 20",
+			}))
+		})
+	})
+
+	Context("with new payload attributes", func() {
+		BeforeEach(func() {
+			new["vintage"] = true
+		})
+
+		It("reports optional attributes as non-breaking", func() {
+			Ω(describe(changes)).Should(Equal([]string{"bottle#create payload.vintage: attribute added"}))
+		})
+
+		Context("that are required", func() {
+			BeforeEach(func() {
+				new["required_vintage"] = true
+			})
+
+			It("reports a breaking change", func() {
+				Ω(describe(changes)).Should(Equal([]string{
+					"breaking: bottle#create payload.vintage: required attribute added",
+				}))
+			})
+		})
+	})
+
+	Context("with a removed view attribute", func() {
+		BeforeEach(func() {
+			new["no_name_view"] = true
+		})
+
+		It("reports a breaking change", func() {
+			Ω(describe(changes)).Should(Equal([]string{
+				"breaking: bottle#show responses.200.body.name: removed",
+			}))
+		})
+	})
+
+	Context("with a removed view", func() {
+		BeforeEach(func() {
+			new["no_tiny_view"] = true
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Breaking).Should(BeTrue())
+			Ω(changes[0].Location).Should(Equal("bottle#list"))
+		})
+	})
+})
+
+var _ = Describe("Load", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "gendiff")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("builds the snapshot of design JSON files", func() {
+		s := snapshot(bottleDesign(map[string]bool{}))
+		b, err := json.Marshal(gendesignjson.New(Design))
+		Ω(err).ShouldNot(HaveOccurred())
+		path := filepath.Join(dir, "design.json")
+		Ω(ioutil.WriteFile(path, b, 0644)).Should(Succeed())
+
+		loaded, err := gendiff.Load(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(gendiff.Compare(loaded, s)).Should(BeEmpty())
+
+		changed := snapshot(bottleDesign(map[string]bool{"narrow_color": true}))
+		Ω(gendiff.Breaking(gendiff.Compare(loaded, changed))).Should(HaveLen(1))
+	})
+
+	It("rejects files that are not design JSON documents", func() {
+		path := filepath.Join(dir, "snapshot.json")
+		Ω(ioutil.WriteFile(path, []byte(`{"version":1,"resources":{}}`), 0644)).Should(Succeed())
+		_, err := gendiff.Load(path)
+		Ω(err).Should(MatchError(ContainSubstring("is not a design JSON document")))
+	})
+})
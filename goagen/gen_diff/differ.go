@@ -0,0 +1,88 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_designjson"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+// Differ compares two versions of an API design. Each version is given either as the import path
+// of a design package or as the path to a design JSON file produced by "goagen design-json".
+type Differ struct {
+	// Old is the previous version of the design.
+	Old string
+	// New is the new version of the design.
+	New string
+	// Save is the path to the file where the design JSON of the new version is written if any.
+	Save string
+}
+
+// Diff loads both versions of the design and returns the changes between the two. Diff only
+// saves the design JSON of the new version and returns no change if Old is empty.
+func (d *Differ) Diff() ([]*Change, error) {
+	api, err := loadDesign(d.New)
+	if err != nil {
+		return nil, err
+	}
+	if d.Save != "" {
+		b, err := json.MarshalIndent(gendesignjson.New(api), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(d.Save, b, 0644); err != nil {
+			return nil, err
+		}
+	}
+	n := NewSnapshot(api)
+	if d.Old == "" {
+		return nil, nil
+	}
+	o, err := Load(d.Old)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(o, n), nil
+}
+
+// Load returns the snapshot of a design. source is either the path to a design JSON file produced
+// by "goagen design-json" or the import path of a design package. Design packages are compiled
+// and run using the meta generator.
+func Load(source string) (*Snapshot, error) {
+	api, err := loadDesign(source)
+	if err != nil {
+		return nil, err
+	}
+	return NewSnapshot(api), nil
+}
+
+// loadDesign makes the design described by source the current design and returns it. Design
+// packages are first serialized into a design JSON file by the design JSON generator.
+func loadDesign(source string) (*design.APIDefinition, error) {
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		return gendesignjson.Load(source)
+	}
+	tmpDir, err := ioutil.TempDir("", "goagen-diff")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	gen, err := meta.NewGenerator(
+		"gendesignjson.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_designjson")},
+		map[string]string{"design": source, "out": tmpDir},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gen.Generate(); err != nil {
+		return nil, err
+	}
+	return gendesignjson.Load(filepath.Join(tmpDir, gendesignjson.DesignFile))
+}
@@ -0,0 +1,10 @@
+/*
+Package gendiff compares two versions of an API design and classifies each difference as breaking
+or non-breaking for existing clients.
+The comparison is done on snapshots of the designs that capture the resource actions together
+with their routes, requests, responses and the types they use. Snapshots are built from the
+design JSON representation produced by the gendesignjson package: design packages are first
+serialized by the design JSON generator and design JSON files previously written with "goagen
+design-json" or "goagen diff --save" are loaded directly.
+*/
+package gendiff
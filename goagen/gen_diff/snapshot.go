@@ -0,0 +1,322 @@
+package gendiff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/goadesign/goa/design"
+)
+
+type (
+	// Snapshot captures the contract exposed by an API design: the resource actions with
+	// their routes, requests and responses together with the types they make use of. Snapshots
+	// are built from the API definitions and are not persisted, the design JSON produced by
+	// "goagen design-json" is the format used to save a version of the design.
+	Snapshot struct {
+		// API is the name of the API.
+		API string `json:"api"`
+		// Resources lists the API resources indexed by name.
+		Resources map[string]*Resource `json:"resources,omitempty"`
+		// Types lists the user types and media types used by the API indexed by type name.
+		Types map[string]*Type `json:"types,omitempty"`
+	}
+
+	// Resource describes a snapshot resource.
+	Resource struct {
+		// Actions lists the resource actions indexed by name.
+		Actions map[string]*Action `json:"actions,omitempty"`
+	}
+
+	// Action describes the requests accepted and the responses sent by a resource action.
+	Action struct {
+		// Routes lists the action routes formatted as "VERB /full/path".
+		Routes []string `json:"routes,omitempty"`
+		// Params describes the path and query string parameters.
+		Params *Attribute `json:"params,omitempty"`
+		// Headers describes the request headers including the resource headers.
+		Headers *Attribute `json:"headers,omitempty"`
+		// Cookies describes the request cookies including the resource cookies.
+		Cookies *Attribute `json:"cookies,omitempty"`
+		// Payload describes the request body if any.
+		Payload *Attribute `json:"payload,omitempty"`
+		// PayloadOptional is true if the request body may be omitted.
+		PayloadOptional bool `json:"payload_optional,omitempty"`
+		// InboundMessage describes the messages sent by websocket clients if any.
+		InboundMessage *Attribute `json:"inbound_message,omitempty"`
+		// OutboundMessage describes the messages sent to websocket clients if any.
+		OutboundMessage *Attribute `json:"outbound_message,omitempty"`
+		// Responses lists the action responses indexed by HTTP status code.
+		Responses map[string]*Response `json:"responses,omitempty"`
+		// Security describes the action security requirement if any.
+		Security *Security `json:"security,omitempty"`
+	}
+
+	// Response describes an action response.
+	Response struct {
+		// MediaType is the response media type identifier if any.
+		MediaType string `json:"media_type,omitempty"`
+		// Body describes the response body if any.
+		Body *Attribute `json:"body,omitempty"`
+		// Stream describes the events of Server-Sent Events responses.
+		Stream *Attribute `json:"stream,omitempty"`
+		// Headers describes the response headers.
+		Headers *Attribute `json:"headers,omitempty"`
+	}
+
+	// Security describes the security requirement of an action.
+	Security struct {
+		// Scheme is the name of the security scheme.
+		Scheme string `json:"scheme"`
+		// Scopes lists the required scopes.
+		Scopes []string `json:"scopes,omitempty"`
+	}
+
+	// Type describes a user type or a media type.
+	Type struct {
+		// Attribute describes the type data structure.
+		*Attribute
+		// Identifier is the media type identifier, empty for user types.
+		Identifier string `json:"identifier,omitempty"`
+		// Views lists the media type views indexed by name. Each view maps the names of
+		// the rendered attributes to the view used to render them if any.
+		Views map[string]map[string]string `json:"views,omitempty"`
+		// Links maps the names of the media type links to the view used to render them.
+		Links map[string]string `json:"links,omitempty"`
+	}
+
+	// Attribute describes a data structure together with its validations.
+	Attribute struct {
+		// Type is the name of the attribute type, e.g. "string", "array" or "object".
+		// Type is empty when the attribute is a user type or a media type.
+		Type string `json:"type,omitempty"`
+		// TypeName is the name of the user type or media type if any.
+		TypeName string `json:"type_name,omitempty"`
+		// View is the view used to render media type attributes if not "default".
+		View string `json:"view,omitempty"`
+		// Attributes lists the child attributes of objects indexed by name.
+		Attributes map[string]*Attribute `json:"attributes,omitempty"`
+		// Key describes the keys of hashes.
+		Key *Attribute `json:"key,omitempty"`
+		// Elem describes the elements of arrays and the values of hashes.
+		Elem *Attribute `json:"elem,omitempty"`
+		// Union lists the names of the union member types.
+		Union []string `json:"union,omitempty"`
+		// Discriminator is the name of the union discriminator attribute.
+		Discriminator string `json:"discriminator,omitempty"`
+		// Nullable is true if the attribute value may be null.
+		Nullable bool `json:"nullable,omitempty"`
+		// Required lists the names of the required child attributes.
+		Required []string `json:"required,omitempty"`
+		// Enum lists the accepted values.
+		Enum []interface{} `json:"enum,omitempty"`
+		// Format is the format validation.
+		Format string `json:"format,omitempty"`
+		// Pattern is the regular expression validation.
+		Pattern string `json:"pattern,omitempty"`
+		// Minimum is the minimum value validation.
+		Minimum *float64 `json:"minimum,omitempty"`
+		// Maximum is the maximum value validation.
+		Maximum *float64 `json:"maximum,omitempty"`
+		// ExclusiveMinimum is the exclusive minimum value validation.
+		ExclusiveMinimum *float64 `json:"exclusive_minimum,omitempty"`
+		// ExclusiveMaximum is the exclusive maximum value validation.
+		ExclusiveMaximum *float64 `json:"exclusive_maximum,omitempty"`
+		// MultipleOf is the multiple of validation.
+		MultipleOf *float64 `json:"multiple_of,omitempty"`
+		// MinLength is the minimum length validation.
+		MinLength *int `json:"min_length,omitempty"`
+		// MaxLength is the maximum length validation.
+		MaxLength *int `json:"max_length,omitempty"`
+		// MinProperties is the minimum number of properties validation.
+		MinProperties *int `json:"min_properties,omitempty"`
+		// MaxProperties is the maximum number of properties validation.
+		MaxProperties *int `json:"max_properties,omitempty"`
+		// UniqueItems is the unique items validation.
+		UniqueItems bool `json:"unique_items,omitempty"`
+		// Conditions lists the conditional validations (RequiredIf, DependentRequired and
+		// MutuallyExclusive) serialized as strings.
+		Conditions []string `json:"conditions,omitempty"`
+	}
+)
+
+// NewSnapshot builds the snapshot of the given finalized API definition.
+func NewSnapshot(api *design.APIDefinition) *Snapshot {
+	s := &Snapshot{
+		API:       api.Name,
+		Resources: make(map[string]*Resource),
+		Types:     make(map[string]*Type),
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		res := &Resource{Actions: make(map[string]*Action)}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			res.Actions[a.Name] = s.action(api, a)
+			return nil
+		})
+		s.Resources[r.Name] = res
+		return nil
+	})
+	return s
+}
+
+// action builds the snapshot of the given action.
+func (s *Snapshot) action(api *design.APIDefinition, a *design.ActionDefinition) *Action {
+	act := &Action{
+		Params:          s.attribute(a.AllParams()),
+		InboundMessage:  s.dataType(a.InboundMessage, ""),
+		OutboundMessage: s.dataType(a.OutboundMessage, ""),
+		Responses:       make(map[string]*Response),
+	}
+	for _, r := range a.Routes {
+		act.Routes = append(act.Routes, r.Verb+" "+r.FullPath())
+	}
+	for n := range a.PathParams().Type.ToObject() {
+		// Path parameters are always required
+		if act.Params != nil && !contains(act.Params.Required, n) {
+			act.Params.Required = append(act.Params.Required, n)
+		}
+	}
+	if act.Params != nil {
+		sort.Strings(act.Params.Required)
+	}
+	headers := &design.AttributeDefinition{Type: design.Object{}}
+	headers.Merge(a.Parent.Headers)
+	headers.Merge(a.Headers)
+	if len(headers.Type.ToObject()) > 0 {
+		act.Headers = s.attribute(headers)
+	}
+	act.Cookies = s.attribute(a.AllCookies())
+	if a.Payload != nil {
+		act.Payload = s.dataType(a.Payload, "")
+		act.PayloadOptional = a.PayloadOptional
+	}
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		resp := &Response{
+			MediaType: r.MediaType,
+			Stream:    s.dataType(r.Stream, ""),
+			Headers:   s.attribute(r.Headers),
+		}
+		if r.Type != nil {
+			resp.Body = s.dataType(r.Type, r.ViewName)
+		} else if mt := api.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+			resp.Body = s.dataType(mt, r.ViewName)
+		}
+		act.Responses[strconv.Itoa(r.Status)] = resp
+		return nil
+	})
+	if a.Security != nil && a.Security.Scheme != nil {
+		act.Security = &Security{Scheme: a.Security.Scheme.SchemeName, Scopes: a.Security.Scopes}
+	}
+	return act
+}
+
+// dataType builds the snapshot attribute of the given data type, nil if dt is nil.
+func (s *Snapshot) dataType(dt design.DataType, view string) *Attribute {
+	if dt == nil {
+		return nil
+	}
+	return s.attribute(&design.AttributeDefinition{Type: dt, View: view})
+}
+
+// attribute builds the snapshot of the given attribute, nil if att is nil. The user types and
+// media types used by the attribute are recorded in the snapshot types.
+func (s *Snapshot) attribute(att *design.AttributeDefinition) *Attribute {
+	if att == nil || att.Type == nil {
+		return nil
+	}
+	a := &Attribute{View: att.View, Nullable: att.IsNullable()}
+	switch t := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		a.TypeName = t.TypeName
+		s.mediaType(t)
+	case *design.UserTypeDefinition:
+		a.TypeName = t.TypeName
+		s.userType(t)
+	case design.Object:
+		a.Type = "object"
+		a.Attributes = make(map[string]*Attribute, len(t))
+		for n, child := range t {
+			a.Attributes[n] = s.attribute(child)
+		}
+	case *design.Array:
+		a.Type = "array"
+		a.Elem = s.attribute(t.ElemType)
+	case *design.Hash:
+		a.Type = "hash"
+		a.Key = s.attribute(t.KeyType)
+		a.Elem = s.attribute(t.ElemType)
+	case *design.Union:
+		a.Type = "union"
+		a.Discriminator = t.Discriminator
+		for _, u := range t.Types {
+			a.Union = append(a.Union, u.TypeName)
+			s.userType(u)
+		}
+	default:
+		a.Type = t.Name()
+	}
+	if v := att.Validation; v != nil {
+		a.Required = v.Required
+		a.Enum = v.Values
+		a.Format = v.Format
+		a.Pattern = v.Pattern
+		a.Minimum = v.Minimum
+		a.Maximum = v.Maximum
+		a.ExclusiveMinimum = v.ExclusiveMinimum
+		a.ExclusiveMaximum = v.ExclusiveMaximum
+		a.MultipleOf = v.MultipleOf
+		a.MinLength = v.MinLength
+		a.MaxLength = v.MaxLength
+		a.MinProperties = v.MinProperties
+		a.MaxProperties = v.MaxProperties
+		a.UniqueItems = v.UniqueItems
+		for _, r := range v.RequiredIf {
+			a.Conditions = append(a.Conditions, fmt.Sprintf("%v required if %s is %v", r.Required, r.Attribute, r.Value))
+		}
+		for n, req := range v.DependentRequired {
+			a.Conditions = append(a.Conditions, fmt.Sprintf("%v required if %s is set", req, n))
+		}
+		for _, names := range v.MutuallyExclusive {
+			a.Conditions = append(a.Conditions, fmt.Sprintf("%v mutually exclusive", names))
+		}
+		sort.Strings(a.Conditions)
+	}
+	return a
+}
+
+// userType records the given user type in the snapshot types.
+func (s *Snapshot) userType(u *design.UserTypeDefinition) {
+	if _, ok := s.Types[u.TypeName]; ok {
+		return
+	}
+	t := &Type{}
+	s.Types[u.TypeName] = t
+	t.Attribute = s.attribute(u.AttributeDefinition)
+}
+
+// mediaType records the given media type together with its views and links in the snapshot
+// types.
+func (s *Snapshot) mediaType(mt *design.MediaTypeDefinition) {
+	if _, ok := s.Types[mt.TypeName]; ok {
+		return
+	}
+	t := &Type{Identifier: mt.Identifier}
+	s.Types[mt.TypeName] = t
+	t.Attribute = s.attribute(mt.AttributeDefinition)
+	if len(mt.Views) > 0 {
+		t.Views = make(map[string]map[string]string, len(mt.Views))
+		for n, v := range mt.Views {
+			attrs := make(map[string]string)
+			for an, att := range v.Type.ToObject() {
+				attrs[an] = att.View
+			}
+			t.Views[n] = attrs
+		}
+	}
+	if len(mt.Links) > 0 {
+		t.Links = make(map[string]string, len(mt.Links))
+		for n, l := range mt.Links {
+			t.Links[n] = l.View
+		}
+	}
+}
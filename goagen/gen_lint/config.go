@@ -0,0 +1,60 @@
+package genlint
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config lists the rules enabled or disabled by a configuration file. The file uses YAML (or
+// JSON) and lists the rule names under the "rules" key, for example:
+//
+//	rules:
+//	  action-description: false
+//	  unused-type: true
+//
+// Rules not listed in the configuration are enabled.
+type Config struct {
+	// Rules indicates whether a rule is enabled indexed by rule name.
+	Rules map[string]bool `yaml:"rules" json:"rules"`
+}
+
+// LoadConfig reads the configuration file at the given path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid lint configuration %s: %s", path, err)
+	}
+	for n := range c.Rules {
+		if RuleByName(n) == nil {
+			return nil, fmt.Errorf("invalid lint configuration %s: unknown rule %#v", path, n)
+		}
+	}
+	return &c, nil
+}
+
+// Enabled returns true if the rule with the given name is enabled. All rules are enabled when
+// the configuration is nil.
+func (c *Config) Enabled(rule string) bool {
+	if c == nil {
+		return true
+	}
+	enabled, ok := c.Rules[rule]
+	return !ok || enabled
+}
+
+// Filter returns the issues reported by the enabled rules.
+func (c *Config) Filter(issues []*Issue) []*Issue {
+	var res []*Issue
+	for _, i := range issues {
+		if c.Enabled(i.Rule) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
@@ -0,0 +1,8 @@
+/*
+Package genlint checks API designs against style and correctness rules that go beyond the
+validations performed by the DSL engine: actions without descriptions, inconsistent path naming,
+unused types, examples that do not validate etc.
+Each rule may be disabled through a configuration file, see LoadConfig. The issues found by the
+enabled rules are reported by the "goagen lint" command either as text or as JSON.
+*/
+package genlint
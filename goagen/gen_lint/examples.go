@@ -0,0 +1,347 @@
+package genlint
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// checkExamples reports the examples that do not satisfy the validations of the attributes they
+// illustrate. Only the examples set explicitly with the DSL are checked, generated examples are
+// valid by construction.
+func checkExamples(api *design.APIDefinition) []*Issue {
+	c := &exampleChecker{}
+	c.attribute(api.Context(), "params", api.Params)
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		c.attribute(ut.Context(), "", ut.AttributeDefinition)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		c.attribute(mt.Context(), "", mt.AttributeDefinition)
+		return nil
+	})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		c.attribute(r.Context(), "params", r.Params)
+		c.attribute(r.Context(), "headers", r.Headers)
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			c.attribute(a.Context(), "params", a.Params)
+			c.attribute(a.Context(), "headers", a.Headers)
+			c.attribute(a.Context(), "cookies", a.Cookies)
+			if a.Payload != nil {
+				if _, ok := api.Types[a.Payload.TypeName]; !ok {
+					// Inline payload types are not part of the API types
+					c.attribute(a.Context(), "payload", a.Payload.AttributeDefinition)
+				}
+			}
+			return a.IterateResponses(func(resp *design.ResponseDefinition) error {
+				c.attribute(resp.Context(), "headers", resp.Headers)
+				return nil
+			})
+		})
+	})
+	return c.issues
+}
+
+// exampleChecker records the invalid examples found while traversing attributes.
+type exampleChecker struct {
+	issues []*Issue
+}
+
+// attribute checks the example of att and of its child attributes. path is the path to att
+// from the definition described by location. Attributes whose type is a user type or a media
+// type are not traversed as the types are checked separately.
+func (c *exampleChecker) attribute(location, path string, att *design.AttributeDefinition) {
+	if att == nil || att.Type == nil {
+		return
+	}
+	if att.Example != nil && att.Example != "-" {
+		what := "example"
+		if path != "" {
+			what = fmt.Sprintf("example of %#v", path)
+		}
+		for _, v := range validate(att, att.Example) {
+			c.issues = append(c.issues, &Issue{
+				Location: location,
+				Message:  fmt.Sprintf("%s does not validate: %s", what, v),
+			})
+		}
+	}
+	switch actual := att.Type.(type) {
+	case design.Object:
+		names := make([]string, 0, len(actual))
+		for n := range actual {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			c.attribute(location, join(path, n), actual[n])
+		}
+	case *design.Array:
+		c.attribute(location, path+"[]", actual.ElemType)
+	case *design.Hash:
+		c.attribute(location, path+"[key]", actual.KeyType)
+		c.attribute(location, path+"[value]", actual.ElemType)
+	}
+}
+
+// validate returns the descriptions of the validations of att that val does not satisfy.
+func validate(att *design.AttributeDefinition, val interface{}) []string {
+	if val == nil {
+		return nil
+	}
+	var errs []string
+	if v := att.Validation; v != nil {
+		if v.Values != nil && !oneOf(val, v.Values) {
+			errs = append(errs, fmt.Sprintf("%#v is not one of the accepted values %#v", val, v.Values))
+		}
+		if f, ok := toFloat(val); ok {
+			switch {
+			case v.Minimum != nil && f < *v.Minimum:
+				errs = append(errs, fmt.Sprintf("%v is lower than the minimum %v", val, *v.Minimum))
+			case v.ExclusiveMinimum != nil && f <= *v.ExclusiveMinimum:
+				errs = append(errs, fmt.Sprintf("%v is not greater than the exclusive minimum %v", val, *v.ExclusiveMinimum))
+			}
+			switch {
+			case v.Maximum != nil && f > *v.Maximum:
+				errs = append(errs, fmt.Sprintf("%v is greater than the maximum %v", val, *v.Maximum))
+			case v.ExclusiveMaximum != nil && f >= *v.ExclusiveMaximum:
+				errs = append(errs, fmt.Sprintf("%v is not lower than the exclusive maximum %v", val, *v.ExclusiveMaximum))
+			}
+			if v.MultipleOf != nil && !goa.ValidateMultipleOf(f, *v.MultipleOf) {
+				errs = append(errs, fmt.Sprintf("%v is not a multiple of %v", val, *v.MultipleOf))
+			}
+		}
+		if s, ok := val.(string); ok {
+			n := utf8.RuneCountInString(s)
+			if v.MinLength != nil && n < *v.MinLength {
+				errs = append(errs, fmt.Sprintf("%#v is shorter than the minimum length %d", s, *v.MinLength))
+			}
+			if v.MaxLength != nil && n > *v.MaxLength {
+				errs = append(errs, fmt.Sprintf("%#v is longer than the maximum length %d", s, *v.MaxLength))
+			}
+			if v.Pattern != "" && !goa.ValidatePattern(v.Pattern, s) {
+				errs = append(errs, fmt.Sprintf("%#v does not match the pattern %#v", s, v.Pattern))
+			}
+			if v.Format != "" {
+				if err := goa.ValidateFormat(goa.Format(v.Format), s); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+		} else if rv := reflect.ValueOf(val); rv.Kind() == reflect.Slice {
+			n := rv.Len()
+			if v.MinLength != nil && n < *v.MinLength {
+				errs = append(errs, fmt.Sprintf("%d items is fewer than the minimum length %d", n, *v.MinLength))
+			}
+			if v.MaxLength != nil && n > *v.MaxLength {
+				errs = append(errs, fmt.Sprintf("%d items is more than the maximum length %d", n, *v.MaxLength))
+			}
+			if v.UniqueItems && hasDuplicates(rv) {
+				errs = append(errs, "items are not unique")
+			}
+		} else if rv.Kind() == reflect.Map {
+			n := rv.Len()
+			if v.MinProperties != nil && n < *v.MinProperties {
+				errs = append(errs, fmt.Sprintf("%d properties is fewer than the minimum %d", n, *v.MinProperties))
+			}
+			if v.MaxProperties != nil && n > *v.MaxProperties {
+				errs = append(errs, fmt.Sprintf("%d properties is more than the maximum %d", n, *v.MaxProperties))
+			}
+			for _, r := range v.Required {
+				if _, ok := lookup(rv, r); !ok {
+					errs = append(errs, fmt.Sprintf("required attribute %#v is missing", r))
+				}
+			}
+			errs = append(errs, conditionals(v, rv)...)
+		}
+	}
+
+	switch actual := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		errs = append(errs, validate(actual.AttributeDefinition, val)...)
+	case *design.UserTypeDefinition:
+		errs = append(errs, validate(actual.AttributeDefinition, val)...)
+	case *design.Union:
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Map {
+			break
+		}
+		d, ok := lookup(rv, actual.Discriminator)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("required attribute %#v is missing", actual.Discriminator))
+			break
+		}
+		t := unionType(actual, d)
+		if t == nil {
+			errs = append(errs, fmt.Sprintf("%s: %#v is not one of the accepted values %#v",
+				actual.Discriminator, d, actual.DiscriminatorValues()))
+			break
+		}
+		errs = append(errs, validate(t.AttributeDefinition, val)...)
+	case design.Object:
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Map {
+			break
+		}
+		for _, k := range sortedKeys(rv) {
+			if child, ok := actual[fmt.Sprint(k.Interface())]; ok {
+				for _, e := range validate(child, rv.MapIndex(k).Interface()) {
+					errs = append(errs, fmt.Sprintf("%v: %s", k.Interface(), e))
+				}
+			}
+		}
+	case *design.Array:
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice {
+			break
+		}
+		for i := 0; i < rv.Len(); i++ {
+			for _, e := range validate(actual.ElemType, rv.Index(i).Interface()) {
+				errs = append(errs, fmt.Sprintf("[%d]: %s", i, e))
+			}
+		}
+	case *design.Hash:
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Map {
+			break
+		}
+		for _, k := range sortedKeys(rv) {
+			for _, e := range validate(actual.KeyType, k.Interface()) {
+				errs = append(errs, fmt.Sprintf("[%v]: %s", k.Interface(), e))
+			}
+			for _, e := range validate(actual.ElemType, rv.MapIndex(k).Interface()) {
+				errs = append(errs, fmt.Sprintf("[%v]: %s", k.Interface(), e))
+			}
+		}
+	}
+	return errs
+}
+
+// conditionals returns the descriptions of the conditional required validations of v that the
+// object rv does not satisfy.
+func conditionals(v *dslengine.ValidationDefinition, rv reflect.Value) []string {
+	var errs []string
+	for _, r := range v.RequiredIf {
+		if val, ok := lookup(rv, r.Attribute); !ok || !oneOf(val, []interface{}{r.Value}) {
+			continue
+		}
+		for _, n := range r.Required {
+			if _, ok := lookup(rv, n); !ok {
+				errs = append(errs, fmt.Sprintf("attribute %#v is required when %#v is %#v", n, r.Attribute, r.Value))
+			}
+		}
+	}
+	names := make([]string, 0, len(v.DependentRequired))
+	for n := range v.DependentRequired {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := lookup(rv, name); !ok {
+			continue
+		}
+		for _, n := range v.DependentRequired[name] {
+			if _, ok := lookup(rv, n); !ok {
+				errs = append(errs, fmt.Sprintf("attribute %#v is required when %#v is set", n, name))
+			}
+		}
+	}
+	for _, group := range v.MutuallyExclusive {
+		var set []string
+		for _, n := range group {
+			if _, ok := lookup(rv, n); ok {
+				set = append(set, n)
+			}
+		}
+		if len(set) > 1 {
+			errs = append(errs, fmt.Sprintf("attributes %#v are mutually exclusive", set))
+		}
+	}
+	return errs
+}
+
+// unionType returns the type of the union identified by the given discriminator value, nil if
+// there is none.
+func unionType(u *design.Union, discriminator interface{}) *design.UserTypeDefinition {
+	for _, t := range u.Types {
+		if u.DiscriminatorValue(t) == fmt.Sprint(discriminator) {
+			return t
+		}
+	}
+	return nil
+}
+
+// oneOf returns true if val is equal to one of vals. Numbers are compared by value regardless of
+// their Go types.
+func oneOf(val interface{}, vals []interface{}) bool {
+	f, isNum := toFloat(val)
+	for _, v := range vals {
+		if isNum {
+			if g, ok := toFloat(v); ok && f == g {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(val, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// toFloat converts numbers to float64.
+func toFloat(val interface{}) (float64, bool) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// hasDuplicates returns true if the given slice contains the same value twice.
+func hasDuplicates(rv reflect.Value) bool {
+	for i := 0; i < rv.Len(); i++ {
+		for j := i + 1; j < rv.Len(); j++ {
+			if reflect.DeepEqual(rv.Index(i).Interface(), rv.Index(j).Interface()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookup returns the value of the map entry whose key string representation is key.
+func lookup(rv reflect.Value, key string) (interface{}, bool) {
+	for _, k := range rv.MapKeys() {
+		if fmt.Sprint(k.Interface()) == key {
+			return rv.MapIndex(k).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// sortedKeys returns the keys of the given map sorted by their string representation.
+func sortedKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
+
+// join appends name to the attribute path.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
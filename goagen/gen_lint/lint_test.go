@@ -0,0 +1,363 @@
+package genlint_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_lint"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+	var rule string
+	var issues []*genlint.Issue
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("cellar", func() {})
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		issues = genlint.RuleByName(rule).Check(Design)
+	})
+
+	messages := func() []string {
+		res := make([]string, len(issues))
+		for i, issue := range issues {
+			res[i] = issue.Location + ": " + issue.Message
+		}
+		return res
+	}
+
+	Context("action-description", func() {
+		BeforeEach(func() {
+			rule = "action-description"
+			Resource("bottle", func() {
+				Action("show", func() {
+					Description("Show a bottle")
+					Routing(GET("/bottles/:id"))
+					Response(OK)
+				})
+				Action("list", func() {
+					Routing(GET("/bottles"))
+					Response(OK)
+				})
+			})
+		})
+
+		It("reports actions without description", func() {
+			Ω(messages()).Should(Equal([]string{`resource "bottle" action "list": action has no description`}))
+		})
+	})
+
+	Context("path-naming", func() {
+		BeforeEach(func() {
+			rule = "path-naming"
+			Resource("bottle", func() {
+				Action("list", func() {
+					Routing(GET("/wine-bottles"), GET("/wine_bottles"))
+					Response(OK)
+				})
+				Action("show", func() {
+					Routing(GET("/wine-bottles/:bottle_id/tasting-notes"))
+					Response(OK)
+				})
+			})
+		})
+
+		It("reports segments that do not follow the most common convention", func() {
+			Ω(messages()).Should(Equal([]string{
+				`route GET "/wine_bottles" of resource "bottle" action "list": path segment "wine_bottles" uses snake_case but most path segments use kebab-case`,
+			}))
+		})
+	})
+
+	Context("default-view", func() {
+		BeforeEach(func() {
+			rule = "default-view"
+			MediaType("application/vnd.bottle", func() {
+				Attributes(func() {
+					Attribute("id", Integer)
+				})
+				View("default", func() {
+					Attribute("id")
+				})
+			})
+		})
+
+		It("does not report media types with a default view", func() {
+			Ω(issues).Should(BeEmpty())
+		})
+
+		Context("with a media type without default view", func() {
+			JustBeforeEach(func() {
+				mt := Design.MediaTypeWithIdentifier("application/vnd.bottle")
+				delete(mt.Views, "default")
+				issues = genlint.RuleByName(rule).Check(Design)
+			})
+
+			It("reports it", func() {
+				Ω(issues).Should(HaveLen(1))
+				Ω(issues[0].Message).Should(Equal("media type does not define a default view"))
+			})
+		})
+	})
+
+	Context("unused-type", func() {
+		BeforeEach(func() {
+			rule = "unused-type"
+			origin := Type("Origin", func() {
+				Attribute("country", String)
+			})
+			payload := Type("BottlePayload", func() {
+				Attribute("name", String)
+				Attribute("origin", origin)
+			})
+			Type("Unused", func() {
+				Attribute("name", String)
+			})
+			Resource("bottle", func() {
+				Action("create", func() {
+					Routing(POST("/bottles"))
+					Payload(payload)
+					Response(Created)
+				})
+			})
+		})
+
+		It("reports types that are not used", func() {
+			Ω(messages()).Should(Equal([]string{`type "Unused": type is not used`}))
+		})
+	})
+
+	Context("secured-error-responses", func() {
+		BeforeEach(func() {
+			rule = "secured-error-responses"
+			jwt := JWTSecurity("jwt", func() {
+				Header("Authorization")
+				Scope("api:write")
+			})
+			Resource("bottle", func() {
+				Security(jwt)
+				Action("show", func() {
+					Routing(GET("/bottles/:id"))
+					Response(OK)
+					Response(Unauthorized)
+				})
+				Action("list", func() {
+					Routing(GET("/bottles"))
+					Response(OK)
+				})
+				Action("create", func() {
+					Security(jwt, func() {
+						Scope("api:write")
+					})
+					Routing(POST("/bottles"))
+					Response(Created)
+					Response(Unauthorized)
+				})
+				Action("health", func() {
+					NoSecurity()
+					Routing(GET("/health"))
+					Response(OK)
+				})
+			})
+		})
+
+		It("reports secured actions missing error responses", func() {
+			Ω(messages()).Should(Equal([]string{
+				`resource "bottle" action "create": secured action requires scopes but does not define a Forbidden (403) response`,
+				`resource "bottle" action "list": secured action does not define an Unauthorized (401) response`,
+			}))
+		})
+	})
+
+	Context("example-validation", func() {
+		BeforeEach(func() {
+			rule = "example-validation"
+			Type("Bottle", func() {
+				Attribute("name", String, func() {
+					MaxLength(5)
+					Example("Cabernet")
+				})
+				Attribute("vintage", Integer, func() {
+					Minimum(1900)
+					Example(2015)
+				})
+				Attribute("color", String, func() {
+					Enum("red", "white")
+					Example("blue")
+				})
+				Attribute("email", String, func() {
+					Format("email")
+					Example("not an email")
+				})
+			})
+			Resource("bottle", func() {
+				Action("list", func() {
+					Routing(GET("/bottles"))
+					Params(func() {
+						Param("limit", Integer, func() {
+							Maximum(100)
+							Example(500)
+						})
+					})
+					Response(OK)
+				})
+			})
+		})
+
+		It("reports examples that do not validate", func() {
+			Ω(messages()).Should(ConsistOf(
+				`resource "bottle" action "list": example of "params.limit" does not validate: 500 is greater than the maximum 100`,
+				`type "Bottle": example of "color" does not validate: "blue" is not one of the accepted values []interface {}{"red", "white"}`,
+				`type "Bottle": example of "email" does not validate: invalid email value, mail: no angle-addr`,
+				`type "Bottle": example of "name" does not validate: "Cabernet" is longer than the maximum length 5`,
+			))
+		})
+	})
+
+	Context("example-validation with conditional validations and unions", func() {
+		BeforeEach(func() {
+			rule = "example-validation"
+			Cat := Type("Cat", func() {
+				Attribute("kind", String, func() {
+					Enum("cat")
+				})
+				Attribute("lives", Integer, func() {
+					Maximum(9)
+				})
+				Required("kind")
+			})
+			Dog := Type("Dog", func() {
+				Attribute("kind", String, func() {
+					Enum("dog")
+				})
+				Attribute("breed", String)
+				Required("kind", "breed")
+			})
+			Type("Pet", func() {
+				OneOf("kind", Cat, Dog)
+			})
+			Type("Order", func() {
+				Attribute("payment", func() {
+					Attribute("payment_type", String)
+					Attribute("card_number", String)
+					Attribute("credit_card", String)
+					Attribute("billing_address", String)
+					Attribute("email", String)
+					Attribute("phone", String)
+					RequiredIf("payment_type", "card", "card_number")
+					DependentRequired("credit_card", "billing_address")
+					MutuallyExclusive("email", "phone")
+					Example(map[string]interface{}{
+						"payment_type": "card",
+						"credit_card":  "4111111111111111",
+						"email":        "me@example.com",
+						"phone":        "555-0100",
+					})
+				})
+				Attribute("pets", ArrayOf("Pet"), func() {
+					Example([]interface{}{
+						map[string]interface{}{"kind": "cat", "lives": 10},
+						map[string]interface{}{"kind": "dog"},
+						map[string]interface{}{"kind": "fish"},
+						map[string]interface{}{"lives": 1},
+						map[string]interface{}{"kind": "dog", "breed": "beagle"},
+					})
+				})
+			})
+		})
+
+		It("reports examples that do not validate", func() {
+			Ω(messages()).Should(ConsistOf(
+				`type "Order": example of "payment" does not validate: attribute "card_number" is required when "payment_type" is "card"`,
+				`type "Order": example of "payment" does not validate: attribute "billing_address" is required when "credit_card" is set`,
+				`type "Order": example of "payment" does not validate: attributes []string{"email", "phone"} are mutually exclusive`,
+				`type "Order": example of "pets" does not validate: [0]: lives: 10 is greater than the maximum 9`,
+				`type "Order": example of "pets" does not validate: [1]: required attribute "breed" is missing`,
+				`type "Order": example of "pets" does not validate: [2]: kind: "fish" is not one of the accepted values []string{"cat", "dog"}`,
+				`type "Order": example of "pets" does not validate: [3]: required attribute "kind" is missing`,
+			))
+		})
+	})
+
+	Context("operation-id", func() {
+		BeforeEach(func() {
+			rule = "operation-id"
+			Resource("bottle", func() {
+				Action("list_all", func() {
+					Routing(GET("/bottles"))
+					Response(OK)
+				})
+				Action("listAll", func() {
+					Routing(GET("/all-bottles"))
+					Response(OK)
+				})
+			})
+		})
+
+		It("reports conflicting operation IDs", func() {
+			Ω(messages()).Should(Equal([]string{
+				`resource "bottle" action "list_all": operation ID "bottle#list_all" conflicts with "bottle#listAll"`,
+			}))
+		})
+	})
+})
+
+var _ = Describe("Config", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "genlint")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(content string) string {
+		path := filepath.Join(dir, "lint.yaml")
+		Ω(ioutil.WriteFile(path, []byte(content), 0644)).Should(Succeed())
+		return path
+	}
+
+	It("disables rules", func() {
+		config, err := genlint.LoadConfig(write("rules:\n  action-description: false\n  unused-type: true\n"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(config.Enabled("action-description")).Should(BeFalse())
+		Ω(config.Enabled("unused-type")).Should(BeTrue())
+		Ω(config.Enabled("path-naming")).Should(BeTrue())
+
+		dslengine.Reset()
+		API("cellar", func() {})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/bottles/:id"))
+				Response(OK)
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		Ω(genlint.Lint(Design, nil)).Should(HaveLen(1))
+		Ω(genlint.Lint(Design, config)).Should(BeEmpty())
+	})
+
+	It("accepts JSON", func() {
+		config, err := genlint.LoadConfig(write(`{"rules": {"operation-id": false}}`))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(config.Enabled("operation-id")).Should(BeFalse())
+	})
+
+	It("rejects unknown rules", func() {
+		_, err := genlint.LoadConfig(write("rules:\n  unknown: false\n"))
+		Ω(err).Should(MatchError(ContainSubstring(`unknown rule "unknown"`)))
+	})
+})
@@ -0,0 +1,57 @@
+package genlint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+// Linter runs the lint rules against a design package.
+type Linter struct {
+	// Design is the import path of the design package.
+	Design string
+	// Config is the path to the configuration file that enables or disables rules if any.
+	Config string
+}
+
+// Lint compiles and runs the design package with the meta generator and returns the issues
+// reported by the rules enabled in the configuration.
+func (l *Linter) Lint() ([]*Issue, error) {
+	var config *Config
+	if l.Config != "" {
+		var err error
+		if config, err = LoadConfig(l.Config); err != nil {
+			return nil, err
+		}
+	}
+	tmpDir, err := ioutil.TempDir("", "goagen-lint")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	gen, err := meta.NewGenerator(
+		"genlint.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_lint")},
+		map[string]string{"design": l.Design, "out": tmpDir},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gen.Generate(); err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(filepath.Join(tmpDir, IssuesFile))
+	if err != nil {
+		return nil, err
+	}
+	var issues []*Issue
+	if err := json.Unmarshal(b, &issues); err != nil {
+		return nil, err
+	}
+	return config.Filter(issues), nil
+}
@@ -0,0 +1,370 @@
+package genlint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+type (
+	// Issue describes a problem found by a lint rule.
+	Issue struct {
+		// Rule is the name of the rule that found the issue.
+		Rule string `json:"rule"`
+		// Location describes the definition the issue applies to, e.g.
+		// `resource "bottle" action "show"`.
+		Location string `json:"location"`
+		// Message describes the issue.
+		Message string `json:"message"`
+	}
+
+	// Rule is a lint rule.
+	Rule struct {
+		// Name is the name used to refer to the rule in configuration files.
+		Name string
+		// Description describes what the rule checks.
+		Description string
+		// Check runs the rule against the given finalized API definition.
+		Check func(api *design.APIDefinition) []*Issue
+	}
+)
+
+// Rules lists the lint rules in the order they run.
+var Rules = []*Rule{
+	{
+		Name:        "action-description",
+		Description: "actions must have a description",
+		Check:       checkActionDescriptions,
+	},
+	{
+		Name:        "path-naming",
+		Description: "path segments must all use the same naming convention",
+		Check:       checkPathNaming,
+	},
+	{
+		Name:        "default-view",
+		Description: "media types must define a default view",
+		Check:       checkDefaultViews,
+	},
+	{
+		Name:        "unused-type",
+		Description: "user types must be used by the API",
+		Check:       checkUnusedTypes,
+	},
+	{
+		Name:        "secured-error-responses",
+		Description: "secured actions must define Unauthorized and, if they require scopes, Forbidden responses",
+		Check:       checkSecuredResponses,
+	},
+	{
+		Name:        "example-validation",
+		Description: "examples must satisfy the validations of their attributes",
+		Check:       checkExamples,
+	},
+	{
+		Name:        "operation-id",
+		Description: "operation IDs must be unique regardless of case and separators",
+		Check:       checkOperationIDs,
+	},
+}
+
+// namingStyles lists the path naming conventions detected by the path-naming rule, the first
+// style wins when two styles are used equally.
+var namingStyles = []string{"kebab-case", "snake_case", "camelCase"}
+
+// RuleByName returns the rule with the given name, nil if there is none.
+func RuleByName(name string) *Rule {
+	for _, r := range Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Lint runs the rules enabled by the given configuration against the finalized API definition
+// and returns the issues found. All the rules run if config is nil.
+func Lint(api *design.APIDefinition, config *Config) []*Issue {
+	var issues []*Issue
+	for _, r := range Rules {
+		if !config.Enabled(r.Name) {
+			continue
+		}
+		for _, i := range r.Check(api) {
+			i.Rule = r.Name
+			issues = append(issues, i)
+		}
+	}
+	return issues
+}
+
+// String returns a human friendly description of the issue.
+func (i *Issue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Location, i.Message, i.Rule)
+}
+
+// checkActionDescriptions reports the actions that do not have a description.
+func checkActionDescriptions(api *design.APIDefinition) []*Issue {
+	var issues []*Issue
+	iterateActions(api, func(a *design.ActionDefinition) {
+		if strings.TrimSpace(a.Description) == "" {
+			issues = append(issues, &Issue{Location: a.Context(), Message: "action has no description"})
+		}
+	})
+	return issues
+}
+
+// checkPathNaming reports the route path segments that do not follow the naming convention used
+// by most segments.
+func checkPathNaming(api *design.APIDefinition) []*Issue {
+	type segment struct {
+		route       *design.RouteDefinition
+		name, style string
+	}
+	var segments []*segment
+	counts := make(map[string]int)
+	iterateActions(api, func(a *design.ActionDefinition) {
+		for _, r := range a.Routes {
+			for _, s := range strings.Split(r.FullPath(), "/") {
+				if s == "" || s[0] == ':' || s[0] == '*' {
+					continue
+				}
+				if style := namingStyle(s); style != "" {
+					segments = append(segments, &segment{route: r, name: s, style: style})
+					counts[style]++
+				}
+			}
+		}
+	})
+	var convention string
+	for _, s := range namingStyles {
+		if counts[s] > counts[convention] {
+			convention = s
+		}
+	}
+	var issues []*Issue
+	for _, s := range segments {
+		if s.style != convention {
+			issues = append(issues, &Issue{
+				Location: s.route.Context(),
+				Message:  fmt.Sprintf("path segment %#v uses %s but most path segments use %s", s.name, s.style, convention),
+			})
+		}
+	}
+	return issues
+}
+
+// namingStyle returns the naming convention used by the given path segment, empty if the segment
+// could be using any convention (e.g. "bottles").
+func namingStyle(segment string) string {
+	switch {
+	case strings.Contains(segment, "-"):
+		return "kebab-case"
+	case strings.Contains(segment, "_"):
+		return "snake_case"
+	case strings.ToLower(segment) != segment:
+		return "camelCase"
+	}
+	return ""
+}
+
+// checkDefaultViews reports the media types that do not define a default view. The DSL engine
+// rejects such media types when they are defined with the DSL, the rule covers API definitions
+// built by other means.
+func checkDefaultViews(api *design.APIDefinition) []*Issue {
+	var issues []*Issue
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if _, ok := mt.Views["default"]; !ok {
+			issues = append(issues, &Issue{Location: mt.Context(), Message: "media type does not define a default view"})
+		}
+		return nil
+	})
+	return issues
+}
+
+// checkUnusedTypes reports the user types that are not used by any action, webhook, error or media
+// type, directly or through other types.
+func checkUnusedTypes(api *design.APIDefinition) []*Issue {
+	used := make(map[string]bool)
+	markUsed := func(dt design.DataType) {
+		if ut, ok := dt.(*design.UserTypeDefinition); ok {
+			used[ut.TypeName] = true
+		}
+	}
+	mark := func(att *design.AttributeDefinition) {
+		if att == nil || att.Type == nil {
+			return
+		}
+		att.Walk(func(a *design.AttributeDefinition) error {
+			markUsed(a.Type)
+			markUsed(a.Reference)
+			for _, b := range a.Bases {
+				markUsed(b)
+			}
+			return nil
+		})
+	}
+	markType := func(dt design.DataType) {
+		if dt != nil {
+			mark(&design.AttributeDefinition{Type: dt})
+		}
+	}
+	markResponse := func(r *design.ResponseDefinition) {
+		markType(r.Type)
+		markType(r.Stream)
+		mark(r.Headers)
+		mark(r.Cookies)
+	}
+	markErrors := func(errs map[string]*design.ErrorDefinition) {
+		for _, e := range errs {
+			if e.Meta != nil {
+				markType(e.Meta)
+			}
+		}
+	}
+	markWebhooks := func(webhooks map[string]*design.WebhookDefinition) {
+		for _, w := range webhooks {
+			if w.Payload != nil {
+				markType(w.Payload)
+			}
+			for _, r := range w.Responses {
+				markResponse(r)
+			}
+		}
+	}
+
+	mark(api.Params)
+	for _, r := range api.Responses {
+		markResponse(r)
+	}
+	markErrors(api.Errors)
+	markWebhooks(api.Webhooks)
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		mark(r.Params)
+		mark(r.Headers)
+		mark(r.Cookies)
+		for _, resp := range r.Responses {
+			markResponse(resp)
+		}
+		markErrors(r.Errors)
+		markWebhooks(r.Webhooks)
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			mark(a.Params)
+			mark(a.Headers)
+			mark(a.Cookies)
+			if a.Payload != nil {
+				markType(a.Payload)
+			}
+			markType(a.InboundMessage)
+			markType(a.OutboundMessage)
+			for _, resp := range a.Responses {
+				markResponse(resp)
+			}
+			markErrors(a.Errors)
+			return nil
+		})
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		mark(mt.AttributeDefinition)
+		return nil
+	})
+
+	var issues []*Issue
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		if !used[ut.TypeName] {
+			issues = append(issues, &Issue{Location: ut.Context(), Message: "type is not used"})
+		}
+		return nil
+	})
+	return issues
+}
+
+// checkSecuredResponses reports the secured actions that do not define a 401 Unauthorized
+// response or that require scopes and do not define a 403 Forbidden response.
+func checkSecuredResponses(api *design.APIDefinition) []*Issue {
+	var issues []*Issue
+	iterateActions(api, func(a *design.ActionDefinition) {
+		if a.Security == nil {
+			return
+		}
+		if !hasStatus(a, 401) {
+			issues = append(issues, &Issue{
+				Location: a.Context(),
+				Message:  "secured action does not define an Unauthorized (401) response",
+			})
+		}
+		if len(a.Security.Scopes) > 0 && !hasStatus(a, 403) {
+			issues = append(issues, &Issue{
+				Location: a.Context(),
+				Message:  "secured action requires scopes but does not define a Forbidden (403) response",
+			})
+		}
+	})
+	return issues
+}
+
+// hasStatus returns true if the action defines a response with the given status.
+func hasStatus(a *design.ActionDefinition, status int) bool {
+	for _, r := range a.Responses {
+		if r.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOperationIDs reports the operation IDs that conflict with other operation IDs. The
+// operation IDs are computed the same way as in the Swagger specification: "resource#action"
+// and "resource#action#index" for the additional routes of an action. Two IDs conflict if they
+// only differ by case or by separators as they produce the same names in generated clients.
+func checkOperationIDs(api *design.APIDefinition) []*Issue {
+	var issues []*Issue
+	ids := make(map[string]string)
+	add := func(id, location string) {
+		key := strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '#':
+				return r
+			case r >= 'A' && r <= 'Z':
+				return r - 'A' + 'a'
+			}
+			return -1
+		}, id)
+		if other, ok := ids[key]; ok {
+			issues = append(issues, &Issue{
+				Location: location,
+				Message:  fmt.Sprintf("operation ID %#v conflicts with %#v", id, other),
+			})
+			return
+		}
+		ids[key] = id
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			for i := range a.Routes {
+				id := fmt.Sprintf("%s#%s", r.Name, a.Name)
+				if i > 0 {
+					id = fmt.Sprintf("%s#%d", id, i)
+				}
+				add(id, a.Context())
+			}
+			return nil
+		})
+		return r.IterateFileServers(func(fs *design.FileServerDefinition) error {
+			add(fmt.Sprintf("%s#%s", r.Name, fs.RequestPath), fs.Context())
+			return nil
+		})
+	})
+	return issues
+}
+
+// iterateActions calls it on each API action sorted by resource and action name.
+func iterateActions(api *design.APIDefinition, it func(*design.ActionDefinition)) {
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			it(a)
+			return nil
+		})
+	})
+}
@@ -442,11 +442,18 @@ func (c *{{ $ctrlName }}) {{ goify .Name true }}WSHandler(ctx *{{ targetPkg }}.{
 		// {{ $actionDescr }}: start_implement
 
 		{{ actionBody $actionDescr }}
-{{ if printResp $actionDescr }}
+{{ if printResp $actionDescr }}{{ if .InboundMessage }}
+		// Dummy websocket server reading messages until the connection is closed
+		for {
+			if _, err := ctx.ReceiveMessage(ws); err != nil {
+				return
+			}
+		}
+{{ else }}
 		ws.Write([]byte("{{ .Name }} {{ .Parent.Name }}"))
 		// Dummy echo websocket server
 		io.Copy(ws, ws)
-{{ end }}		// {{ $actionDescr }}: end_implement
+{{ end }}{{ end }}		// {{ $actionDescr }}: end_implement
 	}
 }`
 
@@ -0,0 +1,7 @@
+/*
+Package genopenapi provides a generator for the OpenAPI 3.0 specification of the API.
+The specification is written in both JSON and YAML formats in the "openapi" directory and can be
+consumed by API gateways, documentation portals and client generators that support OpenAPI 3.
+See https://spec.openapis.org/oas/v3.0.3 for more information.
+*/
+package genopenapi
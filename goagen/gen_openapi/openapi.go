@@ -0,0 +1,1185 @@
+package genopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+type (
+	// OpenAPI represents an instance of an OpenAPI 3.0 document.
+	// See https://spec.openapis.org/oas/v3.0.3
+	OpenAPI struct {
+		OpenAPI      string               `json:"openapi"`
+		Info         *Info                `json:"info"`
+		Servers      []*Server            `json:"servers,omitempty"`
+		Paths        map[string]*PathItem `json:"paths"`
+		Components   *Components          `json:"components,omitempty"`
+		Tags         []*Tag               `json:"tags,omitempty"`
+		ExternalDocs *ExternalDocs        `json:"externalDocs,omitempty"`
+		// Webhooks describes the webhooks sent by the API, OpenAPI 3.0 has no equivalent so
+		// they are rendered as the "x-webhooks" extension using the OpenAPI 3.1 layout.
+		Webhooks map[string]*PathItem `json:"x-webhooks,omitempty"`
+	}
+
+	// Info provides metadata about the API.
+	Info struct {
+		Title          string                    `json:"title"`
+		Description    string                    `json:"description,omitempty"`
+		TermsOfService string                    `json:"termsOfService,omitempty"`
+		Contact        *design.ContactDefinition `json:"contact,omitempty"`
+		License        *design.LicenseDefinition `json:"license,omitempty"`
+		Version        string                    `json:"version"`
+		Extensions     map[string]interface{}    `json:"-"`
+	}
+
+	// Server describes a server hosting the API.
+	Server struct {
+		// URL of the server, it may be relative to the location of the document.
+		URL string `json:"url"`
+		// Description of the server.
+		Description string `json:"description,omitempty"`
+	}
+
+	// PathItem describes the operations available on a single path.
+	PathItem struct {
+		// Get defines a GET operation on this path.
+		Get *Operation `json:"get,omitempty"`
+		// Put defines a PUT operation on this path.
+		Put *Operation `json:"put,omitempty"`
+		// Post defines a POST operation on this path.
+		Post *Operation `json:"post,omitempty"`
+		// Delete defines a DELETE operation on this path.
+		Delete *Operation `json:"delete,omitempty"`
+		// Options defines a OPTIONS operation on this path.
+		Options *Operation `json:"options,omitempty"`
+		// Head defines a HEAD operation on this path.
+		Head *Operation `json:"head,omitempty"`
+		// Patch defines a PATCH operation on this path.
+		Patch *Operation `json:"patch,omitempty"`
+		// Extensions defines the OpenAPI extensions.
+		Extensions map[string]interface{} `json:"-"`
+	}
+
+	// Operation describes a single API operation on a path.
+	Operation struct {
+		// Tags is a list of tags for API documentation control.
+		Tags []string `json:"tags,omitempty"`
+		// Summary is a short summary of what the operation does.
+		Summary string `json:"summary,omitempty"`
+		// Description is a verbose explanation of the operation behavior.
+		Description string `json:"description,omitempty"`
+		// ExternalDocs points to additional external documentation for this operation.
+		ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+		// OperationID is a unique string used to identify the operation.
+		OperationID string `json:"operationId,omitempty"`
+		// Parameters is a list of parameters that are applicable for this operation.
+		Parameters []*Parameter `json:"parameters,omitempty"`
+		// RequestBody describes the request body if any.
+		RequestBody *RequestBody `json:"requestBody,omitempty"`
+		// Responses is the list of possible responses indexed by HTTP status code.
+		Responses map[string]*Response `json:"responses"`
+		// Deprecated declares this operation to be deprecated.
+		Deprecated bool `json:"deprecated,omitempty"`
+		// Security is a declaration of which security schemes are applied for this operation.
+		Security []map[string][]string `json:"security,omitempty"`
+		// Servers overrides the API servers for this operation.
+		Servers []*Server `json:"servers,omitempty"`
+		// Extensions defines the OpenAPI extensions.
+		Extensions map[string]interface{} `json:"-"`
+	}
+
+	// Parameter describes a single operation parameter.
+	Parameter struct {
+		// Name of the parameter. Parameter names are case sensitive.
+		Name string `json:"name"`
+		// In is the location of the parameter.
+		// Possible values are "query", "header", "path" or "cookie".
+		In string `json:"in"`
+		// Description is a brief description of the parameter.
+		Description string `json:"description,omitempty"`
+		// Required determines whether this parameter is mandatory.
+		Required bool `json:"required,omitempty"`
+		// Deprecated declares this parameter to be deprecated.
+		Deprecated bool `json:"deprecated,omitempty"`
+		// Style describes how the parameter value is serialized.
+		Style string `json:"style,omitempty"`
+		// Explode determines whether array and object values generate separate parameters.
+		Explode *bool `json:"explode,omitempty"`
+		// Schema defines the type used for the parameter.
+		Schema *Schema `json:"schema"`
+		// Extensions defines the OpenAPI extensions.
+		Extensions map[string]interface{} `json:"-"`
+	}
+
+	// RequestBody describes a request body.
+	RequestBody struct {
+		// Description of the request body.
+		Description string `json:"description,omitempty"`
+		// Content describes the request body indexed by content type.
+		Content map[string]*MediaType `json:"content"`
+		// Required determines whether the request body is mandatory.
+		Required bool `json:"required,omitempty"`
+	}
+
+	// MediaType describes the content of a request or response body for a given content type.
+	MediaType struct {
+		// Schema defines the type of the content.
+		Schema *Schema `json:"schema,omitempty"`
+	}
+
+	// Response describes an operation response.
+	Response struct {
+		// Description of the response.
+		Description string `json:"description"`
+		// Headers is a list of headers that are sent with the response.
+		Headers map[string]*Header `json:"headers,omitempty"`
+		// Content describes the response body indexed by content type.
+		Content map[string]*MediaType `json:"content,omitempty"`
+		// Extensions defines the OpenAPI extensions.
+		Extensions map[string]interface{} `json:"-"`
+	}
+
+	// Header describes a response header.
+	Header struct {
+		// Description is a brief description of the header.
+		Description string `json:"description,omitempty"`
+		// Required determines whether the header is always sent.
+		Required bool `json:"required,omitempty"`
+		// Schema defines the type used for the header.
+		Schema *Schema `json:"schema"`
+	}
+
+	// Components holds the reusable objects referenced by the rest of the document.
+	Components struct {
+		// Schemas contains the user types and media types schemas indexed by type name.
+		Schemas map[string]*Schema `json:"schemas,omitempty"`
+		// Responses contains the API responses indexed by name.
+		Responses map[string]*Response `json:"responses,omitempty"`
+		// SecuritySchemes contains the API security schemes indexed by name.
+		SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	// SecurityScheme defines a security scheme that can be used by the operations.
+	SecurityScheme struct {
+		// Type of the security scheme. Valid values are "apiKey", "http" and "oauth2".
+		Type string `json:"type"`
+		// Description for security scheme.
+		Description string `json:"description,omitempty"`
+		// Name of the header, query or cookie parameter to be used when type is "apiKey".
+		Name string `json:"name,omitempty"`
+		// In is the location of the API key when type is "apiKey".
+		In string `json:"in,omitempty"`
+		// Scheme is the name of the HTTP authorization scheme when type is "http".
+		Scheme string `json:"scheme,omitempty"`
+		// BearerFormat is a hint describing the format of bearer tokens.
+		BearerFormat string `json:"bearerFormat,omitempty"`
+		// Flows describes the OAuth2 flow when type is "oauth2".
+		Flows *OAuthFlows `json:"flows,omitempty"`
+		// Extensions defines the OpenAPI extensions.
+		Extensions map[string]interface{} `json:"-"`
+	}
+
+	// OAuthFlows lists the supported OAuth2 flows.
+	OAuthFlows struct {
+		Implicit          *OAuthFlow `json:"implicit,omitempty"`
+		Password          *OAuthFlow `json:"password,omitempty"`
+		ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+		AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	}
+
+	// OAuthFlow describes an OAuth2 flow.
+	OAuthFlow struct {
+		// AuthorizationURL is the authorization URL to be used for this flow.
+		AuthorizationURL string `json:"authorizationUrl,omitempty"`
+		// TokenURL is the token URL to be used for this flow.
+		TokenURL string `json:"tokenUrl,omitempty"`
+		// Scopes list the available scopes and their descriptions.
+		Scopes map[string]string `json:"scopes"`
+	}
+
+	// Schema is the OpenAPI 3.0 subset of JSON schema used to describe data types.
+	Schema struct {
+		Ref                  string             `json:"$ref,omitempty"`
+		Title                string             `json:"title,omitempty"`
+		Type                 string             `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Description          string             `json:"description,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		AdditionalProperties bool               `json:"additionalProperties,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+		Default              interface{}        `json:"default,omitempty"`
+		Example              interface{}        `json:"example,omitempty"`
+		Enum                 []interface{}      `json:"enum,omitempty"`
+		Pattern              string             `json:"pattern,omitempty"`
+		Minimum              *float64           `json:"minimum,omitempty"`
+		Maximum              *float64           `json:"maximum,omitempty"`
+		ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+		MultipleOf           *float64           `json:"multipleOf,omitempty"`
+		MinLength            *int               `json:"minLength,omitempty"`
+		MaxLength            *int               `json:"maxLength,omitempty"`
+		MinItems             *int               `json:"minItems,omitempty"`
+		MaxItems             *int               `json:"maxItems,omitempty"`
+		UniqueItems          bool               `json:"uniqueItems,omitempty"`
+		MinProperties        *int               `json:"minProperties,omitempty"`
+		MaxProperties        *int               `json:"maxProperties,omitempty"`
+		AllOf                []*Schema          `json:"allOf,omitempty"`
+		AnyOf                []*Schema          `json:"anyOf,omitempty"`
+		OneOf                []*Schema          `json:"oneOf,omitempty"`
+		Not                  *Schema            `json:"not,omitempty"`
+		Discriminator        *Discriminator     `json:"discriminator,omitempty"`
+		Nullable             bool               `json:"nullable,omitempty"`
+		ReadOnly             bool               `json:"readOnly,omitempty"`
+		Deprecated           bool               `json:"deprecated,omitempty"`
+		// EnumDescriptions lists the descriptions of the Enum values in the same order, it
+		// is rendered as the "x-enum-descriptions" extension.
+		EnumDescriptions []string `json:"x-enum-descriptions,omitempty"`
+	}
+
+	// Discriminator describes the property used to tell apart the schemas listed in oneOf.
+	Discriminator struct {
+		// PropertyName is the name of the discriminator property.
+		PropertyName string `json:"propertyName"`
+		// Mapping maps the discriminator values to the schema references.
+		Mapping map[string]string `json:"mapping,omitempty"`
+	}
+
+	// Tag allows adding meta data to a single tag that is used by the Operation Object.
+	Tag struct {
+		// Name of the tag.
+		Name string `json:"name"`
+		// Description is a short description of the tag.
+		Description string `json:"description,omitempty"`
+		// ExternalDocs is additional external documentation for this tag.
+		ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+		// Extensions defines the OpenAPI extensions.
+		Extensions map[string]interface{} `json:"-"`
+	}
+
+	// ExternalDocs allows referencing an external resource for extended documentation.
+	ExternalDocs struct {
+		// Description is a short description of the target documentation.
+		Description string `json:"description,omitempty"`
+		// URL for the target documentation.
+		URL string `json:"url"`
+	}
+
+	// These types are used in marshalJSON() to avoid recursive call of json.Marshal().
+	_Info           Info
+	_PathItem       PathItem
+	_Operation      Operation
+	_Parameter      Parameter
+	_Response       Response
+	_SecurityScheme SecurityScheme
+	_Tag            Tag
+)
+
+// schemasRef is the prefix of the references to the schemas defined in the document components.
+const schemasRef = "#/components/schemas/"
+
+func marshalJSON(v interface{}, extensions map[string]interface{}) ([]byte, error) {
+	marshaled, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(extensions) == 0 {
+		return marshaled, nil
+	}
+	var unmarshaled map[string]interface{}
+	if err := json.Unmarshal(marshaled, &unmarshaled); err != nil {
+		return nil, err
+	}
+	for k, v := range extensions {
+		unmarshaled[k] = v
+	}
+	return json.Marshal(unmarshaled)
+}
+
+// MarshalJSON returns the JSON encoding of i.
+func (i Info) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_Info(i), i.Extensions)
+}
+
+// MarshalJSON returns the JSON encoding of p.
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_PathItem(p), p.Extensions)
+}
+
+// MarshalJSON returns the JSON encoding of o.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_Operation(o), o.Extensions)
+}
+
+// MarshalJSON returns the JSON encoding of p.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_Parameter(p), p.Extensions)
+}
+
+// MarshalJSON returns the JSON encoding of r.
+func (r Response) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_Response(r), r.Extensions)
+}
+
+// MarshalJSON returns the JSON encoding of s.
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_SecurityScheme(s), s.Extensions)
+}
+
+// MarshalJSON returns the JSON encoding of t.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_Tag(t), t.Extensions)
+}
+
+// New creates an OpenAPI 3.0 document from an API definition. The document honors the same
+// "swagger:generate", "swagger:summary", "swagger:tag" and "swagger:extension" metadata as the
+// Swagger specification.
+func New(api *design.APIDefinition) (*OpenAPI, error) {
+	if api == nil {
+		return nil, nil
+	}
+	basePath := api.BasePath
+	if hasAbsoluteRoutes(api) || len(design.ExtractWildcards(basePath)) > 0 {
+		// The base path is part of the path keys if some routes do not use it or if it
+		// defines parameters.
+		basePath = ""
+	}
+	o := &OpenAPI{
+		OpenAPI: "3.0.3",
+		Info: &Info{
+			Title:          api.Title,
+			Description:    api.Description,
+			TermsOfService: api.TermsOfService,
+			Contact:        api.Contact,
+			License:        api.License,
+			Version:        api.Version,
+			Extensions:     extensionsFromDefinition(api.Metadata),
+		},
+		Servers:      serversFromDefinition(api, api.Schemes, basePath),
+		Paths:        make(map[string]*PathItem),
+		Tags:         tagsFromDefinition(api.Metadata),
+		ExternalDocs: docsFromDefinition(api.Docs),
+		Components:   &Components{SecuritySchemes: securitySchemesFromDefinition(api.SecuritySchemes)},
+	}
+	if o.Info.Title == "" {
+		o.Info.Title = api.Name
+	}
+	if o.Info.Version == "" {
+		o.Info.Version = "1.0"
+	}
+
+	err := api.IterateResponses(func(r *design.ResponseDefinition) error {
+		res, err := responseFromDefinition(api, r)
+		if err != nil {
+			return err
+		}
+		if o.Components.Responses == nil {
+			o.Components.Responses = make(map[string]*Response)
+		}
+		o.Components.Responses[r.Name] = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = api.IterateResources(func(res *design.ResourceDefinition) error {
+		err := res.IterateFileServers(func(fs *design.FileServerDefinition) error {
+			if !mustGenerate(fs.Metadata) {
+				return nil
+			}
+			return buildPathFromFileServer(o, api, fs)
+		})
+		if err != nil {
+			return err
+		}
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if !mustGenerate(a.Metadata) {
+				return nil
+			}
+			for _, route := range a.Routes {
+				if err := buildPathFromDefinition(o, api, route, basePath); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	o.Webhooks = webhooksFromDefinition(api)
+	if len(genschema.Definitions) > 0 {
+		o.Components.Schemas = make(map[string]*Schema, len(genschema.Definitions))
+		for n, d := range genschema.Definitions {
+			o.Components.Schemas[n] = schemaFromJSONSchema(d)
+		}
+	}
+	return o, nil
+}
+
+// schemaFromJSONSchema converts the JSON schema produced by the genschema package into an
+// OpenAPI schema. The hyper schema fields are dropped, the references are rewritten to point to
+// the document components and the conditional validations that OpenAPI 3.0 does not support
+// are expressed with equivalent "anyOf" and "not" schemas.
+func schemaFromJSONSchema(js *genschema.JSONSchema) *Schema {
+	if js == nil {
+		return nil
+	}
+	s := &Schema{
+		Ref:                  strings.Replace(js.Ref, "#/definitions/", schemasRef, 1),
+		Title:                js.Title,
+		Type:                 string(js.Type),
+		Format:               js.Format,
+		Description:          js.Description,
+		Items:                schemaFromJSONSchema(js.Items),
+		AdditionalProperties: js.AdditionalProperties,
+		Required:             js.Required,
+		Default:              js.DefaultValue,
+		Example:              js.Example,
+		Enum:                 js.Enum,
+		Pattern:              js.Pattern,
+		Minimum:              js.Minimum,
+		Maximum:              js.Maximum,
+		ExclusiveMinimum:     js.ExclusiveMinimum,
+		ExclusiveMaximum:     js.ExclusiveMaximum,
+		MultipleOf:           js.MultipleOf,
+		MinLength:            js.MinLength,
+		MaxLength:            js.MaxLength,
+		MinItems:             js.MinItems,
+		MaxItems:             js.MaxItems,
+		UniqueItems:          js.UniqueItems,
+		MinProperties:        js.MinProperties,
+		MaxProperties:        js.MaxProperties,
+		Not:                  schemaFromJSONSchema(js.Not),
+		Nullable:             js.Nullable,
+		ReadOnly:             js.ReadOnly,
+		Deprecated:           js.Deprecated,
+		EnumDescriptions:     js.EnumDescriptions,
+	}
+	if js.Type == genschema.JSONFile {
+		s.Type = genschema.JSONString
+		s.Format = "binary"
+	}
+	if len(js.Properties) > 0 {
+		s.Properties = make(map[string]*Schema, len(js.Properties))
+		for n, p := range js.Properties {
+			s.Properties[n] = schemaFromJSONSchema(p)
+		}
+	}
+	for _, a := range js.AllOf {
+		s.AllOf = append(s.AllOf, schemaFromJSONSchema(a))
+	}
+	var deps []string
+	for n := range js.Dependencies {
+		deps = append(deps, n)
+	}
+	sort.Strings(deps)
+	for _, n := range deps {
+		// "dependencies" is not supported, either the attribute is absent or the dependent
+		// attributes are required.
+		s.AllOf = append(s.AllOf, &Schema{AnyOf: []*Schema{
+			{Not: &Schema{Required: []string{n}}},
+			{Required: js.Dependencies[n]},
+		}})
+	}
+	if s.Not != nil || len(s.AllOf) > 0 {
+		// The generated examples do not honor the conditional validations.
+		s.Example = nil
+	}
+	for _, a := range js.AnyOf {
+		s.AnyOf = append(s.AnyOf, schemaFromJSONSchema(a))
+	}
+	for _, a := range js.OneOf {
+		s.OneOf = append(s.OneOf, schemaFromJSONSchema(a))
+	}
+	if js.Discriminator != "" {
+		s.Discriminator = &Discriminator{PropertyName: js.Discriminator}
+		if p, ok := js.Properties[js.Discriminator]; ok && len(p.Enum) == len(s.OneOf) {
+			s.Discriminator.Mapping = make(map[string]string, len(s.OneOf))
+			for i, v := range p.Enum {
+				s.Discriminator.Mapping[fmt.Sprintf("%v", v)] = s.OneOf[i].Ref
+			}
+		}
+	}
+	return s
+}
+
+// typeSchema returns the OpenAPI schema describing the given data type.
+func typeSchema(api *design.APIDefinition, t design.DataType) *Schema {
+	return schemaFromJSONSchema(genschema.TypeSchema(api, t))
+}
+
+// attributeSchema returns the OpenAPI schema describing the given attribute.
+func attributeSchema(api *design.APIDefinition, at *design.AttributeDefinition) *Schema {
+	return schemaFromJSONSchema(genschema.AttributeSchema(api, at))
+}
+
+// serversFromDefinition returns the servers built from the API host and the given schemes and
+// base path. The server URL is relative if the API does not define a host.
+func serversFromDefinition(api *design.APIDefinition, schemes []string, basePath string) []*Server {
+	if api.Host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []*Server{{URL: basePath}}
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	servers := make([]*Server, len(schemes))
+	for i, scheme := range schemes {
+		u := url.URL{Scheme: scheme, Host: api.Host, Path: basePath}
+		servers[i] = &Server{URL: u.String()}
+	}
+	return servers
+}
+
+// mustGenerate returns true if the metadata indicates that an OpenAPI specification should be
+// generated, false otherwise.
+func mustGenerate(meta dslengine.MetadataDefinition) bool {
+	if m, ok := meta["swagger:generate"]; ok {
+		if len(m) > 0 && m[0] == "false" {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAbsoluteRoutes returns true if any action exposed by the API uses an absolute route or if the
+// API has file servers. The base path cannot be part of the server URLs in this case.
+func hasAbsoluteRoutes(api *design.APIDefinition) bool {
+	for _, res := range api.Resources {
+		for _, fs := range res.FileServers {
+			if mustGenerate(fs.Metadata) {
+				return true
+			}
+		}
+		for _, a := range res.Actions {
+			if !mustGenerate(a.Metadata) {
+				continue
+			}
+			for _, ro := range a.Routes {
+				if ro.IsAbsolute() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// securitySchemesFromDefinition returns the OpenAPI security schemes corresponding to the API
+// security schemes. Basic auth uses the "http" type, API keys the "apiKey" type, OAuth2 the
+// "oauth2" type and JWT the "http" type with the "bearer" scheme when the token is read from the
+// Authorization header, the "apiKey" type otherwise.
+func securitySchemesFromDefinition(schemes []*design.SecuritySchemeDefinition) map[string]*SecurityScheme {
+	if len(schemes) == 0 {
+		return nil
+	}
+	defs := make(map[string]*SecurityScheme)
+	for _, scheme := range schemes {
+		def := &SecurityScheme{
+			Description: scheme.Description,
+			Extensions:  extensionsFromDefinition(scheme.Metadata),
+		}
+		switch scheme.Kind {
+		case design.BasicAuthSecurityKind:
+			def.Type = "http"
+			def.Scheme = "basic"
+		case design.APIKeySecurityKind:
+			def.Type = "apiKey"
+			def.Name = scheme.Name
+			def.In = scheme.In
+		case design.JWTSecurityKind:
+			if scheme.In == "header" && strings.EqualFold(scheme.Name, "Authorization") {
+				def.Type = "http"
+				def.Scheme = "bearer"
+				def.BearerFormat = "JWT"
+			} else {
+				def.Type = "apiKey"
+				def.Name = scheme.Name
+				def.In = scheme.In
+			}
+			if scheme.TokenURL != "" {
+				def.Description += fmt.Sprintf("\n\n**Token URL**: %s", scheme.TokenURL)
+			}
+			if len(scheme.Scopes) != 0 {
+				def.Description += fmt.Sprintf("\n\n**Security Scopes**:\n%s", scopesMapList(scheme.Scopes))
+			}
+		case design.OAuth2SecurityKind:
+			def.Type = "oauth2"
+			scopes := scheme.Scopes
+			if scopes == nil {
+				scopes = make(map[string]string)
+			}
+			flow := &OAuthFlow{
+				AuthorizationURL: scheme.AuthorizationURL,
+				TokenURL:         scheme.TokenURL,
+				Scopes:           scopes,
+			}
+			switch scheme.Flow {
+			case "implicit":
+				def.Flows = &OAuthFlows{Implicit: flow}
+			case "password":
+				def.Flows = &OAuthFlows{Password: flow}
+			case "application":
+				def.Flows = &OAuthFlows{ClientCredentials: flow}
+			case "accessCode":
+				def.Flows = &OAuthFlows{AuthorizationCode: flow}
+			}
+		default:
+			continue
+		}
+		defs[scheme.SchemeName] = def
+	}
+	if len(defs) == 0 {
+		return nil
+	}
+	return defs
+}
+
+func scopesMapList(scopes map[string]string) string {
+	names := []string{}
+	for name := range scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{}
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("  * `%s`: %s", name, scopes[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func tagsFromDefinition(mdata dslengine.MetadataDefinition) (tags []*Tag) {
+	var keys []string
+	for k := range mdata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		chunks := strings.Split(key, ":")
+		if len(chunks) != 3 {
+			continue
+		}
+		if chunks[0] != "swagger" || chunks[1] != "tag" {
+			continue
+		}
+
+		tag := &Tag{Name: chunks[2]}
+		if desc := mdata[key+":desc"]; len(desc) != 0 {
+			tag.Description = desc[0]
+		}
+		docs := &ExternalDocs{}
+		if u := mdata[key+":url"]; len(u) != 0 {
+			docs.URL = u[0]
+		}
+		if desc := mdata[key+":url:desc"]; len(desc) != 0 {
+			docs.Description = desc[0]
+		}
+		if docs.URL != "" {
+			tag.ExternalDocs = docs
+		}
+		tag.Extensions = extensionsFromDefinition(mdata)
+
+		tags = append(tags, tag)
+	}
+	return
+}
+
+func tagNamesFromDefinitions(mdatas ...dslengine.MetadataDefinition) (tagNames []string) {
+	for _, mdata := range mdatas {
+		tags := tagsFromDefinition(mdata)
+		for _, tag := range tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+	}
+	return
+}
+
+func summaryFromDefinition(name string, metadata dslengine.MetadataDefinition) string {
+	if mdata, ok := metadata["swagger:summary"]; ok && len(mdata) > 0 {
+		return mdata[0]
+	}
+	return name
+}
+
+func extensionsFromDefinition(mdata dslengine.MetadataDefinition) map[string]interface{} {
+	extensions := make(map[string]interface{})
+	for key, value := range mdata {
+		chunks := strings.Split(key, ":")
+		if len(chunks) != 3 {
+			continue
+		}
+		if chunks[0] != "swagger" || chunks[1] != "extension" {
+			continue
+		}
+		if !strings.HasPrefix(chunks[2], "x-") {
+			continue
+		}
+		val := value[0]
+		ival := interface{}(val)
+		if err := json.Unmarshal([]byte(val), &ival); err != nil {
+			extensions[chunks[2]] = val
+			continue
+		}
+		extensions[chunks[2]] = ival
+	}
+	if len(extensions) == 0 {
+		return nil
+	}
+	return extensions
+}
+
+func docsFromDefinition(docs *design.DocsDefinition) *ExternalDocs {
+	if docs == nil {
+		return nil
+	}
+	return &ExternalDocs{
+		Description: docs.Description,
+		URL:         docs.URL,
+	}
+}
+
+// paramsFromDefinition returns the path and query string parameters described by params. The
+// parameters whose name matches a wildcard of the given path are path parameters.
+func paramsFromDefinition(api *design.APIDefinition, params *design.AttributeDefinition, path string) ([]*Parameter, error) {
+	if params == nil {
+		return nil, nil
+	}
+	obj := params.Type.ToObject()
+	if obj == nil {
+		return nil, fmt.Errorf("invalid parameters definition, not an object")
+	}
+	var res []*Parameter
+	wildcards := design.ExtractWildcards(path)
+	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		in := "query"
+		required := params.IsRequired(n)
+		for _, w := range wildcards {
+			if n == w {
+				in = "path"
+				required = true
+				break
+			}
+		}
+		res = append(res, paramFor(api, at, n, in, required))
+		return nil
+	})
+	return res, nil
+}
+
+// paramsFromHeaders returns the parameters describing the action request headers.
+func paramsFromHeaders(api *design.APIDefinition, action *design.ActionDefinition) []*Parameter {
+	var params []*Parameter
+	action.IterateHeaders(func(name string, required bool, header *design.AttributeDefinition) error {
+		params = append(params, paramFor(api, header, name, "header", required))
+		return nil
+	})
+	return params
+}
+
+// paramsFromCookies returns the parameters describing the action request cookies.
+func paramsFromCookies(api *design.APIDefinition, action *design.ActionDefinition) []*Parameter {
+	cookies := action.AllCookies()
+	if cookies == nil {
+		return nil
+	}
+	var params []*Parameter
+	cookies.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		params = append(params, paramFor(api, at, n, "cookie", cookies.IsRequired(n)))
+		return nil
+	})
+	return params
+}
+
+func paramFor(api *design.APIDefinition, at *design.AttributeDefinition, name, in string, required bool) *Parameter {
+	p := &Parameter{
+		In:          in,
+		Name:        name,
+		Description: at.Description,
+		Required:    required,
+		Deprecated:  at.Deprecation != nil,
+		Schema:      attributeSchema(api, at),
+		Extensions:  extensionsFromDefinition(at.Metadata),
+	}
+	// The parameter description is not repeated in its schema.
+	p.Schema.Description = ""
+	if in == "query" && (at.Style != "" || at.Explode != nil) {
+		explode := at.IsExploded()
+		p.Style = at.ParamStyle()
+		p.Explode = &explode
+	}
+	return p
+}
+
+// requestBodyFromDefinition returns the request body of the given action. Multipart payloads are
+// described with the "multipart/form-data" content type, other payloads with each content type
+// listed in the API Consumes.
+func requestBodyFromDefinition(api *design.APIDefinition, action *design.ActionDefinition) *RequestBody {
+	if action.Payload == nil {
+		return nil
+	}
+	schema := typeSchema(api, action.Payload)
+	var mimeTypes []string
+	if action.PayloadMultipart {
+		mimeTypes = []string{"multipart/form-data"}
+	} else {
+		for _, c := range api.Consumes {
+			mimeTypes = append(mimeTypes, c.MIMETypes...)
+		}
+	}
+	if len(mimeTypes) == 0 {
+		mimeTypes = []string{"application/json"}
+	}
+	content := make(map[string]*MediaType, len(mimeTypes))
+	for _, m := range mimeTypes {
+		content[m] = &MediaType{Schema: schema}
+	}
+	return &RequestBody{
+		Description: action.Payload.Description,
+		Content:     content,
+		Required:    !action.PayloadOptional,
+	}
+}
+
+func responseFromDefinition(api *design.APIDefinition, r *design.ResponseDefinition) (*Response, error) {
+	resp := &Response{
+		Description: r.Description,
+		Extensions:  extensionsFromDefinition(r.Metadata),
+	}
+	if resp.Description == "" {
+		resp.Description = http.StatusText(r.Status)
+	}
+	if r.MediaType != "" {
+		content := &MediaType{}
+		if mt, ok := api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]; ok {
+			view := r.ViewName
+			if view == "" {
+				view = design.DefaultView
+			}
+			ref := genschema.MediaTypeRef(api, mt, view)
+			content.Schema = &Schema{Ref: strings.Replace(ref, "#/definitions/", schemasRef, 1)}
+		}
+		resp.Content = map[string]*MediaType{r.MediaType: content}
+	}
+	if r.IsStream() {
+		// The schema describes the data of each event.
+		resp.Content = map[string]*MediaType{
+			design.EventStreamMediaType: {Schema: typeSchema(api, r.Stream)},
+		}
+	}
+	if r.Headers != nil {
+		obj := r.Headers.Type.ToObject()
+		if obj == nil {
+			return nil, fmt.Errorf("invalid headers definition, not an object")
+		}
+		resp.Headers = make(map[string]*Header)
+		obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+			header := &Header{
+				Description: at.Description,
+				Required:    r.Headers.IsRequired(n),
+				Schema:      attributeSchema(api, at),
+			}
+			header.Schema.Description = ""
+			resp.Headers[n] = header
+			return nil
+		})
+	}
+	return resp, nil
+}
+
+// errorSchema returns the schema of the responses rendering the given errors: the error media type
+// schema with the "code" member restricted to the error names. The "meta" member is described by
+// the error meta type if there is a single error. The "type" member and the extension members are
+// used instead if the API renders problem details.
+func errorSchema(api *design.APIDefinition, base *Schema, errs []*design.ErrorDefinition) *Schema {
+	code := &Schema{Type: genschema.JSONString}
+	var described bool
+	for _, e := range errs {
+		code.Enum = append(code.Enum, e.Name)
+		code.EnumDescriptions = append(code.EnumDescriptions, e.Description)
+		described = described || e.Description != ""
+	}
+	if !described {
+		code.EnumDescriptions = nil
+	}
+	ext := &Schema{Type: genschema.JSONObject, Properties: make(map[string]*Schema)}
+	schema := &Schema{AllOf: []*Schema{base, ext}}
+	if api.ProblemDetails {
+		ext.Properties["type"] = code
+		if len(errs) == 1 && errs[0].Meta != nil {
+			schema.AllOf = append(schema.AllOf, typeSchema(api, errs[0].Meta))
+		}
+		return schema
+	}
+	ext.Properties["code"] = code
+	if len(errs) == 1 && errs[0].Meta != nil {
+		ext.Properties["meta"] = typeSchema(api, errs[0].Meta)
+	}
+	return schema
+}
+
+// isErrorMedia returns true if the response renders the error media type of the API.
+func isErrorMedia(api *design.APIDefinition, r *design.ResponseDefinition) bool {
+	mt := api.MediaTypeWithIdentifier(r.MediaType)
+	return mt != nil && mt.IsError()
+}
+
+func buildPathFromFileServer(o *OpenAPI, api *design.APIDefinition, fs *design.FileServerDefinition) error {
+	wcs := design.ExtractWildcards(fs.RequestPath)
+	var params []*Parameter
+	if len(wcs) > 0 {
+		params = []*Parameter{{
+			In:          "path",
+			Name:        wcs[0],
+			Description: "Relative file path",
+			Required:    true,
+			Schema:      &Schema{Type: genschema.JSONString},
+		}}
+	}
+
+	responses := map[string]*Response{
+		"200": {
+			Description: "File downloaded",
+			Content: map[string]*MediaType{
+				"*/*": {Schema: &Schema{Type: genschema.JSONString, Format: "binary"}},
+			},
+		},
+	}
+	if len(wcs) > 0 {
+		errorMedia, id := design.ErrorMedia, design.ErrorMediaIdentifier
+		if api.ProblemDetails {
+			errorMedia, id = design.ProblemMedia, design.ProblemMediaIdentifier
+		}
+		responses["404"] = &Response{
+			Description: "File not found",
+			Content:     map[string]*MediaType{id: {Schema: typeSchema(api, errorMedia)}},
+		}
+	}
+
+	operation := &Operation{
+		Description:  fs.Description,
+		Summary:      summaryFromDefinition(fmt.Sprintf("Download %s", fs.FilePath), fs.Metadata),
+		ExternalDocs: docsFromDefinition(fs.Docs),
+		OperationID:  fmt.Sprintf("%s#%s", fs.Parent.Name, fs.RequestPath),
+		Parameters:   params,
+		Responses:    responses,
+	}
+	applySecurity(operation, fs.Security)
+
+	p := pathItem(o, fs.RequestPath, "")
+	p.Get = operation
+	p.Extensions = extensionsFromDefinition(fs.Metadata)
+	return nil
+}
+
+func buildPathFromDefinition(o *OpenAPI, api *design.APIDefinition, route *design.RouteDefinition, basePath string) error {
+	action := route.Parent
+
+	tagNames := tagNamesFromDefinitions(action.Parent.Metadata, action.Metadata)
+	if len(tagNames) == 0 {
+		// By default tag with resource name
+		tagNames = []string{action.Parent.Name}
+	}
+	params, err := paramsFromDefinition(api, action.AllParams(), route.FullPath())
+	if err != nil {
+		return err
+	}
+	params = append(params, paramsFromHeaders(api, action)...)
+	params = append(params, paramsFromCookies(api, action)...)
+
+	responses := make(map[string]*Response, len(action.Responses))
+	for _, r := range action.Responses {
+		resp, err := responseFromDefinition(api, r)
+		if err != nil {
+			return err
+		}
+		if errs := action.ErrorsWithStatus(r.Status); len(errs) > 0 && isErrorMedia(api, r) {
+			for _, c := range resp.Content {
+				c.Schema = errorSchema(api, c.Schema, errs)
+			}
+		}
+		responses[strconv.Itoa(r.Status)] = resp
+	}
+	if len(responses) == 0 {
+		responses["default"] = &Response{Description: "Default response"}
+	}
+
+	operationID := fmt.Sprintf("%s#%s", action.Parent.Name, action.Name)
+	for i, rt := range action.Routes {
+		if rt == route && i > 0 {
+			operationID = fmt.Sprintf("%s#%d", operationID, i)
+			break
+		}
+	}
+
+	operation := &Operation{
+		Tags:         tagNames,
+		Description:  action.Description,
+		Summary:      summaryFromDefinition(action.Name+" "+action.Parent.Name, action.Metadata),
+		ExternalDocs: docsFromDefinition(action.Docs),
+		OperationID:  operationID,
+		Parameters:   params,
+		RequestBody:  requestBodyFromDefinition(api, action),
+		Responses:    responses,
+		Deprecated:   action.Deprecation != nil,
+		Extensions:   extensionsFromDefinition(route.Metadata),
+	}
+	if len(action.Schemes) > 0 && !sameSchemes(action.Schemes, api.Schemes) {
+		operation.Servers = serversFromDefinition(api, action.Schemes, basePath)
+	}
+
+	// OpenAPI 3.0 cannot describe websocket messages, the "x-inbound-message" and
+	// "x-outbound-message" extensions hold their schemas.
+	for ext, m := range map[string]design.DataType{"x-inbound-message": action.InboundMessage, "x-outbound-message": action.OutboundMessage} {
+		if m == nil {
+			continue
+		}
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions[ext] = typeSchema(api, m)
+	}
+
+	applySecurity(operation, action.Security)
+
+	p := pathItem(o, route.FullPath(), basePath)
+	switch route.Verb {
+	case "GET":
+		p.Get = operation
+	case "PUT":
+		p.Put = operation
+	case "POST":
+		p.Post = operation
+	case "DELETE":
+		p.Delete = operation
+	case "OPTIONS":
+		p.Options = operation
+	case "HEAD":
+		p.Head = operation
+	case "PATCH":
+		p.Patch = operation
+	}
+	p.Extensions = extensionsFromDefinition(action.Metadata)
+	return nil
+}
+
+// pathItem returns the path item for the given goa path creating it if needed. The path wildcards
+// are replaced with OpenAPI path templates and the base path, which is part of the server URLs,
+// is removed.
+func pathItem(o *OpenAPI, path, basePath string) *PathItem {
+	key := design.WildcardRegex.ReplaceAllStringFunc(
+		path,
+		func(w string) string {
+			return fmt.Sprintf("/{%s}", w[2:])
+		},
+	)
+	if basePath != "/" {
+		key = strings.TrimPrefix(key, basePath)
+	}
+	if key == "" {
+		key = "/"
+	}
+	p, ok := o.Paths[key]
+	if !ok {
+		p = new(PathItem)
+		o.Paths[key] = p
+	}
+	return p
+}
+
+// sameSchemes returns true if a and b contain the same schemes.
+func sameSchemes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, s := range a {
+		found := false
+		for _, s2 := range b {
+			if s == s2 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// applySecurity sets the security requirement of the operation. OpenAPI 3.0 only allows scopes
+// in the requirements of OAuth2 schemes, the scopes required by JWT schemes are listed in the
+// operation description instead.
+func applySecurity(operation *Operation, security *design.SecurityDefinition) {
+	if security == nil || security.Scheme.Kind == design.NoSecurityKind {
+		return
+	}
+	scopes := make([]string, 0)
+	switch security.Scheme.Kind {
+	case design.OAuth2SecurityKind:
+		scopes = append(scopes, security.Scopes...)
+	case design.JWTSecurityKind:
+		if len(security.Scopes) > 0 {
+			if operation.Description != "" {
+				operation.Description += "\n\n"
+			}
+			operation.Description += fmt.Sprintf("Required security scopes:\n%s", scopesList(security.Scopes))
+		}
+	}
+	operation.Security = []map[string][]string{{security.Scheme.SchemeName: scopes}}
+}
+
+func scopesList(scopes []string) string {
+	sorted := make([]string, len(scopes))
+	copy(sorted, scopes)
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, scope := range sorted {
+		lines = append(lines, fmt.Sprintf("  * `%s`", scope))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// webhooksFromDefinition returns the path items describing the webhook requests sent by the API
+// indexed by webhook name, nil if there are none.
+func webhooksFromDefinition(api *design.APIDefinition) map[string]*PathItem {
+	var webhooks map[string]*PathItem
+	api.IterateWebhooks(func(wh *design.WebhookDefinition) error {
+		if webhooks == nil {
+			webhooks = make(map[string]*PathItem)
+		}
+		op := &Operation{
+			Description: wh.Description,
+			OperationID: wh.Name,
+			RequestBody: &RequestBody{
+				Content:  map[string]*MediaType{"application/json": {Schema: typeSchema(api, wh.Payload)}},
+				Required: true,
+			},
+			Responses: make(map[string]*Response),
+		}
+		wh.IterateResponses(func(r *design.ResponseDefinition) error {
+			desc := r.Description
+			if desc == "" {
+				desc = http.StatusText(r.Status)
+			}
+			op.Responses[strconv.Itoa(r.Status)] = &Response{Description: desc}
+			return nil
+		})
+		webhooks[wh.Name] = &PathItem{Post: op}
+		return nil
+	})
+	return webhooks
+}
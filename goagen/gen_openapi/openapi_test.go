@@ -0,0 +1,494 @@
+package genopenapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var openapi *genopenapi.OpenAPI
+	var newErr error
+
+	BeforeEach(func() {
+		openapi = nil
+		newErr = nil
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		openapi, newErr = genopenapi.New(Design)
+	})
+
+	Context("with a valid API definition", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Title("title")
+				Description("description")
+				Version("2.0")
+				Host("example.com")
+				Scheme("http", "https")
+				BasePath("/base")
+				Docs(func() {
+					Description("docs")
+					URL("http://docs.example.com")
+				})
+			})
+		})
+
+		It("sets the document metadata", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(openapi.OpenAPI).Should(Equal("3.0.3"))
+			Ω(openapi.Info.Title).Should(Equal("title"))
+			Ω(openapi.Info.Description).Should(Equal("description"))
+			Ω(openapi.Info.Version).Should(Equal("2.0"))
+			Ω(openapi.ExternalDocs).ShouldNot(BeNil())
+			Ω(openapi.ExternalDocs.URL).Should(Equal("http://docs.example.com"))
+		})
+
+		It("builds the servers from the host, schemes and base path", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(openapi.Servers).Should(HaveLen(2))
+			Ω(openapi.Servers[0].URL).Should(Equal("http://example.com/base"))
+			Ω(openapi.Servers[1].URL).Should(Equal("https://example.com/base"))
+		})
+
+		It("serializes to JSON", func() {
+			b, err := json.Marshal(openapi)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(ContainSubstring(`"openapi":"3.0.3"`))
+			Ω(string(b)).Should(ContainSubstring(`"paths":{}`))
+		})
+
+		Context("with an absolute route", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("health", func() {
+						Routing(GET("//health"))
+						Response(NoContent)
+					})
+					Action("show", func() {
+						Routing(GET("/:id"))
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("leaves the base path out of the servers", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				Ω(openapi.Servers[0].URL).Should(Equal("http://example.com"))
+				Ω(openapi.Paths).Should(HaveKey("/health"))
+				Ω(openapi.Paths).Should(HaveKey("/base/{id}"))
+			})
+		})
+	})
+
+	Context("with a base path containing wildcards", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Host("example.com")
+				BasePath("/accounts/:accountID")
+				Params(func() {
+					Param("accountID", Integer)
+				})
+			})
+			Resource("res", func() {
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("moves the base path to the paths", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(openapi.Servers).Should(HaveLen(1))
+			Ω(openapi.Servers[0].URL).Should(Equal("http://example.com"))
+			Ω(openapi.Paths).Should(HaveKey("/accounts/{accountID}/{id}"))
+			op := openapi.Paths["/accounts/{accountID}/{id}"].Get
+			Ω(op).ShouldNot(BeNil())
+			Ω(op.OperationID).Should(Equal("res#show"))
+			Ω(op.Parameters).Should(HaveLen(2))
+			Ω(op.Parameters[0].Name).Should(Equal("accountID"))
+			Ω(op.Parameters[0].In).Should(Equal("path"))
+			Ω(op.Parameters[0].Required).Should(BeTrue())
+			Ω(op.Parameters[0].Schema.Type).Should(Equal("integer"))
+		})
+	})
+
+	Context("with payloads", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Consumes("application/json")
+				Consumes("application/xml")
+			})
+			Resource("res", func() {
+				Action("create", func() {
+					Routing(POST("/"))
+					Payload(func() {
+						Attribute("name", String)
+						Required("name")
+					})
+					Response(Created)
+				})
+				Action("update", func() {
+					Routing(PATCH("/"))
+					OptionalPayload(func() {
+						Attribute("name", String)
+					})
+					Response(NoContent)
+				})
+				Action("upload", func() {
+					Routing(POST("/upload"))
+					MultipartForm()
+					Payload(func() {
+						Attribute("file", File)
+					})
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("lists the consumed content types in the request body", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			body := openapi.Paths["/"].Post.RequestBody
+			Ω(body).ShouldNot(BeNil())
+			Ω(body.Required).Should(BeTrue())
+			Ω(body.Content).Should(HaveLen(2))
+			Ω(body.Content).Should(HaveKey("application/json"))
+			Ω(body.Content).Should(HaveKey("application/xml"))
+			Ω(body.Content["application/json"].Schema.Ref).Should(Equal("#/components/schemas/CreateResPayload"))
+			Ω(openapi.Components.Schemas).Should(HaveKey("CreateResPayload"))
+			Ω(openapi.Components.Schemas["CreateResPayload"].Required).Should(Equal([]string{"name"}))
+		})
+
+		It("marks optional payloads as not required", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(openapi.Paths["/"].Patch.RequestBody.Required).Should(BeFalse())
+		})
+
+		It("uses multipart/form-data for multipart payloads", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			body := openapi.Paths["/upload"].Post.RequestBody
+			Ω(body.Content).Should(HaveLen(1))
+			Ω(body.Content).Should(HaveKey("multipart/form-data"))
+			s := openapi.Components.Schemas["UploadResPayload"]
+			Ω(s.Properties["file"].Type).Should(Equal("string"))
+			Ω(s.Properties["file"].Format).Should(Equal("binary"))
+		})
+	})
+
+	Context("with security schemes", func() {
+		BeforeEach(func() {
+			basic := BasicAuthSecurity("basic")
+			key := APIKeySecurity("key", func() {
+				Query("k")
+			})
+			jwt := JWTSecurity("jwt", func() {
+				Header("Authorization")
+				TokenURL("https://example.com/token")
+				Scope("api:read", "Read access")
+			})
+			oauth := OAuth2Security("oauth", func() {
+				AccessCodeFlow("https://example.com/auth", "https://example.com/token")
+				Scope("api:write", "Write access")
+			})
+			API("test", func() {})
+			Resource("res", func() {
+				Action("basic", func() {
+					Security(basic)
+					Routing(GET("/basic"))
+					Response(NoContent)
+				})
+				Action("key", func() {
+					Security(key)
+					Routing(GET("/key"))
+					Response(NoContent)
+				})
+				Action("jwt", func() {
+					Security(jwt, func() {
+						Scope("api:read")
+					})
+					Routing(GET("/jwt"))
+					Response(NoContent)
+				})
+				Action("oauth", func() {
+					Security(oauth, func() {
+						Scope("api:write")
+					})
+					Routing(GET("/oauth"))
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("defines the security schemes components", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			schemes := openapi.Components.SecuritySchemes
+			Ω(schemes).Should(HaveLen(4))
+
+			Ω(schemes["basic"].Type).Should(Equal("http"))
+			Ω(schemes["basic"].Scheme).Should(Equal("basic"))
+
+			Ω(schemes["key"].Type).Should(Equal("apiKey"))
+			Ω(schemes["key"].Name).Should(Equal("k"))
+			Ω(schemes["key"].In).Should(Equal("query"))
+
+			Ω(schemes["jwt"].Type).Should(Equal("http"))
+			Ω(schemes["jwt"].Scheme).Should(Equal("bearer"))
+			Ω(schemes["jwt"].BearerFormat).Should(Equal("JWT"))
+			Ω(schemes["jwt"].Description).Should(ContainSubstring("https://example.com/token"))
+
+			Ω(schemes["oauth"].Type).Should(Equal("oauth2"))
+			flow := schemes["oauth"].Flows.AuthorizationCode
+			Ω(flow).ShouldNot(BeNil())
+			Ω(flow.AuthorizationURL).Should(Equal("https://example.com/auth"))
+			Ω(flow.TokenURL).Should(Equal("https://example.com/token"))
+			Ω(flow.Scopes).Should(Equal(map[string]string{"api:write": "Write access"}))
+		})
+
+		It("sets the operation security requirements", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(openapi.Paths["/basic"].Get.Security).Should(Equal([]map[string][]string{{"basic": {}}}))
+			Ω(openapi.Paths["/jwt"].Get.Security).Should(Equal([]map[string][]string{{"jwt": {}}}))
+			Ω(openapi.Paths["/jwt"].Get.Description).Should(ContainSubstring("api:read"))
+			Ω(openapi.Paths["/oauth"].Get.Security).Should(Equal([]map[string][]string{{"oauth": {"api:write"}}}))
+		})
+	})
+
+	Context("with parameters", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			Resource("res", func() {
+				Action("list", func() {
+					Routing(GET("/"))
+					Params(func() {
+						Param("filter", HashOf(String, String), func() {
+							Style("deepObject")
+						})
+						Param("ids", ArrayOf(Integer), func() {
+							Explode(false)
+						})
+						Param("q", String)
+					})
+					Headers(func() {
+						Header("X-Request-Id", String)
+						Required("X-Request-Id")
+					})
+					Cookies(func() {
+						Cookie("session", String)
+					})
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("describes the query, header and cookie parameters", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			params := make(map[string]*genopenapi.Parameter)
+			for _, p := range openapi.Paths["/"].Get.Parameters {
+				params[p.Name] = p
+			}
+			Ω(params).Should(HaveLen(5))
+
+			Ω(params["filter"].In).Should(Equal("query"))
+			Ω(params["filter"].Style).Should(Equal("deepObject"))
+			Ω(*params["filter"].Explode).Should(BeTrue())
+
+			Ω(params["ids"].Style).Should(Equal("form"))
+			Ω(*params["ids"].Explode).Should(BeFalse())
+			Ω(params["ids"].Schema.Items.Type).Should(Equal("integer"))
+
+			Ω(params["q"].Style).Should(BeEmpty())
+			Ω(params["q"].Explode).Should(BeNil())
+
+			Ω(params["X-Request-Id"].In).Should(Equal("header"))
+			Ω(params["X-Request-Id"].Required).Should(BeTrue())
+
+			Ω(params["session"].In).Should(Equal("cookie"))
+			Ω(params["session"].Required).Should(BeFalse())
+		})
+	})
+
+	Context("with media types and user types", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			cat := Type("Cat", func() {
+				Attribute("kind", String, func() { Enum("cat") })
+				Required("kind")
+			})
+			dog := Type("Dog", func() {
+				Attribute("kind", String, func() { Enum("dog") })
+				Required("kind")
+			})
+			pet := Type("Pet", func() {
+				OneOf("kind", cat, dog)
+			})
+			mt := MediaType("application/vnd.owner+json", func() {
+				TypeName("Owner")
+				Attributes(func() {
+					Attribute("name", String)
+					Attribute("nick", String, func() { Nullable() })
+					Attribute("pet", pet)
+				})
+				View("default", func() {
+					Attribute("name")
+					Attribute("nick")
+					Attribute("pet")
+				})
+			})
+			Resource("res", func() {
+				Action("show", func() {
+					Routing(GET("/"))
+					Response(OK, mt)
+				})
+			})
+		})
+
+		It("references the components schemas", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			resp := openapi.Paths["/"].Get.Responses["200"]
+			Ω(resp).ShouldNot(BeNil())
+			Ω(resp.Description).Should(Equal("OK"))
+			Ω(resp.Content).Should(HaveKey("application/vnd.owner+json"))
+			Ω(resp.Content["application/vnd.owner+json"].Schema.Ref).Should(Equal("#/components/schemas/Owner"))
+		})
+
+		It("marks nullable attributes", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			owner := openapi.Components.Schemas["Owner"]
+			Ω(owner).ShouldNot(BeNil())
+			Ω(owner.Properties["nick"].Nullable).Should(BeTrue())
+			Ω(owner.Properties["name"].Nullable).Should(BeFalse())
+			Ω(owner.Properties["pet"].Ref).Should(Equal("#/components/schemas/Pet"))
+		})
+
+		It("describes unions with oneOf and a discriminator", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			pet := openapi.Components.Schemas["Pet"]
+			Ω(pet).ShouldNot(BeNil())
+			Ω(pet.OneOf).Should(HaveLen(2))
+			Ω(pet.OneOf[0].Ref).Should(Equal("#/components/schemas/Cat"))
+			Ω(pet.OneOf[1].Ref).Should(Equal("#/components/schemas/Dog"))
+			Ω(pet.Discriminator).ShouldNot(BeNil())
+			Ω(pet.Discriminator.PropertyName).Should(Equal("kind"))
+			Ω(pet.Discriminator.Mapping).Should(Equal(map[string]string{
+				"cat": "#/components/schemas/Cat",
+				"dog": "#/components/schemas/Dog",
+			}))
+		})
+
+		It("keeps the unions when the Swagger specification is generated first", func() {
+			_, err := genswagger.New(Design)
+			Ω(err).ShouldNot(HaveOccurred())
+			openapi, err := genopenapi.New(Design)
+			Ω(err).ShouldNot(HaveOccurred())
+			pet := openapi.Components.Schemas["Pet"]
+			Ω(pet).ShouldNot(BeNil())
+			Ω(pet.OneOf).Should(HaveLen(2))
+			Ω(pet.Discriminator).ShouldNot(BeNil())
+			Ω(pet.Discriminator.Mapping).Should(HaveLen(2))
+		})
+	})
+
+	Context("with conditional validations", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			payment := Type("Payment", func() {
+				Attribute("payment_type", String, func() { Enum("card", "cash") })
+				Attribute("card_number", String)
+				Attribute("credit_card", String)
+				Attribute("billing_address", String)
+				RequiredIf("payment_type", "card", "card_number")
+				DependentRequired("credit_card", "billing_address")
+			})
+			Resource("res", func() {
+				Action("pay", func() {
+					Routing(POST("/"))
+					Payload(payment)
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("converts them to anyOf and not", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			s := openapi.Components.Schemas["Payment"]
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Example).Should(BeNil())
+			Ω(s.AllOf).Should(HaveLen(2))
+
+			cond := s.AllOf[0]
+			Ω(cond.AnyOf).Should(HaveLen(2))
+			Ω(cond.AnyOf[0].Not).ShouldNot(BeNil())
+			Ω(cond.AnyOf[0].Not.Required).Should(Equal([]string{"payment_type"}))
+			Ω(cond.AnyOf[0].Not.Properties["payment_type"].Enum).Should(Equal([]interface{}{"card"}))
+			Ω(cond.AnyOf[1].Required).Should(Equal([]string{"card_number"}))
+
+			dep := s.AllOf[1]
+			Ω(dep.AnyOf).Should(HaveLen(2))
+			Ω(dep.AnyOf[0].Not.Required).Should(Equal([]string{"credit_card"}))
+			Ω(dep.AnyOf[1].Required).Should(Equal([]string{"billing_address"}))
+		})
+
+		It("keeps them when the Swagger specification is generated first", func() {
+			_, err := genswagger.New(Design)
+			Ω(err).ShouldNot(HaveOccurred())
+			openapi, err := genopenapi.New(Design)
+			Ω(err).ShouldNot(HaveOccurred())
+			s := openapi.Components.Schemas["Payment"]
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.AllOf).Should(HaveLen(2))
+		})
+	})
+
+	Context("with errors", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Error("rate_limited", func() {
+					Status(429)
+					Description("Too many requests")
+				})
+			})
+			Resource("res", func() {
+				Action("cancel", func() {
+					Routing(POST("/"))
+					Error("already_cancelled", func() {
+						Status(409)
+					})
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("describes the error codes in the error responses", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			responses := openapi.Paths["/"].Post.Responses
+			Ω(responses).Should(HaveKey("204"))
+			Ω(responses).Should(HaveKey("409"))
+			Ω(responses).Should(HaveKey("429"))
+
+			content := responses["409"].Content["application/vnd.goa.error"]
+			Ω(content).ShouldNot(BeNil())
+			Ω(content.Schema.AllOf).Should(HaveLen(2))
+			Ω(content.Schema.AllOf[0].Ref).Should(Equal("#/components/schemas/error"))
+			code := content.Schema.AllOf[1].Properties["code"]
+			Ω(code.Enum).Should(Equal([]interface{}{"already_cancelled"}))
+
+			code = responses["429"].Content["application/vnd.goa.error"].Schema.AllOf[1].Properties["code"]
+			Ω(code.Enum).Should(Equal([]interface{}{"rate_limited"}))
+			Ω(code.EnumDescriptions).Should(Equal([]string{"Too many requests"}))
+			Ω(openapi.Components.Schemas).Should(HaveKey("error"))
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 )
 
 type (
@@ -40,15 +41,47 @@ type (
 		Pattern              string        `json:"pattern,omitempty"`
 		Minimum              *float64      `json:"minimum,omitempty"`
 		Maximum              *float64      `json:"maximum,omitempty"`
+		ExclusiveMinimum     bool          `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum     bool          `json:"exclusiveMaximum,omitempty"`
+		MultipleOf           *float64      `json:"multipleOf,omitempty"`
 		MinLength            *int          `json:"minLength,omitempty"`
 		MaxLength            *int          `json:"maxLength,omitempty"`
 		MinItems             *int          `json:"minItems,omitempty"`
 		MaxItems             *int          `json:"maxItems,omitempty"`
+		UniqueItems          bool          `json:"uniqueItems,omitempty"`
+		MinProperties        *int          `json:"minProperties,omitempty"`
+		MaxProperties        *int          `json:"maxProperties,omitempty"`
 		Required             []string      `json:"required,omitempty"`
 		AdditionalProperties bool          `json:"additionalProperties,omitempty"`
 
+		// Conditional validations
+		Dependencies map[string][]string `json:"dependencies,omitempty"`
+		AllOf        []*JSONSchema       `json:"allOf,omitempty"`
+		Not          *JSONSchema         `json:"not,omitempty"`
+
 		// Union
-		AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+		AnyOf         []*JSONSchema `json:"anyOf,omitempty"`
+		OneOf         []*JSONSchema `json:"oneOf,omitempty"`
+		Discriminator string        `json:"discriminator,omitempty"`
+
+		// DiscriminatorValue is the value of the discriminator of the unions that the type
+		// is a variant of, it is rendered as the "x-discriminator-value" extension.
+		DiscriminatorValue string `json:"x-discriminator-value,omitempty"`
+
+		// Nullable is true if the value may be null, it is rendered as the
+		// "x-nullable" extension understood by most swagger tooling.
+		Nullable bool `json:"x-nullable,omitempty"`
+
+		// EnumDescriptions lists the descriptions of the Enum values in the same order, it
+		// is rendered as the "x-enum-descriptions" extension.
+		EnumDescriptions []string `json:"x-enum-descriptions,omitempty"`
+
+		// Deprecated is true if the value should not be used anymore.
+		Deprecated bool `json:"deprecated,omitempty"`
+
+		// Webhooks describes the webhooks sent by the API indexed by name, it is rendered as
+		// the "x-webhooks" extension.
+		Webhooks map[string]*JSONWebhook `json:"x-webhooks,omitempty"`
 	}
 
 	// JSONType is the JSON type enum.
@@ -72,6 +105,19 @@ type (
 		MediaType    string      `json:"mediaType,omitempty"`
 		EncType      string      `json:"encType,omitempty"`
 	}
+
+	// JSONWebhook describes a webhook request sent by the API.
+	JSONWebhook struct {
+		Description string                          `json:"description,omitempty"`
+		Method      string                          `json:"method"`
+		Schema      *JSONSchema                     `json:"schema,omitempty"`
+		Responses   map[string]*JSONWebhookResponse `json:"responses,omitempty"`
+	}
+
+	// JSONWebhookResponse describes a response expected from the receivers of a webhook.
+	JSONWebhookResponse struct {
+		Description string `json:"description,omitempty"`
+	}
 )
 
 const (
@@ -152,6 +198,7 @@ func APISchema(api *design.APIDefinition) *JSONSchema {
 			},
 		},
 	}
+	webhooks := WebhookSchemas(api)
 	s := JSONSchema{
 		ID:          fmt.Sprintf("%s/schema", href),
 		Title:       api.Title,
@@ -160,10 +207,37 @@ func APISchema(api *design.APIDefinition) *JSONSchema {
 		Definitions: Definitions,
 		Properties:  propertiesFromDefs(Definitions, "#/definitions/"),
 		Links:       links,
+		Webhooks:    webhooks,
 	}
 	return &s
 }
 
+// WebhookSchemas produces the descriptions of the webhooks sent by the API indexed by name. It
+// returns nil if the API does not define webhooks.
+func WebhookSchemas(api *design.APIDefinition) map[string]*JSONWebhook {
+	var webhooks map[string]*JSONWebhook
+	api.IterateWebhooks(func(wh *design.WebhookDefinition) error {
+		if webhooks == nil {
+			webhooks = make(map[string]*JSONWebhook)
+		}
+		w := &JSONWebhook{
+			Description: wh.Description,
+			Method:      "POST",
+			Schema:      TypeSchema(api, wh.Payload),
+		}
+		wh.IterateResponses(func(r *design.ResponseDefinition) error {
+			if w.Responses == nil {
+				w.Responses = make(map[string]*JSONWebhookResponse)
+			}
+			w.Responses[strconv.Itoa(r.Status)] = &JSONWebhookResponse{Description: r.Description}
+			return nil
+		})
+		webhooks[wh.Name] = w
+		return nil
+	})
+	return webhooks
+}
+
 // GenerateResourceDefinition produces the JSON schema corresponding to the given API resource.
 // It stores the results in cachedSchema.
 func GenerateResourceDefinition(api *design.APIDefinition, r *design.ResourceDefinition) {
@@ -279,6 +353,34 @@ func GenerateTypeDefinition(api *design.APIDefinition, ut *design.UserTypeDefini
 	buildAttributeSchema(api, s, ut.AttributeDefinition)
 }
 
+// TypeFormat returns the JSON schema format of the given primitive type, empty string if there
+// isn't one.
+func TypeFormat(t design.DataType) string {
+	switch t.Kind() {
+	case design.UUIDKind:
+		return "uuid"
+	case design.DateTimeKind:
+		return "date-time"
+	case design.DateKind:
+		return "date"
+	case design.BytesKind:
+		return "byte"
+	case design.NumberKind, design.Float64Kind:
+		return "double"
+	case design.Float32Kind:
+		return "float"
+	case design.IntegerKind, design.Int64Kind:
+		return "int64"
+	case design.Int32Kind:
+		return "int32"
+	case design.UInt32Kind:
+		return "uint32"
+	case design.UInt64Kind:
+		return "uint64"
+	}
+	return ""
+}
+
 // TypeSchema produces the JSON schema corresponding to the given data type.
 func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	s := NewJSONSchema()
@@ -287,16 +389,7 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 		if name := actual.Name(); name != "any" {
 			s.Type = JSONType(actual.Name())
 		}
-		switch actual.Kind() {
-		case design.UUIDKind:
-			s.Format = "uuid"
-		case design.DateTimeKind:
-			s.Format = "date-time"
-		case design.NumberKind:
-			s.Format = "double"
-		case design.IntegerKind:
-			s.Format = "int64"
-		}
+		s.Format = TypeFormat(actual)
 	case *design.Array:
 		s.Type = JSONArray
 		s.Items = NewJSONSchema()
@@ -316,10 +409,31 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	case *design.MediaTypeDefinition:
 		// Use "default" view by default
 		s.Ref = MediaTypeRef(api, actual, design.DefaultView)
+	case *design.Union:
+		values := actual.DiscriminatorValues()
+		enum := make([]interface{}, len(values))
+		for i, v := range values {
+			enum[i] = v
+		}
+		s.Type = JSONObject
+		s.Discriminator = actual.Discriminator
+		s.Properties[actual.Discriminator] = &JSONSchema{Type: JSONString, Enum: enum}
+		s.Required = []string{actual.Discriminator}
+		for _, ut := range actual.Types {
+			ref := NewJSONSchema()
+			ref.Ref = TypeRef(api, ut)
+			s.OneOf = append(s.OneOf, ref)
+		}
 	}
 	return s
 }
 
+// AttributeSchema produces the JSON schema corresponding to the given attribute including its
+// description, default value, example and validations.
+func AttributeSchema(api *design.APIDefinition, at *design.AttributeDefinition) *JSONSchema {
+	return buildAttributeSchema(api, NewJSONSchema(), at)
+}
+
 type mergeItems []struct {
 	a, b   interface{}
 	needed bool
@@ -339,19 +453,28 @@ func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
 		{&s.Title, other.Title, s.Title == ""},
 		{&s.Media, other.Media, s.Media == nil},
 		{&s.ReadOnly, other.ReadOnly, s.ReadOnly == false},
+		{&s.Nullable, other.Nullable, s.Nullable == false},
+		{&s.Deprecated, other.Deprecated, s.Deprecated == false},
 		{&s.PathStart, other.PathStart, s.PathStart == ""},
 		{&s.Enum, other.Enum, s.Enum == nil},
+		{&s.EnumDescriptions, other.EnumDescriptions, s.EnumDescriptions == nil},
 		{&s.Format, other.Format, s.Format == ""},
 		{&s.Pattern, other.Pattern, s.Pattern == ""},
+		{&s.MultipleOf, other.MultipleOf, s.MultipleOf == nil},
+		{&s.UniqueItems, other.UniqueItems, s.UniqueItems == false},
 		{&s.AdditionalProperties, other.AdditionalProperties, s.AdditionalProperties == false},
+		{&s.OneOf, other.OneOf, s.OneOf == nil},
+		{&s.Discriminator, other.Discriminator, s.Discriminator == ""},
 		{
 			a: s.Minimum, b: other.Minimum,
 			needed: minFloat(s.Minimum, other.Minimum),
 		},
+		{&s.ExclusiveMinimum, other.ExclusiveMinimum, minFloat(s.Minimum, other.Minimum)},
 		{
 			a: s.Maximum, b: other.Maximum,
 			needed: maxFloat(s.Maximum, other.Maximum),
 		},
+		{&s.ExclusiveMaximum, other.ExclusiveMaximum, maxFloat(s.Maximum, other.Maximum)},
 		{
 			a: s.MinLength, b: other.MinLength,
 			needed: minInt(s.MinLength, other.MinLength),
@@ -368,6 +491,14 @@ func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
 			a: s.MaxItems, b: other.MaxItems,
 			needed: maxInt(s.MaxItems, other.MaxItems),
 		},
+		{
+			a: s.MinProperties, b: other.MinProperties,
+			needed: minInt(s.MinProperties, other.MinProperties),
+		},
+		{
+			a: s.MaxProperties, b: other.MaxProperties,
+			needed: maxInt(s.MaxProperties, other.MaxProperties),
+		},
 	}
 }
 
@@ -412,32 +543,58 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Schema:               s.Schema,
 		Type:                 s.Type,
 		DefaultValue:         s.DefaultValue,
+		Example:              s.Example,
 		Title:                s.Title,
 		Media:                s.Media,
 		ReadOnly:             s.ReadOnly,
+		Nullable:             s.Nullable,
+		Deprecated:           s.Deprecated,
 		PathStart:            s.PathStart,
 		Links:                s.Links,
 		Ref:                  s.Ref,
 		Enum:                 s.Enum,
+		EnumDescriptions:     s.EnumDescriptions,
 		Format:               s.Format,
 		Pattern:              s.Pattern,
 		Minimum:              s.Minimum,
 		Maximum:              s.Maximum,
+		ExclusiveMinimum:     s.ExclusiveMinimum,
+		ExclusiveMaximum:     s.ExclusiveMaximum,
+		MultipleOf:           s.MultipleOf,
 		MinLength:            s.MinLength,
 		MaxLength:            s.MaxLength,
 		MinItems:             s.MinItems,
 		MaxItems:             s.MaxItems,
+		UniqueItems:          s.UniqueItems,
+		MinProperties:        s.MinProperties,
+		MaxProperties:        s.MaxProperties,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
-	}
-	for n, p := range s.Properties {
-		js.Properties[n] = p.Dup()
+		Discriminator:        s.Discriminator,
+		DiscriminatorValue:   s.DiscriminatorValue,
+		Dependencies:         s.Dependencies,
+		AllOf:                s.AllOf,
+		AnyOf:                s.AnyOf,
+		Not:                  s.Not,
+		Webhooks:             s.Webhooks,
+	}
+	for _, o := range s.OneOf {
+		js.OneOf = append(js.OneOf, o.Dup())
+	}
+	if s.Properties != nil {
+		js.Properties = make(map[string]*JSONSchema, len(s.Properties))
+		for n, p := range s.Properties {
+			js.Properties[n] = p.Dup()
+		}
 	}
 	if s.Items != nil {
 		js.Items = s.Items.Dup()
 	}
-	for n, d := range s.Definitions {
-		js.Definitions[n] = d.Dup()
+	if s.Definitions != nil {
+		js.Definitions = make(map[string]*JSONSchema, len(s.Definitions))
+		for n, d := range s.Definitions {
+			js.Definitions[n] = d.Dup()
+		}
 	}
 	return &js
 }
@@ -459,12 +616,17 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.Description = at.Description
 	s.Example = at.GenerateExample(api.RandomGenerator(), nil)
 	s.ReadOnly = at.IsReadOnly()
+	s.Nullable = at.IsNullable()
+	s.Deprecated = at.Deprecation != nil
 	val := at.Validation
 	if val == nil {
 		return s
 	}
 	s.Enum = val.Values
-	s.Format = val.Format
+	s.EnumDescriptions = EnumDescriptions(val)
+	if val.Format != "" {
+		s.Format = val.Format
+	}
 	s.Pattern = val.Pattern
 	if val.Minimum != nil {
 		s.Minimum = val.Minimum
@@ -472,6 +634,17 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	if val.Maximum != nil {
 		s.Maximum = val.Maximum
 	}
+	// JSON schema draft 4 represents exclusive bounds with a flag on the minimum and maximum,
+	// keep the most restrictive of the inclusive and exclusive bounds.
+	if val.ExclusiveMinimum != nil && (s.Minimum == nil || *val.ExclusiveMinimum >= *s.Minimum) {
+		s.Minimum = val.ExclusiveMinimum
+		s.ExclusiveMinimum = true
+	}
+	if val.ExclusiveMaximum != nil && (s.Maximum == nil || *val.ExclusiveMaximum <= *s.Maximum) {
+		s.Maximum = val.ExclusiveMaximum
+		s.ExclusiveMaximum = true
+	}
+	s.MultipleOf = val.MultipleOf
 	if val.MinLength != nil {
 		switch {
 		case at.Type.IsArray():
@@ -488,10 +661,45 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 			s.MaxLength = val.MaxLength
 		}
 	}
+	s.UniqueItems = val.UniqueItems
+	s.MinProperties = val.MinProperties
+	s.MaxProperties = val.MaxProperties
 	s.Required = val.Required
+	buildConditionalsSchema(s, val)
 	return s
 }
 
+// buildConditionalsSchema initializes the conditional validations of the given JSON schema.
+// RequiredIf validations are described with an "anyOf" schema: either the condition does not hold
+// or the attributes are required ("if" and "then" are not part of draft 4). Dependent required
+// validations are described with "dependencies" and mutually exclusive validations with a "not"
+// schema that matches any pair of attributes of the group.
+func buildConditionalsSchema(s *JSONSchema, val *dslengine.ValidationDefinition) {
+	for _, r := range val.RequiredIf {
+		cond := NewJSONSchema()
+		cond.Properties[r.Attribute] = &JSONSchema{Enum: []interface{}{r.Value}}
+		cond.Required = []string{r.Attribute}
+		s.AllOf = append(s.AllOf, &JSONSchema{AnyOf: []*JSONSchema{
+			{Not: cond},
+			{Required: r.Required},
+		}})
+	}
+	if len(val.DependentRequired) > 0 {
+		s.Dependencies = val.DependentRequired
+	}
+	var pairs []*JSONSchema
+	for _, group := range val.MutuallyExclusive {
+		for i, n := range group {
+			for _, o := range group[i+1:] {
+				pairs = append(pairs, &JSONSchema{Required: []string{n, o}})
+			}
+		}
+	}
+	if len(pairs) > 0 {
+		s.Not = &JSONSchema{AnyOf: pairs}
+	}
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -596,3 +804,16 @@ func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinit
 	}
 	buildAttributeSchema(api, s, projected.AttributeDefinition)
 }
+
+// EnumDescriptions returns the descriptions of the enum values of the given validation in the
+// order the values are defined, nil if the values are not described.
+func EnumDescriptions(val *dslengine.ValidationDefinition) []string {
+	if len(val.ValueDescriptions) == 0 {
+		return nil
+	}
+	descs := make([]string, len(val.Values))
+	for i, v := range val.Values {
+		descs[i] = val.ValueDescriptions[v]
+	}
+	return descs
+}
@@ -78,4 +78,155 @@ var _ = Describe("TypeSchema", func() {
 		})
 
 	})
+
+	Context("with a union", func() {
+		BeforeEach(func() {
+			Type("Cat", func() {
+				Attribute("kind", design.String, func() { Enum("cat") })
+			})
+			Type("Dog", func() {
+				Attribute("kind", design.String, func() { Enum("dog") })
+			})
+			Type("Pet", func() {
+				OneOf("kind", "Cat", "Dog")
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Pet"].Type
+		})
+
+		It("returns a oneOf JSON schema type", func() {
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Discriminator).Should(Equal("kind"))
+			Ω(s.Required).Should(Equal([]string{"kind"}))
+			Ω(s.Properties).Should(HaveKey("kind"))
+			Ω(s.Properties["kind"].Enum).Should(Equal([]interface{}{"cat", "dog"}))
+			Ω(s.OneOf).Should(HaveLen(2))
+			Ω(s.OneOf[0].Ref).Should(Equal("#/definitions/Cat"))
+			Ω(s.OneOf[1].Ref).Should(Equal("#/definitions/Dog"))
+		})
+	})
+
+	Context("with an object with additional validations", func() {
+		BeforeEach(func() {
+			Type("Item", func() {
+				Attribute("price", design.Number, func() {
+					Minimum(0)
+					ExclusiveMinimum(1)
+					MultipleOf(0.5)
+				})
+				Attribute("tags", ArrayOf(design.String), func() { UniqueItems() })
+				Attribute("labels", HashOf(design.String, design.String), func() {
+					MinProperties(1)
+					MaxProperties(3)
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Item"].Type
+		})
+
+		It("renders the validations", func() {
+			Ω(s).ShouldNot(BeNil())
+			price := s.Properties["price"]
+			Ω(*price.Minimum).Should(Equal(1.0))
+			Ω(price.ExclusiveMinimum).Should(BeTrue())
+			Ω(*price.MultipleOf).Should(Equal(0.5))
+			Ω(s.Properties["tags"].UniqueItems).Should(BeTrue())
+			Ω(*s.Properties["labels"].MinProperties).Should(Equal(1))
+			Ω(*s.Properties["labels"].MaxProperties).Should(Equal(3))
+		})
+	})
+
+	Context("with an object with conditional required validations", func() {
+		BeforeEach(func() {
+			Type("Payment", func() {
+				Attribute("payment_type", design.String)
+				Attribute("card_number", design.String)
+				Attribute("email", design.String)
+				Attribute("phone", design.String)
+				RequiredIf("payment_type", "card", "card_number")
+				DependentRequired("email", "phone")
+				MutuallyExclusive("card_number", "email", "phone")
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Payment"]
+		})
+
+		It("renders the conditional validations", func() {
+			Ω(s).ShouldNot(BeNil())
+			def := genschema.Definitions["Payment"]
+			Ω(def).ShouldNot(BeNil())
+			Ω(def.AllOf).Should(HaveLen(1))
+			Ω(def.AllOf[0].AnyOf).Should(HaveLen(2))
+			Ω(def.AllOf[0].AnyOf[0].Not.Properties["payment_type"].Enum).Should(Equal([]interface{}{"card"}))
+			Ω(def.AllOf[0].AnyOf[0].Not.Required).Should(Equal([]string{"payment_type"}))
+			Ω(def.AllOf[0].AnyOf[1].Required).Should(Equal([]string{"card_number"}))
+			Ω(def.Dependencies).Should(Equal(map[string][]string{"email": {"phone"}}))
+			Ω(def.Not).ShouldNot(BeNil())
+			Ω(def.Not.AnyOf).Should(HaveLen(3))
+			Ω(def.Not.AnyOf[0].Required).Should(Equal([]string{"card_number", "email"}))
+		})
+	})
+
+	Context("with an object with described enum values", func() {
+		BeforeEach(func() {
+			Type("Bottle", func() {
+				Attribute("color", design.String, func() {
+					EnumValue("red", "Red wine")
+					EnumValue("white", "White wine")
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Bottle"].Type
+		})
+
+		It("renders the value descriptions", func() {
+			Ω(s).ShouldNot(BeNil())
+			color := s.Properties["color"]
+			Ω(color.Enum).Should(Equal([]interface{}{"red", "white"}))
+			Ω(color.EnumDescriptions).Should(Equal([]string{"Red wine", "White wine"}))
+		})
+	})
+})
+
+var _ = Describe("WebhookSchemas", func() {
+	var webhooks map[string]*genschema.JSONWebhook
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		API("test", func() {
+			Webhook("order_created", func() {
+				Description("Sent when an order is created")
+				Payload(func() {
+					Member("id", design.Integer)
+					Required("id")
+				})
+				Response(design.OK)
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		webhooks = genschema.WebhookSchemas(design.Design)
+	})
+
+	It("describes the webhooks", func() {
+		Ω(webhooks).Should(HaveLen(1))
+		wh := webhooks["order_created"]
+		Ω(wh).ShouldNot(BeNil())
+		Ω(wh.Method).Should(Equal("POST"))
+		Ω(wh.Description).Should(Equal("Sent when an order is created"))
+		Ω(wh.Schema.Ref).Should(Equal("#/definitions/OrderCreatedWebhookPayload"))
+		Ω(wh.Responses).Should(HaveKey("200"))
+	})
+
+	It("defines the payload types", func() {
+		Ω(genschema.Definitions).Should(HaveKey("OrderCreatedWebhookPayload"))
+		Ω(genschema.Definitions["OrderCreatedWebhookPayload"].Required).Should(Equal([]string{"id"}))
+	})
 })
@@ -30,6 +30,9 @@ type (
 		SecurityDefinitions map[string]*SecurityDefinition   `json:"securityDefinitions,omitempty"`
 		Tags                []*Tag                           `json:"tags,omitempty"`
 		ExternalDocs        *ExternalDocs                    `json:"externalDocs,omitempty"`
+		// Webhooks describes the webhooks sent by the API, swagger has no equivalent so they
+		// are rendered as the "x-webhooks" extension.
+		Webhooks map[string]*genschema.JSONWebhook `json:"x-webhooks,omitempty"`
 	}
 
 	// Info provides metadata about the API. The metadata can be used by the clients if needed,
@@ -452,18 +455,76 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 	if err != nil {
 		return nil, err
 	}
+	s.Webhooks = genschema.WebhookSchemas(api)
 	if len(genschema.Definitions) > 0 {
 		s.Definitions = make(map[string]*genschema.JSONSchema)
+		var unions []string
 		for n, d := range genschema.Definitions {
+			// The definitions are shared with the other generators, strip a copy.
+			d = d.Dup()
 			// sad but swagger doesn't support these
 			d.Media = nil
 			d.Links = nil
+			removeConditionals(d)
+			if d.Discriminator != "" {
+				unions = append(unions, n)
+			}
 			s.Definitions[n] = d
 		}
+		sort.Strings(unions)
+		for _, n := range unions {
+			extendUnion(s.Definitions, n)
+		}
 	}
 	return s, nil
 }
 
+// extendUnion replaces the oneOf of the union definition with the given name with definitions of
+// its variants that extend it: swagger has no oneOf and describes polymorphism with a
+// discriminator in the base definition that the variants include with allOf.
+func extendUnion(defs map[string]*genschema.JSONSchema, name string) {
+	union := defs[name]
+	var values []interface{}
+	if d, ok := union.Properties[union.Discriminator]; ok {
+		values = d.Enum
+	}
+	for i, o := range union.OneOf {
+		variant, ok := defs[strings.TrimPrefix(o.Ref, "#/definitions/")]
+		if !ok {
+			continue
+		}
+		if len(variant.AllOf) == 0 {
+			// Move the variant own schema to the allOf list.
+			own := *variant
+			*variant = genschema.JSONSchema{Title: own.Title, Description: own.Description}
+			own.Title, own.Description = "", ""
+			variant.AllOf = []*genschema.JSONSchema{&own}
+		}
+		base := genschema.NewJSONSchema()
+		base.Ref = "#/definitions/" + name
+		variant.AllOf = append([]*genschema.JSONSchema{base}, variant.AllOf...)
+		if i < len(values) {
+			variant.DiscriminatorValue = fmt.Sprintf("%v", values[i])
+		}
+	}
+	union.OneOf = nil
+}
+
+// removeConditionals removes the conditional validations that swagger does not support from the
+// given schema and its properties recursively.
+func removeConditionals(s *genschema.JSONSchema) {
+	if s == nil {
+		return
+	}
+	s.Dependencies = nil
+	s.AllOf = nil
+	s.Not = nil
+	for _, p := range s.Properties {
+		removeConditionals(p)
+	}
+	removeConditionals(s.Items)
+}
+
 // mustGenerate returns true if the metadata indicates that a Swagger specification should be
 // generated, false otherwise.
 func mustGenerate(meta dslengine.MetadataDefinition) bool {
@@ -690,6 +751,22 @@ func paramsFromHeaders(action *design.ActionDefinition) []*Parameter {
 	return params
 }
 
+// paramsFromCookies returns the parameters describing the action request cookies. Swagger 2.0 has
+// no cookie parameters so these are rendered in the "x-cookies" operation extension using the
+// OpenAPI 3 "cookie" location.
+func paramsFromCookies(action *design.ActionDefinition) []*Parameter {
+	cookies := action.AllCookies()
+	if cookies == nil {
+		return nil
+	}
+	var params []*Parameter
+	cookies.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		params = append(params, paramFor(at, n, "cookie", cookies.IsRequired(n)))
+		return nil
+	})
+	return params
+}
+
 func paramsFromPayload(payload *design.UserTypeDefinition) ([]*Parameter, error) {
 	if payload == nil {
 		return nil, nil
@@ -711,6 +788,17 @@ func paramsFromPayload(payload *design.UserTypeDefinition) ([]*Parameter, error)
 	return res, nil
 }
 
+// paramFormat returns the format of the parameters, headers and items of the given type. Only the
+// sized number types and the date and bytes types need one as the other primitive types are fully
+// described by the swagger type.
+func paramFormat(t design.DataType) string {
+	switch t.Kind() {
+	case design.IntegerKind, design.NumberKind, design.UUIDKind, design.DateTimeKind:
+		return ""
+	}
+	return genschema.TypeFormat(t)
+}
+
 func paramFor(at *design.AttributeDefinition, name, in string, required bool) *Parameter {
 	p := &Parameter{
 		In:          in,
@@ -719,16 +807,48 @@ func paramFor(at *design.AttributeDefinition, name, in string, required bool) *P
 		Description: at.Description,
 		Required:    required,
 		Type:        at.Type.Name(),
+		Format:      paramFormat(at.Type),
 	}
 	if at.Type.IsArray() {
 		p.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
-		p.CollectionFormat = "multi"
+		p.CollectionFormat = collectionFormat(at)
 	}
 	p.Extensions = extensionsFromDefinition(at.Metadata)
+	if at.ParamStyle() == design.ParamStyleDeepObject {
+		// Swagger 2.0 has no object query string parameters, the "x-style" extension uses
+		// the OpenAPI 3 style name.
+		p.Type = "string"
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["x-style"] = design.ParamStyleDeepObject
+	}
+	if at.Deprecation != nil {
+		// Swagger 2.0 parameters cannot be flagged as deprecated
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["x-deprecated"] = true
+	}
 	initValidations(at, p)
 	return p
 }
 
+// collectionFormat returns the Swagger collection format corresponding to the serialization style
+// of the given array parameter.
+func collectionFormat(at *design.AttributeDefinition) string {
+	switch at.ParamDelimiter() {
+	case "":
+		return "multi"
+	case " ":
+		return "ssv"
+	case "|":
+		return "pipes"
+	default:
+		return "csv"
+	}
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -766,7 +886,7 @@ func toString(val interface{}) string {
 }
 
 func itemsFromDefinition(at *design.AttributeDefinition) *Items {
-	items := &Items{Type: at.Type.Name()}
+	items := &Items{Type: at.Type.Name(), Format: paramFormat(at.Type)}
 	initValidations(at, items)
 	if at.Type.IsArray() {
 		items.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
@@ -786,6 +906,16 @@ func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design
 			schema.Ref = genschema.MediaTypeRef(api, mt, view)
 		}
 	}
+	extensions := extensionsFromDefinition(r.Metadata)
+	if r.IsStream() {
+		// Swagger 2.0 cannot describe event streams, the schema describes the data of each
+		// event and the "x-stream" extension flags the response.
+		schema = genschema.TypeSchema(api, r.Stream)
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions["x-stream"] = true
+	}
 	headers, err := headersFromDefinition(r.Headers)
 	if err != nil {
 		return nil, err
@@ -794,7 +924,7 @@ func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design
 		Description: r.Description,
 		Schema:      schema,
 		Headers:     headers,
-		Extensions:  extensionsFromDefinition(r.Metadata),
+		Extensions:  extensions,
 	}, nil
 }
 
@@ -822,6 +952,48 @@ func responseFromDefinition(s *Swagger, api *design.APIDefinition, r *design.Res
 	return response, nil
 }
 
+// errorSchema returns the schema of the responses rendering the given errors: the error media type
+// schema with the "code" member restricted to the error names. The "meta" member is described by
+// the error meta type if there is a single error. The "type" member and the extension members are
+// used instead if the API renders problem details.
+func errorSchema(api *design.APIDefinition, base *genschema.JSONSchema, errs []*design.ErrorDefinition) *genschema.JSONSchema {
+	code := genschema.NewJSONSchema()
+	code.Type = genschema.JSONString
+	var described bool
+	for _, e := range errs {
+		code.Enum = append(code.Enum, e.Name)
+		code.EnumDescriptions = append(code.EnumDescriptions, e.Description)
+		described = described || e.Description != ""
+	}
+	if !described {
+		code.EnumDescriptions = nil
+	}
+	ext := genschema.NewJSONSchema()
+	ext.Type = genschema.JSONObject
+	schema := genschema.NewJSONSchema()
+	schema.AllOf = []*genschema.JSONSchema{base, ext}
+	if api.ProblemDetails {
+		// Problem details render the error code in the "type" member and the metadata as
+		// extension members.
+		ext.Properties["type"] = code
+		if len(errs) == 1 && errs[0].Meta != nil {
+			schema.AllOf = append(schema.AllOf, genschema.TypeSchema(api, errs[0].Meta))
+		}
+		return schema
+	}
+	ext.Properties["code"] = code
+	if len(errs) == 1 && errs[0].Meta != nil {
+		ext.Properties["meta"] = genschema.TypeSchema(api, errs[0].Meta)
+	}
+	return schema
+}
+
+// isErrorMedia returns true if the response renders the error media type of the API.
+func isErrorMedia(api *design.APIDefinition, r *design.ResponseDefinition) bool {
+	mt := api.MediaTypeWithIdentifier(r.MediaType)
+	return mt != nil && mt.IsError()
+}
+
 func headersFromDefinition(headers *design.AttributeDefinition) (map[string]*Header, error) {
 	if headers == nil {
 		return nil, nil
@@ -836,6 +1008,7 @@ func headersFromDefinition(headers *design.AttributeDefinition) (map[string]*Hea
 			Default:     at.DefaultValue,
 			Description: at.Description,
 			Type:        at.Type.Name(),
+			Format:      paramFormat(at.Type),
 		}
 		initValidations(at, header)
 		res[n] = header
@@ -864,7 +1037,11 @@ func buildPathFromFileServer(s *Swagger, api *design.APIDefinition, fs *design.F
 		},
 	}
 	if len(wcs) > 0 {
-		schema := genschema.TypeSchema(api, design.ErrorMedia)
+		errorMedia := design.ErrorMedia
+		if api.ProblemDetails {
+			errorMedia = design.ProblemMedia
+		}
+		schema := genschema.TypeSchema(api, errorMedia)
 		responses["404"] = &Response{Description: "File not found", Schema: schema}
 	}
 
@@ -926,6 +1103,9 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		if err != nil {
 			return err
 		}
+		if errs := action.ErrorsWithStatus(r.Status); len(errs) > 0 && resp.Schema != nil && isErrorMedia(api, r) {
+			resp.Schema = errorSchema(api, resp.Schema, errs)
+		}
 		responses[strconv.Itoa(r.Status)] = resp
 	}
 
@@ -977,10 +1157,29 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		Parameters:   params,
 		Responses:    responses,
 		Schemes:      schemes,
-		Deprecated:   false,
+		Deprecated:   action.Deprecation != nil,
 		Extensions:   extensionsFromDefinition(route.Metadata),
 	}
 
+	if cookies := paramsFromCookies(action); len(cookies) > 0 {
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions["x-cookies"] = cookies
+	}
+
+	// Swagger 2.0 cannot describe websocket messages, the "x-inbound-message" and
+	// "x-outbound-message" extensions hold their schemas.
+	for ext, m := range map[string]design.DataType{"x-inbound-message": action.InboundMessage, "x-outbound-message": action.OutboundMessage} {
+		if m == nil {
+			continue
+		}
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions[ext] = genschema.TypeSchema(api, m)
+	}
+
 	if consumesMultipart {
 		operation.Consumes = append(operation.Consumes, "multipart/form-data")
 	}
@@ -1118,6 +1317,20 @@ func initEnumValidation(def interface{}, values []interface{}) {
 	}
 }
 
+// initEnumDescriptions sets the "x-enum-descriptions" extension of parameters whose enum values
+// are described.
+func initEnumDescriptions(def interface{}, val *dslengine.ValidationDefinition) {
+	p, ok := def.(*Parameter)
+	descs := genschema.EnumDescriptions(val)
+	if !ok || descs == nil {
+		return
+	}
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+	p.Extensions["x-enum-descriptions"] = descs
+}
+
 func initFormatValidation(def interface{}, format string) {
 	switch actual := def.(type) {
 	case *Parameter:
@@ -1168,6 +1381,45 @@ func initMaximumValidation(def interface{}, max *float64) {
 	}
 }
 
+func initExclusiveMinimumValidation(def interface{}, min *float64) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.Minimum = min
+		actual.ExclusiveMinimum = true
+	case *Header:
+		actual.Minimum = min
+		actual.ExclusiveMinimum = true
+	case *Items:
+		actual.Minimum = min
+		actual.ExclusiveMinimum = true
+	}
+}
+
+func initExclusiveMaximumValidation(def interface{}, max *float64) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.Maximum = max
+		actual.ExclusiveMaximum = true
+	case *Header:
+		actual.Maximum = max
+		actual.ExclusiveMaximum = true
+	case *Items:
+		actual.Maximum = max
+		actual.ExclusiveMaximum = true
+	}
+}
+
+func initMultipleOfValidation(def interface{}, multipleOf float64) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.MultipleOf = multipleOf
+	case *Header:
+		actual.MultipleOf = multipleOf
+	case *Items:
+		actual.MultipleOf = multipleOf
+	}
+}
+
 func initMinLengthValidation(def interface{}, isArray bool, min *int) {
 	switch actual := def.(type) {
 	case *Parameter:
@@ -1198,12 +1450,24 @@ func initMaxLengthValidation(def interface{}, isArray bool, max *int) {
 	}
 }
 
+func initUniqueItemsValidation(def interface{}) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.UniqueItems = true
+	case *Header:
+		actual.UniqueItems = true
+	case *Items:
+		actual.UniqueItems = true
+	}
+}
+
 func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	val := attr.Validation
 	if val == nil {
 		return
 	}
 	initEnumValidation(def, val.Values)
+	initEnumDescriptions(def, val)
 	initFormatValidation(def, val.Format)
 	initPatternValidation(def, val.Pattern)
 	if val.Minimum != nil {
@@ -1212,10 +1476,24 @@ func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	if val.Maximum != nil {
 		initMaximumValidation(def, val.Maximum)
 	}
+	// Swagger represents exclusive bounds with a flag on the minimum and maximum, keep the most
+	// restrictive of the inclusive and exclusive bounds.
+	if val.ExclusiveMinimum != nil && (val.Minimum == nil || *val.ExclusiveMinimum >= *val.Minimum) {
+		initExclusiveMinimumValidation(def, val.ExclusiveMinimum)
+	}
+	if val.ExclusiveMaximum != nil && (val.Maximum == nil || *val.ExclusiveMaximum <= *val.Maximum) {
+		initExclusiveMaximumValidation(def, val.ExclusiveMaximum)
+	}
+	if val.MultipleOf != nil {
+		initMultipleOfValidation(def, *val.MultipleOf)
+	}
 	if val.MinLength != nil {
 		initMinLengthValidation(def, attr.Type.IsArray(), val.MinLength)
 	}
 	if val.MaxLength != nil {
 		initMaxLengthValidation(def, attr.Type.IsArray(), val.MaxLength)
 	}
+	if val.UniqueItems {
+		initUniqueItemsValidation(def)
+	}
 }
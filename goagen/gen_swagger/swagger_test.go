@@ -236,6 +236,159 @@ var _ = Describe("New", func() {
 			})
 		})
 
+		Context("with webhooks", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Webhook("order_created", func() {
+						Description("Sent when an order is created")
+						Payload(func() {
+							Member("id", Integer)
+						})
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("describes the webhooks in the x-webhooks extension", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				Ω(swagger.Webhooks).Should(HaveKey("order_created"))
+				wh := swagger.Webhooks["order_created"]
+				Ω(wh.Method).Should(Equal("POST"))
+				Ω(wh.Description).Should(Equal("Sent when an order is created"))
+				Ω(wh.Schema.Ref).Should(Equal("#/definitions/OrderCreatedWebhookPayload"))
+				Ω(wh.Responses).Should(HaveKey("204"))
+				Ω(swagger.Definitions).Should(HaveKey("OrderCreatedWebhookPayload"))
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"x-webhooks":{"order_created":`),
+				})
+			})
+		})
+
+		Context("with a union type", func() {
+			BeforeEach(func() {
+				cat := Type("Cat", func() {
+					Description("A cat")
+					Attribute("kind", String, func() { Enum("cat") })
+					Attribute("lives", Integer)
+					Required("kind")
+				})
+				dog := Type("Dog", func() {
+					Attribute("kind", String, func() { Enum("dog") })
+					Required("kind")
+				})
+				pet := Type("Pet", func() {
+					OneOf("kind", cat, dog)
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(POST("/"))
+						Payload(pet)
+					})
+				})
+			})
+
+			It("makes the variants extend the union definition", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				pet := swagger.Definitions["Pet"]
+				Ω(pet).ShouldNot(BeNil())
+				Ω(pet.Discriminator).Should(Equal("kind"))
+				Ω(pet.OneOf).Should(BeEmpty())
+				cat := swagger.Definitions["Cat"]
+				Ω(cat).ShouldNot(BeNil())
+				Ω(cat.Description).Should(Equal("A cat"))
+				Ω(cat.DiscriminatorValue).Should(Equal("cat"))
+				Ω(cat.AllOf).Should(HaveLen(2))
+				Ω(cat.AllOf[0].Ref).Should(Equal("#/definitions/Pet"))
+				Ω(cat.AllOf[1].Properties).Should(HaveKey("lives"))
+				dog := swagger.Definitions["Dog"]
+				Ω(dog).ShouldNot(BeNil())
+				Ω(dog.DiscriminatorValue).Should(Equal("dog"))
+				Ω(dog.AllOf).Should(HaveLen(2))
+				Ω(dog.AllOf[0].Ref).Should(Equal("#/definitions/Pet"))
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with declared errors", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Error("not_found", func() {
+						Status(404)
+						Description("The resource does not exist")
+					})
+					Action("act", func() {
+						Routing(GET("/"))
+						Error("gone", func() {
+							Status(404)
+						})
+					})
+				})
+			})
+
+			It("restricts the error codes of the error responses", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				resp := swagger.Paths["/"].(*genswagger.Path).Get.Responses["404"]
+				Ω(resp).ShouldNot(BeNil())
+				Ω(resp.Schema.AllOf).Should(HaveLen(2))
+				Ω(resp.Schema.AllOf[0].Ref).Should(Equal("#/definitions/error"))
+				code := resp.Schema.AllOf[1].Properties["code"]
+				Ω(code.Enum).Should(Equal([]interface{}{"gone", "not_found"}))
+				Ω(code.EnumDescriptions).Should(Equal([]string{"", "The resource does not exist"}))
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with declared errors rendered as problem details", func() {
+			BeforeEach(func() {
+				Design.ProblemDetails = true
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(GET("/"))
+						Error("gone", func() {
+							Status(404)
+						})
+					})
+				})
+			})
+
+			It("uses the problem details media type", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				Ω(swagger.Definitions["error"].Title).Should(ContainSubstring(ProblemMediaIdentifier))
+				Ω(swagger.Definitions["error"].Properties).Should(HaveKey("instance"))
+				resp := swagger.Paths["/"].(*genswagger.Path).Get.Responses["404"]
+				Ω(resp).ShouldNot(BeNil())
+				Ω(resp.Schema.AllOf).Should(HaveLen(2))
+				Ω(resp.Schema.AllOf[1].Properties["type"].Enum).Should(Equal([]interface{}{"gone"}))
+				Ω(swagger.Paths["/"].(*genswagger.Path).Get.Produces).Should(ContainElement(ProblemMediaIdentifier))
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with a deprecated action and param", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Deprecated("v2", "", "")
+						Routing(
+							GET("/"),
+						)
+						Params(func() {
+							Param("sort", String, func() {
+								Deprecated("", "", "")
+							})
+						})
+					})
+				})
+			})
+
+			It("flags the operation and the param as deprecated", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"deprecated":true`),
+					[]byte(`"x-deprecated":true`),
+				})
+			})
+		})
+
 		Context("with a payload of type Any", func() {
 			BeforeEach(func() {
 				Resource("res", func() {
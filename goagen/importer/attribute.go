@@ -0,0 +1,670 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// attr describes the arguments of an Attribute, Param, Header or Cookie call.
+	attr struct {
+		// Type is the attribute type expression, nil for inline objects.
+		Type interface{}
+		// Desc is the attribute description.
+		Desc string
+		// View is the view used to render the attribute if it is a media type.
+		View string
+		// DSL lists the validations and, for inline objects, the child attributes.
+		DSL Func
+	}
+
+	// typeRef is a reference to a type or media type declaration. References are rendered
+	// with the variable of the declaration unless doing so creates an initialization cycle in
+	// which case the type name or media type identifier is used instead, see resolveRefs.
+	typeRef struct {
+		// Var is the name of the variable holding the type.
+		Var string
+		// Name is the type name or canonical media type identifier.
+		Name string
+	}
+)
+
+// args returns the arguments of the DSL call defining the attribute with the given name.
+func (a *attr) args(name string) []interface{} {
+	dsl := a.DSL
+	if a.View != "" {
+		dsl = append(Func{call("View", a.View)}, dsl...)
+	}
+	if a.Type == nil {
+		if a.Desc != "" {
+			dsl = append(Func{call("Description", a.Desc)}, dsl...)
+		}
+		return []interface{}{name, dsl}
+	}
+	args := []interface{}{name, a.Type}
+	if a.Desc != "" {
+		args = append(args, a.Desc)
+	}
+	if len(dsl) > 0 {
+		args = append(args, dsl)
+	}
+	return args
+}
+
+// elemArgs returns the arguments of the ArrayOf or HashOf call describing a collection of the
+// given attribute.
+func (a *attr) elemArgs() []interface{} {
+	dsl := a.DSL
+	if a.View != "" {
+		dsl = append(Func{call("View", a.View)}, dsl...)
+	}
+	if a.Desc != "" {
+		dsl = append(Func{call("Description", a.Desc)}, dsl...)
+	}
+	if len(dsl) > 0 {
+		return []interface{}{a.Type, dsl}
+	}
+	return []interface{}{a.Type}
+}
+
+// attribute converts the given schema. where describes the schema location in warnings and name
+// is used to name the types defined for inline objects that cannot be described inline.
+func (b *builder) attribute(where, name string, s *schema) *attr {
+	if s == nil {
+		return &attr{Type: Ident("Any")}
+	}
+	if s.Ref != "" {
+		return b.refAttribute(where, s.Ref)
+	}
+	if merged := b.flatten(where, s); merged != s {
+		a := b.attribute(where, name, merged)
+		if a.Desc == "" {
+			a.Desc = s.Description
+		}
+		return a
+	}
+	a := &attr{Desc: s.Description}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		if union := b.union(where, s); union != nil {
+			a.DSL = Func{union}
+			return a
+		}
+		b.warn("%s: anyOf and oneOf are only supported with a discriminator, using Any", where)
+		a.Type = Ident("Any")
+		return a
+	}
+	kind := s.Type.Name
+	if kind == "" && (len(s.Properties) > 0 || s.AdditionalProperties != nil) {
+		kind = "object"
+	}
+	if s.Type.Multiple {
+		b.warn("%s: multiple types are not supported, using %q", where, kind)
+	}
+	format := s.Format
+	switch kind {
+	case "string":
+		a.Type, format = b.stringType(where, format)
+	case "integer":
+		a.Type, format = b.integerType(where, format)
+	case "number":
+		a.Type, format = b.numberType(where, format)
+	case "boolean":
+		a.Type = Ident("Boolean")
+	case "file":
+		a.Type = Ident("File")
+	case "array":
+		elem := b.attribute(where+" items", name+"Item", s.Items)
+		if elem.Type == nil {
+			elem = &attr{Type: b.hoist(where+" items", name+"Item", s.Items)}
+		}
+		a.Type = call("ArrayOf", elem.elemArgs()...)
+	case "object":
+		switch {
+		case len(s.Properties) > 0:
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+				b.warn("%s: objects cannot define both properties and additional properties, ignoring additional properties", where)
+			}
+			a.DSL = b.objectDSL(where, name, s)
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+			elem := b.attribute(where+" values", name+"Value", s.AdditionalProperties.Schema)
+			if elem.Type == nil {
+				elem = &attr{Type: b.hoist(where+" values", name+"Value", s.AdditionalProperties.Schema)}
+			}
+			a.Type = call("HashOf", append([]interface{}{Ident("String")}, elem.elemArgs()...)...)
+		default:
+			a.Type = call("HashOf", Ident("String"), Ident("Any"))
+		}
+	case "":
+		a.Type = Ident("Any")
+	default:
+		b.warn("%s: type %q is not supported, using Any", where, kind)
+		a.Type = Ident("Any")
+	}
+	if format != "" {
+		a.DSL = append(a.DSL, call("Format", format))
+	}
+	a.DSL = append(a.DSL, b.validations(where, s)...)
+	return a
+}
+
+// refAttribute returns the attribute describing the definition with the given reference.
+func (b *builder) refAttribute(where, ref string) *attr {
+	def, ok := b.defs[refName(ref)]
+	if !ok {
+		b.warn("%s: reference %q cannot be resolved, using Any", where, ref)
+		return &attr{Type: Ident("Any")}
+	}
+	switch def.Kind {
+	case kindMedia:
+		return &attr{Type: b.mediaRef(def.Media), View: viewArg(def.View)}
+	case kindCollection:
+		return &attr{Type: call("CollectionOf", b.mediaRef(def.Media)), View: viewArg(def.View)}
+	case kindBuiltin:
+		return &attr{Type: def.Builtin}
+	case kindInline:
+		if b.inlining[def.Name] {
+			b.warn("%s: recursive definition %q cannot be described, using Any", where, def.Name)
+			return &attr{Type: Ident("Any")}
+		}
+		b.inlining[def.Name] = true
+		defer delete(b.inlining, def.Name)
+		return b.attribute(where, def.Name, def.Schema)
+	case kindLinks:
+		b.warn("%s: links definition %q cannot be used as a type, using Any", where, def.Name)
+		return &attr{Type: Ident("Any")}
+	}
+	return &attr{Type: b.typeRef(def)}
+}
+
+// viewArg returns the view that must be set explicitly to render a media type with the given
+// view.
+func viewArg(view string) string {
+	if view == "default" {
+		return ""
+	}
+	return view
+}
+
+// hoist defines a type for the given inline object or union schema and returns a reference to
+// it.
+func (b *builder) hoist(where, name string, s *schema) *typeRef {
+	tname := b.uniqueTypeName(codegen.Goify(name, true))
+	ref := &typeRef{Var: b.varName(tname, "Type"), Name: tname}
+	a := b.attribute(where, tname, s)
+	dsl := a.DSL
+	if a.Desc != "" {
+		dsl = append(Func{call("Description", a.Desc)}, dsl...)
+	}
+	b.hoisted = append(b.hoisted, &Decl{Name: ref.Var, Value: call("Type", tname, dsl)})
+	return ref
+}
+
+// flatten returns an object schema merging the properties of the schemas listed in the "allOf"
+// field of s that are not conditional validations. It returns s if there is nothing to merge.
+func (b *builder) flatten(where string, s *schema) *schema {
+	var parts, conds []*schema
+	for _, c := range s.AllOf {
+		if isConditional(c) {
+			conds = append(conds, c)
+		} else {
+			parts = append(parts, c)
+		}
+	}
+	if len(parts) == 0 {
+		return s
+	}
+	if len(parts) == 1 && len(conds) == 0 && parts[0].Ref != "" && len(s.Properties) == 0 && s.Type.Name == "" {
+		// allOf is commonly used to add a description to a reference.
+		return &schema{Ref: parts[0].Ref}
+	}
+	b.warn("%s: allOf is described by merging the properties of the schemas", where)
+	merged := *s
+	merged.AllOf = conds
+	merged.Type = schemaType{Name: "object"}
+	merged.Properties = make(map[string]*schema)
+	for n, p := range s.Properties {
+		merged.Properties[n] = p
+	}
+	for _, p := range parts {
+		if p.Ref != "" {
+			def, ok := b.defs[refName(p.Ref)]
+			if !ok {
+				b.warn("%s: reference %q cannot be resolved", where, p.Ref)
+				continue
+			}
+			p = def.Schema
+		}
+		if p.Ref != "" || len(p.AllOf) > 0 {
+			p = b.flatten(where, p)
+		}
+		for n, ps := range p.Properties {
+			if _, ok := merged.Properties[n]; !ok {
+				merged.Properties[n] = ps
+			}
+		}
+		merged.Required = appendUnique(merged.Required, p.Required...)
+		merged.AllOf = append(merged.AllOf, p.AllOf...)
+	}
+	return &merged
+}
+
+// union returns the OneOf call describing the given schema or nil if the schema does not use a
+// discriminator.
+func (b *builder) union(where string, s *schema) *Call {
+	if s.Discriminator == nil || len(s.AnyOf) > 0 {
+		return nil
+	}
+	args := []interface{}{s.Discriminator.PropertyName}
+	for _, o := range s.OneOf {
+		def, ok := b.defs[refName(o.Ref)]
+		if !ok || (def.Kind != kindType && def.Kind != kindMedia) {
+			b.warn("%s: union members must be references to type definitions", where)
+			return nil
+		}
+		args = append(args, b.refAttribute(where, o.Ref).Type)
+	}
+	return call("OneOf", args...)
+}
+
+// objectDSL returns the DSL describing the properties and object validations of the given schema.
+func (b *builder) objectDSL(where, name string, s *schema) Func {
+	var dsl Func
+	for _, n := range sortedKeys(s.Properties) {
+		a := b.attribute(where+"."+n, name+codegen.Goify(n, true), s.Properties[n])
+		dsl = append(dsl, call("Attribute", a.args(n)...))
+	}
+	if len(s.Required) > 0 {
+		dsl = append(dsl, call("Required", stringArgs(s.Required)...))
+	}
+	return append(dsl, b.conditionals(where, s)...)
+}
+
+// stringType returns the type of string schemas with the given format and the format validation
+// that must be applied to it if any.
+func (b *builder) stringType(where, format string) (Ident, string) {
+	switch format {
+	case "":
+		return Ident("String"), ""
+	case "date-time":
+		return Ident("DateTime"), ""
+	case "date":
+		return Ident("Date"), ""
+	case "uuid":
+		return Ident("UUID"), ""
+	case "byte":
+		return Ident("Bytes"), ""
+	case "binary":
+		return Ident("File"), ""
+	}
+	for _, f := range apidsl.SupportedValidationFormats {
+		if f == format {
+			return Ident("String"), format
+		}
+	}
+	b.warn("%s: format %q is not supported", where, format)
+	return Ident("String"), ""
+}
+
+// integerType returns the type of integer schemas with the given format.
+func (b *builder) integerType(where, format string) (Ident, string) {
+	switch format {
+	case "", "int64":
+		return Ident("Integer"), ""
+	case "int32":
+		return Ident("Int32"), ""
+	case "uint32":
+		return Ident("UInt32"), ""
+	case "uint64":
+		return Ident("UInt64"), ""
+	}
+	b.warn("%s: integer format %q is not supported", where, format)
+	return Ident("Integer"), ""
+}
+
+// numberType returns the type of number schemas with the given format.
+func (b *builder) numberType(where, format string) (Ident, string) {
+	switch format {
+	case "", "double":
+		return Ident("Number"), ""
+	case "float":
+		return Ident("Float32"), ""
+	}
+	b.warn("%s: number format %q is not supported", where, format)
+	return Ident("Number"), ""
+}
+
+// validations returns the DSL describing the validations, default value and example of the given
+// schema.
+func (b *builder) validations(where string, s *schema) Func {
+	var dsl Func
+	num := func(f float64) interface{} {
+		if s.Type.Name == "integer" && f == math.Trunc(f) {
+			return int(f)
+		}
+		return f
+	}
+	if len(s.Enum) > 0 {
+		described := false
+		for _, d := range s.EnumDescriptions {
+			described = described || d != ""
+		}
+		if described && len(s.EnumDescriptions) == len(s.Enum) {
+			for i, v := range s.Enum {
+				dsl = append(dsl, call("EnumValue", jsonValue(v, s), s.EnumDescriptions[i]))
+			}
+		} else {
+			vals := make([]interface{}, len(s.Enum))
+			for i, v := range s.Enum {
+				vals[i] = jsonValue(v, s)
+			}
+			dsl = append(dsl, call("Enum", vals...))
+		}
+	}
+	if s.Pattern != "" {
+		dsl = append(dsl, call("Pattern", s.Pattern))
+	}
+	if s.Minimum != nil {
+		if s.ExclusiveMinimum.Set && s.ExclusiveMinimum.Value == nil {
+			dsl = append(dsl, call("ExclusiveMinimum", num(*s.Minimum)))
+		} else {
+			dsl = append(dsl, call("Minimum", num(*s.Minimum)))
+		}
+	}
+	if s.ExclusiveMinimum.Value != nil {
+		dsl = append(dsl, call("ExclusiveMinimum", num(*s.ExclusiveMinimum.Value)))
+	}
+	if s.Maximum != nil {
+		if s.ExclusiveMaximum.Set && s.ExclusiveMaximum.Value == nil {
+			dsl = append(dsl, call("ExclusiveMaximum", num(*s.Maximum)))
+		} else {
+			dsl = append(dsl, call("Maximum", num(*s.Maximum)))
+		}
+	}
+	if s.ExclusiveMaximum.Value != nil {
+		dsl = append(dsl, call("ExclusiveMaximum", num(*s.ExclusiveMaximum.Value)))
+	}
+	if s.MultipleOf != nil {
+		dsl = append(dsl, call("MultipleOf", num(*s.MultipleOf)))
+	}
+	for _, l := range []*int{s.MinLength, s.MinItems} {
+		if l != nil {
+			dsl = append(dsl, call("MinLength", *l))
+		}
+	}
+	for _, l := range []*int{s.MaxLength, s.MaxItems} {
+		if l != nil {
+			dsl = append(dsl, call("MaxLength", *l))
+		}
+	}
+	if s.UniqueItems {
+		dsl = append(dsl, call("UniqueItems"))
+	}
+	if s.MinProperties != nil {
+		dsl = append(dsl, call("MinProperties", *s.MinProperties))
+	}
+	if s.MaxProperties != nil {
+		dsl = append(dsl, call("MaxProperties", *s.MaxProperties))
+	}
+	// Bytes default values and examples are byte slices which cannot be written as literals.
+	bytes := s.Type.Name == "string" && s.Format == "byte"
+	if s.Default != nil {
+		if bytes {
+			b.warn("%s: default values of byte strings are not supported", where)
+		} else {
+			dsl = append(dsl, call("Default", jsonValue(s.Default, s)))
+		}
+	}
+	if s.Example != nil && !bytes {
+		switch s.Example.(type) {
+		case string, float64, bool:
+			dsl = append(dsl, call("Example", jsonValue(s.Example, s)))
+		}
+	}
+	if s.ReadOnly {
+		dsl = append(dsl, call("ReadOnly"))
+	}
+	if s.WriteOnly {
+		b.warn("%s: writeOnly is not supported", where)
+	}
+	if s.Deprecated {
+		dsl = append(dsl, call("Deprecated", "", "", ""))
+	}
+	if s.Nullable || s.XNullable || s.Type.Nullable {
+		dsl = append(dsl, call("Nullable"))
+	}
+	if s.XML != nil {
+		b.warn("%s: XML serialization settings are not supported", where)
+	}
+	return dsl
+}
+
+// isConditional returns true if the given "allOf" member describes a conditional validation
+// either with "if" and "then" or with "anyOf" and a negated condition as produced by goagen.
+func isConditional(s *schema) bool {
+	if s.If != nil {
+		return true
+	}
+	return s.Ref == "" && len(s.Properties) == 0 && len(s.AnyOf) == 2 && s.AnyOf[0].Not != nil
+}
+
+// conditionals returns the DSL describing the conditional validations of the given object
+// schema.
+func (b *builder) conditionals(where string, s *schema) Func {
+	var dsl Func
+	requiredIf := func(cond, then *schema) bool {
+		if len(cond.Properties) != 1 || then == nil || len(then.Required) == 0 {
+			return false
+		}
+		for n, p := range cond.Properties {
+			if len(p.Enum) != 1 {
+				return false
+			}
+			val := p.Enum[0]
+			if prop, ok := s.Properties[n]; ok {
+				val = jsonValue(val, prop)
+			}
+			dsl = append(dsl, call("RequiredIf", append([]interface{}{n, val}, stringArgs(then.Required)...)...))
+		}
+		return true
+	}
+	for _, c := range s.AllOf {
+		switch {
+		case c.If != nil:
+			if requiredIf(c.If, c.Then) {
+				continue
+			}
+		case len(c.AnyOf) == 2 && c.AnyOf[0].Not != nil:
+			cond, then := c.AnyOf[0].Not, c.AnyOf[1]
+			if len(cond.Properties) == 0 && len(cond.Required) == 1 && len(then.Required) > 0 {
+				dsl = append(dsl, call("DependentRequired", append([]interface{}{cond.Required[0]}, stringArgs(then.Required)...)...))
+				continue
+			}
+			if requiredIf(cond, then) {
+				continue
+			}
+		}
+		b.warn("%s: allOf conditional validation is not supported", where)
+	}
+	if s.If != nil && !requiredIf(s.If, s.Then) {
+		b.warn("%s: if conditional validation is not supported", where)
+	}
+	for _, n := range sortedKeys(s.Dependencies) {
+		deps, ok := s.Dependencies[n].([]interface{})
+		if !ok {
+			b.warn("%s: schema dependency of %q is not supported", where, n)
+			continue
+		}
+		args := []interface{}{n}
+		for _, d := range deps {
+			args = append(args, fmt.Sprintf("%v", d))
+		}
+		dsl = append(dsl, call("DependentRequired", args...))
+	}
+	for _, n := range sortedKeys(s.DependentRequired) {
+		dsl = append(dsl, call("DependentRequired", append([]interface{}{n}, stringArgs(s.DependentRequired[n])...)...))
+	}
+	if s.Not != nil {
+		if groups := exclusiveGroups(s.Not); groups != nil {
+			for _, g := range groups {
+				dsl = append(dsl, call("MutuallyExclusive", stringArgs(g)...))
+			}
+		} else {
+			b.warn("%s: not is only supported to describe mutually exclusive properties", where)
+		}
+	}
+	return dsl
+}
+
+// exclusiveGroups returns the groups of mutually exclusive properties described by the given
+// "not" schema: "anyOf" lists the required pairs of each group in order.
+func exclusiveGroups(not *schema) [][]string {
+	if len(not.AnyOf) == 0 {
+		return nil
+	}
+	var groups [][]string
+	var current []string
+	in := func(n string) bool {
+		for _, c := range current {
+			if c == n {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range not.AnyOf {
+		if len(p.Required) != 2 {
+			return nil
+		}
+		switch {
+		case in(p.Required[0]):
+			if !in(p.Required[1]) {
+				current = append(current, p.Required[1])
+			}
+		default:
+			if current != nil {
+				groups = append(groups, current)
+			}
+			current = []string{p.Required[0], p.Required[1]}
+		}
+	}
+	return append(groups, current)
+}
+
+// jsonValue converts a JSON value to the value used in the DSL of attributes described by the
+// given schema: integral numbers are converted to ints for integer schemas.
+func jsonValue(v interface{}, s *schema) interface{} {
+	switch actual := v.(type) {
+	case float64:
+		if s != nil && s.Type.Name == "integer" && actual == math.Trunc(actual) {
+			return int(actual)
+		}
+	case []interface{}:
+		var items *schema
+		if s != nil {
+			items = s.Items
+		}
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = jsonValue(e, items)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			var prop *schema
+			if s != nil {
+				prop = s.Properties[k]
+			}
+			res[k] = jsonValue(e, prop)
+		}
+		return res
+	}
+	return v
+}
+
+// stringArgs converts a list of strings into call arguments.
+func stringArgs(vals []string) []interface{} {
+	args := make([]interface{}, len(vals))
+	for i, v := range vals {
+		args[i] = v
+	}
+	return args
+}
+
+// appendUnique appends the values that are not already in the list.
+func appendUnique(list []string, vals ...string) []string {
+	for _, v := range vals {
+		found := false
+		for _, e := range list {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// resolveRefs replaces the type references of the given declarations with the declaration
+// variables or, when that would create an initialization cycle, with the type names.
+func resolveRefs(decls []*Decl) {
+	edges := make(map[string][]string)
+	var reaches func(from, to string, seen map[string]bool) bool
+	reaches = func(from, to string, seen map[string]bool) bool {
+		if from == to {
+			return true
+		}
+		if seen[from] {
+			return false
+		}
+		seen[from] = true
+		for _, n := range edges[from] {
+			if reaches(n, to, seen) {
+				return true
+			}
+		}
+		return false
+	}
+	var resolve func(owner string, v interface{}) interface{}
+	resolve = func(owner string, v interface{}) interface{} {
+		switch actual := v.(type) {
+		case *typeRef:
+			if actual.Name == "" {
+				return Ident(actual.Var)
+			}
+			if owner != "" && reaches(actual.Var, owner, make(map[string]bool)) {
+				return actual.Name
+			}
+			if owner != "" {
+				edges[owner] = append(edges[owner], actual.Var)
+			}
+			return Ident(actual.Var)
+		case *Call:
+			for i, a := range actual.Args {
+				actual.Args[i] = resolve(owner, a)
+			}
+		case Func:
+			for _, c := range actual {
+				resolve(owner, c)
+			}
+		}
+		return v
+	}
+	for _, d := range decls {
+		resolve(d.Name, d.Value)
+	}
+}
+
+// sortedStrings returns a sorted copy of the given strings.
+func sortedStrings(vals []string) []string {
+	res := append([]string{}, vals...)
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,922 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// definitionKind describes how a schema definition is expressed with the DSL.
+type definitionKind int
+
+const (
+	// kindType is a definition described with Type.
+	kindType definitionKind = iota
+	// kindMedia is a view of a media type described with MediaType.
+	kindMedia
+	// kindCollection is a view of a collection described with CollectionOf.
+	kindCollection
+	// kindLinks describes the links of a media type.
+	kindLinks
+	// kindBuiltin is a media type defined by goa such as ErrorMedia.
+	kindBuiltin
+	// kindInline is a definition described inline where used: definitions that are not objects
+	// and the types goagen generates for inline payloads.
+	kindInline
+)
+
+type (
+	// builder builds the design package declarations from a document.
+	builder struct {
+		doc       *document
+		warnings  []string
+		defs      map[string]*definition
+		media     map[string]*mediaType
+		vars      map[string]bool
+		typeNames map[string]bool
+		refs      map[string]int
+		inlining  map[string]bool
+		hoisted   []*Decl
+		errors    map[string]*errorDef
+		problem   bool
+	}
+
+	// definition is a schema definition of the document.
+	definition struct {
+		// Name is the definition name.
+		Name string
+		// Schema is the definition schema.
+		Schema *schema
+		// Kind describes how the definition is expressed.
+		Kind definitionKind
+		// Var is the name of the variable holding the type for kindType definitions.
+		Var string
+		// Media is the media type for kindMedia and kindCollection definitions.
+		Media *mediaType
+		// View is the media type view for kindMedia and kindCollection definitions.
+		View string
+		// Builtin is the goa media type for kindBuiltin definitions.
+		Builtin Ident
+	}
+
+	// mediaType is a media type built from the definitions of its views.
+	mediaType struct {
+		// Identifier is the media type identifier.
+		Identifier string
+		// TypeName is the media type name.
+		TypeName string
+		// Var is the name of the variable holding the media type.
+		Var string
+		// Description is the media type description.
+		Description string
+		// Views lists the schemas of the views indexed by name.
+		Views map[string]*schema
+		// Links is the name of the definition describing the media type links if any.
+		Links string
+	}
+)
+
+// reserved lists the exported identifiers of the design and apidsl packages, the generated
+// variables must not use them as both packages are dot imported.
+var reserved = make(map[string]bool)
+
+func init() {
+	for _, n := range strings.Fields(`API APIDefinition APIKeySecurity APIKeySecurityKind Accepted
+		AccessCodeFlow Action ActionDefinition ActionIterator Any AnyKind ApplicationFlow Array
+		ArrayKind ArrayOf ArrayVal Async AsyncDefinition Attribute AttributeDefinition
+		AttributeIterator Attributes BadGateway BadRequest BasePath BasicAuthSecurity
+		BasicAuthSecurityKind Boolean BooleanKind ByFilePath Bytes BytesKind CONNECT
+		CORSDefinition CanonicalActionName CanonicalIdentifier CollectionOf Conflict Consumes
+		Contact ContactDefinition ContainerDefinition ContentType Continue Cookie CookieDomain
+		CookieHTTPOnly CookieMaxAge CookiePath CookieSameSite CookieSecure Cookies Created
+		Credentials DELETE DataStructure DataType Date DateKind DateTime DateTimeKind Default
+		DefaultDecoders DefaultEncoders DefaultMedia DefaultView DependentRequired Deprecated
+		DeprecationDefinition Description Design Docs DocsDefinition Dup DupAtt Email
+		EncodingDefinition Enum EnumValue Error ErrorDefinition ErrorIterator ErrorMedia
+		ErrorMediaIdentifier EventStreamMediaType Example ExclusiveMaximum ExclusiveMinimum
+		ExpectationFailed Explode Expose Extend ExtractWildcards File FileKind
+		FileServerDefinition FileServerIterator Files Float32 Float32Kind Float64 Float64Kind
+		Forbidden Format Found Function GET GatewayTimeout GeneratedMediaTypes GobContentTypes
+		Gone HEAD HTTPVersionNotSupported HasFile HasKnownEncoder Hash HashKind HashOf HashVal
+		Header HeaderIterator Headers Host ImplicitFlow InboundMessage Int32 Int32Kind Int64
+		Int64Kind Integer IntegerKind InternalServerError IsInteger IsNumber JSONContentTypes
+		JWTSecurity JWTSecurityKind JobMedia JobMediaIdentifier Kind KnownEncoderFunctions
+		KnownEncoders LengthRequired License LicenseDefinition Link LinkDefinition Links
+		LongRunning MaxAge MaxLength MaxProperties Maximum Media MediaType MediaTypeDefinition
+		MediaTypeIterator MediaTypeKind MediaTypeRoot Member Metadata MethodNotAllowed Methods
+		MinLength MinProperties Minimum MovedPermanently MultipartForm MultipleChoices
+		MultipleOf MutuallyExclusive Name NewAPIDefinition NewMediaTypeDefinition
+		NewRandomGenerator NewResourceDefinition NewUserTypeDefinition NoContent NoExample
+		NoSecurity NoSecurityKind NonAuthoritativeInfo NotAcceptable NotFound NotImplemented
+		NotModified Nullable Number NumberKind OAuth2Security OAuth2SecurityKind OK OPTIONS
+		Object ObjectKind OneOf OptionalPayload Origin OutboundMessage PATCH POST PUT Package
+		Param ParamStyleDeepObject ParamStyleForm ParamStylePipeDelimited ParameterizedTrait
+		ParamStyleSpaceDelimited Params Parent PartialContent PasswordFlow Pattern Payload
+		PaymentRequired PreconditionFailed Primitive ProblemDetails ProblemMedia
+		ProblemMediaIdentifier Produces ProjectedMediaTypes ProxyAuthRequired Query
+		RandomGenerator ReadOnly Reference RequestEntityTooLarge RequestTimeout
+		RequestURITooLong RequestedRangeNotSatisfiable Required RequiredIf ResetContent Resource
+		ResourceDefinition ResourceIterator Response ResponseDefinition ResponseIterator
+		ResponseTemplate ResponseTemplateDefinition RouteDefinition Routing Scheme Scope
+		Security SecurityDefinition SecuritySchemeDefinition SecuritySchemeKind SeeOther
+		ServiceUnavailable Status Stream String StringKind Style SupportedValidationFormats
+		SwitchingProtocols TRACE Teapot TemporaryRedirect TermsOfService Title TokenURL Trait
+		Type TypeName UInt32 UInt32Kind UInt64 UInt64Kind URL UUID UUIDKind Unauthorized Union
+		UnionKind UniqueItems UnprocessableEntity UnsupportedMediaType UseProxy UseTrait UseTraitWith
+		UserTypeDefinition UserTypeIterator UserTypeKind UserTypes Version View ViewDefinition
+		ViewIterator Webhook WebhookDefinition WebhookIterator WildcardRegex XMLContentTypes`) {
+		reserved[n] = true
+	}
+}
+
+// Import builds the design package describing the API defined by the given Swagger 2.0 or
+// OpenAPI 3 document. The document may be encoded with JSON or YAML.
+func Import(data []byte) (*Package, error) {
+	b := &builder{
+		defs:      make(map[string]*definition),
+		media:     make(map[string]*mediaType),
+		vars:      make(map[string]bool),
+		typeNames: make(map[string]bool),
+		refs:      make(map[string]int),
+		inlining:  make(map[string]bool),
+	}
+	doc, err := parse(data, b.warn)
+	if err != nil {
+		return nil, err
+	}
+	b.doc = doc
+	return &Package{Decls: b.build(), Warnings: b.warnings}, nil
+}
+
+// warn records a warning.
+func (b *builder) warn(format string, args ...interface{}) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+// build returns the declarations of the design package.
+func (b *builder) build() []*Decl {
+	b.classify()
+	var types, media []*Decl
+	for _, n := range sortedKeys(b.defs) {
+		if def := b.defs[n]; def.Kind == kindType {
+			types = append(types, b.typeDecl(def))
+		}
+	}
+	for _, id := range sortedKeys(b.media) {
+		media = append(media, b.mediaDecl(b.media[id]))
+	}
+	resources, apiDSL := b.resources()
+	decls := []*Decl{b.apiDecl(apiDSL)}
+	decls = append(decls, b.securityDecls()...)
+	decls = append(decls, types...)
+	decls = append(decls, b.hoisted...)
+	decls = append(decls, media...)
+	decls = append(decls, resources...)
+	resolveRefs(decls)
+	return decls
+}
+
+// classify initializes the definitions and media types.
+func (b *builder) classify() {
+	for _, n := range sortedKeys(b.doc.Schemas) {
+		s := b.doc.Schemas[n]
+		if s == nil {
+			s = &schema{}
+		}
+		def := &definition{Name: n, Schema: s}
+		b.defs[n] = def
+		walkRefs(s, b.countRef)
+		if strings.HasPrefix(s.Title, "Mediatype identifier: ") {
+			b.classifyMedia(def, strings.TrimPrefix(s.Title, "Mediatype identifier: "))
+		}
+	}
+	for _, op := range append(append([]*operation{}, b.doc.Operations...), b.doc.Webhooks...) {
+		for _, p := range op.Params {
+			walkRefs(p.Schema, b.countRef)
+		}
+		walkRefs(op.Body, b.countRef)
+		for _, r := range op.Responses {
+			walkRefs(r.Schema, b.countRef)
+		}
+	}
+	for _, mt := range b.media {
+		if mt.Description == mt.TypeName+" media type" {
+			mt.Description = ""
+		}
+		if def, ok := b.defs[mt.TypeName+"Links"]; ok && def.Kind == kindType {
+			def.Kind = kindLinks
+			mt.Links = def.Name
+		}
+		if def, ok := b.defs[mt.TypeName+"LinksArray"]; ok && def.Kind == kindType {
+			def.Kind = kindLinks
+		}
+	}
+	for _, n := range sortedKeys(b.defs) {
+		if def := b.defs[n]; def.Kind == kindType && !isObject(def.Schema) {
+			def.Kind = kindInline
+		}
+	}
+	// goagen generates definitions for inline payloads, multipart payloads are described with
+	// form parameters and do not reference it.
+	byName := make(map[string]*definition)
+	for _, def := range b.defs {
+		byName[alnum(def.Name)] = def
+	}
+	for _, op := range append(append([]*operation{}, b.doc.Operations...), b.doc.Webhooks...) {
+		if op.Body == nil {
+			continue
+		}
+		name := op.ID + "WebhookPayload"
+		if res, act, _ := operationName(op); !contains(b.doc.Webhooks, op) {
+			name = act + res + "Payload"
+		}
+		def, ok := byName[alnum(name)]
+		if !ok || def.Kind != kindType {
+			continue
+		}
+		if op.Body.Ref != "" && refName(op.Body.Ref) == def.Name && b.refs[def.Name] == 1 ||
+			op.Multipart && b.refs[def.Name] == 0 {
+			def.Kind = kindInline
+		}
+	}
+	for _, op := range b.doc.Operations {
+		if op.Multipart && op.Body != nil && op.Body.Ref == "" {
+			b.formType(op)
+		}
+	}
+	for _, op := range b.doc.Operations {
+		for _, code := range sortedKeys(op.Responses) {
+			if code == "default" || op.Responses[code].ContentType == design.EventStreamMediaType {
+				continue
+			}
+			s := b.inlined(op.Responses[code].Schema)
+			if s != nil && s.Type.Name == "array" && s.Items != nil {
+				s = s.Items
+			}
+			if s == nil || s.Ref == "" {
+				continue
+			}
+			if def, ok := b.defs[refName(s.Ref)]; ok && def.Kind == kindType {
+				b.promote(def)
+			}
+		}
+	}
+	for _, n := range sortedKeys(b.defs) {
+		def := b.defs[n]
+		b.typeNames[n] = true
+		if def.Kind == kindType {
+			def.Var = b.varName(codegen.Goify(n, true), "Type")
+		}
+	}
+	for _, id := range sortedKeys(b.media) {
+		mt := b.media[id]
+		b.typeNames[mt.TypeName] = true
+		name := codegen.Goify(mt.TypeName, true)
+		if !strings.HasSuffix(name, "Media") {
+			name += "Media"
+		}
+		mt.Var = b.varName(name, "Type")
+	}
+}
+
+// formType sets the body of the given multipart operation to a reference to the definition
+// goagen generated for its payload type if there is one. Multipart payloads are described with
+// form parameters which do not reference the definition, the definition is found by comparing
+// the properties.
+func (b *builder) formType(op *operation) {
+	props := strings.Join(sortedKeys(op.Body.Properties), ",")
+	required := strings.Join(sortedStrings(op.Body.Required), ",")
+	var match *definition
+	for _, n := range sortedKeys(b.defs) {
+		def := b.defs[n]
+		if def.Kind != kindType || b.refs[n] > 0 {
+			continue
+		}
+		if strings.Join(sortedKeys(def.Schema.Properties), ",") != props ||
+			strings.Join(sortedStrings(def.Schema.Required), ",") != required {
+			continue
+		}
+		if match != nil {
+			return
+		}
+		match = def
+	}
+	if match != nil {
+		op.Body = &schema{Ref: "#/definitions/" + match.Name}
+		b.refs[match.Name]++
+	}
+}
+
+// classifyMedia initializes a definition generated by goagen for a media type view given the
+// identifier of the projected media type.
+func (b *builder) classifyMedia(def *definition, identifier string) {
+	base, params, err := mime.ParseMediaType(identifier)
+	if err != nil {
+		b.warn("definition %q: invalid media type identifier %q", def.Name, identifier)
+		return
+	}
+	view := params["view"]
+	if view == "" {
+		view = design.DefaultView
+	}
+	delete(params, "view")
+	canonical := design.CanonicalIdentifier(mime.FormatMediaType(base, params))
+	for id, builtin := range map[string]Ident{
+		design.ErrorMediaIdentifier:   "ErrorMedia",
+		design.ProblemMediaIdentifier: "ProblemMedia",
+		design.JobMediaIdentifier:     "JobMedia",
+	} {
+		if canonical == design.CanonicalIdentifier(id) {
+			def.Kind = kindBuiltin
+			def.Builtin = builtin
+			b.problem = b.problem || builtin == "ProblemMedia"
+			return
+		}
+	}
+	def.View = view
+	if params["type"] == "collection" {
+		delete(params, "type")
+		def.Kind = kindCollection
+		def.Media = b.mediaType(mime.FormatMediaType(base, params))
+		return
+	}
+	mt := b.mediaType(mime.FormatMediaType(base, params))
+	def.Kind = kindMedia
+	def.Media = mt
+	mt.Views[view] = def.Schema
+	if view == design.DefaultView || mt.TypeName == "" {
+		mt.TypeName = strings.TrimSuffix(def.Name, strings.Title(view))
+		if view == design.DefaultView {
+			mt.TypeName = def.Name
+		}
+		mt.Description = strings.TrimSuffix(def.Schema.Description, " ("+view+" view)")
+	}
+}
+
+// mediaType returns the media type with the given identifier, creating it if needed.
+func (b *builder) mediaType(identifier string) *mediaType {
+	canonical := design.CanonicalIdentifier(identifier)
+	if mt, ok := b.media[canonical]; ok {
+		return mt
+	}
+	mt := &mediaType{Identifier: identifier, Views: make(map[string]*schema)}
+	b.media[canonical] = mt
+	return mt
+}
+
+// promote describes a definition used by responses with a media type.
+func (b *builder) promote(def *definition) {
+	base := "application/vnd." + strings.ToLower(def.Name)
+	identifier := base + "+json"
+	for i := 2; b.media[design.CanonicalIdentifier(identifier)] != nil; i++ {
+		identifier = fmt.Sprintf("%s%d+json", base, i)
+	}
+	b.warn("definition %q is used in responses and is described as media type %q", def.Name, identifier)
+	mt := b.mediaType(identifier)
+	mt.TypeName = def.Name
+	mt.Description = def.Schema.Description
+	mt.Views[design.DefaultView] = def.Schema
+	def.Kind = kindMedia
+	def.Media = mt
+	def.View = design.DefaultView
+}
+
+// inlined returns the schema of the definition referenced by s if the definition is described
+// inline, s otherwise.
+func (b *builder) inlined(s *schema) *schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	if def, ok := b.defs[refName(s.Ref)]; ok && def.Kind == kindInline {
+		return def.Schema
+	}
+	return s
+}
+
+// countRef records a reference to a definition.
+func (b *builder) countRef(ref string) {
+	b.refs[refName(ref)]++
+}
+
+// varName returns a unique variable name built from name. suffix is appended to the names that
+// clash with the identifiers of the design packages.
+func (b *builder) varName(name, suffix string) string {
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "T" + name
+	}
+	if reserved[name] {
+		name += suffix
+	}
+	v := name
+	for i := 2; b.vars[v] || reserved[v]; i++ {
+		v = fmt.Sprintf("%s%d", name, i)
+	}
+	b.vars[v] = true
+	return v
+}
+
+// uniqueTypeName returns a type name built from name that is not used by other types.
+func (b *builder) uniqueTypeName(name string) string {
+	n := name
+	for i := 2; b.typeNames[n]; i++ {
+		n = fmt.Sprintf("%s%d", name, i)
+	}
+	b.typeNames[n] = true
+	return n
+}
+
+// typeRef returns a reference to the type of the given definition.
+func (b *builder) typeRef(def *definition) *typeRef {
+	return &typeRef{Var: def.Var, Name: def.Name}
+}
+
+// mediaRef returns a reference to the given media type.
+func (b *builder) mediaRef(mt *mediaType) *typeRef {
+	return &typeRef{Var: mt.Var, Name: design.CanonicalIdentifier(mt.Identifier)}
+}
+
+// apiDecl returns the API declaration, dsl lists the API level errors and webhooks.
+func (b *builder) apiDecl(dsl Func) *Decl {
+	doc := b.doc
+	var api Func
+	if doc.Title != "" {
+		api = append(api, call("Title", doc.Title))
+	}
+	if doc.Description != "" {
+		api = append(api, call("Description", doc.Description))
+	}
+	if doc.Version != "" {
+		api = append(api, call("Version", doc.Version))
+	}
+	if doc.TermsOfService != "" {
+		api = append(api, call("TermsOfService", doc.TermsOfService))
+	}
+	if c := doc.Contact; c != nil {
+		api = append(api, call("Contact", nonEmpty(Func{call("Name", c.Name), call("Email", c.Email), call("URL", c.URL)})))
+	}
+	if l := doc.License; l != nil {
+		api = append(api, call("License", nonEmpty(Func{call("Name", l.Name), call("URL", l.URL)})))
+	}
+	if doc.Docs != nil {
+		api = append(api, docsCall(doc.Docs))
+	}
+	if doc.Host != "" {
+		api = append(api, call("Host", doc.Host))
+	}
+	if len(doc.Schemes) > 0 {
+		api = append(api, call("Scheme", stringArgs(doc.Schemes)...))
+	}
+	if doc.BasePath != "" && doc.BasePath != "/" {
+		api = append(api, call("BasePath", pathDSL(doc.BasePath)))
+	}
+	if c := b.encodings("consumes", doc.Consumes, design.DefaultDecoders); c != nil {
+		api = append(api, call("Consumes", c...))
+	}
+	if p := b.encodings("produces", doc.Produces, design.DefaultEncoders); p != nil {
+		api = append(api, call("Produces", p...))
+	}
+	if sec := b.security("API", doc.Security); sec != nil {
+		api = append(api, sec)
+	}
+	if b.problem {
+		api = append(api, call("ProblemDetails"))
+	}
+	api = append(api, dsl...)
+	api = append(api, extensionsDSL(doc.Extensions)...)
+	return &Decl{Value: call("API", apiName(doc.Title), api)}
+}
+
+// encodings returns the arguments of the Consumes or Produces call describing the given MIME
+// types or nil if the MIME types are the default ones.
+func (b *builder) encodings(field string, mimeTypes []string, defaults []*design.EncodingDefinition) []interface{} {
+	if len(mimeTypes) == 0 {
+		return nil
+	}
+	var def []string
+	for _, enc := range defaults {
+		def = append(def, enc.MIMETypes...)
+	}
+	if strings.Join(sortedStrings(def), ",") == strings.Join(sortedStrings(mimeTypes), ",") {
+		return nil
+	}
+	var args []interface{}
+	for _, m := range mimeTypes {
+		if _, ok := design.KnownEncoders[m]; !ok {
+			b.warn("%s: MIME type %q has no known encoder, ignoring it", field, m)
+			continue
+		}
+		args = append(args, m)
+	}
+	return args
+}
+
+// securityDecls returns the declarations of the security schemes.
+func (b *builder) securityDecls() []*Decl {
+	var decls []*Decl
+	for _, n := range sortedKeys(b.doc.SecuritySchemes) {
+		s := b.doc.SecuritySchemes[n]
+		var dsl Func
+		if s.Description != "" {
+			dsl = append(dsl, call("Description", s.Description))
+		}
+		var fn string
+		switch s.Kind {
+		case "basic":
+			fn = "BasicAuthSecurity"
+		case "apiKey", "jwt":
+			fn = "APIKeySecurity"
+			if s.Kind == "jwt" {
+				fn = "JWTSecurity"
+			}
+			switch s.In {
+			case "header":
+				dsl = append(dsl, call("Header", s.Name))
+			case "query":
+				dsl = append(dsl, call("Query", s.Name))
+			default:
+				b.warn("security scheme %q: location %q is not supported", n, s.In)
+				continue
+			}
+			if s.TokenURL != "" {
+				dsl = append(dsl, call("TokenURL", s.TokenURL))
+			}
+		case "oauth2":
+			fn = "OAuth2Security"
+			switch s.Flow {
+			case "accessCode":
+				dsl = append(dsl, call("AccessCodeFlow", s.AuthorizationURL, s.TokenURL))
+			case "implicit":
+				dsl = append(dsl, call("ImplicitFlow", s.AuthorizationURL))
+			case "password":
+				dsl = append(dsl, call("PasswordFlow", s.TokenURL))
+			case "application":
+				dsl = append(dsl, call("ApplicationFlow", s.TokenURL))
+			default:
+				b.warn("security scheme %q: OAuth2 flow %q is not supported", n, s.Flow)
+				continue
+			}
+		default:
+			continue
+		}
+		for _, scope := range sortedKeys(s.Scopes) {
+			args := []interface{}{scope}
+			if desc := s.Scopes[scope]; desc != "" {
+				args = append(args, desc)
+			}
+			dsl = append(dsl, call("Scope", args...))
+		}
+		args := []interface{}{n}
+		if len(dsl) > 0 {
+			args = append(args, dsl)
+		}
+		decls = append(decls, &Decl{Value: call(fn, args...)})
+	}
+	return decls
+}
+
+// security returns the Security or NoSecurity call describing the given requirements or nil if
+// there are none.
+func (b *builder) security(where string, reqs []map[string][]string) *Call {
+	if reqs == nil {
+		return nil
+	}
+	if len(reqs) == 0 || (len(reqs) == 1 && len(reqs[0]) == 0) {
+		return call("NoSecurity")
+	}
+	if len(reqs) > 1 || len(reqs[0]) > 1 {
+		b.warn("%s: only one security requirement can be described, using the first one", where)
+	}
+	name := sortedKeys(reqs[0])[0]
+	if _, ok := b.doc.SecuritySchemes[name]; !ok {
+		b.warn("%s: security scheme %q is not defined", where, name)
+		return nil
+	}
+	scopes := reqs[0][name]
+	if len(scopes) == 0 {
+		return call("Security", name)
+	}
+	var dsl Func
+	for _, s := range scopes {
+		dsl = append(dsl, call("Scope", s))
+	}
+	return call("Security", name, dsl)
+}
+
+// typeDecl returns the declaration of the type described by the given definition.
+func (b *builder) typeDecl(def *definition) *Decl {
+	where := fmt.Sprintf("definition %q", def.Name)
+	s := b.flatten(where, def.Schema)
+	var dsl Func
+	if s.Description != "" {
+		dsl = append(dsl, call("Description", s.Description))
+	}
+	if union := b.typeUnion(where, s, false); union != nil {
+		dsl = append(dsl, union)
+	} else {
+		dsl = append(dsl, b.objectDSL(where, def.Name, s)...)
+		dsl = append(dsl, b.validations(where, s)...)
+	}
+	return &Decl{Name: def.Var, Value: call("Type", def.Name, dsl)}
+}
+
+// typeUnion returns the OneOf call describing the union type defined by s if any. goagen
+// describes unions in Swagger with the discriminator property only, the union members are the
+// definitions whose discriminator value is listed in the discriminator property enum. media is
+// true if the union is a media type.
+func (b *builder) typeUnion(where string, s *schema, media bool) *Call {
+	if s.Discriminator == nil {
+		return nil
+	}
+	if len(s.OneOf) > 0 {
+		return b.union(where, s)
+	}
+	disc := s.Discriminator.PropertyName
+	prop, ok := s.Properties[disc]
+	if !ok || len(prop.Enum) == 0 {
+		b.warn("%s: discriminator %q values are not listed, the union members cannot be found", where, disc)
+		return nil
+	}
+	args := []interface{}{disc}
+	for _, v := range prop.Enum {
+		var member *definition
+		for _, n := range sortedKeys(b.defs) {
+			def := b.defs[n]
+			if def.Kind != kindType && def.Kind != kindMedia {
+				continue
+			}
+			p, ok := def.Schema.Properties[disc]
+			if !ok || len(p.Enum) != 1 || p.Enum[0] != v {
+				continue
+			}
+			// Prefer the media types members for media type unions and the types members for
+			// type unions when both define the same discriminator value.
+			if member == nil || isMedia(def) == media && isMedia(member) != media {
+				member = def
+			}
+		}
+		if member == nil {
+			member = b.defs[fmt.Sprintf("%v", v)]
+		}
+		if member == nil {
+			b.warn("%s: no definition matches discriminator value %v", where, v)
+			return nil
+		}
+		args = append(args, b.refAttribute(where, "#/definitions/"+member.Name).Type)
+	}
+	return call("OneOf", args...)
+}
+
+// mediaDecl returns the declaration of the given media type.
+func (b *builder) mediaDecl(mt *mediaType) *Decl {
+	where := fmt.Sprintf("media type %q", mt.Identifier)
+	var views []string
+	for v := range mt.Views {
+		if v != design.DefaultView {
+			views = append(views, v)
+		}
+	}
+	sort.Strings(views)
+	if _, ok := mt.Views[design.DefaultView]; ok {
+		views = append([]string{design.DefaultView}, views...)
+	}
+	isLinks := func(p *schema) bool {
+		return mt.Links != "" && p.Ref != "" && refName(p.Ref) == mt.Links
+	}
+
+	var (
+		props    = make(map[string]*schema)
+		order    []string
+		required []string
+		primary  *schema
+	)
+	for _, v := range views {
+		s := b.flatten(where, mt.Views[v])
+		mt.Views[v] = s
+		if primary == nil {
+			primary = s
+		}
+		for _, n := range sortedKeys(s.Properties) {
+			if p := s.Properties[n]; !isLinks(p) {
+				if _, ok := props[n]; !ok {
+					props[n] = p
+					order = append(order, n)
+				}
+			}
+		}
+		required = appendUnique(required, s.Required...)
+	}
+
+	var dsl Func
+	if mt.Description != "" {
+		dsl = append(dsl, call("Description", mt.Description))
+	}
+	if mt.TypeName != mediaTypeName(mt.Identifier) {
+		dsl = append(dsl, call("TypeName", mt.TypeName))
+	}
+	if union := b.typeUnion(where, primary, true); union != nil {
+		return &Decl{Name: mt.Var, Value: call("MediaType", mt.Identifier, append(dsl, union))}
+	}
+
+	var attrs Func
+	for _, n := range order {
+		a := b.attribute(where+"."+n, mt.TypeName+codegen.Goify(n, true), props[n])
+		a.View = ""
+		attrs = append(attrs, call("Attribute", a.args(n)...))
+	}
+	var links Func
+	if ldef, ok := b.defs[mt.Links]; ok {
+		for _, n := range sortedKeys(ldef.Schema.Properties) {
+			p := ldef.Schema.Properties[n]
+			def, ok := b.defs[refName(p.Ref)]
+			if !ok || def.Kind != kindMedia {
+				b.warn("%s: link %q does not reference a media type", where, n)
+				continue
+			}
+			args := []interface{}{n}
+			if def.View != "link" {
+				args = append(args, def.View)
+			}
+			links = append(links, call("Link", args...))
+			if _, ok := props[n]; !ok {
+				attrs = append(attrs, call("Attribute", n, b.mediaRef(def.Media)))
+			}
+		}
+	}
+	if len(required) > 0 {
+		attrs = append(attrs, call("Required", stringArgs(required)...))
+	}
+	if primary != nil {
+		attrs = append(attrs, b.conditionals(where, primary)...)
+		attrs = append(attrs, b.validations(where, primary)...)
+	}
+	dsl = append(dsl, call("Attributes", attrs))
+	if len(links) > 0 {
+		dsl = append(dsl, call("Links", links))
+	}
+	if _, ok := mt.Views[design.DefaultView]; !ok {
+		var view Func
+		for _, n := range order {
+			view = append(view, call("Attribute", n))
+		}
+		dsl = append(dsl, call("View", design.DefaultView, view))
+	}
+	for _, v := range views {
+		var view Func
+		s := mt.Views[v]
+		for _, n := range sortedKeys(s.Properties) {
+			p := s.Properties[n]
+			if pv := b.viewOf(p); pv != "" && pv != design.DefaultView && !isLinks(p) {
+				view = append(view, call("Attribute", n, Func{call("View", pv)}))
+				continue
+			}
+			view = append(view, call("Attribute", n))
+		}
+		dsl = append(dsl, call("View", v, view))
+	}
+	return &Decl{Name: mt.Var, Value: call("MediaType", mt.Identifier, dsl)}
+}
+
+// viewOf returns the view of the media type referenced by the given schema if any.
+func (b *builder) viewOf(s *schema) string {
+	if s.Ref == "" {
+		return ""
+	}
+	if def, ok := b.defs[refName(s.Ref)]; ok && (def.Kind == kindMedia || def.Kind == kindCollection) {
+		return def.View
+	}
+	return ""
+}
+
+// mediaTypeName returns the default type name of the media type with the given identifier, see
+// apidsl.MediaType.
+func mediaTypeName(identifier string) string {
+	base, _, err := mime.ParseMediaType(identifier)
+	if err != nil {
+		base = identifier
+	}
+	last := base[strings.LastIndex(base, "/")+1:]
+	if i := strings.Index(last, "+"); i > 0 {
+		last = last[:i]
+	}
+	elems := strings.Split(strings.TrimPrefix(last, "vnd."), ".")
+	for i, e := range elems {
+		elems[i] = strings.Title(e)
+	}
+	return strings.Join(elems, "")
+}
+
+// isMedia returns true if the given definition describes a media type: either a media type view
+// or a type whose name ends with "Media" as goagen describes the members of media type unions
+// with types.
+func isMedia(def *definition) bool {
+	return def.Kind == kindMedia || strings.HasSuffix(def.Name, "Media")
+}
+
+// isObject returns true if the definition schema can be described with Type.
+func isObject(s *schema) bool {
+	if len(s.Properties) > 0 || len(s.AllOf) > 0 || len(s.OneOf) > 0 || s.Discriminator != nil {
+		return true
+	}
+	return s.Type.Name == "object" && s.AdditionalProperties == nil
+}
+
+// walkRefs calls fn with each reference used by the given schema.
+func walkRefs(s *schema, fn func(string)) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		fn(s.Ref)
+	}
+	walkRefs(s.Items, fn)
+	for _, p := range s.Properties {
+		walkRefs(p, fn)
+	}
+	if s.AdditionalProperties != nil {
+		walkRefs(s.AdditionalProperties.Schema, fn)
+	}
+	for _, l := range [][]*schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, c := range l {
+			walkRefs(c, fn)
+		}
+	}
+}
+
+// contains returns true if ops contains op.
+func contains(ops []*operation, op *operation) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// alnum returns the lower case letters and digits of s.
+func alnum(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// docsCall returns the Docs call describing the given external documentation.
+func docsCall(d *externalDocs) *Call {
+	return call("Docs", nonEmpty(Func{call("Description", d.Description), call("URL", d.URL)}))
+}
+
+// nonEmpty removes the calls whose single argument is an empty string.
+func nonEmpty(dsl Func) Func {
+	var res Func
+	for _, c := range dsl {
+		if len(c.Args) == 1 && c.Args[0] == "" {
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// extensionsDSL returns the metadata describing the given Swagger extensions.
+func extensionsDSL(exts map[string]interface{}) Func {
+	var dsl Func
+	for _, k := range sortedKeys(exts) {
+		val, err := json.Marshal(exts[k])
+		if err != nil {
+			continue
+		}
+		dsl = append(dsl, call("Metadata", "swagger:extension:"+k, string(val)))
+	}
+	return dsl
+}
+
+// pathDSL converts a path template into a goa path: "{id}" becomes ":id".
+func pathDSL(path string) string {
+	var res []string
+	for _, s := range strings.Split(path, "/") {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			s = ":" + s[1:len(s)-1]
+		}
+		res = append(res, s)
+	}
+	return strings.Join(res, "/")
+}
+
+// apiName returns the name of the API with the given title.
+func apiName(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "api"
+	}
+	return strings.Join(words, "_")
+}
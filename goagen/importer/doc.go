@@ -0,0 +1,15 @@
+/*
+Package importer builds a goa design package from a Swagger 2.0 or OpenAPI 3 document.
+It makes it possible to adopt goa for existing APIs whose only description is a specification
+file: the generated package uses the design/apidsl functions to describe the API, its resources,
+actions, types, media types and security schemes.
+
+Some constructs of the specifications cannot be expressed with the DSL, for example "anyOf"
+schemas or operations that accept multiple security requirements. The importer approximates or
+ignores them and reports each occurrence with a warning so that the design can be reviewed.
+
+Documents produced by goagen (see the genswagger and genopenapi packages) are recognized: the
+media type views, collections, links, errors and inline payloads they describe are converted
+back to the corresponding DSL.
+*/
+package importer
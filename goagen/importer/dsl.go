@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Package is the result of an import: the top-level declarations of the design package and
+	// the warnings describing the parts of the document that could not be expressed with the
+	// DSL.
+	Package struct {
+		// Decls lists the package declarations in order.
+		Decls []*Decl
+		// Warnings lists the constructs of the document that were ignored or approximated.
+		Warnings []string
+	}
+
+	// Decl is a top-level declaration of the design package: "var Name = Value". Declarations
+	// with an empty name are rendered with the blank identifier.
+	Decl struct {
+		// Name is the Go variable name.
+		Name string
+		// Value is the DSL function call initializing the variable.
+		Value *Call
+	}
+
+	// Call is a call to a DSL function. The arguments may be strings, ints, float64s, bools,
+	// identifiers, nested calls, anonymous functions or arbitrary JSON values (maps, slices and
+	// nil) rendered as Go literals.
+	Call struct {
+		// Func is the name of the DSL function.
+		Func string
+		// Args lists the call arguments.
+		Args []interface{}
+	}
+
+	// Ident is a reference to a package declaration or to an exported identifier of the goa
+	// design package, e.g. String or OK.
+	Ident string
+
+	// Func is an anonymous function whose body consists of DSL calls.
+	Func []*Call
+)
+
+// Source returns the formatted Go source code of the design package with the given name.
+func (p *Package) Source(pkg string) ([]byte, error) {
+	decls := make(map[string]bool)
+	for _, d := range p.Decls {
+		if d.Name != "" {
+			decls[d.Name] = true
+		}
+	}
+	var body bytes.Buffer
+	usesDesign := false
+	for _, d := range p.Decls {
+		name := d.Name
+		if name == "" {
+			name = "_"
+		}
+		fmt.Fprintf(&body, "var %s = ", name)
+		writeValue(&body, d.Value, func(id Ident) {
+			if !decls[string(id)] {
+				usesDesign = true
+			}
+		})
+		body.WriteString("\n\n")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n", pkg)
+	if usesDesign {
+		buf.WriteString("\t. \"github.com/goadesign/goa/design\"\n")
+	}
+	buf.WriteString("\t. \"github.com/goadesign/goa/design/apidsl\"\n)\n\n")
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+// writeValue writes the Go literal corresponding to v. seen is called for each identifier.
+func writeValue(buf *bytes.Buffer, v interface{}, seen func(Ident)) {
+	switch actual := v.(type) {
+	case nil:
+		buf.WriteString("nil")
+	case string:
+		writeString(buf, actual)
+	case bool:
+		buf.WriteString(strconv.FormatBool(actual))
+	case int:
+		buf.WriteString(strconv.Itoa(actual))
+	case float64:
+		s := strconv.FormatFloat(actual, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEn") {
+			// Make sure the literal is a float64 when passed as an interface{}
+			s += ".0"
+		}
+		buf.WriteString(s)
+	case Ident:
+		seen(actual)
+		buf.WriteString(string(actual))
+	case *Call:
+		buf.WriteString(actual.Func)
+		buf.WriteByte('(')
+		for i, a := range actual.Args {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeValue(buf, a, seen)
+		}
+		buf.WriteByte(')')
+	case Func:
+		buf.WriteString("func() {\n")
+		for _, c := range actual {
+			writeValue(buf, c, seen)
+			buf.WriteByte('\n')
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteString("[]interface{}{")
+		for i, e := range actual {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeValue(buf, e, seen)
+		}
+		buf.WriteByte('}')
+	case []string:
+		buf.WriteString("[]string{")
+		for i, e := range actual {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeString(buf, e)
+		}
+		buf.WriteByte('}')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(actual))
+		for k := range actual {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteString("map[string]interface{}{")
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeString(buf, k)
+			buf.WriteString(": ")
+			writeValue(buf, actual[k], seen)
+		}
+		buf.WriteByte('}')
+	default:
+		// JSON values only use the types above
+		panic(fmt.Sprintf("importer: unexpected value %#v", v)) // bug
+	}
+}
+
+// writeString writes a Go string literal, multiline strings use raw string literals.
+func writeString(buf *bytes.Buffer, s string) {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "`") && !strings.Contains(s, "\r") {
+		buf.WriteString("`" + s + "`")
+		return
+	}
+	buf.WriteString(strconv.Quote(s))
+}
+
+// call is a helper that builds a DSL call.
+func call(fn string, args ...interface{}) *Call {
+	return &Call{Func: fn, Args: args}
+}
@@ -0,0 +1,85 @@
+package importer_test
+
+import (
+	"github.com/goadesign/goa/goagen/importer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Package", func() {
+	Describe("Source", func() {
+		var (
+			pkg *importer.Package
+			src []byte
+			err error
+		)
+
+		call := func(fn string, args ...interface{}) *importer.Call {
+			return &importer.Call{Func: fn, Args: args}
+		}
+
+		JustBeforeEach(func() {
+			src, err = pkg.Source("design")
+		})
+
+		Context("with declarations using the design package", func() {
+			BeforeEach(func() {
+				pkg = &importer.Package{Decls: []*importer.Decl{
+					{Value: call("API", "api", importer.Func{call("Title", "The \"API\"")})},
+					{Name: "Bottle", Value: call("Type", "Bottle", importer.Func{
+						call("Attribute", "name", importer.Ident("String"), importer.Func{
+							call("Enum", "a", "b"),
+							call("Minimum", 1.0),
+							call("Default", map[string]interface{}{"b": []interface{}{1, true}, "a": nil}),
+						}),
+						call("Required", "name"),
+					})},
+					{Name: "Cellar", Value: call("Type", "Cellar", importer.Func{
+						call("Attribute", "bottles", call("ArrayOf", importer.Ident("Bottle"))),
+					})},
+				}}
+			})
+
+			It("renders the declarations", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(src)).Should(Equal(`package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("api", func() {
+	Title("The \"API\"")
+})
+
+var Bottle = Type("Bottle", func() {
+	Attribute("name", String, func() {
+		Enum("a", "b")
+		Minimum(1.0)
+		Default(map[string]interface{}{"a": nil, "b": []interface{}{1, true}})
+	})
+	Required("name")
+})
+
+var Cellar = Type("Cellar", func() {
+	Attribute("bottles", ArrayOf(Bottle))
+})
+`))
+			})
+		})
+
+		Context("with declarations using the apidsl package only", func() {
+			BeforeEach(func() {
+				pkg = &importer.Package{Decls: []*importer.Decl{
+					{Value: call("API", "api", importer.Func{call("Host", "example.com")})},
+				}}
+			})
+
+			It("does not import the design package", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(src)).ShouldNot(ContainSubstring(`"github.com/goadesign/goa/design"`))
+			})
+		})
+	})
+})
@@ -0,0 +1,48 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Importer writes the design package describing the API defined by a Swagger 2.0 or OpenAPI 3
+// document.
+type Importer struct {
+	// Spec is the path to the Swagger or OpenAPI document encoded in JSON or YAML.
+	Spec string
+	// OutDir is the directory where the design package directory is created.
+	OutDir string
+	// Package is the name of the design package.
+	Package string
+	// Warnings lists the constructs of the document that could not be expressed with the DSL
+	// once Import returns.
+	Warnings []string
+}
+
+// Import generates the design package and returns the list of generated files.
+func (i *Importer) Import() ([]string, error) {
+	data, err := ioutil.ReadFile(i.Spec)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := Import(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", i.Spec, err)
+	}
+	i.Warnings = pkg.Warnings
+	src, err := pkg.Source(i.Package)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format design package: %s", err)
+	}
+	dir := filepath.Join(i.OutDir, i.Package)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, "design.go")
+	if err := ioutil.WriteFile(file, src, 0644); err != nil {
+		return nil, err
+	}
+	return []string{file}, nil
+}
@@ -0,0 +1,546 @@
+package importer_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	"github.com/goadesign/goa/goagen/importer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Import", func() {
+	var (
+		data      []byte
+		pkg       *importer.Package
+		warnErr   error
+		workspace *codegen.Workspace
+	)
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		pkg, warnErr = importer.Import(data)
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	// run compiles the imported design package together with a program that runs its DSL and
+	// prints the output selected by the given argument, see designMainCode.
+	run := func(arg string) []byte {
+		src, err := pkg.Source("design")
+		Ω(err).ShouldNot(HaveOccurred())
+		dpkg, err := workspace.NewPackage("design")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(ioutil.WriteFile(filepath.Join(dpkg.Abs(), "design.go"), src, 0644)).Should(Succeed())
+		mpkg, err := workspace.NewPackage("main")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(ioutil.WriteFile(filepath.Join(mpkg.Abs(), "main.go"), []byte(designMainCode), 0644)).Should(Succeed())
+		bin, err := mpkg.Compile("design")
+		Ω(err).ShouldNot(HaveOccurred())
+		out, err := exec.Command(bin, arg).CombinedOutput()
+		Ω(err).ShouldNot(HaveOccurred(), string(out))
+		return out
+	}
+
+	Context("with a document generated by goagen", func() {
+		var (
+			generate func() interface{}
+			output   string
+		)
+
+		// spec runs the current DSL and returns the document produced by generate encoded in
+		// JSON without the randomly generated examples.
+		spec := func() []byte {
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			genschema.Definitions = make(map[string]*genschema.JSONSchema)
+			b, err := json.Marshal(generate())
+			Ω(err).ShouldNot(HaveOccurred())
+			return stripExamples(b)
+		}
+
+		BeforeEach(func() {
+			dslengine.Reset()
+			cellarDesign()
+		})
+
+		roundTrip := func() {
+			It("produces an equivalent design", func() {
+				Ω(warnErr).ShouldNot(HaveOccurred())
+				Ω(pkg.Warnings).Should(BeEmpty())
+				Ω(stripExamples(run(output))).Should(MatchJSON(data))
+			})
+		}
+
+		Context("in Swagger", func() {
+			BeforeEach(func() {
+				output = "swagger"
+				generate = func() interface{} {
+					s, err := genswagger.New(Design)
+					Ω(err).ShouldNot(HaveOccurred())
+					return s
+				}
+				data = spec()
+			})
+
+			roundTrip()
+		})
+
+		Context("in OpenAPI", func() {
+			BeforeEach(func() {
+				output = "openapi"
+				generate = func() interface{} {
+					s, err := genopenapi.New(Design)
+					Ω(err).ShouldNot(HaveOccurred())
+					return s
+				}
+				data = spec()
+			})
+
+			roundTrip()
+		})
+	})
+
+	Context("with an OpenAPI document", func() {
+		BeforeEach(func() {
+			data = []byte(petstore)
+		})
+
+		It("describes the API", func() {
+			Ω(warnErr).ShouldNot(HaveOccurred())
+			src, err := pkg.Source("design")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(src)).Should(ContainSubstring(`var _ = API("swagger_petstore", func() {`))
+			Ω(string(src)).Should(ContainSubstring(`BasePath("/v1")`))
+			Ω(string(src)).Should(ContainSubstring(`var PetMedia = MediaType("application/vnd.pet+json", func() {`))
+			Ω(string(src)).Should(ContainSubstring(`var _ = Resource("pets", func() {`))
+			Ω(string(src)).Should(ContainSubstring(`Action("listPets", func() {`))
+			Ω(string(src)).Should(ContainSubstring(`Response(OK, CollectionOf(PetMedia), func() {`))
+			Ω(string(src)).Should(ContainSubstring(`Param("petId", String, "The id of the pet to retrieve")`))
+		})
+
+		It("produces a valid design", func() {
+			Ω(run("actions")).Should(MatchJSON(`{"pets":3}`))
+		})
+
+		It("reports the constructs that cannot be described", func() {
+			Ω(pkg.Warnings).Should(ConsistOf(
+				`definition "Pet" is used in responses and is described as media type "application/vnd.pet+json"`,
+				`media type "application/vnd.pet+json".tag: anyOf and oneOf are only supported with a discriminator, using Any`,
+				`GET /pets: default responses are not supported`,
+				`POST /pets: default responses are not supported`,
+				`GET /pets/{petId}: default responses are not supported`,
+			))
+		})
+	})
+
+	Context("with an unsupported version", func() {
+		BeforeEach(func() {
+			data = []byte(`{"swagger": "1.2"}`)
+		})
+
+		It("returns an error", func() {
+			Ω(warnErr).Should(MatchError(`unsupported Swagger version "1.2"`))
+		})
+	})
+
+	Context("with a document that is not a specification", func() {
+		BeforeEach(func() {
+			data = []byte(`{"title": "foo"}`)
+		})
+
+		It("returns an error", func() {
+			Ω(warnErr).Should(MatchError("invalid document: missing swagger or openapi version"))
+		})
+	})
+})
+
+// cellarDesign defines the API used by the round trip tests.
+func cellarDesign() {
+	API("cellar", func() {
+		Title("The virtual wine cellar")
+		Description("A basic example of a CRUD API")
+		Version("1.0")
+		Host("cellar.example.com")
+		Scheme("http", "https")
+		BasePath("/cellar")
+		Contact(func() {
+			Name("goa")
+			Email("goa@example.com")
+		})
+		License(func() {
+			Name("MIT")
+			URL("https://opensource.org/licenses/MIT")
+		})
+		Error("rate_limited", func() {
+			Status(429)
+			Description("Too many requests")
+		})
+	})
+	BasicAuthSecurity("basic", func() {
+		Description("Basic auth")
+	})
+	JWTSecurity("jwt", func() {
+		Header("Authorization")
+		TokenURL("https://example.com/token")
+		Scope("api:read", "Read access")
+	})
+	payload := Type("BottlePayload", func() {
+		Attribute("name", String, func() {
+			MinLength(2)
+		})
+		Attribute("vintage", Integer, func() {
+			Minimum(1900)
+			Maximum(2100)
+		})
+		Attribute("color", String, func() {
+			Enum("red", "white", "rose")
+		})
+		Attribute("tags", ArrayOf(String), func() {
+			MaxLength(5)
+		})
+		Attribute("ratings", HashOf(String, Integer))
+		Attribute("origin", func() {
+			Attribute("country", String)
+			Attribute("region", String, func() {
+				Pattern("^[A-Z]")
+			})
+			Required("country")
+		})
+		Required("name", "vintage")
+	})
+	account := MediaType("application/vnd.goa.example.account+json", func() {
+		Description("A tenant account")
+		Attributes(func() {
+			Attribute("id", Integer, "ID of account")
+			Attribute("href", String, "API href of account")
+			Attribute("name", String, "Name of account")
+			Required("id", "href", "name")
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("href")
+			Attribute("name")
+		})
+		View("tiny", func() {
+			Attribute("id")
+			Attribute("href")
+		})
+		View("link", func() {
+			Attribute("id")
+			Attribute("href")
+		})
+	})
+	bottle := MediaType("application/vnd.goa.example.bottle+json", func() {
+		Description("A bottle of wine")
+		Reference(payload)
+		Attributes(func() {
+			Attribute("id", Integer, "ID of bottle")
+			Attribute("name")
+			Attribute("vintage")
+			Attribute("account", account)
+			Required("id", "name", "account")
+		})
+		Links(func() {
+			Link("account")
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("name")
+			Attribute("vintage")
+			Attribute("links")
+		})
+		View("full", func() {
+			Attribute("id")
+			Attribute("name")
+			Attribute("vintage")
+			Attribute("account", func() {
+				View("tiny")
+			})
+			Attribute("links")
+		})
+	})
+	Resource("bottle", func() {
+		BasePath("/bottles")
+		Files("/docs/*filepath", "public/docs")
+		Action("list", func() {
+			Description("List the bottles")
+			Routing(GET(""), GET("/all"))
+			Params(func() {
+				Param("years", ArrayOf(Integer))
+				Param("sort", String, func() {
+					Enum("name", "vintage")
+					Default("name")
+				})
+			})
+			Response(OK, CollectionOf(bottle))
+		})
+		Action("show", func() {
+			Routing(GET("/:bottleID"))
+			Params(func() {
+				Param("bottleID", Integer, "Bottle ID")
+			})
+			Error("bottle_not_found", func() {
+				Status(404)
+				Description("The bottle does not exist")
+			})
+			Response(OK, func() {
+				Media(bottle, "full")
+			})
+		})
+		Action("create", func() {
+			Routing(POST(""))
+			Security("jwt", func() {
+				Scope("api:read")
+			})
+			Headers(func() {
+				Header("X-Request-Id", String)
+				Required("X-Request-Id")
+			})
+			Payload(payload)
+			Response(Created, func() {
+				Headers(func() {
+					Header("Location", String, "Bottle href")
+				})
+			})
+			Response(BadRequest, ErrorMedia)
+		})
+		Action("rate", func() {
+			Routing(PUT("/:bottleID/actions/rate"))
+			Security("basic")
+			Params(func() {
+				Param("bottleID", Integer)
+			})
+			Payload(func() {
+				Attribute("rating", Integer, func() {
+					Minimum(1)
+					Maximum(5)
+				})
+				Required("rating")
+			})
+			Response(NoContent)
+			Response(NotFound)
+		})
+		Action("import", func() {
+			Routing(POST("/import"))
+			Async()
+		})
+	})
+	Webhook("bottle_created", func() {
+		Description("Sent when a bottle is created")
+		Payload(func() {
+			Attribute("id", Integer)
+			Required("id")
+		})
+		Response(OK)
+	})
+}
+
+// stripExamples removes the randomly generated examples from the given JSON document.
+func stripExamples(b []byte) []byte {
+	var doc interface{}
+	Ω(json.Unmarshal(b, &doc)).ShouldNot(HaveOccurred())
+	b, err := json.Marshal(withoutExamples(doc))
+	Ω(err).ShouldNot(HaveOccurred())
+	return b
+}
+
+// withoutExamples removes the examples from the given JSON value.
+func withoutExamples(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		delete(actual, "example")
+		delete(actual, "examples")
+		for k, e := range actual {
+			actual[k] = withoutExamples(e)
+		}
+	case []interface{}:
+		for i, e := range actual {
+			actual[i] = withoutExamples(e)
+		}
+	}
+	return v
+}
+
+// designMainCode is the program compiled together with the imported design package. It runs
+// the DSL and prints the Swagger or OpenAPI specification or the number of actions of each
+// resource depending on its argument.
+const designMainCode = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	_ "design"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+func main() {
+	if err := dslengine.Run(); err != nil {
+		fail(err)
+	}
+	var (
+		doc interface{}
+		err error
+	)
+	switch os.Args[1] {
+	case "swagger":
+		doc, err = genswagger.New(design.Design)
+	case "openapi":
+		doc, err = genopenapi.New(design.Design)
+	case "actions":
+		actions := make(map[string]int)
+		for n, r := range design.Design.Resources {
+			actions[n] = len(r.Actions)
+		}
+		doc = actions
+	}
+	if err != nil {
+		fail(err)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Print(string(b))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+`
+
+const petstore = `openapi: 3.0.0
+info:
+  title: Swagger Petstore
+  version: 1.0.0
+servers:
+  - url: http://petstore.swagger.io/v1
+paths:
+  /pets:
+    get:
+      summary: List all pets
+      operationId: listPets
+      tags:
+        - pets
+      parameters:
+        - name: limit
+          in: query
+          description: How many items to return at one time (max 100)
+          schema:
+            type: integer
+            format: int32
+            maximum: 100
+      responses:
+        '200':
+          description: A paged array of pets
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pets"
+        default:
+          description: unexpected error
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Error"
+    post:
+      summary: Create a pet
+      operationId: createPets
+      tags:
+        - pets
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: Null response
+        default:
+          description: unexpected error
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Error"
+  /pets/{petId}:
+    get:
+      summary: Info for a specific pet
+      operationId: showPetById
+      tags:
+        - pets
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          description: The id of the pet to retrieve
+          schema:
+            type: string
+      responses:
+        '200':
+          description: Expected response to a valid request
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        default:
+          description: unexpected error
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Error"
+components:
+  schemas:
+    Pet:
+      type: object
+      required:
+        - id
+        - name
+      properties:
+        id:
+          type: integer
+          format: int64
+        name:
+          type: string
+        tag:
+          anyOf:
+            - type: string
+            - type: integer
+    Pets:
+      type: array
+      items:
+        $ref: "#/components/schemas/Pet"
+    Error:
+      type: object
+      required:
+        - code
+        - message
+      properties:
+        code:
+          type: integer
+          format: int32
+        message:
+          type: string
+`
@@ -0,0 +1,546 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type (
+	// openapiDoc is an OpenAPI 3 document.
+	openapiDoc struct {
+		Info         *info                                 `json:"info"`
+		Servers      []*openapiServer                      `json:"servers"`
+		Paths        map[string]map[string]json.RawMessage `json:"paths"`
+		Components   *openapiComponents                    `json:"components"`
+		Security     []map[string][]string                 `json:"security"`
+		ExternalDocs *externalDocs                         `json:"externalDocs"`
+		Webhooks     map[string]map[string]json.RawMessage `json:"webhooks"`
+		XWebhooks    map[string]map[string]json.RawMessage `json:"x-webhooks"`
+	}
+
+	// openapiServer is an OpenAPI 3 server.
+	openapiServer struct {
+		URL       string `json:"url"`
+		Variables map[string]*struct {
+			Default string `json:"default"`
+		} `json:"variables"`
+	}
+
+	// openapiComponents holds the OpenAPI 3 reusable objects.
+	openapiComponents struct {
+		Schemas         map[string]*schema                `json:"schemas"`
+		Parameters      map[string]*openapiParam          `json:"parameters"`
+		RequestBodies   map[string]*openapiRequestBody    `json:"requestBodies"`
+		Responses       map[string]*openapiResponse       `json:"responses"`
+		Headers         map[string]*openapiHeader         `json:"headers"`
+		SecuritySchemes map[string]*openapiSecurityScheme `json:"securitySchemes"`
+	}
+
+	// openapiOperation is an OpenAPI 3 operation.
+	openapiOperation struct {
+		Tags         []string                    `json:"tags"`
+		Summary      string                      `json:"summary"`
+		Description  string                      `json:"description"`
+		ExternalDocs *externalDocs               `json:"externalDocs"`
+		OperationID  string                      `json:"operationId"`
+		Parameters   []*openapiParam             `json:"parameters"`
+		RequestBody  *openapiRequestBody         `json:"requestBody"`
+		Responses    map[string]*openapiResponse `json:"responses"`
+		Callbacks    interface{}                 `json:"callbacks"`
+		Deprecated   bool                        `json:"deprecated"`
+		Security     *[]map[string][]string      `json:"security"`
+		Servers      []*openapiServer            `json:"servers"`
+		Extensions   extensionsMap               `json:"-"`
+	}
+
+	// openapiParam is an OpenAPI 3 parameter.
+	openapiParam struct {
+		Ref         string        `json:"$ref"`
+		Name        string        `json:"name"`
+		In          string        `json:"in"`
+		Description string        `json:"description"`
+		Required    bool          `json:"required"`
+		Deprecated  bool          `json:"deprecated"`
+		Style       string        `json:"style"`
+		Explode     *bool         `json:"explode"`
+		Schema      *schema       `json:"schema"`
+		Content     interface{}   `json:"content"`
+		Extensions  extensionsMap `json:"-"`
+	}
+
+	// openapiRequestBody is an OpenAPI 3 request body.
+	openapiRequestBody struct {
+		Ref         string                       `json:"$ref"`
+		Description string                       `json:"description"`
+		Required    bool                         `json:"required"`
+		Content     map[string]*openapiMediaType `json:"content"`
+	}
+
+	// openapiMediaType is an OpenAPI 3 media type.
+	openapiMediaType struct {
+		Schema *schema `json:"schema"`
+	}
+
+	// openapiResponse is an OpenAPI 3 response.
+	openapiResponse struct {
+		Ref         string                       `json:"$ref"`
+		Description string                       `json:"description"`
+		Headers     map[string]*openapiHeader    `json:"headers"`
+		Content     map[string]*openapiMediaType `json:"content"`
+		Links       interface{}                  `json:"links"`
+		Extensions  extensionsMap                `json:"-"`
+	}
+
+	// openapiHeader is an OpenAPI 3 header.
+	openapiHeader struct {
+		Ref         string  `json:"$ref"`
+		Description string  `json:"description"`
+		Required    bool    `json:"required"`
+		Schema      *schema `json:"schema"`
+	}
+
+	// openapiSecurityScheme is an OpenAPI 3 security scheme.
+	openapiSecurityScheme struct {
+		Type         string `json:"type"`
+		Description  string `json:"description"`
+		Name         string `json:"name"`
+		In           string `json:"in"`
+		Scheme       string `json:"scheme"`
+		BearerFormat string `json:"bearerFormat"`
+		Flows        map[string]*struct {
+			AuthorizationURL string            `json:"authorizationUrl"`
+			TokenURL         string            `json:"tokenUrl"`
+			Scopes           map[string]string `json:"scopes"`
+		} `json:"flows"`
+	}
+)
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *openapiOperation) UnmarshalJSON(b []byte) error {
+	type _openapiOperation openapiOperation
+	if err := json.Unmarshal(b, (*_openapiOperation)(o)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &o.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *openapiParam) UnmarshalJSON(b []byte) error {
+	type _openapiParam openapiParam
+	if err := json.Unmarshal(b, (*_openapiParam)(p)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &p.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *openapiResponse) UnmarshalJSON(b []byte) error {
+	type _openapiResponse openapiResponse
+	if err := json.Unmarshal(b, (*_openapiResponse)(r)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &r.Extensions)
+}
+
+// openapiFlows maps the OpenAPI 3 OAuth2 flow names to the Swagger 2.0 ones.
+var openapiFlows = map[string]string{
+	"implicit":          "implicit",
+	"password":          "password",
+	"clientCredentials": "application",
+	"authorizationCode": "accessCode",
+}
+
+// parseOpenAPI decodes an OpenAPI 3 document.
+func parseOpenAPI(b []byte, warn func(string, ...interface{})) (*document, error) {
+	var oa openapiDoc
+	if err := json.Unmarshal(b, &oa); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %s", err)
+	}
+	if oa.Components == nil {
+		oa.Components = &openapiComponents{}
+	}
+	doc := &document{
+		Docs:            oa.ExternalDocs,
+		Security:        oa.Security,
+		Schemas:         oa.Components.Schemas,
+		SecuritySchemes: make(map[string]*securityScheme),
+	}
+	setInfo(doc, oa.Info)
+	doc.Host, doc.BasePath, doc.Schemes = servers(oa.Servers, "", warn)
+	for _, n := range sortedKeys(oa.Components.SecuritySchemes) {
+		if s := openapiSecurity(n, oa.Components.SecuritySchemes[n], warn); s != nil {
+			doc.SecuritySchemes[n] = s
+		}
+	}
+
+	p := &openapiParser{doc: &oa, warn: warn}
+	ops, err := p.operations(oa.Paths, "path")
+	if err != nil {
+		return nil, err
+	}
+	doc.Operations = ops
+	doc.Consumes = consumes(ops)
+	webhooks := oa.Webhooks
+	if webhooks == nil {
+		webhooks = oa.XWebhooks
+	}
+	whs, err := p.operations(webhooks, "webhook")
+	if err != nil {
+		return nil, err
+	}
+	for _, wh := range whs {
+		if wh.Method != "POST" {
+			warn("webhook %q: only POST webhooks are supported", wh.Path)
+			continue
+		}
+		wh.ID = wh.Path
+		doc.Webhooks = append(doc.Webhooks, wh)
+	}
+	return doc, nil
+}
+
+// consumes returns the content types accepted by all the request bodies of the given operations
+// other than multipart bodies or nil if they differ. OpenAPI lists the content types of each
+// request body while goa defines them for the whole API.
+func consumes(ops []*operation) []string {
+	var res []string
+	for _, op := range ops {
+		if op.Body == nil || op.Multipart {
+			continue
+		}
+		if res == nil {
+			res = op.BodyTypes
+			continue
+		}
+		if strings.Join(res, ",") != strings.Join(op.BodyTypes, ",") {
+			return nil
+		}
+	}
+	return res
+}
+
+// openapiParser converts the OpenAPI 3 paths into operations.
+type openapiParser struct {
+	doc  *openapiDoc
+	warn func(string, ...interface{})
+}
+
+// operations returns the operations of the given path items sorted by path and method.
+func (p *openapiParser) operations(paths map[string]map[string]json.RawMessage, kind string) ([]*operation, error) {
+	var ops []*operation
+	for _, path := range sortedKeys(paths) {
+		if strings.HasPrefix(path, "x-") {
+			p.warn("%s extension %q is not supported", kind, path)
+			continue
+		}
+		item := paths[path]
+		var shared []*openapiParam
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("invalid parameters of %s %q: %s", kind, path, err)
+			}
+		}
+		for _, key := range sortedKeys(item) {
+			if !isVerb(key) {
+				switch {
+				case key == "parameters", key == "summary", key == "description":
+				case strings.HasPrefix(key, "x-"):
+					p.warn("%s %q: extension %q is not supported", kind, path, key)
+				default:
+					p.warn("%s %q: %q is not supported", kind, path, key)
+				}
+				continue
+			}
+			var oo openapiOperation
+			if err := json.Unmarshal(item[key], &oo); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %s", strings.ToUpper(key), path, err)
+			}
+			op, err := p.operation(path, key, &oo, shared)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+// operation converts an OpenAPI 3 operation.
+func (p *openapiParser) operation(path, method string, oo *openapiOperation, shared []*openapiParam) (*operation, error) {
+	op := &operation{
+		Path:        path,
+		Method:      strings.ToUpper(method),
+		ID:          oo.OperationID,
+		Tags:        oo.Tags,
+		Summary:     oo.Summary,
+		Description: oo.Description,
+		Docs:        oo.ExternalDocs,
+		Deprecated:  oo.Deprecated,
+		Security:    oo.Security,
+		Extensions:  extensions(oo.Extensions),
+		Responses:   make(map[string]*response),
+	}
+	where := fmt.Sprintf("%s %s", op.Method, path)
+	if oo.Callbacks != nil {
+		p.warn("%s: callbacks are not supported", where)
+	}
+	if len(oo.Servers) > 0 {
+		_, _, schemes := servers(oo.Servers, where, p.warn)
+		op.Schemes = schemes
+	}
+
+	for _, op2 := range append(append([]*openapiParam{}, shared...), oo.Parameters...) {
+		op2 = p.resolveParam(op2)
+		if op2 == nil {
+			continue
+		}
+		if op2.Content != nil {
+			p.warn("%s: parameter %q content is not supported", where, op2.Name)
+		}
+		s := op2.Schema
+		if s == nil {
+			s = &schema{Type: schemaType{Name: "string"}}
+		}
+		param := &parameter{
+			Name:        op2.Name,
+			In:          op2.In,
+			Description: op2.Description,
+			Required:    op2.Required || op2.In == "path",
+			Deprecated:  op2.Deprecated,
+			Style:       op2.Style,
+			Explode:     op2.Explode,
+			Schema:      s,
+			Extensions:  extensions(op2.Extensions),
+		}
+		switch op2.In {
+		case "path", "query", "header", "cookie":
+		default:
+			p.warn("%s: parameter %q has unknown location %q", where, op2.Name, op2.In)
+			continue
+		}
+		if param.In != "query" && (param.Style != "" || param.Explode != nil) {
+			p.warn("%s: %s parameter %q serialization style is not supported", where, param.In, param.Name)
+			param.Style, param.Explode = "", nil
+		}
+		if param.Style == "form" {
+			param.Style = ""
+		}
+		for i, existing := range op.Params {
+			if existing.Name == param.Name && existing.In == param.In {
+				op.Params = append(op.Params[:i], op.Params[i+1:]...)
+				break
+			}
+		}
+		op.Params = append(op.Params, param)
+	}
+
+	if body := oo.RequestBody; body != nil {
+		if body.Ref != "" {
+			name := strings.TrimPrefix(body.Ref, "#/components/requestBodies/")
+			body = p.doc.Components.RequestBodies[name]
+			if body == nil || name == oo.RequestBody.Ref {
+				p.warn("%s: request body reference %q cannot be resolved", where, oo.RequestBody.Ref)
+			}
+		}
+		if body != nil {
+			op.BodyDesc = body.Description
+			op.BodyReq = body.Required
+			for _, ct := range sortedKeys(body.Content) {
+				mt := body.Content[ct]
+				if ct == "multipart/form-data" {
+					op.Multipart = true
+				}
+				op.BodyTypes = append(op.BodyTypes, ct)
+				if mt == nil || mt.Schema == nil {
+					continue
+				}
+				if op.Body == nil {
+					op.Body = mt.Schema
+				} else if !sameSchema(op.Body, mt.Schema) {
+					p.warn("%s: request body content %q schema differs from other content types, ignoring it", where, ct)
+				}
+			}
+			if op.Multipart && len(op.BodyTypes) > 1 {
+				p.warn("%s: multipart request bodies cannot be described with other content types", where)
+			}
+		}
+	}
+
+	for _, code := range sortedKeys(oo.Responses) {
+		or := oo.Responses[code]
+		if or.Ref != "" {
+			name := strings.TrimPrefix(or.Ref, "#/components/responses/")
+			ref := p.doc.Components.Responses[name]
+			if ref == nil || name == or.Ref {
+				p.warn("%s: response %s reference %q cannot be resolved", where, code, or.Ref)
+				continue
+			}
+			or = ref
+		}
+		if or.Links != nil {
+			p.warn("%s: response %s links are not supported", where, code)
+		}
+		resp := &response{Description: or.Description, Extensions: extensions(or.Extensions)}
+		types := sortedKeys(or.Content)
+		for _, ct := range types {
+			mt := or.Content[ct]
+			if resp.ContentType == "" {
+				resp.ContentType = ct
+				if mt != nil {
+					resp.Schema = mt.Schema
+				}
+				continue
+			}
+			if mt != nil && resp.Schema != nil && mt.Schema != nil && !sameSchema(resp.Schema, mt.Schema) {
+				p.warn("%s: response %s content %q differs from %q, ignoring it", where, code, ct, resp.ContentType)
+			}
+		}
+		if len(or.Headers) > 0 {
+			resp.Headers = make(map[string]*header)
+			for n, h := range or.Headers {
+				if h.Ref != "" {
+					name := strings.TrimPrefix(h.Ref, "#/components/headers/")
+					ref := p.doc.Components.Headers[name]
+					if ref == nil || name == h.Ref {
+						p.warn("%s: response %s header reference %q cannot be resolved", where, code, h.Ref)
+						continue
+					}
+					h = ref
+				}
+				s := h.Schema
+				if s == nil {
+					s = &schema{Type: schemaType{Name: "string"}}
+				}
+				resp.Headers[n] = &header{Description: h.Description, Required: h.Required, Schema: s}
+			}
+		}
+		op.Responses[code] = resp
+	}
+	return op, nil
+}
+
+// resolveParam returns the parameter referenced by param if any, param otherwise.
+func (p *openapiParser) resolveParam(param *openapiParam) *openapiParam {
+	if param.Ref == "" {
+		return param
+	}
+	name := strings.TrimPrefix(param.Ref, "#/components/parameters/")
+	ref := p.doc.Components.Parameters[name]
+	if ref == nil || name == param.Ref {
+		p.warn("parameter reference %q cannot be resolved", param.Ref)
+		return nil
+	}
+	return ref
+}
+
+// servers returns the host, base path and schemes described by the given servers. goa designs
+// define a single host and base path so only the first server host and path are used.
+func servers(svrs []*openapiServer, where string, warn func(string, ...interface{})) (host, basePath string, schemes []string) {
+	prefix := ""
+	if where != "" {
+		prefix = where + ": "
+	}
+	seen := make(map[string]bool)
+	for i, s := range svrs {
+		raw := s.URL
+		for n, v := range s.Variables {
+			if v == nil {
+				continue
+			}
+			raw = strings.Replace(raw, "{"+n+"}", v.Default, -1)
+		}
+		if raw != s.URL {
+			warn("%sserver URL %q variables are replaced with their default values", prefix, s.URL)
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			warn("%sinvalid server URL %q", prefix, s.URL)
+			continue
+		}
+		path := strings.TrimSuffix(u.Path, "/")
+		if i == 0 {
+			host, basePath = u.Host, path
+		} else if u.Host != host || path != basePath {
+			warn("%sserver %q is ignored, only one host and base path can be described", prefix, s.URL)
+			continue
+		}
+		scheme := u.Scheme
+		if !seen[scheme] {
+			seen[scheme] = true
+			schemes = append(schemes, scheme)
+		}
+	}
+	if len(schemes) == 1 && schemes[0] == "" {
+		// Relative server URL
+		schemes = nil
+	}
+	sort.Strings(schemes)
+	return
+}
+
+// openapiSecurity converts an OpenAPI 3 security scheme.
+func openapiSecurity(name string, s *openapiSecurityScheme, warn func(string, ...interface{})) *securityScheme {
+	res := &securityScheme{Description: s.Description, Name: s.Name, In: s.In}
+	switch s.Type {
+	case "http":
+		switch strings.ToLower(s.Scheme) {
+		case "basic":
+			res.Kind = "basic"
+		case "bearer":
+			res.Kind = "jwt"
+			res.Name = "Authorization"
+			res.In = "header"
+			if s.BearerFormat != "" && s.BearerFormat != "JWT" {
+				warn("security scheme %q: bearer format %q is described as a JWT", name, s.BearerFormat)
+			}
+		default:
+			warn("security scheme %q: HTTP authentication scheme %q is not supported", name, s.Scheme)
+			return nil
+		}
+	case "apiKey":
+		res.Kind = "apiKey"
+		if strings.Contains(s.Description, "**Token URL**: ") {
+			res.Kind = "jwt"
+		}
+		if s.In == "cookie" {
+			warn("security scheme %q: cookie API keys are not supported", name)
+			return nil
+		}
+	case "oauth2":
+		res.Kind = "oauth2"
+		for _, fn := range sortedKeys(s.Flows) {
+			f := s.Flows[fn]
+			if res.Flow != "" {
+				warn("security scheme %q: only one OAuth2 flow can be described, ignoring flow %q", name, fn)
+				continue
+			}
+			res.Flow = openapiFlows[fn]
+			res.AuthorizationURL = f.AuthorizationURL
+			res.TokenURL = f.TokenURL
+			res.Scopes = f.Scopes
+		}
+	default:
+		warn("security scheme %q: type %q is not supported", name, s.Type)
+		return nil
+	}
+	if res.Kind == "jwt" {
+		res.Description, res.TokenURL, res.Scopes = parseJWTDescription(res.Description)
+	}
+	return res
+}
+
+// sameSchema returns true if the two schemas have the same JSON representation.
+func sameSchema(a, b *schema) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
@@ -0,0 +1,793 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// resource groups the operations of a resource.
+	resource struct {
+		Name    string
+		Actions []*action
+		Files   []*operation
+		Errors  []*errorDef
+		actions map[string]*action
+	}
+
+	// action groups the operations implementing the routes of an action.
+	action struct {
+		Name     string
+		Resource *resource
+		Ops      []*operation
+		Errors   []*errorDef
+		// Async is the LongRunning or Async call if the action starts jobs.
+		Async *Call
+	}
+
+	// errorDef describes an error returned by actions.
+	errorDef struct {
+		Name        string
+		Description string
+		Status      int
+		Meta        *schema
+		// Owner is the design, resource or action that declares the error.
+		Owner interface{}
+	}
+)
+
+// statusNames lists the names of the standard responses indexed by status.
+var statusNames = make(map[int]string)
+
+func init() {
+	for n, r := range design.NewAPIDefinition().DefaultResponses {
+		statusNames[r.Status] = n
+	}
+}
+
+// resources returns the declarations of the resources and the DSL describing the API level
+// errors and webhooks.
+func (b *builder) resources() ([]*Decl, Func) {
+	var list []*resource
+	byName := make(map[string]*resource)
+	for _, op := range b.doc.Operations {
+		rname, aname, file := operationName(op)
+		r, ok := byName[rname]
+		if !ok {
+			r = &resource{Name: rname, actions: make(map[string]*action)}
+			byName[rname] = r
+			list = append(list, r)
+		}
+		if file {
+			r.Files = append(r.Files, op)
+			continue
+		}
+		a, ok := r.actions[aname]
+		if ok && !isGoaID(op.ID) {
+			for i := 2; ok; i++ {
+				aname = fmt.Sprintf("%s_%d", aname, i)
+				a, ok = r.actions[aname]
+			}
+		}
+		if !ok {
+			a = &action{Name: aname, Resource: r}
+			r.actions[aname] = a
+			r.Actions = append(r.Actions, a)
+		}
+		a.Ops = append(a.Ops, op)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	for _, r := range list {
+		b.longRunning(r)
+	}
+	for _, op := range b.doc.Operations {
+		b.requiredScopes(op)
+	}
+
+	b.hoistSecurity()
+	apiErrors := b.placeErrors(list)
+
+	var decls []*Decl
+	for _, r := range list {
+		decls = append(decls, &Decl{Value: call("Resource", r.Name, b.resourceDSL(r))})
+	}
+	var dsl Func
+	for _, e := range apiErrors {
+		dsl = append(dsl, b.errorCall(e))
+	}
+	for _, wh := range b.doc.Webhooks {
+		dsl = append(dsl, b.webhookCall(wh))
+	}
+	return decls, dsl
+}
+
+// operationName returns the names of the resource and action implementing the given operation
+// and whether the operation describes a file server. goagen uses operation IDs of the form
+// "resource#action", "resource#action#n" for additional routes and "resource#path" for file
+// servers. Other operations are grouped by tag or by first path segment.
+func operationName(op *operation) (string, string, bool) {
+	if isGoaID(op.ID) {
+		parts := strings.SplitN(op.ID, "#", 3)
+		return parts[0], parts[1], strings.HasPrefix(parts[1], "/")
+	}
+	var segments []string
+	for _, s := range strings.Split(op.Path, "/") {
+		if s != "" && !strings.HasPrefix(s, "{") {
+			segments = append(segments, s)
+		}
+	}
+	res := "api"
+	switch {
+	case len(op.Tags) > 0:
+		res = op.Tags[0]
+	case len(segments) > 0:
+		res = segments[0]
+	}
+	act := op.ID
+	if act == "" {
+		act = strings.Join(append([]string{strings.ToLower(op.Method)}, segments...), "_")
+	}
+	return res, act, false
+}
+
+// isGoaID returns true if the given operation ID was produced by goagen.
+func isGoaID(id string) bool {
+	parts := strings.SplitN(id, "#", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return false
+	}
+	if len(parts) == 3 {
+		if _, err := strconv.Atoi(parts[2]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// longRunning identifies the long-running actions of the given resource and removes the
+// companion status actions generated by LongRunning from the resource actions.
+func (b *builder) longRunning(r *resource) {
+	var actions []*action
+	for _, a := range r.Actions {
+		if !strings.HasSuffix(a.Name, "_status") {
+			actions = append(actions, a)
+			continue
+		}
+		name := strings.TrimSuffix(a.Name, "_status")
+		started, ok := r.actions[name]
+		desc := fmt.Sprintf("Returns the status of the jobs started by the %s action.", name)
+		if !ok || a.Ops[0].Description != desc || a.Ops[0].Responses["200"] == nil {
+			actions = append(actions, a)
+			continue
+		}
+		status := a.Ops[0].Responses["200"].Schema
+		if status == nil || status.Ref == "" {
+			actions = append(actions, a)
+			continue
+		}
+		switch def := b.defs[refName(status.Ref)]; {
+		case def != nil && def.Kind == kindBuiltin && def.Builtin == "JobMedia":
+			started.Async = call("Async")
+		case def != nil && def.Kind == kindMedia:
+			started.Async = call("LongRunning", b.mediaRef(def.Media))
+		default:
+			actions = append(actions, a)
+		}
+	}
+	r.Actions = actions
+}
+
+// hoistSecurity sets the API security requirements to the requirements of all the operations if
+// they are identical and the document does not define any.
+func (b *builder) hoistSecurity() {
+	if b.doc.Security != nil || len(b.doc.Operations) == 0 {
+		return
+	}
+	var sec *[]map[string][]string
+	for _, op := range b.doc.Operations {
+		if op.Security == nil || len(*op.Security) == 0 {
+			return
+		}
+		if sec != nil && !reflect.DeepEqual(normalizeSecurity(*sec), normalizeSecurity(*op.Security)) {
+			return
+		}
+		sec = op.Security
+	}
+	b.doc.Security = *sec
+}
+
+// normalizeSecurity returns the given security requirements with empty scope lists set to nil.
+func normalizeSecurity(reqs []map[string][]string) []map[string][]string {
+	res := make([]map[string][]string, len(reqs))
+	for i, r := range reqs {
+		res[i] = make(map[string][]string)
+		for n, scopes := range r {
+			if len(scopes) == 0 {
+				scopes = nil
+			}
+			res[i][n] = scopes
+		}
+	}
+	return res
+}
+
+// placeErrors computes the errors returned by the actions and decides where they are declared:
+// errors returned by all the actions are declared in the API, errors returned by all the
+// actions of a single resource in the resource and other errors in the first action returning
+// them. It returns the API level errors.
+func (b *builder) placeErrors(resources []*resource) []*errorDef {
+	var (
+		order   []string
+		errs    = make(map[string]*errorDef)
+		actions = make(map[string][]*action)
+		total   int
+	)
+	for _, r := range resources {
+		for _, a := range r.Actions {
+			total++
+			op := a.Ops[0]
+			for _, code := range sortedKeys(op.Responses) {
+				for _, e := range b.responseErrors(op.Responses[code], code) {
+					existing, ok := errs[e.Name]
+					if !ok {
+						errs[e.Name] = e
+						order = append(order, e.Name)
+					} else if existing.Status != e.Status {
+						b.warn("%s %s: error %q is also returned with status %d, using status %d", op.Method, op.Path, e.Name, e.Status, existing.Status)
+						continue
+					}
+					actions[e.Name] = append(actions[e.Name], a)
+				}
+			}
+		}
+	}
+	b.errors = errs
+	var apiErrors []*errorDef
+	for _, n := range order {
+		e, acts := errs[n], actions[n]
+		single := true
+		for _, a := range acts {
+			single = single && a.Resource == acts[0].Resource
+		}
+		switch {
+		case len(acts) == total && total > 1:
+			e.Owner = b.doc
+			apiErrors = append(apiErrors, e)
+		case single && len(acts) == len(acts[0].Resource.Actions) && len(acts) > 1:
+			e.Owner = acts[0].Resource
+			acts[0].Resource.Errors = append(acts[0].Resource.Errors, e)
+		default:
+			e.Owner = acts[0]
+			acts[0].Errors = append(acts[0].Errors, e)
+			for _, a := range acts[1:] {
+				b.warn("%s %s: error %q is declared by action %q of resource %q, the response describes the error media type only", a.Ops[0].Method, a.Ops[0].Path, n, acts[0].Name, acts[0].Resource.Name)
+			}
+		}
+	}
+	return apiErrors
+}
+
+// responseErrors returns the errors described by the schema of the given response. goagen
+// describes the error names with an enum on the "code" member of the error media type or on the
+// "type" member of the problem details media type.
+func (b *builder) responseErrors(r *response, code string) []*errorDef {
+	s := r.Schema
+	if s == nil || len(s.AllOf) < 2 || s.AllOf[0].Ref == "" {
+		return nil
+	}
+	if def, ok := b.defs[refName(s.AllOf[0].Ref)]; !ok || def.Kind != kindBuiltin {
+		return nil
+	}
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		return nil
+	}
+	ext := s.AllOf[1]
+	codes := ext.Properties["code"]
+	if codes == nil {
+		codes = ext.Properties["type"]
+	}
+	if codes == nil || len(codes.Enum) == 0 {
+		return nil
+	}
+	meta := ext.Properties["meta"]
+	if meta == nil && len(s.AllOf) > 2 {
+		meta = s.AllOf[2]
+	}
+	var errs []*errorDef
+	for i, v := range codes.Enum {
+		e := &errorDef{Name: fmt.Sprintf("%v", v), Status: status}
+		if len(codes.EnumDescriptions) == len(codes.Enum) {
+			e.Description = codes.EnumDescriptions[i]
+		}
+		if len(codes.Enum) == 1 {
+			e.Meta = meta
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// declares returns true if all the given errors are declared by the API, the resource of the
+// given action or the action itself.
+func (b *builder) declares(a *action, errs []*errorDef) bool {
+	for _, e := range errs {
+		d, ok := b.errors[e.Name]
+		if !ok || d.Status != e.Status {
+			return false
+		}
+		switch owner := d.Owner.(type) {
+		case *resource:
+			if owner != a.Resource {
+				return false
+			}
+		case *action:
+			if owner != a {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// errorCall returns the Error call declaring the given error.
+func (b *builder) errorCall(e *errorDef) *Call {
+	args := []interface{}{e.Name}
+	if e.Meta != nil {
+		meta := b.attribute(fmt.Sprintf("error %q meta", e.Name), codegen.Goify(e.Name, true)+"Meta", e.Meta)
+		switch meta.Type.(type) {
+		case *typeRef:
+			args = append(args, meta.Type)
+		case nil:
+			args = append(args, b.hoist(fmt.Sprintf("error %q meta", e.Name), codegen.Goify(e.Name, true)+"Meta", e.Meta))
+		default:
+			b.warn("error %q: meta must be a type, ignoring it", e.Name)
+		}
+	}
+	var dsl Func
+	if e.Status != http.StatusBadRequest {
+		dsl = append(dsl, call("Status", e.Status))
+	}
+	if e.Description != "" {
+		dsl = append(dsl, call("Description", e.Description))
+	}
+	if len(dsl) > 0 {
+		args = append(args, dsl)
+	}
+	return call("Error", args...)
+}
+
+// resourceDSL returns the DSL describing the given resource.
+func (b *builder) resourceDSL(r *resource) Func {
+	var dsl Func
+	for _, e := range r.Errors {
+		dsl = append(dsl, b.errorCall(e))
+	}
+	for _, op := range r.Files {
+		dsl = append(dsl, b.filesCall(r, op))
+	}
+	for _, a := range r.Actions {
+		dsl = append(dsl, call("Action", a.Name, b.actionDSL(a)))
+	}
+	return dsl
+}
+
+// filesCall returns the Files call describing the given file server operation.
+func (b *builder) filesCall(r *resource, op *operation) *Call {
+	path := strings.SplitN(op.ID, "#", 2)[1]
+	filename := strings.TrimPrefix(op.Summary, "Download ")
+	if filename == op.Summary || filename == "" {
+		b.warn("%s %s: file server file path is unknown, using %q", op.Method, op.Path, "public")
+		filename = "public"
+	}
+	var dsl Func
+	if op.Description != "" {
+		dsl = append(dsl, call("Description", op.Description))
+	}
+	if op.Docs != nil {
+		dsl = append(dsl, docsCall(op.Docs))
+	}
+	if sec := b.operationSecurity(op); sec != nil {
+		dsl = append(dsl, sec)
+	}
+	args := []interface{}{path, filename}
+	if len(dsl) > 0 {
+		args = append(args, dsl)
+	}
+	return call("Files", args...)
+}
+
+// operationSecurity returns the Security or NoSecurity call describing the security
+// requirements of the given operation if they differ from the API ones.
+func (b *builder) operationSecurity(op *operation) *Call {
+	if op.Security == nil {
+		return nil
+	}
+	if b.doc.Security != nil && reflect.DeepEqual(normalizeSecurity(*op.Security), normalizeSecurity(b.doc.Security)) {
+		return nil
+	}
+	if len(*op.Security) == 0 && b.doc.Security == nil {
+		return nil
+	}
+	return b.security(fmt.Sprintf("%s %s", op.Method, op.Path), *op.Security)
+}
+
+// actionDSL returns the DSL describing the given action.
+func (b *builder) actionDSL(a *action) Func {
+	op := a.Ops[0]
+	where := fmt.Sprintf("%s %s", op.Method, op.Path)
+	var dsl Func
+	if op.Description != "" {
+		dsl = append(dsl, call("Description", op.Description))
+	}
+	if op.Docs != nil {
+		dsl = append(dsl, docsCall(op.Docs))
+	}
+	if op.Deprecated {
+		dsl = append(dsl, call("Deprecated", "", "", ""))
+	}
+
+	var (
+		routes   []interface{}
+		messages Func
+	)
+	for _, o := range a.Ops {
+		args := []interface{}{b.routePath(o.Path)}
+		msgs := make(map[string]interface{})
+		exts := make(map[string]interface{})
+		for k, v := range o.Extensions {
+			if k == "x-inbound-message" || k == "x-outbound-message" {
+				msgs[k] = v
+				continue
+			}
+			exts[k] = v
+		}
+		if o == op {
+			for _, k := range sortedKeys(msgs) {
+				fn := "InboundMessage"
+				if k == "x-outbound-message" {
+					fn = "OutboundMessage"
+				}
+				if t := b.messageType(where, msgs[k]); t != nil {
+					messages = append(messages, call(fn, t))
+				}
+			}
+		}
+		if meta := extensionsDSL(exts); len(meta) > 0 {
+			args = append(args, meta)
+		}
+		routes = append(routes, call(o.Method, args...))
+	}
+	dsl = append(dsl, call("Routing", routes...))
+	if len(op.Schemes) > 0 && strings.Join(sortedStrings(op.Schemes), ",") != strings.Join(sortedStrings(b.doc.Schemes), ",") {
+		dsl = append(dsl, call("Scheme", stringArgs(op.Schemes)...))
+	}
+	if sec := b.operationSecurity(op); sec != nil {
+		dsl = append(dsl, sec)
+	}
+
+	var (
+		params, headers, cookies Func
+		preq, hreq, creq         []string
+		seen                     = make(map[string]bool)
+	)
+	for _, o := range a.Ops {
+		for _, p := range o.Params {
+			key := p.In + ":" + p.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			pa := b.paramAttr(where, p)
+			switch p.In {
+			case "path":
+				params = append(params, call("Param", pa.args(p.Name)...))
+			case "query":
+				params = append(params, call("Param", pa.args(p.Name)...))
+				if p.Required {
+					preq = append(preq, p.Name)
+				}
+			case "header":
+				headers = append(headers, call("Header", pa.args(p.Name)...))
+				if p.Required {
+					hreq = append(hreq, p.Name)
+				}
+			case "cookie":
+				cookies = append(cookies, call("Cookie", pa.args(p.Name)...))
+				if p.Required {
+					creq = append(creq, p.Name)
+				}
+			}
+		}
+	}
+	for _, g := range []struct {
+		fn       string
+		dsl      Func
+		required []string
+	}{{"Params", params, preq}, {"Headers", headers, hreq}, {"Cookies", cookies, creq}} {
+		if len(g.dsl) == 0 {
+			continue
+		}
+		if len(g.required) > 0 {
+			g.dsl = append(g.dsl, call("Required", stringArgs(g.required)...))
+		}
+		dsl = append(dsl, call(g.fn, g.dsl))
+	}
+
+	if op.Body != nil {
+		dsl = append(dsl, b.payloadCall(where, op, codegen.Goify(a.Name, true)+codegen.Goify(a.Resource.Name, true)+"Payload"))
+		if op.Multipart {
+			dsl = append(dsl, call("MultipartForm"))
+		}
+	}
+	dsl = append(dsl, messages...)
+	for _, e := range a.Errors {
+		dsl = append(dsl, b.errorCall(e))
+	}
+	if a.Async != nil {
+		dsl = append(dsl, a.Async)
+	}
+	for _, code := range sortedKeys(op.Responses) {
+		if a.Async != nil && code == "202" {
+			continue
+		}
+		if c := b.responseCall(where, a, code, op.Responses[code]); c != nil {
+			dsl = append(dsl, c)
+		}
+	}
+
+	if op.Summary != "" && op.Summary != a.Name+" "+a.Resource.Name {
+		dsl = append(dsl, call("Metadata", "swagger:summary", op.Summary))
+	}
+	if len(op.Tags) > 0 && !(len(op.Tags) == 1 && op.Tags[0] == a.Resource.Name) {
+		for _, t := range op.Tags {
+			dsl = append(dsl, call("Metadata", "swagger:tag:"+t))
+		}
+	}
+	return dsl
+}
+
+// requiredScopes removes the list of required JWT scopes that goagen appends to the description
+// of the given operation and uses it to initialize the operation security requirement scopes
+// which OpenAPI does not list for bearer tokens.
+func (b *builder) requiredScopes(op *operation) {
+	const marker = "Required security scopes:\n"
+	i := strings.Index(op.Description, marker)
+	if i < 0 {
+		return
+	}
+	var scopes []string
+	for _, line := range strings.Split(op.Description[i+len(marker):], "\n") {
+		if !strings.HasPrefix(line, "  * `") || !strings.HasSuffix(line, "`") {
+			return
+		}
+		scopes = append(scopes, line[len("  * `"):len(line)-1])
+	}
+	op.Description = strings.TrimSuffix(op.Description[:i], "\n\n")
+	if op.Security == nil || len(*op.Security) != 1 {
+		return
+	}
+	for n, s := range (*op.Security)[0] {
+		if scheme, ok := b.doc.SecuritySchemes[n]; ok && scheme.Kind == "jwt" && len(s) == 0 {
+			(*op.Security)[0][n] = scopes
+		}
+	}
+}
+
+// routePath returns the path of the route described by the given document path.
+func (b *builder) routePath(path string) string {
+	if path == "/" && b.doc.BasePath != "" && b.doc.BasePath != "/" {
+		return ""
+	}
+	return pathDSL(path)
+}
+
+// paramAttr returns the attribute describing the given parameter.
+func (b *builder) paramAttr(where string, p *parameter) *attr {
+	s := p.Schema
+	if p.Style == "deepObject" && (s.Type.Name == "string" || len(s.Properties) == 0 &&
+		(s.AdditionalProperties == nil || s.AdditionalProperties.Schema == nil)) {
+		// goagen describes deep object parameters with the string type in Swagger and with
+		// objects allowing any additional property in OpenAPI.
+		s = &schema{Type: schemaType{Name: "object"}, AdditionalProperties: &additionalProperties{Allowed: true, Schema: &schema{Type: schemaType{Name: "string"}}}}
+	}
+	pwhere := fmt.Sprintf("%s: %s parameter %q", where, p.In, p.Name)
+	a := b.attribute(pwhere, p.Name, s)
+	if a.Type == nil {
+		b.warn("%s: object parameters must be hashes of strings", pwhere)
+		a = &attr{Type: call("HashOf", Ident("String"), Ident("String"))}
+	}
+	a.Desc = p.Description
+	if p.Style != "" {
+		a.DSL = append(a.DSL, call("Style", p.Style))
+	}
+	if p.Explode != nil && !*p.Explode {
+		a.DSL = append(a.DSL, call("Explode", false))
+	}
+	if p.Deprecated {
+		a.DSL = append(a.DSL, call("Deprecated", "", "", ""))
+	}
+	a.DSL = append(a.DSL, extensionsDSL(p.Extensions)...)
+	return a
+}
+
+// payloadCall returns the Payload or OptionalPayload call describing the request body of the
+// given operation. typeName is the name goa gives to inline payload types.
+func (b *builder) payloadCall(where string, op *operation, typeName string) *Call {
+	fn := "Payload"
+	if !op.BodyReq {
+		fn = "OptionalPayload"
+	}
+	a := b.attribute(where+" payload", typeName, op.Body)
+	a.View = ""
+	if a.Type == nil {
+		dsl := a.DSL
+		if a.Desc != "" {
+			dsl = append(Func{call("Description", a.Desc)}, dsl...)
+		}
+		return call(fn, dsl)
+	}
+	if len(a.DSL) > 0 {
+		return call(fn, a.Type, a.DSL)
+	}
+	return call(fn, a.Type)
+}
+
+// messageType returns the type of the websocket message described by the given goagen extension.
+func (b *builder) messageType(where string, v interface{}) interface{} {
+	var s schema
+	if raw, err := json.Marshal(v); err != nil || json.Unmarshal(raw, &s) != nil {
+		b.warn("%s: invalid websocket message schema", where)
+		return nil
+	}
+	a := b.attribute(where+" message", "Message", &s)
+	switch a.Type.(type) {
+	case *typeRef:
+		return a.Type
+	case nil:
+		return b.hoist(where+" message", "Message", &s)
+	}
+	b.warn("%s: websocket messages must be types", where)
+	return nil
+}
+
+// responseCall returns the Response call describing the given response or nil if the response
+// is added by the errors declared in the design.
+func (b *builder) responseCall(where string, a *action, code string, r *response) *Call {
+	if code == "default" {
+		b.warn("%s: default responses are not supported", where)
+		return nil
+	}
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		b.warn("%s: invalid response status %q", where, code)
+		return nil
+	}
+	where = fmt.Sprintf("%s response %s", where, code)
+	var (
+		media, stream interface{}
+		view          string
+		described     = r.Description != "" && r.Description != http.StatusText(status)
+	)
+	if resErrs := b.responseErrors(r, code); len(resErrs) > 0 {
+		if a != nil && b.declares(a, resErrs) && !described && len(r.Headers) == 0 {
+			return nil
+		}
+		media = Ident("ErrorMedia")
+	} else {
+		media, view, stream = b.responseMedia(where, r)
+	}
+
+	var dsl Func
+	name, standard := statusNames[status]
+	if !standard {
+		name = "Status" + code
+		dsl = append(dsl, call("Status", status))
+	}
+	if described {
+		dsl = append(dsl, call("Description", r.Description))
+	}
+	if media != nil && view != "" {
+		dsl = append(dsl, call("Media", media, view))
+	}
+	if stream != nil {
+		dsl = append(dsl, call("Stream", stream))
+	}
+	if len(r.Headers) > 0 {
+		dsl = append(dsl, call("Headers", b.headersDSL(where, r.Headers)))
+	}
+	if exts := extensionsDSL(r.Extensions); len(exts) > 0 {
+		dsl = append(dsl, exts...)
+	}
+	args := []interface{}{name}
+	if standard {
+		args[0] = Ident(name)
+	}
+	if media != nil && view == "" {
+		args = append(args, media)
+	}
+	if len(dsl) > 0 {
+		args = append(args, dsl)
+	}
+	return call("Response", args...)
+}
+
+// responseMedia returns the media type and view or the stream event type of the given response.
+func (b *builder) responseMedia(where string, r *response) (interface{}, string, interface{}) {
+	s := r.Schema
+	if r.ContentType == design.EventStreamMediaType && s != nil {
+		a := b.attribute(where+" event", "Event", s)
+		if a.Type == nil {
+			return nil, "", b.hoist(where+" event", "Event", s)
+		}
+		return nil, "", a.Type
+	}
+	if s == nil {
+		return nil, "", nil
+	}
+	s = b.inlined(s)
+	if s.Ref != "" {
+		if def, ok := b.defs[refName(s.Ref)]; ok {
+			switch def.Kind {
+			case kindMedia:
+				return b.mediaRef(def.Media), viewArg(def.View), nil
+			case kindCollection:
+				return call("CollectionOf", b.mediaRef(def.Media)), viewArg(def.View), nil
+			case kindBuiltin:
+				return def.Builtin, "", nil
+			}
+		}
+	}
+	if s.Type.Name == "array" && s.Items != nil && s.Items.Ref != "" {
+		if def, ok := b.defs[refName(s.Items.Ref)]; ok && def.Kind == kindMedia {
+			return call("CollectionOf", b.mediaRef(def.Media)), viewArg(def.View), nil
+		}
+	}
+	b.warn("%s: schema cannot be described with a media type, ignoring it", where)
+	return nil, "", nil
+}
+
+// headersDSL returns the DSL describing the given response headers.
+func (b *builder) headersDSL(where string, headers map[string]*header) Func {
+	var dsl Func
+	var required []string
+	for _, n := range sortedKeys(headers) {
+		h := headers[n]
+		a := b.attribute(fmt.Sprintf("%s header %q", where, n), n, h.Schema)
+		a.Desc = h.Description
+		dsl = append(dsl, call("Header", a.args(n)...))
+		if h.Required {
+			required = append(required, n)
+		}
+	}
+	if len(required) > 0 {
+		dsl = append(dsl, call("Required", stringArgs(required)...))
+	}
+	return dsl
+}
+
+// webhookCall returns the Webhook call describing the given webhook operation.
+func (b *builder) webhookCall(wh *operation) *Call {
+	where := fmt.Sprintf("webhook %q", wh.ID)
+	var dsl Func
+	if wh.Description != "" {
+		dsl = append(dsl, call("Description", wh.Description))
+	}
+	if wh.Body != nil {
+		dsl = append(dsl, b.payloadCall(where, wh, codegen.Goify(wh.ID, true)+"WebhookPayload"))
+	}
+	for _, code := range sortedKeys(wh.Responses) {
+		if c := b.responseCall(where, nil, code, wh.Responses[code]); c != nil {
+			dsl = append(dsl, c)
+		}
+	}
+	return call("Webhook", wh.ID, dsl)
+}
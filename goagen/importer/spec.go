@@ -0,0 +1,358 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// document is the representation of a Swagger 2.0 or OpenAPI 3 document the design is built
+	// from.
+	document struct {
+		Title           string
+		Description     string
+		Version         string
+		TermsOfService  string
+		Contact         *contact
+		License         *license
+		Docs            *externalDocs
+		Host            string
+		BasePath        string
+		Schemes         []string
+		Consumes        []string
+		Produces        []string
+		Extensions      map[string]interface{}
+		Security        []map[string][]string
+		SecuritySchemes map[string]*securityScheme
+		Schemas         map[string]*schema
+		Operations      []*operation
+		Webhooks        []*operation
+	}
+
+	// operation describes a single API operation.
+	operation struct {
+		Path        string
+		Method      string
+		ID          string
+		Tags        []string
+		Summary     string
+		Description string
+		Docs        *externalDocs
+		Schemes     []string
+		Deprecated  bool
+		Params      []*parameter
+		Body        *schema
+		BodyTypes   []string
+		BodyDesc    string
+		BodyReq     bool
+		Multipart   bool
+		Responses   map[string]*response
+		Security    *[]map[string][]string
+		Extensions  map[string]interface{}
+	}
+
+	// parameter describes an operation parameter, the location is one of "path", "query",
+	// "header" or "cookie".
+	parameter struct {
+		Name        string
+		In          string
+		Description string
+		Required    bool
+		Deprecated  bool
+		Style       string
+		Explode     *bool
+		Schema      *schema
+		Extensions  map[string]interface{}
+	}
+
+	// response describes an operation response.
+	response struct {
+		Description string
+		Schema      *schema
+		ContentType string
+		Headers     map[string]*header
+		Extensions  map[string]interface{}
+	}
+
+	// header describes a response header.
+	header struct {
+		Description string
+		Required    bool
+		Schema      *schema
+	}
+
+	// securityScheme describes a security scheme, Kind is one of "basic", "apiKey", "jwt" or
+	// "oauth2".
+	securityScheme struct {
+		Kind             string
+		Description      string
+		Name             string
+		In               string
+		Flow             string
+		AuthorizationURL string
+		TokenURL         string
+		Scopes           map[string]string
+	}
+
+	contact struct {
+		Name  string `json:"name,omitempty"`
+		Email string `json:"email,omitempty"`
+		URL   string `json:"url,omitempty"`
+	}
+
+	license struct {
+		Name string `json:"name,omitempty"`
+		URL  string `json:"url,omitempty"`
+	}
+
+	externalDocs struct {
+		Description string `json:"description,omitempty"`
+		URL         string `json:"url,omitempty"`
+	}
+
+	info struct {
+		Title          string        `json:"title"`
+		Description    string        `json:"description"`
+		TermsOfService string        `json:"termsOfService"`
+		Contact        *contact      `json:"contact"`
+		License        *license      `json:"license"`
+		Version        string        `json:"version"`
+		Extensions     extensionsMap `json:"-"`
+	}
+
+	// schema is the subset of JSON schema shared by Swagger 2.0 and OpenAPI 3.
+	schema struct {
+		Ref                  string                 `json:"$ref"`
+		Title                string                 `json:"title"`
+		Description          string                 `json:"description"`
+		Type                 schemaType             `json:"type"`
+		Format               string                 `json:"format"`
+		Items                *schema                `json:"items"`
+		Properties           map[string]*schema     `json:"properties"`
+		AdditionalProperties *additionalProperties  `json:"additionalProperties"`
+		Required             []string               `json:"required"`
+		Enum                 []interface{}          `json:"enum"`
+		Default              interface{}            `json:"default"`
+		Example              interface{}            `json:"example"`
+		Pattern              string                 `json:"pattern"`
+		Minimum              *float64               `json:"minimum"`
+		Maximum              *float64               `json:"maximum"`
+		ExclusiveMinimum     exclusiveBound         `json:"exclusiveMinimum"`
+		ExclusiveMaximum     exclusiveBound         `json:"exclusiveMaximum"`
+		MultipleOf           *float64               `json:"multipleOf"`
+		MinLength            *int                   `json:"minLength"`
+		MaxLength            *int                   `json:"maxLength"`
+		MinItems             *int                   `json:"minItems"`
+		MaxItems             *int                   `json:"maxItems"`
+		UniqueItems          bool                   `json:"uniqueItems"`
+		MinProperties        *int                   `json:"minProperties"`
+		MaxProperties        *int                   `json:"maxProperties"`
+		ReadOnly             bool                   `json:"readOnly"`
+		WriteOnly            bool                   `json:"writeOnly"`
+		Deprecated           bool                   `json:"deprecated"`
+		Nullable             bool                   `json:"nullable"`
+		XNullable            bool                   `json:"x-nullable"`
+		AllOf                []*schema              `json:"allOf"`
+		AnyOf                []*schema              `json:"anyOf"`
+		OneOf                []*schema              `json:"oneOf"`
+		Not                  *schema                `json:"not"`
+		If                   *schema                `json:"if"`
+		Then                 *schema                `json:"then"`
+		Else                 *schema                `json:"else"`
+		Dependencies         map[string]interface{} `json:"dependencies"`
+		DependentRequired    map[string][]string    `json:"dependentRequired"`
+		Discriminator        *discriminator         `json:"discriminator"`
+		EnumDescriptions     []string               `json:"x-enum-descriptions"`
+		XML                  interface{}            `json:"xml"`
+	}
+
+	// schemaType is the schema "type" which OpenAPI 3.1 allows to be a list.
+	schemaType struct {
+		Name     string
+		Nullable bool
+		Multiple bool
+	}
+
+	// additionalProperties is the schema "additionalProperties" which may be a boolean or a
+	// schema.
+	additionalProperties struct {
+		Allowed bool
+		Schema  *schema
+	}
+
+	// exclusiveBound is a "exclusiveMinimum" or "exclusiveMaximum" value which is a boolean in
+	// Swagger 2.0 and OpenAPI 3.0 and a number in OpenAPI 3.1.
+	exclusiveBound struct {
+		Set   bool
+		Value *float64
+	}
+
+	// discriminator is the schema "discriminator" which is a property name in Swagger 2.0 and
+	// an object in OpenAPI 3.
+	discriminator struct {
+		PropertyName string            `json:"propertyName"`
+		Mapping      map[string]string `json:"mapping"`
+	}
+
+	// extensionsMap holds the "x-" extensions of an object.
+	extensionsMap map[string]interface{}
+)
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *schemaType) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		t.Name = name
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(b, &names); err != nil {
+		return err
+	}
+	for _, n := range names {
+		if n == "null" {
+			t.Nullable = true
+			continue
+		}
+		if t.Name != "" {
+			t.Multiple = true
+			continue
+		}
+		t.Name = n
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *additionalProperties) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(b, &a.Schema)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *exclusiveBound) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &e.Set); err == nil {
+		return nil
+	}
+	e.Set = true
+	return json.Unmarshal(b, &e.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *discriminator) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &d.PropertyName); err == nil {
+		return nil
+	}
+	type _discriminator discriminator
+	return json.Unmarshal(b, (*_discriminator)(d))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *info) UnmarshalJSON(b []byte) error {
+	type _info info
+	if err := json.Unmarshal(b, (*_info)(i)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &i.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it only retains the keys starting with "x-".
+func (m *extensionsMap) UnmarshalJSON(b []byte) error {
+	var all map[string]interface{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return err
+	}
+	for k, v := range all {
+		if strings.HasPrefix(k, "x-") {
+			if *m == nil {
+				*m = make(extensionsMap)
+			}
+			(*m)[k] = v
+		}
+	}
+	return nil
+}
+
+// parse decodes the given JSON or YAML Swagger 2.0 or OpenAPI 3 document.
+func parse(data []byte, warn func(string, ...interface{})) (*document, error) {
+	b := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(b, []byte("{")) {
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse document: %s", err)
+		}
+		var err error
+		if b, err = json.Marshal(toJSON(raw)); err != nil {
+			return nil, fmt.Errorf("failed to parse document: %s", err)
+		}
+	}
+	var version struct {
+		Swagger string `json:"swagger"`
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(b, &version); err != nil {
+		return nil, fmt.Errorf("invalid document: %s", err)
+	}
+	switch {
+	case version.Swagger == "2.0":
+		return parseSwagger(b, warn)
+	case strings.HasPrefix(version.OpenAPI, "3."):
+		return parseOpenAPI(b, warn)
+	case version.Swagger != "":
+		return nil, fmt.Errorf("unsupported Swagger version %q", version.Swagger)
+	case version.OpenAPI != "":
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", version.OpenAPI)
+	}
+	return nil, fmt.Errorf("invalid document: missing swagger or openapi version")
+}
+
+// toJSON converts the maps produced by the YAML decoder into maps that can be encoded to JSON.
+func toJSON(val interface{}) interface{} {
+	switch actual := val.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, v := range actual {
+			m[fmt.Sprintf("%v", k)] = toJSON(v)
+		}
+		return m
+	case []interface{}:
+		for i, e := range actual {
+			actual[i] = toJSON(e)
+		}
+		return actual
+	default:
+		return actual
+	}
+}
+
+// refName returns the name of the schema definition referenced by ref or the empty string if ref
+// does not reference a local definition.
+func refName(ref string) string {
+	for _, prefix := range []string{"#/definitions/", "#/components/schemas/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return unescapePointer(ref[len(prefix):])
+		}
+	}
+	return ""
+}
+
+// unescapePointer unescapes a JSON pointer token.
+func unescapePointer(tok string) string {
+	return strings.Replace(strings.Replace(tok, "~1", "/", -1), "~0", "~", -1)
+}
+
+// isVerb returns true if key is a path item key describing an operation.
+func isVerb(key string) bool {
+	switch key {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
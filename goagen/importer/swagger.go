@@ -0,0 +1,496 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+type (
+	// swaggerDoc is a Swagger 2.0 document.
+	swaggerDoc struct {
+		Info                *info                                 `json:"info"`
+		Host                string                                `json:"host"`
+		BasePath            string                                `json:"basePath"`
+		Schemes             []string                              `json:"schemes"`
+		Consumes            []string                              `json:"consumes"`
+		Produces            []string                              `json:"produces"`
+		Paths               map[string]map[string]json.RawMessage `json:"paths"`
+		Definitions         map[string]*schema                    `json:"definitions"`
+		Parameters          map[string]json.RawMessage            `json:"parameters"`
+		Responses           map[string]*swaggerResponse           `json:"responses"`
+		SecurityDefinitions map[string]*swaggerSecurity           `json:"securityDefinitions"`
+		Security            []map[string][]string                 `json:"security"`
+		ExternalDocs        *externalDocs                         `json:"externalDocs"`
+		Webhooks            map[string]*swaggerWebhook            `json:"x-webhooks"`
+	}
+
+	// swaggerWebhook is the description of a webhook in the goagen "x-webhooks" extension.
+	swaggerWebhook struct {
+		Description string                      `json:"description"`
+		Schema      *schema                     `json:"schema"`
+		Responses   map[string]*swaggerResponse `json:"responses"`
+	}
+
+	// swaggerOperation is a Swagger 2.0 operation.
+	swaggerOperation struct {
+		Tags         []string                    `json:"tags"`
+		Summary      string                      `json:"summary"`
+		Description  string                      `json:"description"`
+		ExternalDocs *externalDocs               `json:"externalDocs"`
+		OperationID  string                      `json:"operationId"`
+		Consumes     []string                    `json:"consumes"`
+		Produces     []string                    `json:"produces"`
+		Parameters   []json.RawMessage           `json:"parameters"`
+		Responses    map[string]*swaggerResponse `json:"responses"`
+		Schemes      []string                    `json:"schemes"`
+		Deprecated   bool                        `json:"deprecated"`
+		Security     *[]map[string][]string      `json:"security"`
+		Cookies      []json.RawMessage           `json:"x-cookies"`
+		Extensions   extensionsMap               `json:"-"`
+	}
+
+	// swaggerParam is a Swagger 2.0 parameter, the other fields are decoded as a schema.
+	swaggerParam struct {
+		Ref              string        `json:"$ref"`
+		Name             string        `json:"name"`
+		In               string        `json:"in"`
+		Description      string        `json:"description"`
+		Required         bool          `json:"required"`
+		Schema           *schema       `json:"schema"`
+		CollectionFormat string        `json:"collectionFormat"`
+		AllowEmptyValue  bool          `json:"allowEmptyValue"`
+		Style            string        `json:"x-style"`
+		Deprecated       bool          `json:"x-deprecated"`
+		Extensions       extensionsMap `json:"-"`
+	}
+
+	// swaggerResponse is a Swagger 2.0 response.
+	swaggerResponse struct {
+		Ref         string             `json:"$ref"`
+		Description string             `json:"description"`
+		Schema      *schema            `json:"schema"`
+		Headers     map[string]*schema `json:"headers"`
+		Stream      bool               `json:"x-stream"`
+		Extensions  extensionsMap      `json:"-"`
+	}
+
+	// swaggerSecurity is a Swagger 2.0 security definition.
+	swaggerSecurity struct {
+		Type             string            `json:"type"`
+		Description      string            `json:"description"`
+		Name             string            `json:"name"`
+		In               string            `json:"in"`
+		Flow             string            `json:"flow"`
+		AuthorizationURL string            `json:"authorizationUrl"`
+		TokenURL         string            `json:"tokenUrl"`
+		Scopes           map[string]string `json:"scopes"`
+	}
+)
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *swaggerOperation) UnmarshalJSON(b []byte) error {
+	type _swaggerOperation swaggerOperation
+	if err := json.Unmarshal(b, (*_swaggerOperation)(o)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &o.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *swaggerParam) UnmarshalJSON(b []byte) error {
+	type _swaggerParam swaggerParam
+	if err := json.Unmarshal(b, (*_swaggerParam)(p)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &p.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *swaggerResponse) UnmarshalJSON(b []byte) error {
+	type _swaggerResponse swaggerResponse
+	if err := json.Unmarshal(b, (*_swaggerResponse)(r)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &r.Extensions)
+}
+
+// swaggerExtensions lists the extensions produced by goagen that the importer interprets.
+var swaggerExtensions = map[string]bool{
+	"x-cookies":           true,
+	"x-style":             true,
+	"x-deprecated":        true,
+	"x-stream":            true,
+	"x-enum-descriptions": true,
+	"x-nullable":          true,
+}
+
+// parseSwagger decodes a Swagger 2.0 document.
+func parseSwagger(b []byte, warn func(string, ...interface{})) (*document, error) {
+	var sw swaggerDoc
+	if err := json.Unmarshal(b, &sw); err != nil {
+		return nil, fmt.Errorf("invalid Swagger document: %s", err)
+	}
+	doc := &document{
+		Host:            sw.Host,
+		BasePath:        sw.BasePath,
+		Schemes:         sw.Schemes,
+		Consumes:        sw.Consumes,
+		Produces:        sw.Produces,
+		Docs:            sw.ExternalDocs,
+		Security:        sw.Security,
+		Schemas:         sw.Definitions,
+		SecuritySchemes: make(map[string]*securityScheme),
+	}
+	setInfo(doc, sw.Info)
+	for n, s := range sw.SecurityDefinitions {
+		doc.SecuritySchemes[n] = swaggerSecurityScheme(n, s, warn)
+	}
+
+	p := &swaggerParser{doc: &sw, warn: warn}
+	ops, err := p.operations(sw.Paths)
+	if err != nil {
+		return nil, err
+	}
+	doc.Operations = ops
+	for _, name := range sortedKeys(sw.Webhooks) {
+		wh := sw.Webhooks[name]
+		so := &swaggerOperation{OperationID: name, Description: wh.Description, Responses: wh.Responses}
+		op, err := p.operation(name, "post", so, nil)
+		if err != nil {
+			return nil, err
+		}
+		op.Body = wh.Schema
+		op.BodyReq = true
+		doc.Webhooks = append(doc.Webhooks, op)
+	}
+	return doc, nil
+}
+
+// swaggerParser converts the Swagger 2.0 paths into operations.
+type swaggerParser struct {
+	doc  *swaggerDoc
+	warn func(string, ...interface{})
+}
+
+// operations returns the operations of the given path items sorted by path and method.
+func (p *swaggerParser) operations(paths map[string]map[string]json.RawMessage) ([]*operation, error) {
+	var ops []*operation
+	for _, path := range sortedKeys(paths) {
+		if strings.HasPrefix(path, "x-") {
+			p.warn("paths extension %q is not supported", path)
+			continue
+		}
+		item := paths[path]
+		var shared []json.RawMessage
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("invalid parameters of path %q: %s", path, err)
+			}
+		}
+		for _, key := range sortedKeys(item) {
+			if !isVerb(key) {
+				if key != "parameters" {
+					p.warn("path %q: %q is not supported", path, key)
+				}
+				continue
+			}
+			var so swaggerOperation
+			if err := json.Unmarshal(item[key], &so); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %s", strings.ToUpper(key), path, err)
+			}
+			op, err := p.operation(path, key, &so, shared)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+// operation converts a Swagger 2.0 operation.
+func (p *swaggerParser) operation(path, method string, so *swaggerOperation, shared []json.RawMessage) (*operation, error) {
+	op := &operation{
+		Path:        path,
+		Method:      strings.ToUpper(method),
+		ID:          so.OperationID,
+		Tags:        so.Tags,
+		Summary:     so.Summary,
+		Description: so.Description,
+		Docs:        so.ExternalDocs,
+		Schemes:     so.Schemes,
+		Deprecated:  so.Deprecated,
+		Security:    so.Security,
+		Extensions:  extensions(so.Extensions),
+		Responses:   make(map[string]*response),
+	}
+	where := fmt.Sprintf("%s %s", op.Method, path)
+
+	params := append(append([]json.RawMessage{}, shared...), so.Parameters...)
+	params = append(params, so.Cookies...)
+	var form *schema
+	for _, raw := range params {
+		sp, s, err := p.param(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", where, err)
+		}
+		if sp == nil {
+			continue
+		}
+		switch sp.In {
+		case "body":
+			op.Body = sp.Schema
+			op.BodyDesc = sp.Description
+			op.BodyReq = sp.Required
+		case "formData":
+			if form == nil {
+				form = &schema{Type: schemaType{Name: "object"}, Properties: make(map[string]*schema)}
+			}
+			s.Description = sp.Description
+			form.Properties[sp.Name] = s
+			if sp.Required {
+				form.Required = append(form.Required, sp.Name)
+			}
+		case "path", "query", "header", "cookie":
+			s.Description = ""
+			param := &parameter{
+				Name:        sp.Name,
+				In:          sp.In,
+				Description: sp.Description,
+				Required:    sp.Required || sp.In == "path",
+				Deprecated:  sp.Deprecated,
+				Schema:      s,
+				Extensions:  extensions(sp.Extensions),
+			}
+			if sp.In == "query" {
+				p.style(where, sp, param)
+			}
+			// Parameters defined on the operation override the path item ones
+			for i, existing := range op.Params {
+				if existing.Name == param.Name && existing.In == param.In {
+					op.Params = append(op.Params[:i], op.Params[i+1:]...)
+					break
+				}
+			}
+			op.Params = append(op.Params, param)
+		default:
+			p.warn("%s: parameter %q has unknown location %q", where, sp.Name, sp.In)
+		}
+	}
+	if form != nil {
+		if op.Body != nil {
+			p.warn("%s: both body and form parameters are defined, ignoring form parameters", where)
+		} else {
+			op.Body = form
+			op.BodyReq = len(form.Required) > 0
+			op.Multipart = true
+		}
+	}
+	if op.Body != nil {
+		op.BodyTypes = so.Consumes
+	}
+
+	for _, code := range sortedKeys(so.Responses) {
+		sr := so.Responses[code]
+		if sr.Ref != "" {
+			name := strings.TrimPrefix(sr.Ref, "#/responses/")
+			ref, ok := p.doc.Responses[name]
+			if !ok || name == sr.Ref {
+				p.warn("%s: response %s reference %q cannot be resolved", where, code, sr.Ref)
+				continue
+			}
+			sr = ref
+		}
+		resp := &response{
+			Description: sr.Description,
+			Schema:      sr.Schema,
+			Extensions:  extensions(sr.Extensions),
+		}
+		if sr.Stream {
+			resp.ContentType = "text/event-stream"
+		}
+		if len(sr.Headers) > 0 {
+			resp.Headers = make(map[string]*header)
+			for n, h := range sr.Headers {
+				desc := h.Description
+				h.Description = ""
+				resp.Headers[n] = &header{Description: desc, Schema: h}
+			}
+		}
+		op.Responses[code] = resp
+	}
+	if len(so.Produces) > 0 {
+		for _, resp := range op.Responses {
+			if resp.ContentType == "" && resp.Schema != nil {
+				resp.ContentType = producedType(so.Produces)
+			}
+		}
+	}
+	return op, nil
+}
+
+// param decodes a Swagger 2.0 parameter, it returns the parameter and the schema describing its
+// value for non body parameters.
+func (p *swaggerParser) param(raw json.RawMessage) (*swaggerParam, *schema, error) {
+	var sp swaggerParam
+	if err := json.Unmarshal(raw, &sp); err != nil {
+		return nil, nil, fmt.Errorf("invalid parameter: %s", err)
+	}
+	if sp.Ref != "" {
+		name := strings.TrimPrefix(sp.Ref, "#/parameters/")
+		ref, ok := p.doc.Parameters[name]
+		if !ok || name == sp.Ref {
+			p.warn("parameter reference %q cannot be resolved", sp.Ref)
+			return nil, nil, nil
+		}
+		return p.param(ref)
+	}
+	if sp.In == "body" {
+		return &sp, nil, nil
+	}
+	// The parameter "required" field is a boolean while the schema one lists property names.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil, fmt.Errorf("invalid parameter %q: %s", sp.Name, err)
+	}
+	delete(fields, "required")
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid parameter %q: %s", sp.Name, err)
+	}
+	var s schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, nil, fmt.Errorf("invalid parameter %q: %s", sp.Name, err)
+	}
+	if sp.AllowEmptyValue {
+		p.warn("parameter %q: allowEmptyValue is not supported", sp.Name)
+	}
+	return &sp, &s, nil
+}
+
+// style initializes the serialization style of a query string parameter from its collection
+// format.
+func (p *swaggerParser) style(where string, sp *swaggerParam, param *parameter) {
+	if sp.Style != "" {
+		param.Style = sp.Style
+		return
+	}
+	if param.Schema.Type.Name != "array" {
+		return
+	}
+	no := false
+	switch sp.CollectionFormat {
+	case "multi":
+	case "", "csv":
+		param.Explode = &no
+	case "ssv":
+		param.Style = "spaceDelimited"
+	case "pipes":
+		param.Style = "pipeDelimited"
+	default:
+		p.warn("%s: parameter %q collection format %q is not supported", where, sp.Name, sp.CollectionFormat)
+	}
+}
+
+// swaggerSecurityScheme converts a Swagger 2.0 security definition. goagen describes JWT schemes
+// with API key definitions whose description lists the token URL and scopes.
+func swaggerSecurityScheme(name string, s *swaggerSecurity, warn func(string, ...interface{})) *securityScheme {
+	res := &securityScheme{
+		Kind:             s.Type,
+		Description:      s.Description,
+		Name:             s.Name,
+		In:               s.In,
+		Flow:             s.Flow,
+		AuthorizationURL: s.AuthorizationURL,
+		TokenURL:         s.TokenURL,
+		Scopes:           s.Scopes,
+	}
+	if s.Type == "apiKey" && strings.Contains(s.Description, "**Token URL**: ") {
+		res.Kind = "jwt"
+		res.Description, res.TokenURL, res.Scopes = parseJWTDescription(s.Description)
+	}
+	if res.Kind != "basic" && res.Kind != "apiKey" && res.Kind != "jwt" && res.Kind != "oauth2" {
+		warn("security scheme %q has unknown type %q", name, s.Type)
+	}
+	return res
+}
+
+// parseJWTDescription extracts the token URL and scopes that goagen appends to the description of
+// JWT security schemes.
+func parseJWTDescription(desc string) (string, string, map[string]string) {
+	var tokenURL string
+	var scopes map[string]string
+	if i := strings.Index(desc, "\n\n**Security Scopes**:\n"); i >= 0 {
+		scopes = make(map[string]string)
+		for _, line := range strings.Split(desc[i+len("\n\n**Security Scopes**:\n"):], "\n") {
+			line = strings.TrimPrefix(strings.TrimSpace(line), "* `")
+			if j := strings.Index(line, "`: "); j >= 0 {
+				scopes[line[:j]] = line[j+3:]
+			} else if j := strings.Index(line, "`"); j >= 0 {
+				scopes[line[:j]] = ""
+			}
+		}
+		desc = desc[:i]
+	}
+	if i := strings.Index(desc, "\n\n**Token URL**: "); i >= 0 {
+		tokenURL = strings.TrimSpace(desc[i+len("\n\n**Token URL**: "):])
+		desc = desc[:i]
+	}
+	return desc, tokenURL, scopes
+}
+
+// setInfo initializes the document metadata.
+func setInfo(doc *document, i *info) {
+	if i == nil {
+		return
+	}
+	doc.Title = i.Title
+	doc.Description = i.Description
+	doc.Version = i.Version
+	doc.TermsOfService = i.TermsOfService
+	doc.Contact = i.Contact
+	doc.License = i.License
+	doc.Extensions = extensions(i.Extensions)
+}
+
+// extensions returns the extensions that are not interpreted by the importer.
+func extensions(m extensionsMap) map[string]interface{} {
+	var res map[string]interface{}
+	for k, v := range m {
+		if swaggerExtensions[k] {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]interface{})
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// producedType returns the media type of the responses of an operation producing the given
+// media types, the goa error media types are produced by error responses.
+func producedType(produces []string) string {
+	for _, p := range produces {
+		if p != design.ErrorMediaIdentifier && p != design.ProblemMediaIdentifier {
+			return p
+		}
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of the given map in lexical order.
+func sortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
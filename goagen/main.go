@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	"github.com/goadesign/goa/goagen/gen_lint"
+	"github.com/goadesign/goa/goagen/importer"
 	"github.com/goadesign/goa/goagen/meta"
 	"github.com/goadesign/goa/goagen/utils"
 	"github.com/goadesign/goa/version"
@@ -46,7 +49,7 @@ package and tool and the Swagger specification for the API.
 	)
 
 	rootCmd.PersistentFlags().StringP("out", "o", ".", "output directory")
-	rootCmd.PersistentFlags().StringVarP(&designPkg, "design", "d", "", "design package import path")
+	rootCmd.PersistentFlags().StringVarP(&designPkg, "design", "d", "", "design package import path or design JSON file produced by \"goagen design-json\"")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode, does not cleanup temporary files.")
 
 	// versionCmd implements the "version" command
@@ -110,6 +113,56 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(swaggerCmd)
 
+	// openapiCmd implements the "openapi" command.
+	openapiCmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Generate OpenAPI 3.0 specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genopenapi", c) },
+	}
+	rootCmd.AddCommand(openapiCmd)
+
+	// importCmd implements the "import" command.
+	var spec, designPkgName string
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Generate design package from Swagger or OpenAPI specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = runImport(c, spec, designPkgName) },
+	}
+	importCmd.Flags().StringVar(&spec, "spec", "", "path to the Swagger 2.0 or OpenAPI 3 `file` describing the API (JSON or YAML)")
+	importCmd.Flags().StringVar(&designPkgName, "pkg", "design", "Name of generated design package")
+	rootCmd.AddCommand(importCmd)
+
+	// diffCmd implements the "diff" command.
+	var oldDesign, newDesign, save string
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two versions of the design and report breaking changes",
+		Run:   func(c *cobra.Command, _ []string) { files, err = runDiff(designPkg, oldDesign, newDesign, save) },
+	}
+	diffCmd.Flags().StringVar(&oldDesign, "old", "", "design package import path or design JSON `file` of the previous version of the API")
+	diffCmd.Flags().StringVar(&newDesign, "new", "", "design package import path or design JSON `file` of the new version of the API, defaults to --design")
+	diffCmd.Flags().StringVar(&save, "save", "", "write the design JSON of the new version of the API to `file`")
+	rootCmd.AddCommand(diffCmd)
+
+	// lintCmd implements the "lint" command.
+	var lintConfig, lintFormat string
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check the design against style and correctness rules",
+		Run:   func(c *cobra.Command, _ []string) { files, err = runLint(designPkg, lintConfig, lintFormat) },
+	}
+	lintCmd.Flags().StringVar(&lintConfig, "config", "", "YAML or JSON configuration `file` that enables or disables rules")
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "output format, one of \"text\" or \"json\"")
+	rootCmd.AddCommand(lintCmd)
+
+	// designJSONCmd implements the "design-json" command.
+	designJSONCmd := &cobra.Command{
+		Use:   "design-json",
+		Short: "Generate language-neutral JSON representation of the design",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendesignjson", c) },
+	}
+	rootCmd.AddCommand(designJSONCmd)
+
 	// jsCmd implements the "js" command.
 	var (
 		timeout      = time.Duration(20) * time.Second
@@ -271,6 +324,82 @@ func runGen(c *cobra.Command, args []string) ([]string, error) {
 	return generate(pkgName, pkgPath, c, args)
 }
 
+func runImport(c *cobra.Command, spec, pkg string) ([]string, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("missing --spec flag")
+	}
+	out, err := filepath.Abs(c.Flag("out").Value.String())
+	if err != nil {
+		return nil, err
+	}
+	imp := &importer.Importer{Spec: spec, OutDir: out, Package: pkg}
+	files, err := imp.Import()
+	for _, w := range imp.Warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+w)
+	}
+	return files, err
+}
+
+func runDiff(designPkg, oldDesign, newDesign, save string) ([]string, error) {
+	if newDesign == "" {
+		newDesign = designPkg
+	}
+	if newDesign == "" {
+		return nil, fmt.Errorf("missing --new flag")
+	}
+	if oldDesign == "" && save == "" {
+		return nil, fmt.Errorf("missing --old flag")
+	}
+	d := &gendiff.Differ{Old: oldDesign, New: newDesign, Save: save}
+	changes, err := d.Diff()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		kind := "non-breaking"
+		if c.Breaking {
+			kind = "BREAKING"
+		}
+		fmt.Printf("%-12s  %s\n", kind, c)
+	}
+	if breaking := gendiff.Breaking(changes); len(breaking) > 0 {
+		return nil, fmt.Errorf("%d breaking change(s) found", len(breaking))
+	}
+	return nil, nil
+}
+
+func runLint(designPkg, config, format string) ([]string, error) {
+	if designPkg == "" {
+		return nil, fmt.Errorf("missing --design flag")
+	}
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("invalid --format value %#v, must be \"text\" or \"json\"", format)
+	}
+	l := &genlint.Linter{Design: designPkg, Config: config}
+	issues, err := l.Lint()
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		if issues == nil {
+			issues = []*genlint.Issue{}
+		}
+		b, err := json.MarshalIndent(issues, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println(string(b))
+	} else {
+		for _, i := range issues {
+			fmt.Println(i)
+		}
+	}
+	if len(issues) > 0 {
+		return nil, fmt.Errorf("%d lint issue(s) found", len(issues))
+	}
+	return nil, nil
+}
+
 func generate(pkgName, pkgPath string, c *cobra.Command, args []string) ([]string, error) {
 	m := make(map[string]string)
 	c.Flags().Visit(func(f *pflag.Flag) {
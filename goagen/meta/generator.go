@@ -40,7 +40,8 @@ type Generator struct {
 	// OutDir is the final output directory.
 	OutDir string
 
-	// DesignPkgPath is the Go import path to the design package.
+	// DesignPkgPath is the Go import path to the design package or the path to a design JSON
+	// file produced by "goagen design-json" if it ends with ".json".
 	DesignPkgPath string
 
 	debug bool
@@ -118,13 +119,18 @@ func (m *Generator) Generate() ([]string, error) {
 		fmt.Printf("** Code generator source dir: %s\n", tmpDir)
 	}
 
-	pkgSourcePath, err := codegen.PackageSourcePath(m.DesignPkgPath)
-	if err != nil {
-		return nil, fmt.Errorf("invalid design package import path: %s", err)
-	}
-	pkgName, err := codegen.PackageName(pkgSourcePath)
-	if err != nil {
-		return nil, err
+	pkgName := "design"
+	if !m.isDesignJSON() {
+		pkgSourcePath, err := codegen.PackageSourcePath(m.DesignPkgPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid design package import path: %s", err)
+		}
+		pkgName, err = codegen.PackageName(pkgSourcePath)
+		if err != nil {
+			return nil, err
+		}
+	} else if _, err := os.Stat(m.DesignPkgPath); err != nil {
+		return nil, fmt.Errorf("invalid design JSON file: %s", err)
 	}
 
 	// Generate tool source code.
@@ -156,10 +162,21 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("github.com/goadesign/goa/dslengine"),
-		codegen.NewImport("_", filepath.ToSlash(m.DesignPkgPath)),
 	)
+	source := mainTmpl
+	designFile := ""
+	if m.isDesignJSON() {
+		// Load the design from the JSON file instead of compiling in the design package.
+		imports = append(imports, codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_designjson"))
+		source = mainJSONTmpl
+		if designFile, err = filepath.Abs(m.DesignPkgPath); err != nil {
+			panic(err) // bug
+		}
+	} else {
+		imports = append(imports, codegen.NewImport("_", filepath.ToSlash(m.DesignPkgPath)))
+	}
 	file.WriteHeader("Code Generator", "main", imports)
-	tmpl, err := template.New("generator").Parse(mainTmpl)
+	tmpl, err := template.New("generator").Parse(source)
 	if err != nil {
 		panic(err) // bug
 	}
@@ -170,6 +187,7 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 	context := map[string]string{
 		"Genfunc":       m.Genfunc,
 		"DesignPackage": m.DesignPkgPath,
+		"DesignFile":    designFile,
 		"PkgName":       pkgName,
 	}
 	if err := tmpl.Execute(file, context); err != nil {
@@ -177,6 +195,12 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 	}
 }
 
+// isDesignJSON returns true if the design is given as a design JSON file rather than as a design
+// package.
+func (m *Generator) isDesignJSON() bool {
+	return strings.HasSuffix(m.DesignPkgPath, ".json")
+}
+
 // spawn runs the compiled generator using the arguments initialized by Kingpin
 // when parsing the command line.
 func (m *Generator) spawn(genbin string) ([]string, error) {
@@ -216,3 +240,16 @@ func main() {
 	// We're done
 	fmt.Println(strings.Join(files, "\n"))
 }`
+
+const mainJSONTmpl = `
+func main() {
+	// Load the finalized design from its JSON representation
+	_, err := gendesignjson.Load({{ printf "%q" .DesignFile }})
+	dslengine.FailOnError(err)
+
+	files, err := {{.Genfunc}}()
+	dslengine.FailOnError(err)
+
+	// We're done
+	fmt.Println(strings.Join(files, "\n"))
+}`
@@ -0,0 +1,190 @@
+package goa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// JobState is the state of a job started by a long-running action.
+	JobState string
+
+	// Job describes a long-running operation started by an action.
+	Job struct {
+		// ID identifies the job.
+		ID string
+		// State is the current state of the job.
+		State JobState
+		// Result is the job result once it succeeded.
+		Result interface{}
+		// Error describes the failure once the job failed.
+		Error string
+		// CreatedAt is the time the job was created.
+		CreatedAt time.Time
+		// UpdatedAt is the time the job was last updated.
+		UpdatedAt time.Time
+	}
+
+	// JobStore persists the jobs started by long-running actions so that their status can be
+	// retrieved by the companion status actions. Implementations must be safe for concurrent
+	// use.
+	JobStore interface {
+		// Create creates and stores a new pending job.
+		Create(ctx context.Context) (*Job, error)
+		// Get returns the job with the given ID. It returns an error created with
+		// ErrNotFound if there is no such job.
+		Get(ctx context.Context, id string) (*Job, error)
+		// Update stores the given job. It returns an error created with ErrNotFound if the
+		// job was not created by the store.
+		Update(ctx context.Context, job *Job) error
+		// Delete removes the job with the given ID. It returns an error created with
+		// ErrNotFound if there is no such job.
+		Delete(ctx context.Context, id string) error
+	}
+
+	// MemoryJobStore is a JobStore that keeps the jobs in memory. Jobs are lost when the
+	// process exits and are not shared between processes.
+	MemoryJobStore struct {
+		// TTL is the duration finished jobs are kept after their last update, zero keeps
+		// them until they are deleted. NewMemoryJobStore sets it to DefaultJobTTL.
+		TTL time.Duration
+
+		mu   sync.Mutex
+		jobs map[string]*Job
+	}
+)
+
+const (
+	// JobPending is the state of jobs that have not started yet.
+	JobPending JobState = "pending"
+	// JobRunning is the state of jobs that are running.
+	JobRunning JobState = "running"
+	// JobSucceeded is the state of jobs that completed successfully.
+	JobSucceeded JobState = "succeeded"
+	// JobFailed is the state of jobs that completed with an error.
+	JobFailed JobState = "failed"
+)
+
+// DefaultJobTTL is the duration the stores created with NewMemoryJobStore keep finished jobs.
+const DefaultJobTTL = time.Hour
+
+// Done returns true if the state is final, i.e. the job succeeded or failed.
+func (s JobState) Done() bool {
+	return s == JobSucceeded || s == JobFailed
+}
+
+// NewMemoryJobStore returns an empty in-memory job store.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{TTL: DefaultJobTTL, jobs: make(map[string]*Job)}
+}
+
+// Create creates and stores a new pending job. It also evicts the expired finished jobs.
+func (s *MemoryJobStore) Create(ctx context.Context) (*Job, error) {
+	now := time.Now()
+	job := &Job{ID: newRandomID(), State: JobPending, CreatedAt: now, UpdatedAt: now}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, j := range s.jobs {
+		if s.expired(j, now) {
+			delete(s.jobs, id)
+		}
+	}
+	c := *job
+	s.jobs[job.ID] = &c
+	return job, nil
+}
+
+// Get returns a copy of the job with the given ID.
+func (s *MemoryJobStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok || s.expired(job, time.Now()) {
+		return nil, ErrNotFound("job not found", "id", id)
+	}
+	c := *job
+	return &c, nil
+}
+
+// Update stores a copy of the given job and sets its UpdatedAt field to the current time.
+func (s *MemoryJobStore) Update(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; !ok {
+		return ErrNotFound("job not found", "id", job.ID)
+	}
+	job.UpdatedAt = time.Now()
+	c := *job
+	s.jobs[job.ID] = &c
+	return nil
+}
+
+// Delete removes the job with the given ID.
+func (s *MemoryJobStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return ErrNotFound("job not found", "id", id)
+	}
+	delete(s.jobs, id)
+	return nil
+}
+
+// expired returns true if the given job is finished and was last updated more than TTL before
+// now.
+func (s *MemoryJobStore) expired(job *Job, now time.Time) bool {
+	return s.TTL > 0 && job.State.Done() && now.Sub(job.UpdatedAt) > s.TTL
+}
+
+// StartJob creates a job in the given store and runs fn in a new goroutine. The job state is
+// updated when fn starts and once it returns. The context given to fn carries the logger of ctx
+// but is not canceled when the request completes.
+func StartJob(ctx context.Context, store JobStore, fn func(context.Context) (interface{}, error)) (*Job, error) {
+	job, err := store.Create(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jctx := context.Background()
+	if logger := ContextLogger(ctx); logger != nil {
+		jctx = WithLogger(jctx, logger)
+	}
+	running := *job
+	running.State = JobRunning
+	go func() {
+		if err := store.Update(jctx, &running); err != nil {
+			LogError(jctx, "failed to update job", "job", running.ID, "err", err)
+		}
+		res, err := fn(jctx)
+		done := running
+		if err != nil {
+			done.State = JobFailed
+			done.Error = err.Error()
+		} else {
+			done.State = JobSucceeded
+			done.Result = res
+		}
+		if err := store.Update(jctx, &done); err != nil {
+			LogError(jctx, "failed to update job", "job", done.ID, "err", err)
+		}
+	}()
+	return job, nil
+}
+
+// JobStatusPath returns the path to the status of the job with the given ID started by a
+// long-running action given the path of the request that started it.
+func JobStatusPath(requestPath, id string) string {
+	return strings.TrimSuffix(requestPath, "/") + "/jobs/" + url.PathEscape(id)
+}
+
+// newRandomID returns a random identifier that can be used in URL paths and headers.
+func newRandomID() string {
+	b := make([]byte, 9)
+	io.ReadFull(rand.Reader, b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,125 @@
+package goa_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MemoryJobStore", func() {
+	var store *goa.MemoryJobStore
+	var ctx context.Context
+
+	BeforeEach(func() {
+		store = goa.NewMemoryJobStore()
+		ctx = context.Background()
+	})
+
+	It("creates pending jobs", func() {
+		job, err := store.Create(ctx)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(job.ID).ShouldNot(BeEmpty())
+		Ω(job.State).Should(Equal(goa.JobPending))
+		stored, err := store.Get(ctx, job.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stored).Should(Equal(job))
+	})
+
+	It("updates jobs", func() {
+		job, _ := store.Create(ctx)
+		job.State = goa.JobSucceeded
+		job.Result = 42
+		Ω(store.Update(ctx, job)).ShouldNot(HaveOccurred())
+		stored, err := store.Get(ctx, job.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stored.State.Done()).Should(BeTrue())
+		Ω(stored.Result).Should(Equal(42))
+	})
+
+	It("returns not found errors for unknown jobs", func() {
+		_, err := store.Get(ctx, "unknown")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(404))
+		Ω(store.Update(ctx, &goa.Job{ID: "unknown"})).Should(HaveOccurred())
+		Ω(store.Delete(ctx, "unknown")).Should(HaveOccurred())
+	})
+
+	It("deletes jobs", func() {
+		job, _ := store.Create(ctx)
+		Ω(store.Delete(ctx, job.ID)).ShouldNot(HaveOccurred())
+		_, err := store.Get(ctx, job.ID)
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("evicts the finished jobs once their TTL expires", func() {
+		store.TTL = 10 * time.Millisecond
+		done, _ := store.Create(ctx)
+		done.State = goa.JobSucceeded
+		Ω(store.Update(ctx, done)).ShouldNot(HaveOccurred())
+		running, _ := store.Create(ctx)
+		running.State = goa.JobRunning
+		Ω(store.Update(ctx, running)).ShouldNot(HaveOccurred())
+		time.Sleep(20 * time.Millisecond)
+		_, err := store.Get(ctx, done.ID)
+		Ω(err).Should(HaveOccurred())
+		_, err = store.Get(ctx, running.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("StartJob", func() {
+	var store *goa.MemoryJobStore
+	var fn func(context.Context) (interface{}, error)
+	var job *goa.Job
+
+	BeforeEach(func() {
+		store = goa.NewMemoryJobStore()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		job, err = goa.StartJob(context.Background(), store, fn)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	state := func() goa.JobState {
+		j, err := store.Get(context.Background(), job.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		return j.State
+	}
+
+	Context("with a function that succeeds", func() {
+		BeforeEach(func() {
+			fn = func(context.Context) (interface{}, error) { return "done", nil }
+		})
+
+		It("records the result", func() {
+			Eventually(state, time.Second).Should(Equal(goa.JobSucceeded))
+			j, _ := store.Get(context.Background(), job.ID)
+			Ω(j.Result).Should(Equal("done"))
+		})
+	})
+
+	Context("with a function that fails", func() {
+		BeforeEach(func() {
+			fn = func(context.Context) (interface{}, error) { return nil, errors.New("boom") }
+		})
+
+		It("records the error", func() {
+			Eventually(state, time.Second).Should(Equal(goa.JobFailed))
+			j, _ := store.Get(context.Background(), job.ID)
+			Ω(j.Error).Should(Equal("boom"))
+		})
+	})
+})
+
+var _ = Describe("JobStatusPath", func() {
+	It("appends the job ID to the request path", func() {
+		Ω(goa.JobStatusPath("/imports/", "abc")).Should(Equal("/imports/jobs/abc"))
+		Ω(goa.JobStatusPath("/imports", "abc")).Should(Equal("/imports/jobs/abc"))
+	})
+})
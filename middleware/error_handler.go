@@ -15,6 +15,7 @@ import (
 // them, it turns other Go error types into a 500 internal error response.
 // If verbose is false the details of internal errors is not included in HTTP responses.
 // If you use github.com/pkg/errors then wrapping the error will allow a trace to be printed to the logs
+// The responses are rendered as RFC 7807 problem details if the service ProblemDetails field is true.
 func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
@@ -52,6 +53,12 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 					}
 				}
 			}
+			if service.ProblemDetails {
+				if err, ok := respBody.(error); ok {
+					e = err
+				}
+				return service.SendError(ctx, status, e)
+			}
 			return service.Send(ctx, status, respBody)
 		}
 	}
@@ -194,4 +194,48 @@ var _ = Describe("ErrorHandler", func() {
 			Ω(data).Should(ContainSubstring("error_handler_test.go"))
 		})
 	})
+
+	Context("with a service rendering problem details", func() {
+		var gerr error
+
+		BeforeEach(func() {
+			service = newService(nil)
+			service.ProblemDetails = true
+			gerr = goa.NewErrorClass("code", 418)("teapot", "foobar", 42)
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return gerr
+			}
+		})
+
+		It("maps goa errors to problem details", func() {
+			var decoded goa.ProblemDetails
+			Ω(rw.Status).Should(Equal(418))
+			Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ProblemMediaIdentifier}))
+			err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(decoded.Type).Should(Equal("code"))
+			Ω(decoded.Status).Should(Equal(418))
+			Ω(decoded.Detail).Should(Equal("teapot"))
+			Ω(decoded.Instance).Should(Equal(gerr.(goa.ServiceError).Token()))
+			Ω(decoded.Extensions).Should(Equal(map[string]interface{}{"foobar": 42.0}))
+		})
+
+		Context("and a handler returning a Go error", func() {
+			BeforeEach(func() {
+				h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					return errors.New("boom")
+				}
+			})
+
+			It("renders an internal error", func() {
+				var decoded goa.ProblemDetails
+				Ω(rw.Status).Should(Equal(500))
+				Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ProblemMediaIdentifier}))
+				err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded.Status).Should(Equal(500))
+				Ω(decoded.Detail).Should(Equal("boom"))
+			})
+		})
+	})
 })
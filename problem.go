@@ -0,0 +1,147 @@
+package goa
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemMediaIdentifier is the media type identifier of the RFC 7807 problem details used for
+// error responses when the service ProblemDetails field is true.
+const ProblemMediaIdentifier = "application/problem+json"
+
+// ProblemDetails is the RFC 7807 representation of an error response. The type member contains
+// the error code, the instance member the error ID and the error metadata and validation
+// violations are rendered as extension members.
+type ProblemDetails struct {
+	// Type identifies the problem type, it contains the code of the error class.
+	Type string
+	// Title is a short human-readable summary of the problem type.
+	Title string
+	// Status is the HTTP status code of the response.
+	Status int
+	// Detail describes the specific problem occurrence.
+	Detail string
+	// Instance identifies the specific problem occurrence, it contains the error ID.
+	Instance string
+	// Extensions contains the additional members.
+	Extensions map[string]interface{}
+}
+
+// problemMembers lists the members defined by RFC 7807 which extensions cannot override.
+var problemMembers = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true}
+
+// NewProblemDetails returns the problem details that describe err. Errors that do not implement
+// ServiceError are described as internal errors.
+func NewProblemDetails(err error) *ProblemDetails {
+	p := &ProblemDetails{Status: http.StatusInternalServerError, Detail: err.Error()}
+	switch actual := err.(type) {
+	case *ErrorResponse:
+		p.Type = actual.Code
+		p.Status = actual.Status
+		p.Detail = actual.Detail
+		p.Instance = actual.ID
+		p.Extensions = actual.Meta
+		if len(actual.Violations) > 0 {
+			p.Extensions = make(map[string]interface{}, len(actual.Meta)+1)
+			for k, v := range actual.Meta {
+				p.Extensions[k] = v
+			}
+			p.Extensions["violations"] = actual.Violations
+		}
+	case ServiceError:
+		p.Status = actual.ResponseStatus()
+		p.Instance = actual.Token()
+	}
+	p.Title = http.StatusText(p.Status)
+	return p
+}
+
+// ErrorResponse returns the error response described by the problem details. This makes it
+// possible for clients to handle both error formats the same way.
+func (p *ProblemDetails) ErrorResponse() *ErrorResponse {
+	e := &ErrorResponse{
+		ID:     p.Instance,
+		Code:   p.Type,
+		Status: p.Status,
+		Detail: p.Detail,
+		Meta:   p.Extensions,
+	}
+	if v, ok := p.Extensions["violations"]; ok {
+		if b, err := json.Marshal(v); err == nil && json.Unmarshal(b, &e.Violations) == nil {
+			e.Meta = make(map[string]interface{}, len(p.Extensions)-1)
+			for k, v := range p.Extensions {
+				if k != "violations" {
+					e.Meta[k] = v
+				}
+			}
+			if len(e.Meta) == 0 {
+				e.Meta = nil
+			}
+		}
+	}
+	return e
+}
+
+// Error returns the problem occurrence details.
+func (p *ProblemDetails) Error() string {
+	return p.ErrorResponse().Error()
+}
+
+// MarshalJSON renders the problem details and its extension members in a single JSON object.
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		if !problemMembers[k] {
+			m[k] = v
+		}
+	}
+	typ := p.Type
+	if typ == "" {
+		typ = "about:blank"
+	}
+	m["type"] = typ
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON initializes the problem details from its JSON representation, members not defined
+// by RFC 7807 are stored in Extensions.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*p = ProblemDetails{}
+	for k, v := range m {
+		switch k {
+		case "type":
+			p.Type, _ = v.(string)
+			if p.Type == "about:blank" {
+				p.Type = ""
+			}
+		case "title":
+			p.Title, _ = v.(string)
+		case "status":
+			if f, ok := v.(float64); ok {
+				p.Status = int(f)
+			}
+		case "detail":
+			p.Detail, _ = v.(string)
+		case "instance":
+			p.Instance, _ = v.(string)
+		default:
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]interface{})
+			}
+			p.Extensions[k] = v
+		}
+	}
+	return nil
+}
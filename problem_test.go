@@ -0,0 +1,106 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProblemDetails", func() {
+	var err error
+	var problem *goa.ProblemDetails
+
+	JustBeforeEach(func() {
+		problem = goa.NewProblemDetails(err)
+	})
+
+	Context("of an error created with an error class", func() {
+		BeforeEach(func() {
+			err = goa.NewErrorClass("out_of_stock", 409)("no more items", "sku", "abc")
+		})
+
+		It("maps the error fields", func() {
+			e := err.(*goa.ErrorResponse)
+			Ω(problem.Type).Should(Equal("out_of_stock"))
+			Ω(problem.Title).Should(Equal("Conflict"))
+			Ω(problem.Status).Should(Equal(409))
+			Ω(problem.Detail).Should(Equal("no more items"))
+			Ω(problem.Instance).Should(Equal(e.ID))
+			Ω(problem.Extensions).Should(Equal(map[string]interface{}{"sku": "abc"}))
+		})
+
+		It("renders the metadata as extension members", func() {
+			b, err := json.Marshal(problem)
+			Ω(err).ShouldNot(HaveOccurred())
+			var m map[string]interface{}
+			Ω(json.Unmarshal(b, &m)).ShouldNot(HaveOccurred())
+			Ω(m).Should(HaveKeyWithValue("type", "out_of_stock"))
+			Ω(m).Should(HaveKeyWithValue("status", 409.0))
+			Ω(m).Should(HaveKeyWithValue("sku", "abc"))
+			Ω(m).ShouldNot(HaveKey("meta"))
+		})
+
+		It("round trips through JSON", func() {
+			e := err.(*goa.ErrorResponse)
+			b, err := json.Marshal(problem)
+			Ω(err).ShouldNot(HaveOccurred())
+			var decoded goa.ProblemDetails
+			Ω(json.Unmarshal(b, &decoded)).ShouldNot(HaveOccurred())
+			Ω(decoded.ErrorResponse()).Should(Equal(e))
+		})
+	})
+
+	Context("of an error with metadata that conflicts with the standard members", func() {
+		BeforeEach(func() {
+			err = goa.ErrBadRequest("invalid", "status", "bogus")
+		})
+
+		It("does not override the standard members", func() {
+			b, err := json.Marshal(problem)
+			Ω(err).ShouldNot(HaveOccurred())
+			var m map[string]interface{}
+			Ω(json.Unmarshal(b, &m)).ShouldNot(HaveOccurred())
+			Ω(m).Should(HaveKeyWithValue("status", 400.0))
+		})
+	})
+
+	Context("of a Go error", func() {
+		BeforeEach(func() {
+			err = errors.New("boom")
+		})
+
+		It("describes an internal error", func() {
+			Ω(problem.Type).Should(BeEmpty())
+			Ω(problem.Status).Should(Equal(500))
+			Ω(problem.Title).Should(Equal("Internal Server Error"))
+			Ω(problem.Detail).Should(Equal("boom"))
+		})
+
+		It("uses about:blank as type", func() {
+			b, err := json.Marshal(problem)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(ContainSubstring(`"type":"about:blank"`))
+		})
+	})
+
+	Context("of a validation error", func() {
+		BeforeEach(func() {
+			err = goa.MergeErrors(goa.MissingAttributeError("raw", "name"), goa.ErrBadRequest("bad", "key", "value"))
+		})
+
+		It("renders the violations as an extension member", func() {
+			Ω(problem.Extensions).Should(HaveKey("violations"))
+			Ω(err.(*goa.ErrorResponse).Meta).ShouldNot(HaveKey("violations"))
+			b, err := json.Marshal(problem)
+			Ω(err).ShouldNot(HaveOccurred())
+			var decoded goa.ProblemDetails
+			Ω(json.Unmarshal(b, &decoded)).ShouldNot(HaveOccurred())
+			e := decoded.ErrorResponse()
+			Ω(e.Violations).Should(Equal([]*goa.Violation{{Pointer: "/name", Rule: "required"}}))
+			Ω(e.Meta).Should(Equal(map[string]interface{}{"attribute": "name", "parent": "raw", "key": "value"}))
+		})
+	})
+})
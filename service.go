@@ -39,6 +39,9 @@ type (
 		Decoder *HTTPDecoder
 		// Response body encoder
 		Encoder *HTTPEncoder
+		// ProblemDetails causes error responses to be rendered as RFC 7807 problem details
+		// using the application/problem+json media type.
+		ProblemDetails bool
 
 		middleware []Middleware       // Middleware chain
 		cancel     context.CancelFunc // Service context cancel signal trigger
@@ -241,6 +244,23 @@ func (service *Service) Send(ctx context.Context, code int, body interface{}) er
 	return service.EncodeResponse(ctx, body)
 }
 
+// SendError serializes the given error with Send or as RFC 7807 problem details if the service
+// ProblemDetails field is true. Problem details are always encoded to JSON.
+func (service *Service) SendError(ctx context.Context, code int, err error) error {
+	if !service.ProblemDetails {
+		return service.Send(ctx, code, err)
+	}
+	r := ContextResponse(ctx)
+	if r == nil {
+		return fmt.Errorf("no response data in context")
+	}
+	p := NewProblemDetails(err)
+	p.Status, p.Title = code, http.StatusText(code)
+	r.Header().Set("Content-Type", ProblemMediaIdentifier)
+	r.WriteHeader(code)
+	return service.Encoder.Encode(p, r, "application/json")
+}
+
 // ServeFiles create a "FileServer" controller and calls ServerFiles on it.
 func (service *Service) ServeFiles(path, filename string) error {
 	ctrl := service.NewController("FileServer")
@@ -0,0 +1,76 @@
+package goa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// EventStream writes Server-Sent Events to a HTTP response. Each event is flushed to the client as
+// soon as it is sent.
+type EventStream struct {
+	resp    *ResponseData
+	flusher http.Flusher
+}
+
+// NewEventStream writes the headers of a Server-Sent Events response with the given status code to
+// the response data stored in ctx and returns a stream that writes events to it. It returns an
+// error if the underlying response writer does not support flushing.
+func NewEventStream(ctx context.Context, code int) (*EventStream, error) {
+	resp := ContextResponse(ctx)
+	if resp == nil {
+		return nil, fmt.Errorf("no response data in context")
+	}
+	flusher, ok := resp.ResponseWriter.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("response writer does not support flushing, cannot stream events")
+	}
+	h := resp.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	resp.WriteHeader(code)
+	flusher.Flush()
+	return &EventStream{resp: resp, flusher: flusher}, nil
+}
+
+// LastEventID returns the value of the Last-Event-ID header sent by clients reconnecting to an event
+// stream, the empty string if the request does not have one.
+func LastEventID(ctx context.Context) string {
+	if req := ContextRequest(ctx); req != nil && req.Request != nil {
+		return req.Header.Get("Last-Event-ID")
+	}
+	return ""
+}
+
+// Send writes an event with the given ID, name and data and flushes it to the client. The ID and
+// name are omitted when empty. The data is serialized using the service encoder.
+func (s *EventStream) Send(id, event string, data interface{}) error {
+	var body bytes.Buffer
+	if err := s.resp.Service.Encoder.Encode(data, &body, "application/json"); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if id != "" {
+		fmt.Fprintf(&buf, "id: %s\n", sanitizeEventField(id))
+	}
+	if event != "" {
+		fmt.Fprintf(&buf, "event: %s\n", sanitizeEventField(event))
+	}
+	for _, line := range strings.Split(strings.TrimRight(body.String(), "\n"), "\n") {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+	buf.WriteByte('\n')
+	if _, err := s.resp.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// sanitizeEventField removes the line breaks that would otherwise end the event field early.
+func sanitizeEventField(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
@@ -0,0 +1,49 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventStream", func() {
+	var rw *httptest.ResponseRecorder
+	var req *http.Request
+	var ctx context.Context
+
+	BeforeEach(func() {
+		s := goa.New("test")
+		s.Encoder.Register(goa.NewJSONEncoder, "*/*")
+		rw = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/events", nil)
+		req.Header.Set("Last-Event-ID", "41")
+		ctx = goa.NewContext(context.Background(), rw, req, url.Values{})
+		goa.ContextResponse(ctx).Service = s
+	})
+
+	It("writes the event stream headers", func() {
+		_, err := goa.NewEventStream(ctx, 200)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Header().Get("Content-Type")).Should(Equal("text/event-stream"))
+		Ω(rw.Header().Get("Cache-Control")).Should(Equal("no-cache"))
+	})
+
+	It("writes and flushes events", func() {
+		stream, err := goa.NewEventStream(ctx, 200)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stream.Send("42", "update", map[string]string{"name": "foo"})).ShouldNot(HaveOccurred())
+		Ω(stream.Send("", "", "bar")).ShouldNot(HaveOccurred())
+		Ω(rw.Flushed).Should(BeTrue())
+		Ω(rw.Body.String()).Should(Equal("id: 42\nevent: update\ndata: {\"name\":\"foo\"}\n\ndata: \"bar\"\n\n"))
+	})
+
+	It("returns the Last-Event-ID header", func() {
+		Ω(goa.LastEventID(ctx)).Should(Equal("41"))
+	})
+})
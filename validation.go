@@ -2,9 +2,11 @@ package goa
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/mail"
 	"net/url"
+	"reflect"
 	"regexp"
 	"sync"
 	"time"
@@ -84,7 +86,7 @@ func ValidateFormat(f Format, val string) error {
 	var err error
 	switch f {
 	case FormatDate:
-		_, err = time.Parse("2006-01-02", val)
+		_, err = time.Parse(DateLayout, val)
 	case FormatDateTime:
 		_, err = time.Parse(time.RFC3339, val)
 	case FormatUUID:
@@ -153,3 +155,41 @@ func ValidatePattern(p string, val string) bool {
 	}
 	return r.MatchString(val)
 }
+
+// ValidateMultipleOf returns true if val is a multiple of m. It tolerates the rounding errors
+// inherent to floating point arithmetic so that for example 0.3 is considered a multiple of 0.1.
+func ValidateMultipleOf(val, m float64) bool {
+	q := val / m
+	return math.Abs(q-math.Floor(q+0.5)) <= 1e-9*math.Max(1, math.Abs(q))
+}
+
+// ValidateUniqueItems returns true if the elements of the slice val are all distinct. Elements are
+// compared by value so that two pointers to equal structs are considered equal.
+func ValidateUniqueItems(val interface{}) bool {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice {
+		return true
+	}
+	switch v.Type().Elem().Kind() {
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		seen := make(map[interface{}]struct{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			e := v.Index(i).Interface()
+			if _, ok := seen[e]; ok {
+				return false
+			}
+			seen[e] = struct{}{}
+		}
+	default:
+		for i := 0; i < v.Len(); i++ {
+			for j := i + 1; j < v.Len(); j++ {
+				if reflect.DeepEqual(v.Index(i).Interface(), v.Index(j).Interface()) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -396,3 +396,35 @@ var _ = Describe("ValidateFormat", func() {
 		})
 	})
 })
+
+var _ = Describe("ValidateMultipleOf", func() {
+	It("accepts integer multiples", func() {
+		Ω(goa.ValidateMultipleOf(15, 5)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(-15, 5)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(16, 5)).Should(BeFalse())
+	})
+
+	It("tolerates floating point rounding errors", func() {
+		Ω(goa.ValidateMultipleOf(0.3, 0.1)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(19.99, 0.01)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(0.35, 0.1)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("ValidateUniqueItems", func() {
+	type item struct{ Name string }
+
+	It("detects duplicate primitive values", func() {
+		Ω(goa.ValidateUniqueItems([]int{1, 2, 3})).Should(BeTrue())
+		Ω(goa.ValidateUniqueItems([]string{"a", "b", "a"})).Should(BeFalse())
+	})
+
+	It("compares pointers by value", func() {
+		Ω(goa.ValidateUniqueItems([]*item{{"a"}, {"b"}})).Should(BeTrue())
+		Ω(goa.ValidateUniqueItems([]*item{{"a"}, {"a"}})).Should(BeFalse())
+	})
+
+	It("compares nested slices by value", func() {
+		Ω(goa.ValidateUniqueItems([][]byte{[]byte("a"), []byte("a")})).Should(BeFalse())
+	})
+})
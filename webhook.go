@@ -0,0 +1,154 @@
+package goa
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// WebhookEventHeader is the name of the header that contains the name of the webhook.
+	WebhookEventHeader = "X-Webhook-Event"
+	// WebhookIDHeader is the name of the header that identifies a webhook delivery, the value
+	// is the same for all the attempts so that receivers can discard duplicates.
+	WebhookIDHeader = "X-Webhook-ID"
+	// WebhookTimestampHeader is the name of the header that contains the time the webhook
+	// request was signed as a Unix timestamp.
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader is the name of the header that contains the webhook request
+	// signature.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// DefaultWebhookTolerance is the maximum age of the webhook requests accepted by the generated
+// verifiers.
+var DefaultWebhookTolerance = 5 * time.Minute
+
+// WebhookSender sends webhook requests signed with HMAC-SHA256 and retries failed deliveries.
+type WebhookSender struct {
+	// Secret is the key shared with the receivers used to sign the requests.
+	Secret []byte
+	// Client is the HTTP client used to send the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// MaxAttempts is the maximum number of delivery attempts, a single attempt is made if
+	// MaxAttempts is lower than 2.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it doubles after each attempt.
+	Backoff time.Duration
+}
+
+// NewWebhookSender returns a sender that signs requests with the given secret and makes up to 5
+// delivery attempts.
+func NewWebhookSender(secret []byte) *WebhookSender {
+	return &WebhookSender{Secret: secret, MaxAttempts: 5, Backoff: time.Second}
+}
+
+// Send encodes the payload to JSON, signs it and posts it to the given URL. The delivery succeeds
+// if the receiver responds with one of the expected status codes or with any 2xx status code if
+// none is given. Requests that fail because of network errors or that get a 429 or 5xx response
+// are retried. Send returns the last error once all the attempts failed or the context is done.
+func (s *WebhookSender) Send(ctx context.Context, url, event string, payload interface{}, expected ...int) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	id := newRandomID()
+	delay := s.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := s.deliver(ctx, client, url, event, id, body, expected)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.MaxAttempts {
+			return fmt.Errorf("webhook %s: %s", event, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("webhook %s: %s", event, ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// deliver makes one delivery attempt, it returns true and an error if the attempt failed and
+// should be retried.
+func (s *WebhookSender) deliver(ctx context.Context, client *http.Client, url, event, id string, body []byte, expected []int) (bool, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookIDHeader, id)
+	req.Header.Set(WebhookTimestampHeader, ts)
+	req.Header.Set(WebhookSignatureHeader, WebhookSignature(s.Secret, ts, body))
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if len(expected) == 0 && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return false, nil
+		}
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected response status %s", resp.Status)
+}
+
+// WebhookSignature returns the signature of a webhook request sent at the given Unix timestamp
+// with the given body. The signature is the hex encoded HMAC-SHA256 of the timestamp followed by a
+// dot and the body prefixed with "sha256=".
+func WebhookSignature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook reads the body of the given webhook request and checks its signature against the
+// shared secret. It also rejects requests signed more than tolerance ago if tolerance is not 0.
+// The returned errors are created with ErrUnauthorized if the request cannot be authenticated.
+func VerifyWebhook(req *http.Request, secret []byte, tolerance time.Duration) ([]byte, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, ErrBadRequest(err)
+	}
+	ts := req.Header.Get(WebhookTimestampHeader)
+	if tolerance > 0 {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return nil, ErrUnauthorized("invalid webhook timestamp", "timestamp", ts)
+		}
+		if age := time.Since(time.Unix(sec, 0)); age > tolerance || age < -tolerance {
+			return nil, ErrUnauthorized("webhook timestamp is outside of the tolerance", "timestamp", ts)
+		}
+	}
+	expected := WebhookSignature(secret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(req.Header.Get(WebhookSignatureHeader))) {
+		return nil, ErrUnauthorized("invalid webhook signature")
+	}
+	return body, nil
+}
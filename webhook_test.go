@@ -0,0 +1,165 @@
+package goa_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookSender", func() {
+	var secret = []byte("secret")
+	var sender *goa.WebhookSender
+	var statuses []int
+	var requests []*http.Request
+	var bodies [][]byte
+	var server *httptest.Server
+	var err error
+
+	BeforeEach(func() {
+		sender = goa.NewWebhookSender(secret)
+		sender.Backoff = time.Millisecond
+		statuses = nil
+		requests = nil
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, r)
+			bodies = append(bodies, body)
+			status := 200
+			if len(statuses) > 0 {
+				status, statuses = statuses[0], statuses[1:]
+			}
+			w.WriteHeader(status)
+		}))
+	})
+
+	JustBeforeEach(func() {
+		err = sender.Send(context.Background(), server.URL, "created", map[string]int{"id": 1}, 200, 204)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends signed requests", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(requests).Should(HaveLen(1))
+		r := requests[0]
+		Ω(r.Method).Should(Equal("POST"))
+		Ω(r.Header.Get(goa.WebhookEventHeader)).Should(Equal("created"))
+		Ω(r.Header.Get(goa.WebhookIDHeader)).ShouldNot(BeEmpty())
+		ts := r.Header.Get(goa.WebhookTimestampHeader)
+		Ω(r.Header.Get(goa.WebhookSignatureHeader)).Should(Equal(goa.WebhookSignature(secret, ts, bodies[0])))
+		var payload map[string]int
+		Ω(json.Unmarshal(bodies[0], &payload)).ShouldNot(HaveOccurred())
+		Ω(payload).Should(Equal(map[string]int{"id": 1}))
+	})
+
+	Context("with a receiver that fails temporarily", func() {
+		BeforeEach(func() {
+			statuses = []int{503, 429}
+		})
+
+		It("retries with the same delivery ID", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(requests).Should(HaveLen(3))
+			id := requests[0].Header.Get(goa.WebhookIDHeader)
+			Ω(requests[1].Header.Get(goa.WebhookIDHeader)).Should(Equal(id))
+			Ω(requests[2].Header.Get(goa.WebhookIDHeader)).Should(Equal(id))
+		})
+	})
+
+	Context("with a receiver that keeps failing", func() {
+		BeforeEach(func() {
+			sender.MaxAttempts = 2
+			statuses = []int{500, 500, 500}
+		})
+
+		It("gives up after the maximum number of attempts", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(requests).Should(HaveLen(2))
+		})
+	})
+
+	Context("with a receiver that rejects the request", func() {
+		BeforeEach(func() {
+			statuses = []int{400}
+		})
+
+		It("does not retry", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(requests).Should(HaveLen(1))
+		})
+	})
+
+	Context("with a receiver that responds with an unexpected success status", func() {
+		BeforeEach(func() {
+			statuses = []int{202}
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(requests).Should(HaveLen(1))
+		})
+	})
+})
+
+var _ = Describe("VerifyWebhook", func() {
+	var secret = []byte("secret")
+	var body = []byte(`{"id":1}`)
+	var timestamp string
+	var signature string
+	var verified []byte
+	var err error
+
+	BeforeEach(func() {
+		timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+		signature = ""
+	})
+
+	JustBeforeEach(func() {
+		if signature == "" {
+			signature = goa.WebhookSignature(secret, timestamp, body)
+		}
+		req, _ := http.NewRequest("POST", "/", bytes.NewReader(body))
+		req.Header.Set(goa.WebhookTimestampHeader, timestamp)
+		req.Header.Set(goa.WebhookSignatureHeader, signature)
+		verified, err = goa.VerifyWebhook(req, secret, time.Minute)
+	})
+
+	It("returns the body of valid requests", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(verified).Should(Equal(body))
+	})
+
+	Context("with an invalid signature", func() {
+		BeforeEach(func() {
+			signature = goa.WebhookSignature([]byte("other"), timestamp, body)
+		})
+
+		It("returns an unauthorized error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+
+	Context("with an expired timestamp", func() {
+		BeforeEach(func() {
+			timestamp = strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+		})
+
+		It("returns an unauthorized error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+})